@@ -2,7 +2,9 @@ package alerts
 
 import (
 	"context"
+	"errors"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,9 +12,13 @@ import (
 	"github.com/highlight-run/highlight/backend/model"
 	"github.com/highlight-run/highlight/backend/routing"
 	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
 
 	"github.com/highlight-run/highlight/backend/alerts/integrations"
 	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/microsoftteams"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/opsgenie"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/pagerduty"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 )
@@ -26,9 +32,13 @@ type SendErrorAlertEvent struct {
 	ErrorCount      int64
 	VisitedURL      string
 	FirstErrorAlert bool
+	// Regression is set when the alert is sent because a resolved error group reoccurred
+	Regression bool
 }
 
-func SendErrorAlert(ctx context.Context, event SendErrorAlertEvent) error {
+// SendErrorAlert notifies the destinations of an error alert other than slack and email.
+// The caller checks the alert silences once for every destination of the alert, see model.GetErrorAlertSilenceMatch.
+func SendErrorAlert(ctx context.Context, db *gorm.DB, event SendErrorAlertEvent) error {
 	payload := integrations.ErrorAlertPayload{
 		ErrorCount:      event.ErrorCount,
 		ErrorTitle:      event.ErrorGroup.Event,
@@ -42,13 +52,17 @@ func SendErrorAlert(ctx context.Context, event SendErrorAlertEvent) error {
 		SessionExcluded: event.Session.Excluded && *event.Session.Processed,
 		VisitedURL:      event.VisitedURL,
 		FirstTimeAlert:  event.FirstErrorAlert,
+		Regression:      event.Regression,
 	}
 
+	// copied before the goroutines attach their referrer to the payload
+	alertIntegrationsPayload := payload
+
 	var g errgroup.Group
 	g.Go(func() error {
 		payload = attachReferrerToErrorAlertPayload(ctx, payload, routing.Webhook)
 		for _, wh := range event.ErrorAlert.WebhookDestinations {
-			if err := webhook.SendErrorAlert(wh, &payload); err != nil {
+			if err := webhook.SendErrorAlert(ctx, db, event.ErrorAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -76,9 +90,46 @@ func SendErrorAlert(ctx context.Context, event SendErrorAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		return sendAlertIntegrations(ctx, event.ErrorAlert.AlertIntegrations, getErrorAlertDedupKey(event.ErrorAlert, event.ErrorGroup), alertIntegrationsPayload, pagerduty.SendErrorAlert, opsgenie.SendErrorAlert, microsoftteams.SendErrorAlert)
+	})
+
 	return g.Wait()
 }
 
+// ResolveErrorGroupIncidents resolves the PagerDuty incidents and closes the Opsgenie alerts
+// opened by the error alerts that were sent for an error group, once the group is resolved.
+func ResolveErrorGroupIncidents(ctx context.Context, db *gorm.DB, errorGroup *model.ErrorGroup) error {
+	var errorAlerts []*model.ErrorAlert
+	if err := db.WithContext(ctx).
+		Where(&model.ErrorAlert{Alert: model.Alert{ProjectID: errorGroup.ProjectID}}).
+		Where("id IN (?)", db.Model(&model.ErrorAlertEvent{}).
+			Select("error_alert_id").
+			Where("error_object_id IN (?)", db.Model(&model.ErrorObject{}).
+				Select("id").
+				Where(&model.ErrorObject{ErrorGroupID: errorGroup.ID}))).
+		Find(&errorAlerts).Error; err != nil {
+		return err
+	}
+
+	payload := integrations.ErrorAlertPayload{
+		ErrorTitle: errorGroup.Event,
+		Resolved:   true,
+	}
+	var errs []error
+	for _, errorAlert := range errorAlerts {
+		// microsoft teams has no incident to resolve
+		incidentIntegrations := model.AlertIntegrations{
+			PagerDutyServices: errorAlert.PagerDutyServices,
+			OpsgenieTeams:     errorAlert.OpsgenieTeams,
+		}
+		if err := sendAlertIntegrations(ctx, incidentIntegrations, getErrorAlertDedupKey(errorAlert, errorGroup), payload, pagerduty.SendErrorAlert, opsgenie.SendErrorAlert, microsoftteams.SendErrorAlert); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func attachReferrerToErrorAlertPayload(ctx context.Context, payload integrations.ErrorAlertPayload, referrer routing.Referrer) integrations.ErrorAlertPayload {
 	payload.ErrorURL = routing.AttachReferrer(ctx, payload.ErrorURL, referrer)
 	payload.ErrorResolveURL = routing.AttachReferrer(ctx, payload.ErrorResolveURL, referrer)
@@ -124,7 +175,7 @@ type SendNewUserAlertEvent struct {
 	Workspace    *model.Workspace
 }
 
-func SendNewUserAlert(event SendNewUserAlertEvent) error {
+func SendNewUserAlert(ctx context.Context, db *gorm.DB, event SendNewUserAlertEvent) error {
 	sessionUserProperties, err := event.Session.GetUserProperties()
 	if err != nil {
 		return err
@@ -142,7 +193,7 @@ func SendNewUserAlert(event SendNewUserAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendNewUserAlert(wh, &payload); err != nil {
+			if err := webhook.SendNewUserAlert(ctx, db, event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -169,6 +220,10 @@ func SendNewUserAlert(event SendNewUserAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		return sendAlertIntegrations(ctx, event.SessionAlert.AlertIntegrations, getDedupKey(model.AlertType.NEW_USER, event.SessionAlert.ID, event.Session.SecureID), payload, pagerduty.SendNewUserAlert, opsgenie.SendNewUserAlert, microsoftteams.SendNewUserAlert)
+	})
+
 	return g.Wait()
 }
 
@@ -179,7 +234,7 @@ type SendNewSessionAlertEvent struct {
 	VisitedURL   *string
 }
 
-func SendNewSessionAlert(event SendNewSessionAlertEvent) error {
+func SendNewSessionAlert(ctx context.Context, db *gorm.DB, event SendNewSessionAlertEvent) error {
 	var sessionUserProperties map[string]string
 	var err error
 
@@ -204,7 +259,7 @@ func SendNewSessionAlert(event SendNewSessionAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendNewSessionAlert(wh, &payload); err != nil {
+			if err := webhook.SendNewSessionAlert(ctx, db, event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -233,6 +288,10 @@ func SendNewSessionAlert(event SendNewSessionAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		return sendAlertIntegrations(ctx, event.SessionAlert.AlertIntegrations, getDedupKey(model.AlertType.NEW_SESSION, event.SessionAlert.ID, event.Session.SecureID), payload, pagerduty.SendNewSessionAlert, opsgenie.SendNewSessionAlert, microsoftteams.SendNewSessionAlert)
+	})
+
 	return g.Wait()
 }
 
@@ -244,7 +303,7 @@ type TrackPropertiesAlertEvent struct {
 	RelatedFields []*model.Field
 }
 
-func SendTrackPropertiesAlert(event TrackPropertiesAlertEvent) error {
+func SendTrackPropertiesAlert(ctx context.Context, db *gorm.DB, event TrackPropertiesAlertEvent) error {
 	// format matched properties
 	var mappedMatchedProperties []integrations.Property
 	var mappedRelatedProperties []integrations.Property
@@ -271,7 +330,7 @@ func SendTrackPropertiesAlert(event TrackPropertiesAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendTrackPropertiesAlert(wh, &payload); err != nil {
+			if err := webhook.SendTrackPropertiesAlert(ctx, db, event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -300,6 +359,10 @@ func SendTrackPropertiesAlert(event TrackPropertiesAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		return sendAlertIntegrations(ctx, event.SessionAlert.AlertIntegrations, getDedupKey(model.AlertType.TRACK_PROPERTIES, event.SessionAlert.ID, event.Session.SecureID), payload, pagerduty.SendTrackPropertiesAlert, opsgenie.SendTrackPropertiesAlert, microsoftteams.SendTrackPropertiesAlert)
+	})
+
 	return g.Wait()
 }
 
@@ -310,7 +373,7 @@ type UserPropertiesAlertEvent struct {
 	MatchedFields []*model.Field
 }
 
-func SendUserPropertiesAlert(event UserPropertiesAlertEvent) error {
+func SendUserPropertiesAlert(ctx context.Context, db *gorm.DB, event UserPropertiesAlertEvent) error {
 	var mappedProperties []integrations.Property
 
 	for _, field := range event.MatchedFields {
@@ -329,7 +392,7 @@ func SendUserPropertiesAlert(event UserPropertiesAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendUserPropertiesAlert(wh, &payload); err != nil {
+			if err := webhook.SendUserPropertiesAlert(ctx, db, event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -358,6 +421,10 @@ func SendUserPropertiesAlert(event UserPropertiesAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		return sendAlertIntegrations(ctx, event.SessionAlert.AlertIntegrations, getDedupKey(model.AlertType.USER_PROPERTIES, event.SessionAlert.ID, event.Session.SecureID), payload, pagerduty.SendUserPropertiesAlert, opsgenie.SendUserPropertiesAlert, microsoftteams.SendUserPropertiesAlert)
+	})
+
 	return g.Wait()
 }
 
@@ -370,7 +437,7 @@ type ErrorFeedbackAlertEvent struct {
 	UserEmail      *string
 }
 
-func SendErrorFeedbackAlert(event ErrorFeedbackAlertEvent) error {
+func SendErrorFeedbackAlert(ctx context.Context, db *gorm.DB, event ErrorFeedbackAlertEvent) error {
 	identifier := "Someone"
 	if event.UserName != nil {
 		identifier = *event.UserName
@@ -387,7 +454,7 @@ func SendErrorFeedbackAlert(event ErrorFeedbackAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.ErrorAlert.WebhookDestinations {
-			if err := webhook.SendErrorFeedbackAlert(wh, &payload); err != nil {
+			if err := webhook.SendErrorFeedbackAlert(ctx, db, event.ErrorAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -416,6 +483,10 @@ func SendErrorFeedbackAlert(event ErrorFeedbackAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		return sendAlertIntegrations(ctx, event.ErrorAlert.AlertIntegrations, getDedupKey(model.AlertType.ERROR_FEEDBACK, event.ErrorAlert.ID, strconv.Itoa(event.SessionComment.ID)), payload, pagerduty.SendErrorFeedbackAlert, opsgenie.SendErrorFeedbackAlert, microsoftteams.SendErrorFeedbackAlert)
+	})
+
 	return g.Wait()
 }
 
@@ -426,7 +497,7 @@ type RageClicksAlertEvent struct {
 	RageClicksCount int64
 }
 
-func SendRageClicksAlert(event RageClicksAlertEvent) error {
+func SendRageClicksAlert(ctx context.Context, db *gorm.DB, event RageClicksAlertEvent) error {
 	payload := integrations.RageClicksAlertPayload{
 		RageClicksCount: event.RageClicksCount,
 		SessionURL:      getSessionURL(event.SessionAlert.ProjectID, event.Session),
@@ -436,7 +507,7 @@ func SendRageClicksAlert(event RageClicksAlertEvent) error {
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.SessionAlert.WebhookDestinations {
-			if err := webhook.SendRageClicksAlert(wh, &payload); err != nil {
+			if err := webhook.SendRageClicksAlert(ctx, db, event.SessionAlert.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -465,6 +536,10 @@ func SendRageClicksAlert(event RageClicksAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		return sendAlertIntegrations(ctx, event.SessionAlert.AlertIntegrations, getDedupKey(model.AlertType.RAGE_CLICK, event.SessionAlert.ID, event.Session.SecureID), payload, pagerduty.SendRageClicksAlert, opsgenie.SendRageClicksAlert, microsoftteams.SendRageClicksAlert)
+	})
+
 	return g.Wait()
 }
 
@@ -475,9 +550,11 @@ type MetricMonitorAlertEvent struct {
 	DiffOverValue string
 	Value         string
 	Threshold     string
+	// Resolved is set when notifying that the monitored value returned below the threshold
+	Resolved bool
 }
 
-func SendMetricMonitorAlert(event MetricMonitorAlertEvent) error {
+func SendMetricMonitorAlert(ctx context.Context, db *gorm.DB, event MetricMonitorAlertEvent) error {
 	payload := integrations.MetricMonitorAlertPayload{
 		MetricToMonitor: event.MetricMonitor.MetricToMonitor,
 		MonitorURL:      getMonitorURL(event.MetricMonitor),
@@ -485,12 +562,13 @@ func SendMetricMonitorAlert(event MetricMonitorAlertEvent) error {
 		DiffOverValue:   event.DiffOverValue,
 		Value:           event.Value,
 		Threshold:       event.Value,
+		Resolved:        event.Resolved,
 	}
 
 	var g errgroup.Group
 	g.Go(func() error {
 		for _, wh := range event.MetricMonitor.WebhookDestinations {
-			if err := webhook.SendMetricMonitorAlert(wh, &payload); err != nil {
+			if err := webhook.SendMetricMonitorAlert(ctx, db, event.MetricMonitor.ProjectID, wh, &payload); err != nil {
 				return err
 			}
 		}
@@ -519,6 +597,10 @@ func SendMetricMonitorAlert(event MetricMonitorAlertEvent) error {
 		return nil
 	})
 
+	g.Go(func() error {
+		return sendAlertIntegrations(ctx, event.MetricMonitor.AlertIntegrations, getDedupKey(model.AlertType.METRIC_MONITOR, event.MetricMonitor.ID), payload, pagerduty.SendMetricMonitorAlert, opsgenie.SendMetricMonitorAlert, microsoftteams.SendMetricMonitorAlert)
+	})
+
 	return g.Wait()
 }
 
@@ -526,28 +608,37 @@ type LogAlertEvent struct {
 	LogAlert  *model.LogAlert
 	Workspace *model.Workspace
 	Count     int
+	// Threshold is the crossed count, which is learned from the baseline of anomaly alerts
+	Threshold int
 	StartDate time.Time
 	EndDate   time.Time
+	// Resolved is set when notifying that the alert condition cleared
+	Resolved bool
 }
 
-func SendLogAlert(event LogAlertEvent) error {
+func SendLogAlert(ctx context.Context, db *gorm.DB, event LogAlertEvent) error {
 	payload := integrations.LogAlertPayload{
 		Name:           event.LogAlert.Name,
 		Query:          event.LogAlert.Query,
 		Count:          event.Count,
 		StartDate:      event.StartDate,
 		EndDate:        event.EndDate,
-		Threshold:      event.LogAlert.CountThreshold,
+		Threshold:      event.Threshold,
 		BelowThreshold: event.LogAlert.BelowThreshold,
 		AlertURL:       model.GetLogAlertURL(event.LogAlert.ProjectID, event.LogAlert.Query, event.StartDate, event.EndDate),
+		Resolved:       event.Resolved,
 	}
 
 	for _, wh := range event.LogAlert.WebhookDestinations {
-		if err := webhook.SendLogAlert(wh, &payload); err != nil {
+		if err := webhook.SendLogAlert(ctx, db, event.LogAlert.ProjectID, wh, &payload); err != nil {
 			return err
 		}
 	}
 
+	if err := sendAlertIntegrations(ctx, event.LogAlert.AlertIntegrations, getDedupKey(model.AlertType.LOG, event.LogAlert.ID), payload, pagerduty.SendLogAlert, opsgenie.SendLogAlert, microsoftteams.SendLogAlert); err != nil {
+		return err
+	}
+
 	if !isWorkspaceIntegratedWithDiscord(*event.Workspace) {
 		return nil
 	}
@@ -570,6 +661,109 @@ func SendLogAlert(event LogAlertEvent) error {
 	return nil
 }
 
+type TraceAlertEvent struct {
+	TraceAlert *model.TraceAlert
+	Workspace  *model.Workspace
+	// GroupKey is the group of grouped alerts that triggered the alert
+	GroupKey  string
+	Value     float64
+	StartDate time.Time
+	EndDate   time.Time
+	// Resolved is set when notifying that the alert condition cleared
+	Resolved bool
+}
+
+func SendTraceAlert(ctx context.Context, db *gorm.DB, event TraceAlertEvent) error {
+	query := event.TraceAlert.GetGroupQuery(event.GroupKey)
+	payload := integrations.TraceAlertPayload{
+		Name:           event.TraceAlert.Name,
+		Query:          event.TraceAlert.Query,
+		GroupKey:       event.GroupKey,
+		MetricName:     event.TraceAlert.GetMetricName(),
+		Value:          event.Value,
+		Threshold:      event.TraceAlert.Threshold,
+		ValueStr:       event.TraceAlert.FormatValue(event.Value),
+		ThresholdStr:   event.TraceAlert.FormatValue(event.TraceAlert.Threshold),
+		StartDate:      event.StartDate,
+		EndDate:        event.EndDate,
+		BelowThreshold: event.TraceAlert.BelowThreshold,
+		AlertURL:       model.GetTraceAlertURL(event.TraceAlert.ProjectID, query, event.StartDate, event.EndDate),
+		Resolved:       event.Resolved,
+	}
+
+	for _, wh := range event.TraceAlert.WebhookDestinations {
+		if err := webhook.SendTraceAlert(ctx, db, event.TraceAlert.ProjectID, wh, &payload); err != nil {
+			return err
+		}
+	}
+
+	if err := sendAlertIntegrations(ctx, event.TraceAlert.AlertIntegrations, getDedupKey(model.AlertType.TRACE, event.TraceAlert.ID, event.GroupKey), payload, pagerduty.SendTraceAlert, opsgenie.SendTraceAlert, microsoftteams.SendTraceAlert); err != nil {
+		return err
+	}
+
+	if !isWorkspaceIntegratedWithDiscord(*event.Workspace) {
+		return nil
+	}
+
+	bot, err := discord.NewDiscordBot(*event.Workspace.DiscordGuildId)
+	if err != nil {
+		return err
+	}
+
+	channels := event.TraceAlert.DiscordChannelsToNotify
+
+	for _, channel := range channels {
+		err = bot.SendTraceAlert(channel.ID, payload)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getDedupKey identifies the incident of an alert in incident management tools, so that repeated notifications
+// update the same incident and resolved alerts resolve it.
+func getDedupKey(alertType string, alertID int, parts ...string) string {
+	return strings.Join(append([]string{"highlight", alertType, strconv.Itoa(alertID)}, parts...), "-")
+}
+
+// getErrorAlertDedupKey identifies the incident of an error alert for an error group.
+func getErrorAlertDedupKey(errorAlert *model.ErrorAlert, errorGroup *model.ErrorGroup) string {
+	return getDedupKey(model.AlertType.ERROR, errorAlert.ID, "error-group", strconv.Itoa(errorGroup.ID))
+}
+
+// sendAlertIntegrations notifies the PagerDuty services, Opsgenie teams and Microsoft Teams channels of an alert.
+// Every destination is notified even if another fails.
+func sendAlertIntegrations[T any](
+	ctx context.Context,
+	alertIntegrations model.AlertIntegrations,
+	dedupKey string,
+	payload T,
+	sendPagerDuty func(context.Context, *model.PagerDutyService, string, T) error,
+	sendOpsgenie func(context.Context, *model.OpsgenieTeam, string, T) error,
+	sendMicrosoftTeams func(context.Context, *model.MicrosoftTeamsChannel, T) error,
+) error {
+	var errs []error
+	for _, service := range alertIntegrations.PagerDutyServices {
+		if err := sendPagerDuty(ctx, service, dedupKey, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, team := range alertIntegrations.OpsgenieTeams {
+		if err := sendOpsgenie(ctx, team, dedupKey, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, channel := range alertIntegrations.MicrosoftTeamsChannels {
+		if err := sendMicrosoftTeams(ctx, channel, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func isWorkspaceIntegratedWithDiscord(workspace model.Workspace) bool {
 	return workspace.DiscordGuildId != nil
 }
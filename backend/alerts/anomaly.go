@@ -0,0 +1,222 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// AnomalyBaselineWeeks is the history that the baseline of anomaly alerts is learned from.
+const AnomalyBaselineWeeks = 4
+
+// DefaultAnomalyDeviations is used by anomaly alerts that do not configure the number of standard deviations.
+const DefaultAnomalyDeviations = 3.
+
+// minBaselineSamples is the number of hourly counts needed for a seasonal slot to be used on its own.
+const minBaselineSamples = 3
+
+const anomalyTimeFormat = "2006-01-02T15:04:05.000Z"
+
+type BaselineStats struct {
+	Samples int
+	Mean    float64
+	StdDev  float64
+}
+
+// AlertBaseline is the seasonal baseline of hourly counts, by hour of the week and hour of the day in UTC.
+// Hours of the week without enough history fall back to the hour of the day, then to the overall statistics.
+type AlertBaseline struct {
+	HourOfWeek []BaselineStats
+	HourOfDay  []BaselineStats
+	Overall    BaselineStats
+}
+
+func NewAlertBaseline(times []time.Time, counts []float64) *AlertBaseline {
+	byHourOfWeek := make([][]float64, 7*24)
+	byHourOfDay := make([][]float64, 24)
+	for i, t := range times {
+		t = t.UTC()
+		byHourOfWeek[hourOfWeek(t)] = append(byHourOfWeek[hourOfWeek(t)], counts[i])
+		byHourOfDay[t.Hour()] = append(byHourOfDay[t.Hour()], counts[i])
+	}
+	return &AlertBaseline{
+		HourOfWeek: lo.Map(byHourOfWeek, func(c []float64, _ int) BaselineStats { return newBaselineStats(c) }),
+		HourOfDay:  lo.Map(byHourOfDay, func(c []float64, _ int) BaselineStats { return newBaselineStats(c) }),
+		Overall:    newBaselineStats(counts),
+	}
+}
+
+func newBaselineStats(counts []float64) BaselineStats {
+	stats := BaselineStats{Samples: len(counts)}
+	if len(counts) == 0 {
+		return stats
+	}
+	stats.Mean = lo.Sum(counts) / float64(len(counts))
+	var variance float64
+	for _, c := range counts {
+		variance += (c - stats.Mean) * (c - stats.Mean)
+	}
+	stats.StdDev = math.Sqrt(variance / float64(len(counts)))
+	return stats
+}
+
+func hourOfWeek(t time.Time) int {
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+func (b *AlertBaseline) stats(t time.Time) BaselineStats {
+	t = t.UTC()
+	if stats := b.HourOfWeek[hourOfWeek(t)]; stats.Samples >= minBaselineSamples {
+		return stats
+	}
+	if stats := b.HourOfDay[t.Hour()]; stats.Samples >= minBaselineSamples {
+		return stats
+	}
+	return b.Overall
+}
+
+// Band returns the expected count of the window starting at t and the bounds outside of which the count is anomalous.
+// Counts are assumed to vary at least as much as a Poisson process, so that a flat history does not make every change anomalous.
+func (b *AlertBaseline) Band(t time.Time, window time.Duration, deviations float64) (expected float64, lower float64, upper float64) {
+	if deviations <= 0 {
+		deviations = DefaultAnomalyDeviations
+	}
+	stats := b.stats(t)
+	scale := window.Hours()
+	expected = stats.Mean * scale
+	stdDev := math.Max(stats.StdDev*scale, math.Max(math.Sqrt(expected), 1))
+	return expected, math.Max(expected-deviations*stdDev, 0), expected + deviations*stdDev
+}
+
+// IsAnomaly returns whether the count of the window starting at t is above the band, or below it for below threshold alerts,
+// along with the crossed bound.
+func (b *AlertBaseline) IsAnomaly(value float64, t time.Time, window time.Duration, deviations float64, belowThreshold bool) (bool, float64) {
+	_, lower, upper := b.Band(t, window, deviations)
+	if belowThreshold {
+		return value < lower, lower
+	}
+	return value > upper, upper
+}
+
+// Buckets returns the hourly counts along with their expected values and bounds to chart the baseline.
+func (b *AlertBaseline) Buckets(times []time.Time, counts []float64, deviations float64) []*modelInputs.AlertBaselineBucket {
+	var buckets []*modelInputs.AlertBaselineBucket
+	for i, t := range times {
+		expected, lower, upper := b.Band(t, time.Hour, deviations)
+		buckets = append(buckets, &modelInputs.AlertBaselineBucket{
+			Timestamp:  t,
+			Value:      counts[i],
+			Expected:   expected,
+			LowerBound: lower,
+			UpperBound: upper,
+		})
+	}
+	return buckets
+}
+
+// SplitHistory splits hourly counts into those before t and those from t onwards.
+func SplitHistory(times []time.Time, counts []float64, t time.Time) ([]time.Time, []float64, []time.Time, []float64) {
+	i := 0
+	for i < len(times) && times[i].Before(t) {
+		i++
+	}
+	return times[:i], counts[:i], times[i:], counts[i:]
+}
+
+// ReadLogAlertHistory returns the hourly counts of the logs matching the query of a log alert.
+func ReadLogAlertHistory(ctx context.Context, ccClient *clickhouse.Client, alert *model.LogAlert, start time.Time, end time.Time) ([]time.Time, []float64, error) {
+	start, end = start.Truncate(time.Hour), end.Truncate(time.Hour)
+	nBuckets := int(end.Sub(start).Hours())
+	if nBuckets <= 0 {
+		return nil, nil, nil
+	}
+
+	histogram, err := ccClient.ReadLogsHistogram(ctx, alert.ProjectID, modelInputs.QueryInput{Query: alert.Query, DateRange: &modelInputs.DateRangeRequiredInput{
+		StartDate: start,
+		EndDate:   end,
+	}}, nBuckets)
+	if err != nil {
+		return nil, nil, e.Wrap(err, "error querying log histogram")
+	}
+
+	times := make([]time.Time, nBuckets)
+	counts := make([]float64, nBuckets)
+	for i := range times {
+		times[i] = start.Add(time.Duration(i) * time.Hour)
+	}
+	for _, bucket := range histogram.Buckets {
+		for _, count := range bucket.Counts {
+			counts[bucket.BucketID] += float64(count.Count)
+		}
+	}
+	return times, counts, nil
+}
+
+// ReadErrorAlertHistory returns the hourly counts of the errors of a project, or of one of its error groups.
+func ReadErrorAlertHistory(ctx context.Context, ccClient *clickhouse.Client, projectID int, errorGroupID *int, start time.Time, end time.Time) ([]time.Time, []float64, error) {
+	start, end = start.Truncate(time.Hour), end.Truncate(time.Hour)
+	if !end.After(start) {
+		return nil, nil, nil
+	}
+
+	rules := [][]string{
+		{"error-field_timestamp", "between_date", fmt.Sprintf("%s_%s", start.Format(anomalyTimeFormat), end.Format(anomalyTimeFormat))},
+	}
+	if errorGroupID != nil {
+		rules = append(rules, []string{"error-field_error_group_id", "is", strconv.Itoa(*errorGroupID)})
+	}
+
+	bucketTimes, totals, err := ccClient.QueryErrorHistogram(ctx, projectID, modelInputs.ClickhouseQuery{IsAnd: true, Rules: rules}, start, modelInputs.DateHistogramOptions{
+		BucketSize: &modelInputs.DateHistogramBucketSize{
+			CalendarInterval: modelInputs.OpenSearchCalendarIntervalHour,
+			Multiple:         1,
+		},
+		TimeZone: "UTC",
+		Bounds: &modelInputs.DateRangeInput{
+			StartDate: &start,
+			EndDate:   &end,
+		},
+	})
+	if err != nil {
+		return nil, nil, e.Wrap(err, "error querying error histogram")
+	}
+
+	return bucketTimes, lo.Map(totals, func(total int64, _ int) float64 { return float64(total) }), nil
+}
+
+// GetLogAlertBaseline returns the baseline of a log alert learned from the weeks before end.
+// The baseline is cached until the alert is edited or the hour ends.
+func GetLogAlertBaseline(ctx context.Context, ccClient *clickhouse.Client, redisClient *redis.Client, alert *model.LogAlert, end time.Time) (*AlertBaseline, error) {
+	end = end.Truncate(time.Hour)
+	key := fmt.Sprintf("log-alert-baseline-%d-%d-%d", alert.ID, alert.UpdatedAt.Unix(), end.Unix())
+	return redis.CachedEval(ctx, redisClient, key, time.Minute, time.Hour, func() (*AlertBaseline, error) {
+		times, counts, err := ReadLogAlertHistory(ctx, ccClient, alert, end.AddDate(0, 0, -7*AnomalyBaselineWeeks), end)
+		if err != nil {
+			return nil, err
+		}
+		return NewAlertBaseline(times, counts), nil
+	})
+}
+
+// GetErrorAlertBaseline returns the baseline of the errors of a project, or of one of its error groups,
+// learned from the weeks before end. The baseline is cached until the hour ends.
+func GetErrorAlertBaseline(ctx context.Context, ccClient *clickhouse.Client, redisClient *redis.Client, projectID int, errorGroupID *int, end time.Time) (*AlertBaseline, error) {
+	end = end.Truncate(time.Hour)
+	key := fmt.Sprintf("error-alert-baseline-%d-%d-%d", projectID, lo.FromPtr(errorGroupID), end.Unix())
+	return redis.CachedEval(ctx, redisClient, key, time.Minute, time.Hour, func() (*AlertBaseline, error) {
+		times, counts, err := ReadErrorAlertHistory(ctx, ccClient, projectID, errorGroupID, end.AddDate(0, 0, -7*AnomalyBaselineWeeks), end)
+		if err != nil {
+			return nil, err
+		}
+		return NewAlertBaseline(times, counts), nil
+	})
+}
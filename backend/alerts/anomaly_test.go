@@ -0,0 +1,96 @@
+package alerts
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func diurnalHistory(start time.Time, weeks int) ([]time.Time, []float64) {
+	var times []time.Time
+	var counts []float64
+	for i := 0; i < weeks*7*24; i++ {
+		t := start.Add(time.Duration(i) * time.Hour)
+		count := 10.
+		if t.Hour() >= 9 && t.Hour() < 17 {
+			count = 1000.
+		}
+		// alternate the daytime counts between weeks so the baseline has some variance
+		if i/(7*24)%2 == 0 && count > 10 {
+			count += 100
+		}
+		times = append(times, t)
+		counts = append(counts, count)
+	}
+	return times, counts
+}
+
+func TestAlertBaselineBand(t *testing.T) {
+	start := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	times, counts := diurnalHistory(start, AnomalyBaselineWeeks)
+	baseline := NewAlertBaseline(times, counts)
+
+	now := start.AddDate(0, 0, 7*AnomalyBaselineWeeks)
+	day := now.Add(12 * time.Hour)
+	night := now.Add(2 * time.Hour)
+
+	expected, lower, upper := baseline.Band(day, time.Hour, 3)
+	assert.Equal(t, 1050., expected)
+	assert.Equal(t, 900., lower)
+	assert.Equal(t, 1200., upper)
+
+	// a flat history is as noisy as a Poisson process
+	expected, lower, upper = baseline.Band(night, time.Hour, 3)
+	assert.Equal(t, 10., expected)
+	assert.InDelta(t, 10-3*math.Sqrt(10), lower, 1e-9)
+	assert.InDelta(t, 10+3*math.Sqrt(10), upper, 1e-9)
+
+	// windows shorter than an hour scale the baseline
+	expected, _, upper = baseline.Band(day, 15*time.Minute, 2)
+	assert.Equal(t, 262.5, expected)
+	assert.InDelta(t, 262.5+2*math.Sqrt(262.5), upper, 1e-9)
+
+	// a daytime count is anomalous at night but not during the day
+	anomaly, threshold := baseline.IsAnomaly(1000, night, time.Hour, 3, false)
+	assert.True(t, anomaly)
+	assert.InDelta(t, 10+3*math.Sqrt(10), threshold, 1e-9)
+	anomaly, _ = baseline.IsAnomaly(1000, day, time.Hour, 3, false)
+	assert.False(t, anomaly)
+	anomaly, threshold = baseline.IsAnomaly(10, day, time.Hour, 3, true)
+	assert.True(t, anomaly)
+	assert.Equal(t, 900., threshold)
+}
+
+func TestAlertBaselineFallback(t *testing.T) {
+	start := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	// a single day of history falls back to the overall statistics
+	times, counts := diurnalHistory(start, 1)
+	baseline := NewAlertBaseline(times[:24], counts[:24])
+	expected, _, _ := baseline.Band(start.AddDate(0, 0, 8).Add(12*time.Hour), time.Hour, 3)
+	assert.InDelta(t, (8*1100.+16*10.)/24, expected, 1e-9)
+
+	// without history, any count above a few occurrences is anomalous
+	baseline = NewAlertBaseline(nil, nil)
+	expected, lower, upper := baseline.Band(start, time.Hour, 0)
+	assert.Equal(t, 0., expected)
+	assert.Equal(t, 0., lower)
+	assert.Equal(t, DefaultAnomalyDeviations, upper)
+}
+
+func TestSplitHistory(t *testing.T) {
+	start := time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)
+	times, counts := diurnalHistory(start, 1)
+
+	beforeTimes, beforeCounts, afterTimes, afterCounts := SplitHistory(times, counts, start.Add(24*time.Hour))
+	assert.Len(t, beforeTimes, 24)
+	assert.Len(t, beforeCounts, 24)
+	assert.Len(t, afterTimes, 6*24)
+	assert.Len(t, afterCounts, 6*24)
+	assert.Equal(t, start.Add(24*time.Hour), afterTimes[0])
+
+	buckets := NewAlertBaseline(beforeTimes, beforeCounts).Buckets(afterTimes, afterCounts, 3)
+	assert.Len(t, buckets, 6*24)
+	assert.Equal(t, afterCounts[0], buckets[0].Value)
+}
@@ -0,0 +1,51 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	e "github.com/pkg/errors"
+)
+
+// Client is the HTTP client shared by the alert destinations, retrying failed requests.
+var Client = newClient()
+
+func newClient() *retryablehttp.Client {
+	client := retryablehttp.NewClient()
+	client.RetryMax = 3
+	client.HTTPClient.Timeout = 10 * time.Second
+	return client
+}
+
+// PostJSON sends the body as JSON to the API of an alert destination, returning an error for non 2xx responses.
+func PostJSON(ctx context.Context, url string, headers map[string]string, body interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return e.Wrap(err, "error marshalling alert request")
+	}
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, url, data)
+	if err != nil {
+		return e.Wrap(err, "error creating alert request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return e.New(fmt.Sprintf("alert request received unexpected response code %d: %s", resp.StatusCode, respBody))
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ func newMessageEmbed() *discordgo.MessageEmbed {
 
 var RED_ALERT = 0x961e13
 var YELLOW_ALERT = 0xf2c94c
+var GREEN_ALERT = 0x2eb886
 
 func (bot *Bot) SendErrorAlert(channelId string, payload integrations.ErrorAlertPayload) error {
 	fields := []*discordgo.MessageEmbedField{}
@@ -46,7 +47,10 @@ func (bot *Bot) SendErrorAlert(channelId string, payload integrations.ErrorAlert
 	})
 
 	embed := newMessageEmbed()
-	if payload.FirstTimeAlert {
+	if payload.Regression {
+		embed.Title = "Highlight Error Alert (Regression 🔁)"
+		embed.Color = RED_ALERT
+	} else if payload.FirstTimeAlert {
 		embed.Title = "Highlight Error Alert (New Occurence ❇️)"
 		embed.Color = YELLOW_ALERT
 	} else {
@@ -386,6 +390,11 @@ func (bot *Bot) SendMetricMonitorAlert(channelId string, payload integrations.Me
 	embed := newMessageEmbed()
 	embed.Title = "Highlight Metric Monitor Alert"
 	embed.Description = fmt.Sprintf("*%s* is currently %s %s over the threshold.", payload.MetricToMonitor, payload.DiffOverValue, payload.UnitsFormat)
+	if payload.Resolved {
+		embed.Title = "Highlight Metric Monitor Alert (Resolved)"
+		embed.Color = GREEN_ALERT
+		embed.Description = fmt.Sprintf("*%s* is no longer over the threshold.", payload.MetricToMonitor)
+	}
 	embed.Fields = fields
 
 	messageSend := discordgo.MessageSend{
@@ -443,6 +452,11 @@ func (bot *Bot) SendLogAlert(channelId string, payload integrations.LogAlertPayl
 	embed.Title = "Highlight Log Alert"
 	embed.Color = RED_ALERT
 	embed.Description = fmt.Sprintf("*%s* is currently %s the threshold.", payload.Name, aboveStr)
+	if payload.Resolved {
+		embed.Title = "Highlight Log Alert (Resolved)"
+		embed.Color = GREEN_ALERT
+		embed.Description = fmt.Sprintf("*%s* is no longer %s the threshold.", payload.Name, aboveStr)
+	}
 	embed.Fields = fields
 
 	messageSend := discordgo.MessageSend{
@@ -467,3 +481,73 @@ func (bot *Bot) SendLogAlert(channelId string, payload integrations.LogAlertPayl
 
 	return err
 }
+
+func (bot *Bot) SendTraceAlert(channelId string, payload integrations.TraceAlertPayload) error {
+	fields := []*discordgo.MessageEmbedField{}
+
+	if payload.Query != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Query",
+			Value:  payload.Query,
+			Inline: true,
+		})
+	}
+
+	if payload.GroupKey != "" {
+		fields = append(fields, &discordgo.MessageEmbedField{
+			Name:   "Group",
+			Value:  payload.GroupKey,
+			Inline: true,
+		})
+	}
+
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:   payload.MetricName,
+		Value:  payload.ValueStr,
+		Inline: true,
+	})
+
+	fields = append(fields, &discordgo.MessageEmbedField{
+		Name:   "Threshold",
+		Value:  payload.ThresholdStr,
+		Inline: true,
+	})
+
+	aboveStr := "above"
+	if payload.BelowThreshold {
+		aboveStr = "below"
+	}
+
+	embed := newMessageEmbed()
+	embed.Title = "Highlight Trace Alert"
+	embed.Color = RED_ALERT
+	embed.Description = fmt.Sprintf("*%s* is currently %s the threshold.", payload.Name, aboveStr)
+	if payload.Resolved {
+		embed.Title = "Highlight Trace Alert (Resolved)"
+		embed.Color = GREEN_ALERT
+		embed.Description = fmt.Sprintf("*%s* is no longer %s the threshold.", payload.Name, aboveStr)
+	}
+	embed.Fields = fields
+
+	messageSend := discordgo.MessageSend{
+		Embeds: []*discordgo.MessageEmbed{
+			embed,
+		},
+		Components: []discordgo.MessageComponent{
+			discordgo.ActionsRow{
+				Components: []discordgo.MessageComponent{
+					discordgo.Button{
+						Label:    "View Traces",
+						Style:    discordgo.LinkButton,
+						Disabled: false,
+						URL:      payload.AlertURL,
+					},
+				},
+			},
+		},
+	}
+
+	_, err := bot.Session.ChannelMessageSendComplex(channelId, &messageSend)
+
+	return err
+}
@@ -19,6 +19,9 @@ type ErrorAlertPayload struct {
 	UserIdentifier  string
 	VisitedURL      string
 	FirstTimeAlert  bool
+	Regression      bool
+	// Resolved is set when the error group is resolved, to resolve the incidents opened for it
+	Resolved bool
 }
 
 type NewUserAlertPayload struct {
@@ -72,6 +75,7 @@ type MetricMonitorAlertPayload struct {
 	Value           string
 	Threshold       string
 	MonitorURL      string
+	Resolved        bool
 }
 
 type LogAlertPayload struct {
@@ -83,6 +87,23 @@ type LogAlertPayload struct {
 	Threshold      int
 	BelowThreshold bool
 	AlertURL       string
+	Resolved       bool
+}
+
+type TraceAlertPayload struct {
+	Name           string
+	Query          string
+	GroupKey       string
+	MetricName     string
+	Value          float64
+	Threshold      float64
+	ValueStr       string
+	ThresholdStr   string
+	StartDate      time.Time
+	EndDate        time.Time
+	BelowThreshold bool
+	AlertURL       string
+	Resolved       bool
 }
 
 type BaseAlertIntegration interface {
@@ -96,4 +117,5 @@ type BaseAlertIntegration interface {
 	SendRageClicksAlert(channelId string, payload RageClicksAlertPayload) error
 	SendMetricMonitorAlert(channelId string, payload MetricMonitorAlertPayload) error
 	SendLogAlert(channelId string, payload MetricMonitorAlertPayload) error
+	SendTraceAlert(channelId string, payload TraceAlertPayload) error
 }
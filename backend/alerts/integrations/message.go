@@ -0,0 +1,241 @@
+package integrations
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityError    Severity = "error"
+	SeverityWarning  Severity = "warning"
+	SeverityInfo     Severity = "info"
+)
+
+type Link struct {
+	Text string
+	URL  string
+}
+
+// Message is the content of an alert for destinations that render every alert type the same way,
+// such as incident management tools and chat cards.
+type Message struct {
+	Title    string
+	Summary  string
+	Severity Severity
+	Fields   []Property
+	Links    []Link
+	// Resolved is set when notifying that the condition of an alert cleared
+	Resolved bool
+}
+
+// Truncate shortens s to at most length characters, ending it with an ellipsis when it is cut.
+// Characters are counted as runes so that multi-byte UTF-8 characters are never split.
+func Truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	return string(runes[:length-3]) + "..."
+}
+
+func (m *Message) addField(key string, value string) {
+	if value == "" {
+		return
+	}
+	m.Fields = append(m.Fields, Property{Key: key, Value: value})
+}
+
+func (m *Message) addLink(text string, url string) {
+	if url == "" {
+		return
+	}
+	m.Links = append(m.Links, Link{Text: text, URL: url})
+}
+
+func (m *Message) addProperties(properties map[string]string) {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		m.addField(key, properties[key])
+	}
+}
+
+func userOrAnonymous(userIdentifier string) string {
+	if userIdentifier == "" {
+		return "An anonymous user"
+	}
+	return userIdentifier
+}
+
+func NewErrorAlertMessage(payload ErrorAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight Error Alert",
+		Summary:  payload.ErrorTitle,
+		Severity: SeverityError,
+		Resolved: payload.Resolved,
+	}
+	if payload.Resolved {
+		message.Title = "Highlight Error Alert (Resolved)"
+	} else if payload.Regression {
+		message.Title = "Highlight Error Alert (Regression)"
+		message.Severity = SeverityCritical
+	} else if payload.FirstTimeAlert {
+		message.Title = "Highlight Error Alert (New Occurrence)"
+	}
+	message.addField("Error count", strconv.FormatInt(payload.ErrorCount, 10))
+	message.addField("User", payload.UserIdentifier)
+	message.addField("Visited URL", payload.VisitedURL)
+	message.addLink("View Error", payload.ErrorURL)
+	if payload.SessionSecureID != "" && !payload.SessionExcluded {
+		message.addLink("View Session", payload.SessionURL)
+	}
+	message.addLink("Resolve Error", payload.ErrorResolveURL)
+	message.addLink("Ignore Error", payload.ErrorIgnoreURL)
+	message.addLink("Snooze Error", payload.ErrorSnoozeURL)
+	return message
+}
+
+func NewNewUserAlertMessage(payload NewUserAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight New User Alert",
+		Summary:  fmt.Sprintf("%s started their first session.", userOrAnonymous(payload.UserIdentifier)),
+		Severity: SeverityInfo,
+	}
+	message.addProperties(payload.UserProperties)
+	message.addLink("View Session", payload.SessionURL)
+	return message
+}
+
+func NewNewSessionAlertMessage(payload NewSessionAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight New Session Alert",
+		Summary:  fmt.Sprintf("%s started a new session.", userOrAnonymous(payload.UserIdentifier)),
+		Severity: SeverityInfo,
+	}
+	if payload.VisitedURL != nil {
+		message.addField("Visited URL", *payload.VisitedURL)
+	}
+	message.addProperties(payload.UserProperties)
+	message.addLink("View Session", payload.SessionURL)
+	return message
+}
+
+func NewTrackPropertiesAlertMessage(payload TrackPropertiesAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight Track Properties Alert",
+		Summary:  fmt.Sprintf("%s tracked matching properties.", userOrAnonymous(payload.UserIdentifier)),
+		Severity: SeverityInfo,
+	}
+	for _, property := range payload.MatchedProperties {
+		message.addField(property.Key, property.Value)
+	}
+	for _, property := range payload.RelatedProperties {
+		message.addField(property.Key, property.Value)
+	}
+	return message
+}
+
+func NewUserPropertiesAlertMessage(payload UserPropertiesAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight User Properties Alert",
+		Summary:  fmt.Sprintf("%s was identified with matching properties.", userOrAnonymous(payload.UserIdentifier)),
+		Severity: SeverityInfo,
+	}
+	for _, property := range payload.MatchedProperties {
+		message.addField(property.Key, property.Value)
+	}
+	message.addLink("View Session", payload.SessionURL)
+	return message
+}
+
+func NewErrorFeedbackAlertMessage(payload ErrorFeedbackAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight Error Feedback Alert",
+		Summary:  fmt.Sprintf("%s left feedback on an error.", userOrAnonymous(payload.UserIdentifier)),
+		Severity: SeverityInfo,
+	}
+	message.addField("Comment", payload.CommentText)
+	message.addLink("View Comment", payload.SessionCommentURL)
+	return message
+}
+
+func NewRageClicksAlertMessage(payload RageClicksAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight Rage Clicks Alert",
+		Summary:  fmt.Sprintf("%s rage clicked %d times.", userOrAnonymous(payload.UserIdentifier), payload.RageClicksCount),
+		Severity: SeverityWarning,
+	}
+	message.addField("User", payload.UserIdentifier)
+	message.addField("Rage click count", strconv.FormatInt(payload.RageClicksCount, 10))
+	message.addLink("View Session", payload.SessionURL)
+	return message
+}
+
+func NewMetricMonitorAlertMessage(payload MetricMonitorAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight Metric Monitor Alert",
+		Summary:  fmt.Sprintf("%s is currently %s %s over the threshold.", payload.MetricToMonitor, payload.DiffOverValue, payload.UnitsFormat),
+		Severity: SeverityError,
+		Resolved: payload.Resolved,
+	}
+	if payload.Resolved {
+		message.Title = "Highlight Metric Monitor Alert (Resolved)"
+		message.Summary = fmt.Sprintf("%s is no longer over the threshold.", payload.MetricToMonitor)
+	}
+	message.addField("Value", strings.TrimSpace(fmt.Sprintf("%s %s", payload.Value, payload.UnitsFormat)))
+	message.addField("Threshold", strings.TrimSpace(fmt.Sprintf("%s %s", payload.Threshold, payload.UnitsFormat)))
+	message.addLink("View Monitor", payload.MonitorURL)
+	return message
+}
+
+func aboveOrBelow(belowThreshold bool) string {
+	if belowThreshold {
+		return "below"
+	}
+	return "above"
+}
+
+func NewLogAlertMessage(payload LogAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight Log Alert",
+		Summary:  fmt.Sprintf("%s is currently %s the threshold.", payload.Name, aboveOrBelow(payload.BelowThreshold)),
+		Severity: SeverityError,
+		Resolved: payload.Resolved,
+	}
+	if payload.Resolved {
+		message.Title = "Highlight Log Alert (Resolved)"
+		message.Summary = fmt.Sprintf("%s is no longer %s the threshold.", payload.Name, aboveOrBelow(payload.BelowThreshold))
+	}
+	message.addField("Query", payload.Query)
+	message.addField("Count", strconv.Itoa(payload.Count))
+	message.addField("Threshold", strconv.Itoa(payload.Threshold))
+	message.addLink("View Logs", payload.AlertURL)
+	return message
+}
+
+func NewTraceAlertMessage(payload TraceAlertPayload) Message {
+	message := Message{
+		Title:    "Highlight Trace Alert",
+		Summary:  fmt.Sprintf("%s is currently %s the threshold.", payload.Name, aboveOrBelow(payload.BelowThreshold)),
+		Severity: SeverityError,
+		Resolved: payload.Resolved,
+	}
+	if payload.Resolved {
+		message.Title = "Highlight Trace Alert (Resolved)"
+		message.Summary = fmt.Sprintf("%s is no longer %s the threshold.", payload.Name, aboveOrBelow(payload.BelowThreshold))
+	}
+	message.addField("Query", payload.Query)
+	message.addField("Group", payload.GroupKey)
+	message.addField(payload.MetricName, payload.ValueStr)
+	message.addField("Threshold", payload.ThresholdStr)
+	message.addLink("View Traces", payload.AlertURL)
+	return message
+}
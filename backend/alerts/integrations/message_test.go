@@ -0,0 +1,20 @@
+package integrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", Truncate("short", 10))
+	assert.Equal(t, "abcdefg...", Truncate("abcdefghijklmnop", 10))
+	assert.Equal(t, "ééééééé...", Truncate("éééééééééééé", 10))
+	assert.Equal(t, "日本語", Truncate("日本語", 3))
+}
+
+func TestNewErrorAlertMessageResolved(t *testing.T) {
+	message := NewErrorAlertMessage(ErrorAlertPayload{ErrorTitle: "boom", Regression: true, Resolved: true})
+	assert.True(t, message.Resolved)
+	assert.Equal(t, "Highlight Error Alert (Resolved)", message.Title)
+}
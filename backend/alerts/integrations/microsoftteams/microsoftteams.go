@@ -0,0 +1,146 @@
+package microsoftteams
+
+import (
+	"context"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	e "github.com/pkg/errors"
+)
+
+var colors = map[integrations.Severity]string{
+	integrations.SeverityCritical: "Attention",
+	integrations.SeverityError:    "Attention",
+	integrations.SeverityWarning:  "Warning",
+	integrations.SeverityInfo:     "Accent",
+}
+
+type Fact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+type Element struct {
+	Type   string `json:"type"`
+	Text   string `json:"text,omitempty"`
+	Size   string `json:"size,omitempty"`
+	Weight string `json:"weight,omitempty"`
+	Color  string `json:"color,omitempty"`
+	Wrap   bool   `json:"wrap,omitempty"`
+	Facts  []Fact `json:"facts,omitempty"`
+}
+
+type Action struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+type AdaptiveCard struct {
+	Schema  string    `json:"$schema"`
+	Type    string    `json:"type"`
+	Version string    `json:"version"`
+	Body    []Element `json:"body"`
+	Actions []Action  `json:"actions,omitempty"`
+}
+
+type Attachment struct {
+	ContentType string        `json:"contentType"`
+	Content     *AdaptiveCard `json:"content"`
+}
+
+// Message is the body of incoming webhooks posting adaptive cards.
+type Message struct {
+	Type        string       `json:"type"`
+	Attachments []Attachment `json:"attachments"`
+}
+
+// NewMessage returns the message posting the adaptive card of an alert.
+func NewMessage(message integrations.Message) *Message {
+	color := colors[message.Severity]
+	if message.Resolved {
+		color = "Good"
+	}
+
+	body := []Element{{
+		Type:   "TextBlock",
+		Text:   message.Title,
+		Size:   "Medium",
+		Weight: "Bolder",
+		Color:  color,
+		Wrap:   true,
+	}}
+	if message.Summary != "" {
+		body = append(body, Element{Type: "TextBlock", Text: message.Summary, Wrap: true})
+	}
+	if len(message.Fields) > 0 {
+		var facts []Fact
+		for _, field := range message.Fields {
+			facts = append(facts, Fact{Title: field.Key, Value: field.Value})
+		}
+		body = append(body, Element{Type: "FactSet", Facts: facts})
+	}
+
+	var actions []Action
+	for _, link := range message.Links {
+		actions = append(actions, Action{Type: "Action.OpenUrl", Title: link.Text, URL: link.URL})
+	}
+
+	return &Message{
+		Type: "message",
+		Attachments: []Attachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: &AdaptiveCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body:    body,
+				Actions: actions,
+			},
+		}},
+	}
+}
+
+func sendMessage(ctx context.Context, channel *model.MicrosoftTeamsChannel, message integrations.Message) error {
+	return e.Wrapf(integrations.PostJSON(ctx, channel.WebhookURL, nil, NewMessage(message)), "error sending microsoft teams message to %s", channel.Name)
+}
+
+func SendErrorAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.ErrorAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewErrorAlertMessage(payload))
+}
+
+func SendNewUserAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.NewUserAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewNewUserAlertMessage(payload))
+}
+
+func SendNewSessionAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.NewSessionAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewNewSessionAlertMessage(payload))
+}
+
+func SendTrackPropertiesAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.TrackPropertiesAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewTrackPropertiesAlertMessage(payload))
+}
+
+func SendUserPropertiesAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.UserPropertiesAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewUserPropertiesAlertMessage(payload))
+}
+
+func SendErrorFeedbackAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.ErrorFeedbackAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewErrorFeedbackAlertMessage(payload))
+}
+
+func SendRageClicksAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.RageClicksAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewRageClicksAlertMessage(payload))
+}
+
+func SendMetricMonitorAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.MetricMonitorAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewMetricMonitorAlertMessage(payload))
+}
+
+func SendLogAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.LogAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewLogAlertMessage(payload))
+}
+
+func SendTraceAlert(ctx context.Context, channel *model.MicrosoftTeamsChannel, payload integrations.TraceAlertPayload) error {
+	return sendMessage(ctx, channel, integrations.NewTraceAlertMessage(payload))
+}
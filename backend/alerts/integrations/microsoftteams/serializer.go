@@ -0,0 +1,23 @@
+package microsoftteams
+
+import (
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+)
+
+// GQLInputToGo returns nil when the channels are not provided so that updates keep the existing channels.
+func GQLInputToGo(channels []*modelInputs.MicrosoftTeamsChannelInput) []*model.MicrosoftTeamsChannel {
+	if channels == nil {
+		return nil
+	}
+	ret := []*model.MicrosoftTeamsChannel{}
+	for _, channel := range channels {
+		ret = append(ret, &model.MicrosoftTeamsChannel{
+			Name:       channel.Name,
+			WebhookURL: lo.FromPtr(channel.WebhookURL),
+		})
+	}
+
+	return ret
+}
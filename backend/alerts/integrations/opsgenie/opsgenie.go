@@ -0,0 +1,130 @@
+package opsgenie
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	e "github.com/pkg/errors"
+)
+
+// APIURLs are the endpoints of the Opsgenie instances by region.
+var APIURLs = map[string]string{
+	"us": "https://api.opsgenie.com",
+	"eu": "https://api.eu.opsgenie.com",
+}
+
+// limits of the Alerts API
+const (
+	maxMessageLength     = 130
+	maxAliasLength       = 512
+	maxDescriptionLength = 15000
+)
+
+var priorities = map[integrations.Severity]string{
+	integrations.SeverityCritical: "P1",
+	integrations.SeverityError:    "P2",
+	integrations.SeverityWarning:  "P3",
+	integrations.SeverityInfo:     "P4",
+}
+
+type Alert struct {
+	Message     string            `json:"message"`
+	Alias       string            `json:"alias"`
+	Description string            `json:"description,omitempty"`
+	Details     map[string]string `json:"details,omitempty"`
+	Priority    string            `json:"priority,omitempty"`
+	Source      string            `json:"source"`
+	Tags        []string          `json:"tags,omitempty"`
+}
+
+type CloseAlert struct {
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+func apiURL(team *model.OpsgenieTeam) string {
+	if u, ok := APIURLs[strings.ToLower(team.Region)]; ok {
+		return u
+	}
+	return APIURLs["us"]
+}
+
+// NewAlert returns the alert created in Opsgenie. Alerts of the same Highlight alert share the alias
+// so that Opsgenie deduplicates them and so that they can be closed when resolved.
+func NewAlert(alias string, message integrations.Message) *Alert {
+	description := []string{message.Summary}
+	for _, link := range message.Links {
+		description = append(description, fmt.Sprintf("%s: %s", link.Text, link.URL))
+	}
+
+	details := map[string]string{}
+	for _, field := range message.Fields {
+		details[field.Key] = field.Value
+	}
+
+	return &Alert{
+		Message:     integrations.Truncate(fmt.Sprintf("%s: %s", message.Title, message.Summary), maxMessageLength),
+		Alias:       integrations.Truncate(alias, maxAliasLength),
+		Description: integrations.Truncate(strings.Join(description, "\n"), maxDescriptionLength),
+		Details:     details,
+		Priority:    priorities[message.Severity],
+		Source:      "Highlight",
+		Tags:        []string{"highlight"},
+	}
+}
+
+func sendMessage(ctx context.Context, team *model.OpsgenieTeam, alias string, message integrations.Message) error {
+	headers := map[string]string{"Authorization": "GenieKey " + team.APIKey}
+	if message.Resolved {
+		closeURL := fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", apiURL(team), url.PathEscape(integrations.Truncate(alias, maxAliasLength)))
+		return e.Wrapf(integrations.PostJSON(ctx, closeURL, headers, &CloseAlert{
+			Source: "Highlight",
+			Note:   message.Summary,
+		}), "error closing opsgenie alert for %s", team.Name)
+	}
+	return e.Wrapf(integrations.PostJSON(ctx, apiURL(team)+"/v2/alerts", headers, NewAlert(alias, message)), "error creating opsgenie alert for %s", team.Name)
+}
+
+func SendErrorAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.ErrorAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewErrorAlertMessage(payload))
+}
+
+func SendNewUserAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.NewUserAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewNewUserAlertMessage(payload))
+}
+
+func SendNewSessionAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.NewSessionAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewNewSessionAlertMessage(payload))
+}
+
+func SendTrackPropertiesAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.TrackPropertiesAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewTrackPropertiesAlertMessage(payload))
+}
+
+func SendUserPropertiesAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.UserPropertiesAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewUserPropertiesAlertMessage(payload))
+}
+
+func SendErrorFeedbackAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.ErrorFeedbackAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewErrorFeedbackAlertMessage(payload))
+}
+
+func SendRageClicksAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.RageClicksAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewRageClicksAlertMessage(payload))
+}
+
+func SendMetricMonitorAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.MetricMonitorAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewMetricMonitorAlertMessage(payload))
+}
+
+func SendLogAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.LogAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewLogAlertMessage(payload))
+}
+
+func SendTraceAlert(ctx context.Context, team *model.OpsgenieTeam, alias string, payload integrations.TraceAlertPayload) error {
+	return sendMessage(ctx, team, alias, integrations.NewTraceAlertMessage(payload))
+}
@@ -0,0 +1,45 @@
+package opsgenie
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewAlert(t *testing.T) {
+	alert := NewAlert("highlight-ERROR-1-error-group-2", integrations.NewErrorAlertMessage(integrations.ErrorAlertPayload{
+		ErrorTitle:     strings.Repeat("a", 200),
+		ErrorCount:     3,
+		UserIdentifier: "user@example.com",
+		ErrorURL:       "https://app.highlight.io/1/errors/abc",
+		Regression:     true,
+	}))
+	assert.Len(t, alert.Message, maxMessageLength)
+	assert.True(t, strings.HasPrefix(alert.Message, "Highlight Error Alert (Regression): aaa"))
+	assert.Equal(t, "highlight-ERROR-1-error-group-2", alert.Alias)
+	assert.Equal(t, "P1", alert.Priority)
+	assert.Equal(t, map[string]string{"Error count": "3", "User": "user@example.com"}, alert.Details)
+	assert.True(t, strings.HasSuffix(alert.Description, "\nView Error: https://app.highlight.io/1/errors/abc"))
+}
+
+func TestSendLogAlert(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "GenieKey api-key", r.Header.Get("Authorization"))
+		paths = append(paths, r.URL.RequestURI())
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	APIURLs["eu"] = server.URL
+
+	team := &model.OpsgenieTeam{Name: "backend", APIKey: "api-key", Region: "eu"}
+	assert.NoError(t, SendLogAlert(context.Background(), team, "highlight-LOG-1", integrations.LogAlertPayload{Name: "Errors"}))
+	assert.NoError(t, SendLogAlert(context.Background(), team, "highlight-LOG-1", integrations.LogAlertPayload{Name: "Errors", Resolved: true}))
+	assert.Equal(t, []string{"/v2/alerts", "/v2/alerts/highlight-LOG-1/close?identifierType=alias"}, paths)
+}
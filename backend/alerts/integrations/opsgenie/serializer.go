@@ -0,0 +1,24 @@
+package opsgenie
+
+import (
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+)
+
+// GQLInputToGo returns nil when the teams are not provided so that updates keep the existing teams.
+func GQLInputToGo(teams []*modelInputs.OpsgenieTeamInput) []*model.OpsgenieTeam {
+	if teams == nil {
+		return nil
+	}
+	ret := []*model.OpsgenieTeam{}
+	for _, team := range teams {
+		ret = append(ret, &model.OpsgenieTeam{
+			Name:   team.Name,
+			APIKey: lo.FromPtr(team.APIKey),
+			Region: lo.FromPtr(team.Region),
+		})
+	}
+
+	return ret
+}
@@ -0,0 +1,134 @@
+package pagerduty
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	e "github.com/pkg/errors"
+)
+
+// EventsURL is the endpoint of the PagerDuty Events API v2.
+var EventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+const (
+	EventActionTrigger = "trigger"
+	EventActionResolve = "resolve"
+)
+
+// maxSummaryLength is the longest summary accepted by the Events API v2.
+const maxSummaryLength = 1024
+
+type Link struct {
+	Href string `json:"href"`
+	Text string `json:"text,omitempty"`
+}
+
+type Payload struct {
+	Summary       string            `json:"summary"`
+	Source        string            `json:"source"`
+	Severity      string            `json:"severity"`
+	Class         string            `json:"class,omitempty"`
+	CustomDetails map[string]string `json:"custom_details,omitempty"`
+}
+
+type Event struct {
+	RoutingKey  string   `json:"routing_key"`
+	EventAction string   `json:"event_action"`
+	DedupKey    string   `json:"dedup_key"`
+	Payload     *Payload `json:"payload,omitempty"`
+	Links       []Link   `json:"links,omitempty"`
+	Client      string   `json:"client,omitempty"`
+	ClientURL   string   `json:"client_url,omitempty"`
+}
+
+// NewEvent returns the event triggering the incident of an alert, or resolving it for resolved alerts.
+// Events of the same alert share the dedup key so that they update the same incident.
+func NewEvent(service *model.PagerDutyService, dedupKey string, message integrations.Message) *Event {
+	if message.Resolved {
+		return &Event{
+			RoutingKey:  service.RoutingKey,
+			EventAction: EventActionResolve,
+			DedupKey:    dedupKey,
+		}
+	}
+
+	summary := message.Title
+	if message.Summary != "" {
+		summary = fmt.Sprintf("%s: %s", message.Title, message.Summary)
+	}
+	summary = integrations.Truncate(summary, maxSummaryLength)
+
+	details := map[string]string{}
+	for _, field := range message.Fields {
+		details[field.Key] = field.Value
+	}
+
+	var links []Link
+	for _, link := range message.Links {
+		links = append(links, Link{Href: link.URL, Text: link.Text})
+	}
+
+	return &Event{
+		RoutingKey:  service.RoutingKey,
+		EventAction: EventActionTrigger,
+		DedupKey:    dedupKey,
+		Payload: &Payload{
+			Summary:       summary,
+			Source:        "Highlight",
+			Severity:      string(message.Severity),
+			Class:         strings.TrimPrefix(message.Title, "Highlight "),
+			CustomDetails: details,
+		},
+		Links:     links,
+		Client:    "Highlight",
+		ClientURL: os.Getenv("FRONTEND_URI"),
+	}
+}
+
+func sendMessage(ctx context.Context, service *model.PagerDutyService, dedupKey string, message integrations.Message) error {
+	return e.Wrapf(integrations.PostJSON(ctx, EventsURL, nil, NewEvent(service, dedupKey, message)), "error sending pagerduty event to %s", service.Name)
+}
+
+func SendErrorAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.ErrorAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewErrorAlertMessage(payload))
+}
+
+func SendNewUserAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.NewUserAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewNewUserAlertMessage(payload))
+}
+
+func SendNewSessionAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.NewSessionAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewNewSessionAlertMessage(payload))
+}
+
+func SendTrackPropertiesAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.TrackPropertiesAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewTrackPropertiesAlertMessage(payload))
+}
+
+func SendUserPropertiesAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.UserPropertiesAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewUserPropertiesAlertMessage(payload))
+}
+
+func SendErrorFeedbackAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.ErrorFeedbackAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewErrorFeedbackAlertMessage(payload))
+}
+
+func SendRageClicksAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.RageClicksAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewRageClicksAlertMessage(payload))
+}
+
+func SendMetricMonitorAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.MetricMonitorAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewMetricMonitorAlertMessage(payload))
+}
+
+func SendLogAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.LogAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewLogAlertMessage(payload))
+}
+
+func SendTraceAlert(ctx context.Context, service *model.PagerDutyService, dedupKey string, payload integrations.TraceAlertPayload) error {
+	return sendMessage(ctx, service, dedupKey, integrations.NewTraceAlertMessage(payload))
+}
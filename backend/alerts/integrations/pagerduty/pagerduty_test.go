@@ -0,0 +1,58 @@
+package pagerduty
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewEvent(t *testing.T) {
+	service := &model.PagerDutyService{Name: "backend", RoutingKey: "routing-key"}
+
+	event := NewEvent(service, "highlight-LOG-1", integrations.NewLogAlertMessage(integrations.LogAlertPayload{
+		Name:      "Errors",
+		Query:     "level:error",
+		Count:     12,
+		Threshold: 10,
+		AlertURL:  "https://app.highlight.io/1/logs",
+	}))
+	assert.Equal(t, EventActionTrigger, event.EventAction)
+	assert.Equal(t, "routing-key", event.RoutingKey)
+	assert.Equal(t, "highlight-LOG-1", event.DedupKey)
+	assert.Equal(t, "Highlight Log Alert: Errors is currently above the threshold.", event.Payload.Summary)
+	assert.Equal(t, "Log Alert", event.Payload.Class)
+	assert.Equal(t, "error", event.Payload.Severity)
+	assert.Equal(t, map[string]string{"Query": "level:error", "Count": "12", "Threshold": "10"}, event.Payload.CustomDetails)
+	assert.Equal(t, []Link{{Href: "https://app.highlight.io/1/logs", Text: "View Logs"}}, event.Links)
+
+	// resolving an incident only needs its dedup key
+	event = NewEvent(service, "highlight-LOG-1", integrations.NewLogAlertMessage(integrations.LogAlertPayload{Name: "Errors", Resolved: true}))
+	assert.Equal(t, &Event{RoutingKey: "routing-key", EventAction: EventActionResolve, DedupKey: "highlight-LOG-1"}, event)
+}
+
+func TestSendMetricMonitorAlert(t *testing.T) {
+	var events []Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	EventsURL = server.URL
+
+	service := &model.PagerDutyService{Name: "backend", RoutingKey: "routing-key"}
+	assert.NoError(t, SendMetricMonitorAlert(context.Background(), service, "highlight-METRIC_MONITOR-1", integrations.MetricMonitorAlertPayload{MetricToMonitor: "LCP"}))
+	assert.NoError(t, SendMetricMonitorAlert(context.Background(), service, "highlight-METRIC_MONITOR-1", integrations.MetricMonitorAlertPayload{MetricToMonitor: "LCP", Resolved: true}))
+
+	assert.Len(t, events, 2)
+	assert.Equal(t, EventActionTrigger, events[0].EventAction)
+	assert.Equal(t, EventActionResolve, events[1].EventAction)
+	assert.Equal(t, events[0].DedupKey, events[1].DedupKey)
+}
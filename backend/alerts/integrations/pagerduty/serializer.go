@@ -0,0 +1,23 @@
+package pagerduty
+
+import (
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+)
+
+// GQLInputToGo returns nil when the services are not provided so that updates keep the existing services.
+func GQLInputToGo(services []*modelInputs.PagerDutyServiceInput) []*model.PagerDutyService {
+	if services == nil {
+		return nil
+	}
+	ret := []*model.PagerDutyService{}
+	for _, service := range services {
+		ret = append(ret, &model.PagerDutyService{
+			Name:       service.Name,
+			RoutingKey: lo.FromPtr(service.RoutingKey),
+		})
+	}
+
+	return ret
+}
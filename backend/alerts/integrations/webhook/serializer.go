@@ -3,14 +3,44 @@ package webhook
 import (
 	"github.com/highlight-run/highlight/backend/model"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
 )
 
+// ValidateInput returns an error when the body template of a webhook cannot be parsed,
+// so that it is reported when the alert is saved rather than when it is sent.
+func ValidateInput(webhooks []*modelInputs.WebhookDestinationInput) error {
+	for _, wh := range webhooks {
+		if wh.BodyTemplate == nil || *wh.BodyTemplate == "" {
+			continue
+		}
+		if _, err := parseBodyTemplate(*wh.BodyTemplate); err != nil {
+			return e.Wrapf(err, "invalid body template for webhook %s", wh.URL)
+		}
+	}
+	return nil
+}
+
 func GQLInputToGo(webhooks []*modelInputs.WebhookDestinationInput) []*model.WebhookDestination {
 	var ret []*model.WebhookDestination
 	for _, wh := range webhooks {
 		ret = append(ret, &model.WebhookDestination{
 			URL:           wh.URL,
 			Authorization: wh.Authorization,
+			Secret:        wh.Secret,
+			Headers:       gqlHeadersToGo(wh.Headers),
+			BodyTemplate:  wh.BodyTemplate,
+		})
+	}
+
+	return ret
+}
+
+func gqlHeadersToGo(headers []*modelInputs.WebhookHeaderInput) []*model.WebhookHeader {
+	var ret []*model.WebhookHeader
+	for _, header := range headers {
+		ret = append(ret, &model.WebhookHeader{
+			Name:  header.Name,
+			Value: header.Value,
 		})
 	}
 
@@ -1,166 +1,288 @@
 package webhook
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"text/template"
+	"time"
 
 	"github.com/hashicorp/go-retryablehttp"
 	"github.com/highlight-run/highlight/backend/alerts/integrations"
 	"github.com/highlight-run/highlight/backend/model"
 	e "github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
+const (
+	// TimestampHeader is the unix time in seconds at which the webhook was signed.
+	TimestampHeader = "X-Highlight-Timestamp"
+	// SignatureHeader is the HMAC-SHA256 of the timestamp and body of the webhook, see Sign.
+	SignatureHeader = "X-Highlight-Signature"
+	// TestEvent is the event of the webhooks sent to test a destination.
+	TestEvent = "TEST_ALERT"
+)
+
+var templateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
 type ErrorAlertWebhook struct {
 	Event string
 	*integrations.ErrorAlertPayload
 }
 
-func sendWebhookData(destination *model.WebhookDestination, body []byte) error {
-	resp, err := retryablehttp.Post(destination.URL, "application/json", body)
+// Sign returns the signature of a webhook body sent at timestamp, which receivers can verify
+// by computing the hex encoded HMAC-SHA256 of "<timestamp>.<body>" with the secret of the destination.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// RenderBody returns the JSON of the payload, or the body template of the destination rendered from the payload.
+func RenderBody(destination *model.WebhookDestination, payload interface{}) ([]byte, error) {
+	if destination.BodyTemplate == nil || *destination.BodyTemplate == "" {
+		return json.Marshal(payload)
+	}
+	tmpl, err := parseBodyTemplate(*destination.BodyTemplate)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, payload); err != nil {
+		return nil, e.Wrap(err, "error rendering webhook body template")
 	}
+	return body.Bytes(), nil
+}
 
-	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
-		logFields := log.Fields{}
-		if err := json.Unmarshal(body, &logFields); err == nil {
-			ctx := context.TODO()
-			logFields["Destination"] = destination
-			logFields["StatusCode"] = resp.StatusCode
-			log.WithContext(ctx).WithFields(logFields).Info("webhook sent successfully")
+func parseBodyTemplate(bodyTemplate string) (*template.Template, error) {
+	tmpl, err := template.New("body").Funcs(templateFuncs).Option("missingkey=error").Parse(bodyTemplate)
+	if err != nil {
+		return nil, e.Wrap(err, "error parsing webhook body template")
+	}
+	return tmpl, nil
+}
+
+// NewRequest returns the request sending the body to the destination, along with its authorization, custom headers and signature.
+func NewRequest(ctx context.Context, destination *model.WebhookDestination, body []byte, now time.Time) (*retryablehttp.Request, error) {
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, destination.URL, body)
+	if err != nil {
+		return nil, e.Wrap(err, "error creating webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if destination.Authorization != nil && *destination.Authorization != "" {
+		req.Header.Set("Authorization", *destination.Authorization)
+	}
+	for _, header := range destination.Headers {
+		if header == nil || header.Name == "" {
+			continue
 		}
+		req.Header.Set(header.Name, header.Value)
+	}
+	timestamp := now.Unix()
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	if destination.Secret != nil && *destination.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(*destination.Secret, timestamp, body))
+	}
+	return req, nil
+}
 
+func postWebhookData(ctx context.Context, destination *model.WebhookDestination, payload interface{}, delivery *model.WebhookDelivery) error {
+	body, err := RenderBody(destination, payload)
+	if err != nil {
+		return err
+	}
+	req, err := NewRequest(ctx, destination, body, time.Now())
+	if err != nil {
+		return err
+	}
+	resp, err := integrations.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	delivery.StatusCode = resp.StatusCode
+
+	if resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		log.WithContext(ctx).WithFields(log.Fields{
+			"URL":        destination.URL,
+			"Event":      delivery.Event,
+			"StatusCode": resp.StatusCode,
+		}).Info("webhook sent successfully")
 		return nil
 	}
 
-	return e.New(fmt.Sprintf("webhook %+v received unexpected response code %d", destination, resp.StatusCode))
+	return e.New(fmt.Sprintf("webhook %s received unexpected response code %d", destination.URL, resp.StatusCode))
+}
+
+// sendWebhookData sends the payload of an event to the destination and records the delivery when db is set.
+func sendWebhookData(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, event string, payload interface{}) (*model.WebhookDelivery, error) {
+	delivery := &model.WebhookDelivery{
+		ProjectID: projectID,
+		URL:       destination.URL,
+		Event:     event,
+	}
+	start := time.Now()
+	err := postWebhookData(ctx, destination, payload, delivery)
+	delivery.DurationMs = int(time.Since(start).Milliseconds())
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	if db != nil {
+		if dbErr := db.WithContext(ctx).Create(delivery).Error; dbErr != nil {
+			log.WithContext(ctx).WithError(dbErr).WithField("project_id", projectID).Error("error recording webhook delivery")
+		}
+	}
+	return delivery, err
 }
 
-func SendErrorAlert(destination *model.WebhookDestination, payload *integrations.ErrorAlertPayload) error {
-	body, err := json.Marshal(&struct {
+// SendTestAlert sends a sample log alert to the destination so that its receiver can be checked.
+func SendTestAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination) (*model.WebhookDelivery, error) {
+	now := time.Now()
+	payload := &integrations.LogAlertPayload{
+		Name:      "Test alert",
+		Query:     "level=error",
+		Count:     10,
+		StartDate: now.Add(-5 * time.Minute),
+		EndDate:   now,
+		Threshold: 5,
+		AlertURL:  model.GetLogAlertURL(projectID, "level=error", now.Add(-5*time.Minute), now),
+	}
+	return sendWebhookData(ctx, db, projectID, destination, TestEvent, &struct {
+		Event string
+		*integrations.LogAlertPayload
+	}{
+		Event:           TestEvent,
+		LogAlertPayload: payload,
+	})
+}
+
+func SendErrorAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.ErrorAlertPayload) error {
+	event := model.AlertType.ERROR
+	if payload.Regression {
+		event = model.AlertType.ERROR_REGRESSION
+	}
+	_, err := sendWebhookData(ctx, db, projectID, destination, event, &struct {
 		Event string
 		*integrations.ErrorAlertPayload
 	}{
-		Event:             model.AlertType.ERROR,
+		Event:             event,
 		ErrorAlertPayload: payload,
 	})
-	if err != nil {
-		return err
-	}
-	return sendWebhookData(destination, body)
+	return err
 }
 
-func SendNewUserAlert(destination *model.WebhookDestination, payload *integrations.NewUserAlertPayload) error {
-	body, err := json.Marshal(&struct {
+func SendNewUserAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.NewUserAlertPayload) error {
+	_, err := sendWebhookData(ctx, db, projectID, destination, model.AlertType.NEW_USER, &struct {
 		Event string
 		*integrations.NewUserAlertPayload
 	}{
 		Event:               model.AlertType.NEW_USER,
 		NewUserAlertPayload: payload,
 	})
-	if err != nil {
-		return err
-	}
-	return sendWebhookData(destination, body)
+	return err
 }
 
-func SendNewSessionAlert(destination *model.WebhookDestination, payload *integrations.NewSessionAlertPayload) error {
-	body, err := json.Marshal(&struct {
+func SendNewSessionAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.NewSessionAlertPayload) error {
+	_, err := sendWebhookData(ctx, db, projectID, destination, model.AlertType.NEW_USER, &struct {
 		Event string
 		*integrations.NewSessionAlertPayload
 	}{
 		Event:                  model.AlertType.NEW_USER,
 		NewSessionAlertPayload: payload,
 	})
-	if err != nil {
-		return err
-	}
-	return sendWebhookData(destination, body)
+	return err
 }
 
-func SendTrackPropertiesAlert(destination *model.WebhookDestination, payload *integrations.TrackPropertiesAlertPayload) error {
-	body, err := json.Marshal(&struct {
+func SendTrackPropertiesAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.TrackPropertiesAlertPayload) error {
+	_, err := sendWebhookData(ctx, db, projectID, destination, model.AlertType.NEW_USER, &struct {
 		Event string
 		*integrations.TrackPropertiesAlertPayload
 	}{
 		Event:                       model.AlertType.NEW_USER,
 		TrackPropertiesAlertPayload: payload,
 	})
-	if err != nil {
-		return err
-	}
-	return sendWebhookData(destination, body)
+	return err
 }
 
-func SendUserPropertiesAlert(destination *model.WebhookDestination, payload *integrations.UserPropertiesAlertPayload) error {
-	body, err := json.Marshal(&struct {
+func SendUserPropertiesAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.UserPropertiesAlertPayload) error {
+	_, err := sendWebhookData(ctx, db, projectID, destination, model.AlertType.NEW_USER, &struct {
 		Event string
 		*integrations.UserPropertiesAlertPayload
 	}{
 		Event:                      model.AlertType.NEW_USER,
 		UserPropertiesAlertPayload: payload,
 	})
-	if err != nil {
-		return err
-	}
-	return sendWebhookData(destination, body)
+	return err
 }
 
-func SendErrorFeedbackAlert(destination *model.WebhookDestination, payload *integrations.ErrorFeedbackAlertPayload) error {
-	body, err := json.Marshal(&struct {
+func SendErrorFeedbackAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.ErrorFeedbackAlertPayload) error {
+	_, err := sendWebhookData(ctx, db, projectID, destination, model.AlertType.ERROR_FEEDBACK, &struct {
 		Event string
 		*integrations.ErrorFeedbackAlertPayload
 	}{
 		Event:                     model.AlertType.ERROR_FEEDBACK,
 		ErrorFeedbackAlertPayload: payload,
 	})
-	if err != nil {
-		return err
-	}
-	return sendWebhookData(destination, body)
+	return err
 }
 
-func SendRageClicksAlert(destination *model.WebhookDestination, payload *integrations.RageClicksAlertPayload) error {
-	body, err := json.Marshal(&struct {
+func SendRageClicksAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.RageClicksAlertPayload) error {
+	_, err := sendWebhookData(ctx, db, projectID, destination, model.AlertType.NEW_USER, &struct {
 		Event string
 		*integrations.RageClicksAlertPayload
 	}{
 		Event:                  model.AlertType.NEW_USER,
 		RageClicksAlertPayload: payload,
 	})
-	if err != nil {
-		return err
-	}
-	return sendWebhookData(destination, body)
+	return err
 }
 
-func SendMetricMonitorAlert(destination *model.WebhookDestination, payload *integrations.MetricMonitorAlertPayload) error {
-	body, err := json.Marshal(&struct {
+func SendMetricMonitorAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.MetricMonitorAlertPayload) error {
+	_, err := sendWebhookData(ctx, db, projectID, destination, model.AlertType.NEW_USER, &struct {
 		Event string
 		*integrations.MetricMonitorAlertPayload
 	}{
 		Event:                     model.AlertType.NEW_USER,
 		MetricMonitorAlertPayload: payload,
 	})
-	if err != nil {
-		return err
-	}
-	return sendWebhookData(destination, body)
+	return err
 }
 
-func SendLogAlert(destination *model.WebhookDestination, payload *integrations.LogAlertPayload) error {
-	body, err := json.Marshal(&struct {
+func SendLogAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.LogAlertPayload) error {
+	_, err := sendWebhookData(ctx, db, projectID, destination, model.AlertType.LOG, &struct {
 		Event string
 		*integrations.LogAlertPayload
 	}{
 		Event:           model.AlertType.LOG,
 		LogAlertPayload: payload,
 	})
-	if err != nil {
-		return err
-	}
-	return sendWebhookData(destination, body)
+	return err
+}
+
+func SendTraceAlert(ctx context.Context, db *gorm.DB, projectID int, destination *model.WebhookDestination, payload *integrations.TraceAlertPayload) error {
+	_, err := sendWebhookData(ctx, db, projectID, destination, model.AlertType.TRACE, &struct {
+		Event string
+		*integrations.TraceAlertPayload
+	}{
+		Event:             model.AlertType.TRACE,
+		TraceAlertPayload: payload,
+	})
+	return err
 }
@@ -0,0 +1,118 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/alerts/integrations"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSign(t *testing.T) {
+	// computed with `printf '1700000000.{"Event":"LOG_ALERT"}' | openssl dgst -sha256 -hmac secret`
+	assert.Equal(t, "sha256=713b21108e0bfed24aaab82730a0ec0e850fb4856b3f7a50b3ad1fbaba6ad71b", Sign("secret", 1700000000, []byte(`{"Event":"LOG_ALERT"}`)))
+	assert.NotEqual(t, Sign("secret", 1700000000, []byte("body")), Sign("secret", 1700000001, []byte("body")))
+	assert.NotEqual(t, Sign("secret", 1700000000, []byte("body")), Sign("other", 1700000000, []byte("body")))
+}
+
+func TestRenderBody(t *testing.T) {
+	payload := &struct {
+		Event string
+		*integrations.LogAlertPayload
+	}{
+		Event:           "LOG_ALERT",
+		LogAlertPayload: &integrations.LogAlertPayload{Name: `"quoted" alert`, Count: 12, Threshold: 10},
+	}
+
+	body, err := RenderBody(&model.WebhookDestination{}, payload)
+	assert.NoError(t, err)
+	assert.Contains(t, string(body), `"Event":"LOG_ALERT"`)
+	assert.Contains(t, string(body), `"Count":12`)
+
+	body, err = RenderBody(&model.WebhookDestination{
+		BodyTemplate: lo.ToPtr(`{"text": {{ json .Name }}, "count": {{ .Count }}, "event": "{{ .Event }}"}`),
+	}, payload)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"text": "\"quoted\" alert", "count": 12, "event": "LOG_ALERT"}`, string(body))
+
+	_, err = RenderBody(&model.WebhookDestination{BodyTemplate: lo.ToPtr(`{{ .Missing }}`)}, payload)
+	assert.Error(t, err)
+	_, err = RenderBody(&model.WebhookDestination{BodyTemplate: lo.ToPtr(`{{ .Name `)}, payload)
+	assert.Error(t, err)
+}
+
+func TestValidateInput(t *testing.T) {
+	assert.NoError(t, ValidateInput([]*modelInputs.WebhookDestinationInput{
+		{URL: "https://example.com/hook"},
+		{URL: "https://example.com/other", BodyTemplate: lo.ToPtr(`{"text": {{ json .Name }}}`)},
+	}))
+	err := ValidateInput([]*modelInputs.WebhookDestinationInput{{URL: "https://example.com/hook", BodyTemplate: lo.ToPtr(`{{ .Name `)}})
+	assert.ErrorContains(t, err, "invalid body template for webhook https://example.com/hook")
+}
+
+func TestNewRequest(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	body := []byte(`{"Event":"LOG_ALERT"}`)
+
+	req, err := NewRequest(context.Background(), &model.WebhookDestination{
+		URL:           "https://example.com/hook",
+		Authorization: lo.ToPtr("Bearer token"),
+		Secret:        lo.ToPtr("secret"),
+		Headers: []*model.WebhookHeader{
+			{Name: "X-Team", Value: "platform"},
+			{Name: "Content-Type", Value: "application/vnd.api+json"},
+		},
+	}, body, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://example.com/hook", req.URL.String())
+	assert.Equal(t, "Bearer token", req.Header.Get("Authorization"))
+	assert.Equal(t, "platform", req.Header.Get("X-Team"))
+	assert.Equal(t, "application/vnd.api+json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "1700000000", req.Header.Get(TimestampHeader))
+	assert.Equal(t, Sign("secret", 1700000000, body), req.Header.Get(SignatureHeader))
+
+	sent, err := io.ReadAll(req.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, body, sent)
+
+	// requests to destinations without a secret are not signed
+	req, err = NewRequest(context.Background(), &model.WebhookDestination{URL: "https://example.com/hook"}, body, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Empty(t, req.Header.Get("Authorization"))
+	assert.Empty(t, req.Header.Get(SignatureHeader))
+	assert.Equal(t, "1700000000", req.Header.Get(TimestampHeader))
+}
+
+func TestSendWebhookData(t *testing.T) {
+	var verified bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		verified = r.Header.Get(SignatureHeader) == Sign("secret", timestamp, body)
+		if r.URL.Path == "/reject" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	delivery, err := SendTestAlert(context.Background(), nil, 1, &model.WebhookDestination{URL: server.URL, Secret: lo.ToPtr("secret")})
+	assert.NoError(t, err)
+	assert.True(t, verified)
+	assert.Equal(t, http.StatusOK, delivery.StatusCode)
+	assert.Equal(t, TestEvent, delivery.Event)
+	assert.Empty(t, delivery.Error)
+
+	delivery, err = SendTestAlert(context.Background(), nil, 1, &model.WebhookDestination{URL: server.URL + "/reject", Secret: lo.ToPtr("secret")})
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, delivery.StatusCode)
+	assert.Equal(t, err.Error(), delivery.Error)
+}
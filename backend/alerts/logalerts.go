@@ -2,10 +2,14 @@ package alerts
 
 import (
 	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/microsoftteams"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/opsgenie"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/pagerduty"
 	"github.com/highlight-run/highlight/backend/alerts/integrations/webhook"
 	"github.com/highlight-run/highlight/backend/model"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"github.com/openlyinc/pointy"
+	"github.com/samber/lo"
 )
 
 func BuildLogAlert(project *model.Project, workspace *model.Workspace, admin *model.Admin, input modelInputs.LogAlertInput) (*model.LogAlert, error) {
@@ -24,6 +28,10 @@ func BuildLogAlert(project *model.Project, workspace *model.Workspace, admin *mo
 		return nil, err
 	}
 
+	if err := webhook.ValidateInput(input.WebhookDestinations); err != nil {
+		return nil, err
+	}
+
 	defaultArg := input.Default
 	if defaultArg == nil {
 		defaultArg = pointy.Bool(true)
@@ -47,9 +55,20 @@ func BuildLogAlert(project *model.Project, workspace *model.Workspace, admin *mo
 		},
 		BelowThreshold: input.BelowThreshold,
 		Query:          input.Query,
+		AnomalyThresholdSettings: model.AnomalyThresholdSettings{
+			ThresholdType:     lo.FromPtr(input.ThresholdType),
+			AnomalyDeviations: lo.FromPtr(input.AnomalyDeviations),
+		},
+		AlertEvaluationSettings: model.AlertEvaluationSettings{
+			PendingEvaluations: lo.FromPtr(input.PendingEvaluations),
+			RenotifyInterval:   lo.FromPtr(input.RenotifyInterval),
+		},
 		AlertIntegrations: model.AlertIntegrations{
 			DiscordChannelsToNotify: discord.GQLInputToGo(input.DiscordChannels),
 			WebhookDestinations:     webhook.GQLInputToGo(input.WebhookDestinations),
+			PagerDutyServices:       pagerduty.GQLInputToGo(input.PagerDutyServices),
+			OpsgenieTeams:           opsgenie.GQLInputToGo(input.OpsgenieTeams),
+			MicrosoftTeamsChannels:  microsoftteams.GQLInputToGo(input.MicrosoftTeamsChannels),
 		},
 	}, nil
 }
@@ -9,6 +9,9 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/microsoftteams"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/opsgenie"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/pagerduty"
 	"github.com/highlight-run/highlight/backend/model"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 )
@@ -65,6 +68,10 @@ func BuildSessionAlert(project *model.Project, workspace *model.Workspace, admin
 		return nil, err
 	}
 
+	if err := webhook.ValidateInput(input.WebhookDestinations); err != nil {
+		return nil, err
+	}
+
 	userPropertiesBytes, err := json.Marshal(input.UserProperties)
 	if err != nil {
 		return nil, errors.Wrap(err, "error parsing user properties for user properties alert")
@@ -110,6 +117,9 @@ func BuildSessionAlert(project *model.Project, workspace *model.Workspace, admin
 		AlertIntegrations: model.AlertIntegrations{
 			DiscordChannelsToNotify: discord.GQLInputToGo(input.DiscordChannels),
 			WebhookDestinations:     webhook.GQLInputToGo(input.WebhookDestinations),
+			PagerDutyServices:       pagerduty.GQLInputToGo(input.PagerDutyServices),
+			OpsgenieTeams:           opsgenie.GQLInputToGo(input.OpsgenieTeams),
+			MicrosoftTeamsChannels:  microsoftteams.GQLInputToGo(input.MicrosoftTeamsChannels),
 		},
 	}, nil
 }
@@ -0,0 +1,62 @@
+package alerts
+
+import (
+	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/microsoftteams"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/opsgenie"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/pagerduty"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/webhook"
+	"github.com/highlight-run/highlight/backend/model"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/lib/pq"
+	"github.com/openlyinc/pointy"
+	"github.com/samber/lo"
+)
+
+func BuildTraceAlert(project *model.Project, workspace *model.Workspace, admin *model.Admin, input modelInputs.TraceAlertInput) (*model.TraceAlert, error) {
+	channelsString, err := marshalSlackChannelsToSanitizedSlackChannels(input.SlackChannels)
+	if err != nil {
+		return nil, err
+	}
+
+	emailsString, err := marshalAlertEmails(input.Emails)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := webhook.ValidateInput(input.WebhookDestinations); err != nil {
+		return nil, err
+	}
+
+	return &model.TraceAlert{
+		Alert: model.Alert{
+			ProjectID:         input.ProjectID,
+			OrganizationID:    input.ProjectID,
+			ThresholdWindow:   &input.ThresholdWindow,
+			Type:              pointy.String("TraceAlert"),
+			ChannelsToNotify:  channelsString,
+			EmailsToNotify:    emailsString,
+			Name:              input.Name,
+			LastAdminToEditID: admin.ID,
+			Disabled:          &input.Disabled,
+			Frequency:         input.ThresholdWindow,
+		},
+		Query:          input.Query,
+		Column:         input.Column,
+		Aggregator:     input.Aggregator,
+		Threshold:      input.Threshold,
+		BelowThreshold: input.BelowThreshold,
+		GroupBy:        pq.StringArray(input.GroupBy),
+		AlertEvaluationSettings: model.AlertEvaluationSettings{
+			PendingEvaluations: lo.FromPtr(input.PendingEvaluations),
+			RenotifyInterval:   lo.FromPtr(input.RenotifyInterval),
+		},
+		AlertIntegrations: model.AlertIntegrations{
+			DiscordChannelsToNotify: discord.GQLInputToGo(input.DiscordChannels),
+			WebhookDestinations:     webhook.GQLInputToGo(input.WebhookDestinations),
+			PagerDutyServices:       pagerduty.GQLInputToGo(input.PagerDutyServices),
+			OpsgenieTeams:           opsgenie.GQLInputToGo(input.OpsgenieTeams),
+			MicrosoftTeamsChannels:  microsoftteams.GQLInputToGo(input.MicrosoftTeamsChannels),
+		},
+	}, nil
+}
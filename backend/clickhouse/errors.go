@@ -578,6 +578,6 @@ var errorObjectsTableConfig = tableConfig[modelInputs.ReservedErrorObjectKey]{
 	reservedKeys: modelInputs.AllReservedErrorObjectKey,
 }
 
-func ErrorMatchesQuery(errorObject *model2.BackendErrorObjectInput, filters *queryparser.Filters) bool {
-	return matchesQuery(errorObject, errorObjectsTableConfig, filters)
+func ErrorMatchesQuery(errorObject *model2.BackendErrorObjectInput, query queryparser.Expr) bool {
+	return matchesQuery(errorObject, errorObjectsTableConfig, query)
 }
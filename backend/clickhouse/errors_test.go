@@ -2,7 +2,6 @@ package clickhouse
 
 import (
 	modelInputs "github.com/highlight-run/highlight/backend/public-graph/graph/model"
-	"github.com/highlight-run/highlight/backend/queryparser"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -10,8 +9,8 @@ import (
 
 func Test_ErrorMatchesQuery(t *testing.T) {
 	errorObject := modelInputs.BackendErrorObjectInput{}
-	filters := queryparser.Parse("oops something bad type:console.error os.type:linux resource_name:worker.* service_name:all")
-	matches := ErrorMatchesQuery(&errorObject, &filters)
+	filters := mustParseQuery(t, "oops something bad type:console.error os.type:linux resource_name:worker.* service_name:all")
+	matches := ErrorMatchesQuery(&errorObject, filters)
 	assert.False(t, matches)
 
 	errorObject = modelInputs.BackendErrorObjectInput{
@@ -26,6 +25,6 @@ func Test_ErrorMatchesQuery(t *testing.T) {
 			Version: "asdf",
 		},
 	}
-	matches = ErrorMatchesQuery(&errorObject, &filters)
+	matches = ErrorMatchesQuery(&errorObject, filters)
 	assert.True(t, matches)
 }
@@ -431,6 +431,6 @@ func (client *Client) LogsKeyValues(ctx context.Context, projectID int, keyName
 	return KeyValuesAggregated(ctx, client, LogKeyValuesTable, projectID, keyName, startDate, endDate)
 }
 
-func LogMatchesQuery(logRow *LogRow, filters *queryparser.Filters) bool {
-	return matchesQuery(logRow, logsTableConfig, filters)
+func LogMatchesQuery(logRow *LogRow, query queryparser.Expr) bool {
+	return matchesQuery(logRow, logsTableConfig, query)
 }
@@ -50,6 +50,12 @@ func makeDateWithinRange(now time.Time) *modelInputs.DateRangeRequiredInput {
 	}
 }
 
+func mustParseQuery(tb testing.TB, query string) queryparser.Expr {
+	expr, err := queryparser.ParseQuery(query)
+	assert.NoError(tb, err)
+	return expr
+}
+
 func assertCursorsOutput(t *testing.T, edges []*modelInputs.LogEdge, expectedCursor string) {
 	allCursorsUnique := make(map[string]struct{})
 	for _, edge := range edges {
@@ -1235,8 +1241,8 @@ func FuzzReadLogs(f *testing.F) {
 
 func Test_LogMatchesQuery(t *testing.T) {
 	logRow := LogRow{}
-	filters := queryparser.Parse("hello world os.type:linux resource_name:worker.* service_name:all")
-	matches := LogMatchesQuery(&logRow, &filters)
+	filters := mustParseQuery(t, "hello world os.type:linux resource_name:worker.* service_name:all")
+	matches := LogMatchesQuery(&logRow, filters)
 	assert.False(t, matches)
 
 	logRow = LogRow{
@@ -1247,12 +1253,12 @@ func Test_LogMatchesQuery(t *testing.T) {
 			"resource_name": "worker.kafka.process",
 		},
 	}
-	filters = queryparser.Parse("hello world be me os.type:linux resource_name:worker.* service_name:all")
-	matches = LogMatchesQuery(&logRow, &filters)
+	filters = mustParseQuery(t, "hello world be me os.type:linux resource_name:worker.* service_name:all")
+	matches = LogMatchesQuery(&logRow, filters)
 	assert.True(t, matches)
 
-	filters = queryparser.Parse("not this one os.type:linux resource_name:worker.* service_name:all")
-	matches = LogMatchesQuery(&logRow, &filters)
+	filters = mustParseQuery(t, "not this one os.type:linux resource_name:worker.* service_name:all")
+	matches = LogMatchesQuery(&logRow, filters)
 	assert.False(t, matches)
 }
 
@@ -1268,14 +1274,16 @@ func Test_LogMatchesQuery_Body(t *testing.T) {
 		"*\\x80",
 	} {
 		logRow := LogRow{Body: body}
-		filters := queryparser.Parse(body)
 		// : represents a special case since our query parser treats it as an attribute. don't search on attrs
-		filters.Attributes = map[string][]string{}
-		matches := LogMatchesQuery(&logRow, &filters)
+		filters := queryparser.And(lo.Filter(queryparser.Conjuncts(mustParseQuery(t, body)), func(expr queryparser.Expr, _ int) bool {
+			term, ok := expr.(*queryparser.TermExpr)
+			return !ok || term.Key == ""
+		})...)
+		matches := LogMatchesQuery(&logRow, filters)
 		assert.True(t, matches, "failed on body %s", body)
 
-		filters = queryparser.Parse("no")
-		matches = LogMatchesQuery(&logRow, &filters)
+		filters = mustParseQuery(t, "no")
+		matches = LogMatchesQuery(&logRow, filters)
 		assert.False(t, matches, "failed on body %s", body)
 	}
 }
@@ -1313,9 +1321,9 @@ func Test_LogMatchesQuery_ClickHouse(t *testing.T) {
 	assert.NoError(t, err)
 
 	var filtered []*LogRow
-	filters := queryparser.Parse(query)
+	filters := mustParseQuery(t, query)
 	for _, logRow := range rows {
-		if LogMatchesQuery(logRow, &filters) {
+		if LogMatchesQuery(logRow, filters) {
 			filtered = append(filtered, logRow)
 		}
 	}
@@ -1360,8 +1368,8 @@ func Test_LogMatchesQuery_ClickHouse_Body(t *testing.T) {
 		assert.NoError(t, err)
 
 		var filtered []*LogRow
-		filters := queryparser.Parse(body)
-		if LogMatchesQuery(logRow, &filters) {
+		filters := mustParseQuery(t, body)
+		if LogMatchesQuery(logRow, filters) {
 			filtered = append(filtered, logRow)
 		}
 
@@ -0,0 +1,102 @@
+package clickhouse
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MetricSpanName is the span name of trace rows that carry a metric data point in their first event,
+// as read by `GetMetricTimeline`, `ReadTracesMetrics`, metric monitors and the trace_metrics view.
+const MetricSpanName = "highlight-metric"
+
+type MetricRow struct {
+	Timestamp       time.Time
+	UUID            string
+	ProjectId       uint32
+	SecureSessionId string
+	ServiceName     string
+	ServiceVersion  string
+	MetricName      string
+	MetricType      string
+	MetricUnit      string
+	// Temporality is the aggregation temporality of sums and histograms, either `Cumulative` or `Delta`
+	Temporality string
+	IsMonotonic bool
+	Value       float64
+	Attributes  map[string]string
+}
+
+func NewMetricRow(timestamp time.Time, projectID int, name string, value float64) *MetricRow {
+	return &MetricRow{
+		Timestamp:  timestamp,
+		UUID:       uuid.New().String(),
+		ProjectId:  uint32(projectID),
+		MetricName: name,
+		Value:      value,
+		Attributes: map[string]string{},
+	}
+}
+
+func (m *MetricRow) WithSecureSessionId(sessionId string) *MetricRow {
+	m.SecureSessionId = sessionId
+	return m
+}
+
+func (m *MetricRow) WithServiceName(serviceName string) *MetricRow {
+	m.ServiceName = serviceName
+	return m
+}
+
+func (m *MetricRow) WithServiceVersion(version string) *MetricRow {
+	m.ServiceVersion = version
+	return m
+}
+
+func (m *MetricRow) WithMetricType(metricType string) *MetricRow {
+	m.MetricType = metricType
+	return m
+}
+
+func (m *MetricRow) WithMetricUnit(unit string) *MetricRow {
+	m.MetricUnit = unit
+	return m
+}
+
+func (m *MetricRow) WithTemporality(temporality string, isMonotonic bool) *MetricRow {
+	m.Temporality = temporality
+	m.IsMonotonic = isMonotonic
+	return m
+}
+
+func (m *MetricRow) WithAttributes(attributes map[string]string) *MetricRow {
+	m.Attributes = attributes
+	return m
+}
+
+// TraceRow returns the data point as a MetricSpanName trace row. Metrics are written to the traces table
+// alongside the metrics table, as the metric readers still query the traces table.
+func (m *MetricRow) TraceRow() *TraceRow {
+	attributes := make(map[string]string, len(m.Attributes)+2)
+	for k, v := range m.Attributes {
+		attributes[k] = v
+	}
+	if m.MetricType != "" {
+		attributes["metric.type"] = m.MetricType
+	}
+	if m.MetricUnit != "" {
+		attributes["metric.unit"] = m.MetricUnit
+	}
+	return NewTraceRow(m.Timestamp, int(m.ProjectId)).
+		WithSecureSessionId(m.SecureSessionId).
+		WithTraceId(uuid.New().String()).
+		WithSpanName(MetricSpanName).
+		WithServiceName(m.ServiceName).
+		WithServiceVersion(m.ServiceVersion).
+		WithTraceAttributes(attributes).
+		WithEvents([]map[string]any{{
+			"Name":       "metric",
+			"Timestamp":  m.Timestamp,
+			"Attributes": map[string]any{"metric.name": m.MetricName, "metric.value": m.Value},
+		}})
+}
@@ -0,0 +1,53 @@
+package clickhouse
+
+import (
+	"context"
+	"fmt"
+
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/queryparser"
+	e "github.com/pkg/errors"
+)
+
+const MetricsTable = "metrics"
+
+var metricKeysToColumns = map[modelInputs.ReservedTraceKey]string{
+	modelInputs.ReservedTraceKeySecureSessionID: "SecureSessionId",
+	modelInputs.ReservedTraceKeyServiceName:     "ServiceName",
+	modelInputs.ReservedTraceKeyServiceVersion:  "ServiceVersion",
+	modelInputs.ReservedTraceKeyMetric:          "MetricName",
+}
+
+// metricsTableConfig matches metric rows with the trace keys that apply to them,
+// as metrics are filtered by the trace exclusion query of a project.
+var metricsTableConfig = tableConfig[modelInputs.ReservedTraceKey]{
+	tableName:        MetricsTable,
+	keysToColumns:    metricKeysToColumns,
+	reservedKeys:     []modelInputs.ReservedTraceKey{modelInputs.ReservedTraceKeySecureSessionID, modelInputs.ReservedTraceKeyServiceName, modelInputs.ReservedTraceKeyServiceVersion, modelInputs.ReservedTraceKeyMetric},
+	bodyColumn:       "MetricName",
+	attributesColumn: "Attributes",
+}
+
+func (client *Client) BatchWriteMetricRows(ctx context.Context, metricRows []*MetricRow) error {
+	if len(metricRows) == 0 {
+		return nil
+	}
+
+	batch, err := client.conn.PrepareBatch(ctx, fmt.Sprintf("INSERT INTO %s", MetricsTable))
+	if err != nil {
+		return e.Wrap(err, "failed to create metrics batch")
+	}
+
+	for _, metricRow := range metricRows {
+		err = batch.AppendStruct(metricRow)
+		if err != nil {
+			return err
+		}
+	}
+
+	return batch.Send()
+}
+
+func MetricMatchesQuery(metricRow *MetricRow, query queryparser.Expr) bool {
+	return matchesQuery(metricRow, metricsTableConfig, query)
+}
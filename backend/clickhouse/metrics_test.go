@@ -0,0 +1,43 @@
+package clickhouse
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_MetricMatchesQuery(t *testing.T) {
+	metricRow := NewMetricRow(time.Now(), 1, "http.server.duration.bucket", 3).
+		WithServiceName("api").
+		WithAttributes(map[string]string{"le": "+Inf", "http.route": "/health"})
+
+	assert.True(t, MetricMatchesQuery(metricRow, mustParseQuery(t, "metric:http.server.duration.bucket service_name:api")))
+	assert.True(t, MetricMatchesQuery(metricRow, mustParseQuery(t, "http.route:/health")))
+	assert.True(t, MetricMatchesQuery(metricRow, mustParseQuery(t, "metric:http.server.*")))
+	assert.False(t, MetricMatchesQuery(metricRow, mustParseQuery(t, "service_name:web")))
+	assert.False(t, MetricMatchesQuery(metricRow, mustParseQuery(t, "http.route:/users")))
+}
+
+func Test_MetricRowTraceRow(t *testing.T) {
+	timestamp := time.Now()
+	metricRow := NewMetricRow(timestamp, 1, "http.server.duration.bucket", 3).
+		WithSecureSessionId("session").
+		WithServiceName("api").
+		WithMetricType("Histogram").
+		WithAttributes(map[string]string{"le": "+Inf"})
+
+	traceRow := metricRow.TraceRow()
+	assert.Equal(t, MetricSpanName, traceRow.SpanName)
+	assert.Equal(t, uint32(1), traceRow.ProjectId)
+	assert.Equal(t, "session", traceRow.SecureSessionId)
+	assert.Equal(t, "api", traceRow.ServiceName)
+	assert.NotEmpty(t, traceRow.TraceId)
+	assert.Equal(t, map[string]string{"le": "+Inf", "metric.type": "Histogram"}, traceRow.TraceAttributes)
+	assert.Len(t, traceRow.Events, 1)
+	assert.Equal(t, timestamp, traceRow.Events[0].Timestamp)
+	assert.Equal(t, map[string]string{"metric.name": "http.server.duration.bucket", "metric.value": "3"}, traceRow.Events[0].Attributes)
+
+	// the attributes of the metric row are not modified
+	assert.Equal(t, map[string]string{"le": "+Inf"}, metricRow.Attributes)
+}
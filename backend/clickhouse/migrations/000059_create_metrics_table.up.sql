@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS metrics (
+    Timestamp DateTime64(9),
+    UUID UUID,
+    ProjectId UInt32,
+    SecureSessionId String,
+    ServiceName LowCardinality(String),
+    ServiceVersion String,
+    MetricName LowCardinality(String),
+    MetricType LowCardinality(String),
+    MetricUnit LowCardinality(String),
+    Temporality LowCardinality(String),
+    IsMonotonic Bool,
+    Value Float64,
+    Attributes Map(LowCardinality(String), String),
+    INDEX idx_attr_key mapKeys(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1,
+    INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1
+) ENGINE = MergeTree PARTITION BY toDate(Timestamp)
+ORDER BY (ProjectId, MetricName, Timestamp, UUID) TTL toDateTime(Timestamp) + toIntervalDay(30) SETTINGS index_granularity = 8192,
+    ttl_only_drop_parts = 1,
+    min_age_to_force_merge_seconds = 3600;
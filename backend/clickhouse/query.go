@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -158,7 +160,11 @@ func readObjects[TObj interface{}, TReservedKey ~string](ctx context.Context, cl
 
 func makeSelectBuilder[T ~string](config tableConfig[T], selectStr string,
 	groupBy []string, projectID int, params modelInputs.QueryInput, pagination Pagination, orderBackward string, orderForward string) (*sqlbuilder.SelectBuilder, error) {
-	filters := makeFilters(params.Query, lo.Keys(config.keysToColumns), config.defaultFilters)
+	query, err := parseQuery(config, params.Query)
+	if err != nil {
+		return nil, err
+	}
+
 	sb := sqlbuilder.NewSelectBuilder()
 	cols := []string{selectStr}
 	for _, group := range groupBy {
@@ -171,22 +177,22 @@ func makeSelectBuilder[T ~string](config tableConfig[T], selectStr string,
 	// sql-builder doesn't support PREWHERE natively so we use `SQL` which sets a marker
 	// of where to place the raw SQL later when it is being built.
 	// In this case, we are placing the marker after the `FROM` clause
+	// Body tokens that every row must contain are searched with the token index.
 	preWheres := []string{}
-	bodyQuery := ""
-	for _, body := range filters.body {
-		if strings.Contains(body, "%") {
-			bodyQuery = config.bodyColumn + " ILIKE " + sb.Var(body)
+	wheres := []string{}
+	for _, expr := range queryparser.Conjuncts(query) {
+		if term, ok := expr.(*queryparser.TermExpr); ok && term.Key == "" && !term.Quoted && !term.HasWildcard() {
+			for _, token := range queryparser.SplitBody(term.Value) {
+				preWheres = append(preWheres, "hasTokenCaseInsensitive("+config.bodyColumn+", "+sb.Var(token)+")")
+			}
 		} else {
-			preWheres = append(preWheres, "hasTokenCaseInsensitive("+config.bodyColumn+", "+sb.Var(body)+")")
+			wheres = append(wheres, makeCondition(sb, config, expr))
 		}
 	}
 
 	if len(preWheres) > 0 {
 		sb.SQL("PREWHERE " + strings.Join(preWheres, " AND "))
 	}
-	if bodyQuery != "" {
-		sb.Where(bodyQuery)
-	}
 
 	sb.Where(sb.Equal("ProjectId", projectID))
 
@@ -236,88 +242,96 @@ func makeSelectBuilder[T ~string](config tableConfig[T], selectStr string,
 		}
 	}
 
-	for key, column := range config.keysToColumns {
-		makeFilterConditions(sb, filters.reserved[key], column)
-	}
-
-	conditions := []string{}
-	for key, values := range filters.attributes {
-		if len(values) == 1 {
-			value := values[0]
-			if strings.Contains(value, "%") {
-				conditions = append(conditions, sb.Var(sqlbuilder.Buildf(config.attributesColumn+"[%s] LIKE %s", key, value)))
-			} else if strings.HasPrefix(value, "!") {
-				conditions = append(conditions, sb.Var(sqlbuilder.Buildf(config.attributesColumn+"[%s] != %s", key, value[1:])))
-			} else {
-				conditions = append(conditions, sb.Var(sqlbuilder.Buildf(config.attributesColumn+"[%s] = %s", key, value)))
-			}
-		} else {
-			innerConditions := []string{}
-			for _, value := range values {
-				if strings.Contains(value, "%") {
-					innerConditions = append(innerConditions, sb.Var(sqlbuilder.Buildf(config.attributesColumn+"[%s] LIKE %s", key, value)))
-				} else if strings.HasPrefix(value, "!") {
-					conditions = append(conditions, sb.Var(sqlbuilder.Buildf(config.attributesColumn+"[%s] != %s", key, value[1:])))
-				} else {
-					innerConditions = append(innerConditions, sb.Var(sqlbuilder.Buildf(config.attributesColumn+"[%s] = %s", key, value)))
-				}
-			}
-			conditions = append(conditions, sb.Or(innerConditions...))
-		}
-	}
-	if len(conditions) > 0 {
-		sb.Where(sb.And(conditions...))
+	if len(wheres) > 0 {
+		sb.Where(wheres...)
 	}
 
 	return sb, nil
 }
 
-type filtersWithReservedKeys[T ~string] struct {
-	body       []string
-	attributes map[string][]string
-	reserved   map[T][]string
-}
-
-func makeFilters[T ~string](query string, reservedKeys []T, defaultFilters map[string]string) filtersWithReservedKeys[T] {
-	filters := queryparser.Parse(query)
-	filtersWithReservedKeys := filtersWithReservedKeys[T]{
-		reserved:   make(map[T][]string),
-		attributes: make(map[string][]string),
+// parseQuery parses a search query, adding the default filters of the table for keys that the query does not search.
+func parseQuery[T ~string](config tableConfig[T], query string) (queryparser.Expr, error) {
+	expr, err := queryparser.ParseQuery(query)
+	if err != nil {
+		return nil, err
 	}
 
-	filtersWithReservedKeys.body = filters.Body
-
-	for _, key := range reservedKeys {
-		if val, ok := filters.Attributes[string(key)]; ok {
-			filtersWithReservedKeys.reserved[key] = val
-			delete(filters.Attributes, string(key))
+	keys := lo.Keys(config.defaultFilters)
+	sort.Strings(keys)
+	for _, key := range keys {
+		if queryparser.HasKey(expr, key) {
+			continue
 		}
-	}
-	for key, value := range defaultFilters {
-		if filters.Attributes[key] == nil {
-			filters.Attributes[key] = []string{}
+		value := config.defaultFilters[key]
+		var filter queryparser.Expr = &queryparser.TermExpr{Key: key, Op: queryparser.OpEqual, Value: strings.TrimPrefix(value, "!")}
+		if strings.HasPrefix(value, "!") {
+			filter = &queryparser.NotExpr{Expr: filter}
 		}
-		filters.Attributes[key] = append(filters.Attributes[key], value)
+		expr = queryparser.And(expr, filter)
 	}
+	return expr, nil
+}
 
-	filtersWithReservedKeys.attributes = filters.Attributes
-
-	return filtersWithReservedKeys
+// makeCondition compiles a query expression to a SQL condition.
+func makeCondition[T ~string](sb *sqlbuilder.SelectBuilder, config tableConfig[T], expr queryparser.Expr) string {
+	switch expr := expr.(type) {
+	case *queryparser.AndExpr:
+		return sb.And(lo.Map(expr.Exprs, func(e queryparser.Expr, _ int) string {
+			return makeCondition(sb, config, e)
+		})...)
+	case *queryparser.OrExpr:
+		return sb.Or(lo.Map(expr.Exprs, func(e queryparser.Expr, _ int) string {
+			return makeCondition(sb, config, e)
+		})...)
+	case *queryparser.NotExpr:
+		return "NOT (" + makeCondition(sb, config, expr.Expr) + ")"
+	case *queryparser.TermExpr:
+		return makeTermCondition(sb, config, expr)
+	}
+	return "true"
 }
 
-func makeFilterConditions(sb *sqlbuilder.SelectBuilder, filters []string, column string) {
-	conditions := []string{}
-	for _, filter := range filters {
-		if strings.Contains(filter, "%") {
-			conditions = append(conditions, sb.Like(column, filter))
-		} else {
-			conditions = append(conditions, sb.Equal(column, filter))
+func makeTermCondition[T ~string](sb *sqlbuilder.SelectBuilder, config tableConfig[T], term *queryparser.TermExpr) string {
+	if term.Key == "" {
+		if config.bodyColumn == "" {
+			return "true"
+		}
+		if term.Quoted {
+			return config.bodyColumn + " ILIKE " + sb.Var("%"+term.LikePattern()+"%")
 		}
+		if term.HasWildcard() {
+			return config.bodyColumn + " ILIKE " + sb.Var(term.LikePattern())
+		}
+		conditions := lo.Map(queryparser.SplitBody(term.Value), func(token string, _ int) string {
+			return "hasTokenCaseInsensitive(" + config.bodyColumn + ", " + sb.Var(token) + ")"
+		})
+		if len(conditions) == 0 {
+			return "true"
+		}
+		return sb.And(conditions...)
+	}
+
+	column, reserved := config.keysToColumns[T(term.Key)]
+	if !reserved {
+		if config.attributesColumn == "" {
+			return "true"
+		}
+		column = config.attributesColumn + "[" + sb.Var(term.Key) + "]"
 	}
 
-	if len(conditions) > 0 {
-		sb.Where(sb.Or(conditions...))
+	switch {
+	case term.Op == queryparser.OpExists:
+		if reserved {
+			// the default value of a column is 0 for numeric columns such as Duration, or an empty string
+			return column + " != defaultValueOfArgumentType(" + column + ")"
+		}
+		return "mapContains(" + config.attributesColumn + ", " + sb.Var(term.Key) + ")"
+	case term.Op.IsComparison():
+		return "toFloat64OrNull(toString(" + column + ")) " + string(term.Op) + " " + sb.Var(term.Number)
+	case term.HasWildcard():
+		return column + " LIKE " + sb.Var(term.LikePattern())
 	}
+	return column + " = " + sb.Var(term.Value)
 }
 
 func expandJSON(logAttributes map[string]string) map[string]interface{} {
@@ -431,79 +445,139 @@ func KeyValuesAggregated(ctx context.Context, client *Client, tableName string,
 	return values, rows.Err()
 }
 
-// clickhouse token - https://clickhouse.com/docs/en/sql-reference/functions/splitting-merging-functions#tokens
-var nonAlphaNumericChars = regexp.MustCompile(`[^\w:*]`)
-
-func matchesQuery[TObj interface{}, TReservedKey ~string](row *TObj, config tableConfig[TReservedKey], filters *queryparser.Filters) bool {
+// matchesQuery evaluates a query expression against a row in the same way as the SQL built by makeSelectBuilder.
+// Keys that the row cannot hold are ignored, as we'd prefer to over match since this fn is used to determine sampling.
+func matchesQuery[TObj interface{}, TReservedKey ~string](row *TObj, config tableConfig[TReservedKey], query queryparser.Expr) bool {
 	v := reflect.ValueOf(*row)
-
-	rowBodyTerms := map[string]bool{}
-	if config.bodyColumn != "" && len(filters.Body) > 0 {
-		body := v.FieldByName(config.bodyColumn).String()
-		for _, field := range nonAlphaNumericChars.Split(body, -1) {
-			if field != "" {
-				rowBodyTerms[field] = true
+	switch expr := query.(type) {
+	case *queryparser.AndExpr:
+		for _, e := range expr.Exprs {
+			if !matchesQuery(row, config, e) {
+				return false
 			}
 		}
-		for _, body := range filters.Body {
-			if strings.Contains(body, "%") {
-				pat, err := regexp.Compile(strings.ReplaceAll(regexp.QuoteMeta(body), "%", ".*"))
-				// this may over match if the expression cannot be compiled,
-				// but we'd prefer to over match as this fn is used to determine sampling
-				if err == nil {
-					if !pat.MatchString(body) {
-						return false
-					}
-				}
-			} else if !rowBodyTerms[body] {
-				return false
+		return true
+	case *queryparser.OrExpr:
+		for _, e := range expr.Exprs {
+			if matchesQuery(row, config, e) {
+				return true
 			}
 		}
+		return false
+	case *queryparser.NotExpr:
+		return !matchesQuery(row, config, expr.Expr)
+	case *queryparser.TermExpr:
+		if expr.Key == "" {
+			if config.bodyColumn == "" {
+				return true
+			}
+			return matchesBody(v.FieldByName(config.bodyColumn).String(), expr)
+		}
+		rowValue, exists, ok := getRowValue(v, config, expr.Key)
+		if !ok {
+			return true
+		}
+		switch {
+		case expr.Op == queryparser.OpExists:
+			return exists
+		case expr.Op.IsComparison():
+			number, err := strconv.ParseFloat(rowValue, 64)
+			return err == nil && expr.Op.Compare(number, expr.Number)
+		case expr.HasWildcard():
+			return matchesLikePattern(expr.LikePattern(), rowValue, false)
+		}
+		return expr.Value == rowValue
+	}
+	return true
+}
+
+func matchesBody(body string, term *queryparser.TermExpr) bool {
+	if term.Quoted {
+		return matchesLikePattern("%"+term.LikePattern()+"%", body, true)
+	}
+	if term.HasWildcard() {
+		return matchesLikePattern(term.LikePattern(), body, true)
+	}
+	// clickhouse token - https://clickhouse.com/docs/en/sql-reference/functions/splitting-merging-functions#tokens
+	rowBodyTerms := map[string]bool{}
+	for _, field := range queryparser.SplitBody(body) {
+		rowBodyTerms[strings.ToLower(field)] = true
+	}
+	for _, token := range queryparser.SplitBody(term.Value) {
+		if !rowBodyTerms[strings.ToLower(token)] {
+			return false
+		}
 	}
-	for key, values := range filters.Attributes {
-		var rowValue string
-		if chKey, ok := config.keysToColumns[TReservedKey(key)]; ok {
-			if strings.Contains(chKey, ".") {
-				var value = v
-				for _, part := range strings.Split(chKey, ".") {
-					value = value.FieldByName(part)
-					if value.Kind() == reflect.Pointer {
-						value = value.Elem()
-					}
-				}
-				rowValue = value.String()
-			} else {
-				rowValue = v.FieldByName(chKey).String()
+	return true
+}
+
+func matchesLikePattern(pattern string, value string, caseInsensitive bool) bool {
+	expr := "(?s)^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), "%", ".*") + "$"
+	if caseInsensitive {
+		expr = "(?i)" + expr
+	}
+	pat, err := regexp.Compile(expr)
+	// this may over match if the expression cannot be compiled,
+	// but we'd prefer to over match as this fn is used to determine sampling
+	if err != nil {
+		return true
+	}
+	return pat.MatchString(value)
+}
+
+// getRowValue returns the value of a key for a row, whether the row has the key,
+// and false if the row cannot hold the key.
+func getRowValue[TReservedKey ~string](v reflect.Value, config tableConfig[TReservedKey], key string) (string, bool, bool) {
+	if chKey, ok := config.keysToColumns[TReservedKey(key)]; ok {
+		value := v
+		for _, part := range strings.Split(chKey, ".") {
+			if value.Kind() != reflect.Struct {
+				return "", false, false
 			}
-		} else if config.attributesColumn != "" {
-			value := v.FieldByName(config.attributesColumn)
-			if value.Kind() == reflect.Map {
-				rowValue = value.MapIndex(reflect.ValueOf(key)).String()
-			} else if value.Kind() == reflect.Slice {
-				// assume that the key is a 'field' in `type_name` format
-				fieldParts := strings.SplitN(key, "_", 2)
-				for i := 0; i < value.Len(); i++ {
-					fieldType := value.Index(i).Elem().FieldByName("Type").String()
-					name := value.Index(i).Elem().FieldByName("Name").String()
-					if fieldType == fieldParts[0] && name == fieldParts[1] {
-						rowValue = value.Index(i).Elem().FieldByName("Value").String()
-						break
-					}
-				}
+			value = value.FieldByName(part)
+			if value.Kind() == reflect.Pointer {
+				value = value.Elem()
 			}
-		} else {
-			continue
 		}
-		for _, v := range values {
-			if strings.Contains(v, "%") {
-				if matched, _ := regexp.Match(strings.ReplaceAll(v, "%", ".*"), []byte(rowValue)); !matched {
-					return false
-				}
-			} else if v != rowValue {
-				return false
+		return formatValue(value), value.IsValid() && !value.IsZero(), true
+	}
+	if config.attributesColumn == "" {
+		return "", false, false
+	}
+	value := v.FieldByName(config.attributesColumn)
+	if value.Kind() == reflect.Map {
+		mapValue := value.MapIndex(reflect.ValueOf(key))
+		if !mapValue.IsValid() {
+			return "", false, true
+		}
+		return mapValue.String(), true, true
+	} else if value.Kind() == reflect.Slice {
+		// assume that the key is a 'field' in `type_name` format
+		fieldParts := strings.SplitN(key, "_", 2)
+		if len(fieldParts) < 2 {
+			return "", false, true
+		}
+		for i := 0; i < value.Len(); i++ {
+			fieldType := value.Index(i).Elem().FieldByName("Type").String()
+			name := value.Index(i).Elem().FieldByName("Name").String()
+			if fieldType == fieldParts[0] && name == fieldParts[1] {
+				return value.Index(i).Elem().FieldByName("Value").String(), true, true
 			}
-
 		}
 	}
-	return true
+	return "", false, true
+}
+
+func formatValue(value reflect.Value) string {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	case reflect.String:
+		return value.String()
+	}
+	return ""
 }
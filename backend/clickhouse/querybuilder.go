@@ -144,6 +144,7 @@ var customFieldTypes map[string]FieldType = map[string]FieldType{
 	"app_version":     text,
 	"active_length":   long,
 	"pages_visited":   long,
+	"error_group_id":  long,
 }
 
 // parseColumnRule applies a top-level column filter
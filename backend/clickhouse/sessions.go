@@ -52,6 +52,7 @@ var fieldMap map[string]string = map[string]string{
 	"visited_url":     "VisitedURL",
 	"timestamp":       "Timestamp",
 	"secure_id":       "ErrorGroupSecureID",
+	"error_group_id":  "ErrorGroupID",
 	"service_name":    "ServiceName",
 	"service_version": "ServiceVersion",
 	"Tag":             "ErrorTagTitle",
@@ -440,6 +441,6 @@ var sessionsTableConfig = tableConfig[modelInputs.ReservedSessionKey]{
 	reservedKeys:     modelInputs.AllReservedSessionKey,
 }
 
-func SessionMatchesQuery(session *model.Session, filters *queryparser.Filters) bool {
-	return matchesQuery(session, sessionsTableConfig, filters)
+func SessionMatchesQuery(session *model.Session, query queryparser.Expr) bool {
+	return matchesQuery(session, sessionsTableConfig, query)
 }
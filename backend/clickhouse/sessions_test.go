@@ -6,8 +6,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/highlight-run/highlight/backend/queryparser"
-
 	"github.com/highlight-run/highlight/backend/model"
 	"github.com/stretchr/testify/assert"
 )
@@ -100,8 +98,8 @@ func TestWriteSession(t *testing.T) {
 
 func Test_SessionMatchesQuery(t *testing.T) {
 	session := model.Session{}
-	filters := queryparser.Parse("environment:prod* user_email:*@highlight.io custom_email:bar@highlight.io session_visited-url:example.com user_age:123 service_name:all custom_created_at:when")
-	matches := SessionMatchesQuery(&session, &filters)
+	filters := mustParseQuery(t, "environment:prod* user_email:*@highlight.io custom_email:bar@highlight.io session_visited-url:example.com user_age:123 service_name:all custom_created_at:when")
+	matches := SessionMatchesQuery(&session, filters)
 	assert.False(t, matches)
 
 	session = model.Session{
@@ -135,18 +133,18 @@ func Test_SessionMatchesQuery(t *testing.T) {
 			},
 		},
 	}
-	matches = SessionMatchesQuery(&session, &filters)
+	matches = SessionMatchesQuery(&session, filters)
 	assert.True(t, matches)
 
-	filters = queryparser.Parse("environment:development email:*@highlight.io age:123 service_name:all")
-	matches = SessionMatchesQuery(&session, &filters)
+	filters = mustParseQuery(t, "environment:development email:*@highlight.io age:123 service_name:all")
+	matches = SessionMatchesQuery(&session, filters)
 	assert.False(t, matches)
 
-	filters = queryparser.Parse("environment:*prod* user_email:vadim@highlight.io")
-	matches = SessionMatchesQuery(&session, &filters)
+	filters = mustParseQuery(t, "environment:*prod* user_email:vadim@highlight.io")
+	matches = SessionMatchesQuery(&session, filters)
 	assert.True(t, matches)
 
-	filters = queryparser.Parse("environment:*prod* user_email:bar@highlight.io")
-	matches = SessionMatchesQuery(&session, &filters)
+	filters = mustParseQuery(t, "environment:*prod* user_email:bar@highlight.io")
+	matches = SessionMatchesQuery(&session, filters)
 	assert.False(t, matches)
 }
@@ -315,6 +315,9 @@ func (client *Client) ReadTracesMetrics(ctx context.Context, projectID int, para
 	switch column {
 	case modelInputs.TracesMetricColumnMetricValue:
 		metricColName = "toFloat64OrZero(Events.Attributes[1]['metric.value'])"
+	case modelInputs.TracesMetricColumnError:
+		// 1 for spans with an error status, so that Avg is the error rate and Sum the error count
+		metricColName = "toFloat64(StatusCode = 'Error')"
 	}
 
 	fnStr := ""
@@ -473,6 +476,6 @@ func (client *Client) TracesMetrics(ctx context.Context, projectID int, startDat
 	return KeysAggregated(ctx, client, TraceMetricsTable, projectID, startDate, endDate)
 }
 
-func TraceMatchesQuery(trace *TraceRow, filters *queryparser.Filters) bool {
-	return matchesQuery(trace, tracesTableConfig, filters)
+func TraceMatchesQuery(trace *TraceRow, query queryparser.Expr) bool {
+	return matchesQuery(trace, tracesTableConfig, query)
 }
@@ -2,10 +2,12 @@ package clickhouse
 
 import (
 	"context"
-	"github.com/highlight-run/highlight/backend/queryparser"
 	"testing"
 	"time"
 
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/queryparser"
+	"github.com/huandu/go-sqlbuilder"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -25,8 +27,8 @@ func TestBatchWriteTraceRows(t *testing.T) {
 
 func Test_TraceMatchesQuery(t *testing.T) {
 	trace := TraceRow{}
-	filters := queryparser.Parse("os.type:linux resource_name:worker.* service_name:all")
-	matches := TraceMatchesQuery(&trace, &filters)
+	filters := mustParseQuery(t, "os.type:linux resource_name:worker.* service_name:all")
+	matches := TraceMatchesQuery(&trace, filters)
 	assert.False(t, matches)
 
 	trace = TraceRow{
@@ -36,7 +38,69 @@ func Test_TraceMatchesQuery(t *testing.T) {
 			"resource_name": "worker.kafka.process",
 		},
 	}
-	filters = queryparser.Parse("os.type:linux resource_name:worker.* service_name:all")
-	matches = TraceMatchesQuery(&trace, &filters)
+	filters = mustParseQuery(t, "os.type:linux resource_name:worker.* service_name:all")
+	matches = TraceMatchesQuery(&trace, filters)
 	assert.True(t, matches)
 }
+
+func Test_TraceMatchesQuery_Expressions(t *testing.T) {
+	trace := TraceRow{
+		ServiceName: "api",
+		SpanName:    "POST /checkout",
+		Duration:    int64(time.Second),
+		TraceAttributes: map[string]string{
+			"http.method":      "POST",
+			"http.status_code": "503",
+		},
+	}
+	for query, want := range map[string]bool{
+		"duration>500ms":                                  true,
+		"duration<=500ms":                                 false,
+		"http.status_code>=500 http.method:POST":          true,
+		"http.status_code<500":                            false,
+		"service_name:web OR http.method:POST":            true,
+		"-service_name:api":                               false,
+		"NOT (service_name:web OR http.method:GET)":       true,
+		"http.method:* -user.id:*":                        true,
+		"user.id:*":                                       false,
+		"CHECKOUT":                                        true,
+		"checkout OR duration>1m":                         true,
+		"\"post /check*\"":                                true,
+		"(checkout AND service_name:web) OR cart":         false,
+		"service_name:a* (duration>2s OR http.method:P*)": true,
+		"duration:* -span_kind:*":                         true,
+		"service_name:web service_name:api":               true,
+		"service_name:web AND service_name:api":           false,
+	} {
+		assert.Equal(t, want, TraceMatchesQuery(&trace, mustParseQuery(t, query)), query)
+	}
+}
+
+func Test_TracesQuerySQL(t *testing.T) {
+	now := time.Now()
+	params := modelInputs.QueryInput{
+		DateRange: makeDateWithinRange(now),
+		Query:     "checkout duration>500ms (service_name:api OR -http.method:*)",
+	}
+	sb, err := makeSelectBuilder(tracesTableConfig, "Timestamp, UUID", nil, 1, params, Pagination{CountOnly: true}, OrderBackwardNatural, OrderForwardNatural)
+	assert.NoError(t, err)
+
+	sql, args := sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+	assert.Contains(t, sql, "FROM traces PREWHERE hasTokenCaseInsensitive(SpanName, ?) WHERE")
+	assert.Contains(t, sql, "toFloat64OrNull(toString(Duration)) > ?")
+	assert.Contains(t, sql, "(ServiceName = ? OR NOT (mapContains(TraceAttributes, ?)))")
+	assert.Contains(t, sql, "NOT (TraceAttributes[?] = ?)")
+	assert.Subset(t, args, []interface{}{"checkout", float64(500 * time.Millisecond), "api", "http.method", "highlight.type", "highlight.internal"})
+
+	params.Query = "duration:*"
+	sb, err = makeSelectBuilder(tracesTableConfig, "Timestamp, UUID", nil, 1, params, Pagination{CountOnly: true}, OrderBackwardNatural, OrderForwardNatural)
+	assert.NoError(t, err)
+	sql, _ = sb.BuildWithFlavor(sqlbuilder.ClickHouse)
+	assert.Contains(t, sql, "Duration != defaultValueOfArgumentType(Duration)")
+
+	params.Query = "checkout OR"
+	_, err = makeSelectBuilder(tracesTableConfig, "Timestamp, UUID", nil, 1, params, Pagination{CountOnly: true}, OrderBackwardNatural, OrderForwardNatural)
+	var syntaxError *queryparser.SyntaxError
+	assert.ErrorAs(t, err, &syntaxError)
+	assert.Equal(t, 11, syntaxError.Pos)
+}
@@ -1,9 +1,11 @@
 package errorgroups
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 
+	"github.com/PaesslerAG/jsonpath"
 	"github.com/highlight-run/highlight/backend/model"
 	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 )
@@ -38,6 +40,54 @@ func GetFingerprints(projectID int, errorTraces []*privateModel.ErrorTrace) []*m
 	return fingerprints
 }
 
+// GetJsonFingerprints returns a fingerprint for each of the project's JSON paths found in an event,
+// when the event is a single JSON encoded string.
+func GetJsonFingerprints(projectID int, jsonPaths []string, event string) []*model.ErrorFingerprint {
+	fingerprints := []*model.ErrorFingerprint{}
+	jsonStrings := []string{}
+	if err := json.Unmarshal([]byte(event), &jsonStrings); err != nil || len(jsonStrings) != 1 {
+		return fingerprints
+	}
+	errorAsJson := interface{}(nil)
+	if err := json.Unmarshal([]byte(jsonStrings[0]), &errorAsJson); err != nil {
+		return fingerprints
+	}
+	for _, path := range jsonPaths {
+		value, err := jsonpath.Get(path, errorAsJson)
+		if err != nil {
+			continue
+		}
+		marshalled, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		fingerprints = append(fingerprints, &model.ErrorFingerprint{
+			ProjectID: projectID,
+			Type:      model.Fingerprint.JsonResult,
+			Value:     path + "=" + string(marshalled),
+		})
+	}
+	return fingerprints
+}
+
+// GetRootCause returns the event and structured stacktrace of the root cause of a chained error.
+// The returned bool is false when the error has no causes.
+func GetRootCause(errorObj *model.ErrorObject) (string, []*privateModel.ErrorTrace, bool) {
+	if errorObj.Causes == nil {
+		return "", nil, false
+	}
+	var causes []*model.ErrorCause
+	if err := json.Unmarshal([]byte(*errorObj.Causes), &causes); err != nil || len(causes) == 0 {
+		return "", nil, false
+	}
+	rootCause := causes[len(causes)-1]
+	var errorTraces []*privateModel.ErrorTrace
+	if err := json.Unmarshal([]byte(rootCause.StackTrace), &errorTraces); err != nil {
+		return "", nil, false
+	}
+	return rootCause.Event, errorTraces, true
+}
+
 func joinStringPtrs(ptrs ...*string) string {
 	var sb strings.Builder
 	for _, ptr := range ptrs {
@@ -0,0 +1,45 @@
+package errorgroups
+
+import (
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetRootCause(t *testing.T) {
+	_, _, ok := GetRootCause(&model.ErrorObject{})
+	assert.False(t, ok)
+
+	_, _, ok = GetRootCause(&model.ErrorObject{Causes: ptr.String("not json")})
+	assert.False(t, ok)
+
+	errorObj := &model.ErrorObject{
+		Event: "wrapper",
+		Causes: ptr.String(`[
+			{"type": "java.lang.RuntimeException", "event": "wrapper", "stack_trace": "[{\"fileName\":\"App.java\",\"functionName\":\"com.example.App.run\",\"lineNumber\":10}]"},
+			{"type": "java.io.IOException", "event": "disk full", "stack_trace": "[{\"fileName\":\"Store.java\",\"functionName\":\"com.example.Store.write\",\"lineNumber\":88}]"}
+		]`),
+	}
+	event, errorTraces, ok := GetRootCause(errorObj)
+	assert.True(t, ok)
+	assert.Equal(t, "disk full", event)
+	assert.Len(t, errorTraces, 1)
+	assert.Equal(t, "Store.java", *errorTraces[0].FileName)
+
+	fingerprints := GetFingerprints(1, errorTraces)
+	assert.Len(t, fingerprints, 1)
+	assert.Equal(t, model.Fingerprint.StackFrameMetadata, fingerprints[0].Type)
+	assert.Equal(t, "Store.java;com.example.Store.write;88;", fingerprints[0].Value)
+}
+
+func TestGetJsonFingerprints(t *testing.T) {
+	fingerprints := GetJsonFingerprints(1, []string{"$.code", "$.missing"}, `["{\"code\":42,\"message\":\"timeout\"}"]`)
+	assert.Len(t, fingerprints, 1)
+	assert.Equal(t, model.Fingerprint.JsonResult, fingerprints[0].Type)
+	assert.Equal(t, "$.code=42", fingerprints[0].Value)
+
+	assert.Empty(t, GetJsonFingerprints(1, []string{"$.code"}, "not json"))
+	assert.Empty(t, GetJsonFingerprints(1, []string{"$.code"}, `["a", "b"]`))
+}
@@ -0,0 +1,66 @@
+package errorgroups
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+)
+
+// IsRegression returns whether an error of the given service version reopens a resolved error group.
+// Groups resolved in the next release only reopen for errors of a newer version than the one they were resolved in.
+func IsRegression(errorGroup *model.ErrorGroup, serviceVersion string) bool {
+	if errorGroup.State != privateModel.ErrorStateResolved {
+		return false
+	}
+	resolvedInVersion := lo.FromPtr(errorGroup.ResolvedInVersion)
+	if !errorGroup.ResolveInNextRelease || resolvedInVersion == "" {
+		return true
+	}
+	return IsNewerVersion(serviceVersion, resolvedInVersion)
+}
+
+// IsNewerVersion returns whether version was released after than.
+// Numeric versions such as v1.2.10 are compared by their components, ignoring pre-release and build suffixes.
+// Other versions, such as commit hashes, cannot be ordered and are newer whenever they differ.
+func IsNewerVersion(version string, than string) bool {
+	if version == "" || version == than {
+		return false
+	}
+	a, aOk := parseVersion(version)
+	b, bOk := parseVersion(than)
+	if !aOk || !bOk {
+		return true
+	}
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			return x > y
+		}
+	}
+	return false
+}
+
+func parseVersion(version string) ([]int, bool) {
+	version = strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+	if idx := strings.IndexAny(version, "-+"); idx >= 0 {
+		version = version[:idx]
+	}
+	var parts []int
+	for _, part := range strings.Split(version, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil || number < 0 {
+			return nil, false
+		}
+		parts = append(parts, number)
+	}
+	return parts, true
+}
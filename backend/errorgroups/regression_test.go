@@ -0,0 +1,50 @@
+package errorgroups
+
+import (
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsNewerVersion(t *testing.T) {
+	assert.True(t, IsNewerVersion("1.2.10", "1.2.9"))
+	assert.True(t, IsNewerVersion("v2.0", "1.9.9"))
+	assert.True(t, IsNewerVersion("1.2.1", "1.2"))
+	assert.True(t, IsNewerVersion("1.3.0-beta.1", "1.2.0"))
+	assert.True(t, IsNewerVersion("7a8b9c", "1a2b3c"))
+	assert.True(t, IsNewerVersion("1.0.0", "main"))
+
+	assert.False(t, IsNewerVersion("1.2.9", "1.2.10"))
+	assert.False(t, IsNewerVersion("1.2.0", "v1.2"))
+	assert.False(t, IsNewerVersion("7a8b9c", "7a8b9c"))
+	assert.False(t, IsNewerVersion("", "1.2.0"))
+}
+
+func TestIsRegression(t *testing.T) {
+	open := &model.ErrorGroup{State: privateModel.ErrorStateOpen}
+	assert.False(t, IsRegression(open, "1.0.0"))
+
+	ignored := &model.ErrorGroup{State: privateModel.ErrorStateIgnored}
+	assert.False(t, IsRegression(ignored, "1.0.0"))
+
+	resolved := &model.ErrorGroup{State: privateModel.ErrorStateResolved, ResolvedInVersion: lo.ToPtr("1.0.0")}
+	assert.True(t, IsRegression(resolved, "1.0.0"))
+	assert.True(t, IsRegression(resolved, ""))
+
+	resolvedInNextRelease := &model.ErrorGroup{
+		State:                privateModel.ErrorStateResolved,
+		ResolvedInVersion:    lo.ToPtr("1.0.0"),
+		ResolveInNextRelease: true,
+	}
+	assert.False(t, IsRegression(resolvedInNextRelease, "1.0.0"))
+	assert.False(t, IsRegression(resolvedInNextRelease, "0.9.0"))
+	assert.False(t, IsRegression(resolvedInNextRelease, ""))
+	assert.True(t, IsRegression(resolvedInNextRelease, "1.0.1"))
+
+	// without a known version, groups resolved in the next release reopen like any resolved group
+	resolvedWithoutVersion := &model.ErrorGroup{State: privateModel.ErrorStateResolved, ResolveInNextRelease: true}
+	assert.True(t, IsRegression(resolvedWithoutVersion, "1.0.0"))
+}
@@ -0,0 +1,191 @@
+package errorgroups
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ReneKroon/ttlcache"
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	e "github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+// The fields that an ErrorGroupingRule can group by.
+// File and function refer to the top stack frame remaining after ignored frames are removed.
+const (
+	GroupByType     = "type"
+	GroupByEvent    = "event"
+	GroupByFile     = "file"
+	GroupByFunction = "function"
+)
+
+var groupByFields = []string{GroupByType, GroupByEvent, GroupByFile, GroupByFunction}
+
+type GroupingResult struct {
+	// StackTrace is the stacktrace to fingerprint, without the frames ignored by the rules
+	StackTrace []*privateModel.ErrorTrace
+	// Fingerprint is set when a rule groups the error by its fields, in which case it replaces the default matchers
+	Fingerprint *string
+	// RuleIndex is the index of the rule that set the Fingerprint
+	RuleIndex *int
+}
+
+// ValidateGroupingRule checks that the conditions of a rule are valid regular expressions and that it groups by known fields.
+func ValidateGroupingRule(rule *model.ErrorGroupingRule) error {
+	for name, pattern := range map[string]*string{
+		"match_type":    rule.MatchType,
+		"match_event":   rule.MatchEvent,
+		"match_frame":   rule.MatchFrame,
+		"ignore_frames": rule.IgnoreFrames,
+	} {
+		if pattern == nil {
+			continue
+		}
+		if _, err := regexp.Compile(*pattern); err != nil {
+			return e.Wrapf(err, "invalid %s expression", name)
+		}
+	}
+	for _, field := range rule.GroupBy {
+		if !lo.Contains(groupByFields, field) {
+			return e.Errorf("invalid group_by field %q, expected one of %s", field, strings.Join(groupByFields, ", "))
+		}
+	}
+	return nil
+}
+
+// expressionCache holds the compiled rule expressions by pattern, as the rules of a project are applied to every error.
+var expressionCache = func() *ttlcache.Cache {
+	cache := ttlcache.NewCache()
+	cache.SetTTL(10 * time.Minute)
+	return cache
+}()
+
+type compiledExpression struct {
+	re  *regexp.Regexp
+	err error
+}
+
+func compileOptional(pattern *string) (*regexp.Regexp, error) {
+	if pattern == nil || *pattern == "" {
+		return nil, nil
+	}
+	if cached, ok := expressionCache.Get(*pattern); ok {
+		expression := cached.(compiledExpression)
+		return expression.re, expression.err
+	}
+	re, err := regexp.Compile(*pattern)
+	expressionCache.Set(*pattern, compiledExpression{re: re, err: err})
+	return re, err
+}
+
+func isFrameMatch(re *regexp.Regexp, frame *privateModel.ErrorTrace) bool {
+	return (frame.FileName != nil && re.MatchString(*frame.FileName)) ||
+		(frame.FunctionName != nil && re.MatchString(*frame.FunctionName))
+}
+
+// ApplyGroupingRules applies the grouping rules of a project to an error, in order.
+// Rules with invalid expressions are skipped, and the first matching rule that groups by fields ends the evaluation.
+func ApplyGroupingRules(rules []*model.ErrorGroupingRule, errorType string, event string, stackTrace []*privateModel.ErrorTrace) GroupingResult {
+	result := GroupingResult{StackTrace: stackTrace}
+	for idx, rule := range rules {
+		matchType, err := compileOptional(rule.MatchType)
+		if err != nil {
+			continue
+		}
+		matchEvent, err := compileOptional(rule.MatchEvent)
+		if err != nil {
+			continue
+		}
+		matchFrame, err := compileOptional(rule.MatchFrame)
+		if err != nil {
+			continue
+		}
+		ignoreFrames, err := compileOptional(rule.IgnoreFrames)
+		if err != nil {
+			continue
+		}
+
+		if matchType != nil && !matchType.MatchString(errorType) {
+			continue
+		}
+		if matchEvent != nil && !matchEvent.MatchString(event) {
+			continue
+		}
+		if matchFrame != nil && !lo.SomeBy(result.StackTrace, func(frame *privateModel.ErrorTrace) bool {
+			return isFrameMatch(matchFrame, frame)
+		}) {
+			continue
+		}
+
+		if ignoreFrames != nil {
+			result.StackTrace = lo.Reject(result.StackTrace, func(frame *privateModel.ErrorTrace, _ int) bool {
+				return isFrameMatch(ignoreFrames, frame)
+			})
+		}
+
+		if len(rule.GroupBy) > 0 {
+			fingerprint := getRuleFingerprint(rule.GroupBy, errorType, event, result.StackTrace)
+			result.Fingerprint = &fingerprint
+			result.RuleIndex = lo.ToPtr(idx)
+			return result
+		}
+	}
+	return result
+}
+
+func getRuleFingerprint(groupBy []string, errorType string, event string, stackTrace []*privateModel.ErrorTrace) string {
+	var topFrame privateModel.ErrorTrace
+	if len(stackTrace) > 0 && stackTrace[0] != nil {
+		topFrame = *stackTrace[0]
+	}
+
+	var parts []string
+	for _, field := range groupBy {
+		var value string
+		switch field {
+		case GroupByType:
+			value = errorType
+		case GroupByEvent:
+			value = event
+		case GroupByFile:
+			value = lo.FromPtr(topFrame.FileName)
+		case GroupByFunction:
+			value = lo.FromPtr(topFrame.FunctionName)
+		}
+		parts = append(parts, field+"="+value)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// PreviewGroupingRules shows how the rules would group the given error objects, without changing their error groups.
+// The target error group of errors grouped by a rule fingerprint is left to be resolved by the caller.
+func PreviewGroupingRules(errorObjects []*model.ErrorObject, rules []*model.ErrorGroupingRule) []*privateModel.ErrorGroupingPreview {
+	var previews []*privateModel.ErrorGroupingPreview
+	for _, errorObject := range errorObjects {
+		stackTrace := lo.FromPtr(errorObject.MappedStackTrace)
+		if stackTrace == "" {
+			stackTrace = lo.FromPtr(errorObject.StackTrace)
+		}
+		var errorTraces []*privateModel.ErrorTrace
+		// unstructured stacktraces are grouped as having no frames
+		_ = json.Unmarshal([]byte(stackTrace), &errorTraces)
+
+		result := ApplyGroupingRules(rules, errorObject.Type, errorObject.Event, errorTraces)
+		preview := &privateModel.ErrorGroupingPreview{
+			ErrorObjectID: errorObject.ID,
+			ErrorGroupID:  errorObject.ErrorGroupID,
+			Event:         errorObject.Event,
+			RuleIndex:     result.RuleIndex,
+			Fingerprint:   result.Fingerprint,
+		}
+		// errors not grouped by a rule keep their error group
+		if result.Fingerprint == nil {
+			preview.TargetErrorGroupID = lo.ToPtr(errorObject.ErrorGroupID)
+		}
+		previews = append(previews, preview)
+	}
+	return previews
+}
@@ -0,0 +1,113 @@
+package errorgroups
+
+import (
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGroupingRule(t *testing.T) {
+	assert.NoError(t, ValidateGroupingRule(&model.ErrorGroupingRule{
+		MatchType: ptr.String("^TypeError$"),
+		GroupBy:   []string{GroupByType, GroupByFunction},
+	}))
+	assert.Error(t, ValidateGroupingRule(&model.ErrorGroupingRule{
+		MatchEvent: ptr.String("(unclosed"),
+	}))
+	assert.Error(t, ValidateGroupingRule(&model.ErrorGroupingRule{
+		GroupBy: []string{"line"},
+	}))
+}
+
+func TestApplyGroupingRules(t *testing.T) {
+	stackTrace := []*privateModel.ErrorTrace{
+		{FileName: ptr.String("node_modules/react-dom/index.js"), FunctionName: ptr.String("render")},
+		{FileName: ptr.String("src/app.tsx"), FunctionName: ptr.String("fetchUser")},
+		{FileName: ptr.String("src/index.tsx"), FunctionName: ptr.String("main")},
+	}
+	rules := []*model.ErrorGroupingRule{
+		{
+			MatchType: ptr.String("^RangeError$"),
+			GroupBy:   []string{GroupByType},
+		},
+		{
+			IgnoreFrames: ptr.String("node_modules"),
+		},
+		{
+			MatchEvent: ptr.String("^Request failed"),
+			MatchFrame: ptr.String("^fetch"),
+			GroupBy:    []string{GroupByType, GroupByFile, GroupByFunction},
+		},
+	}
+
+	result := ApplyGroupingRules(rules, "TypeError", "Request failed with status 500", stackTrace)
+	assert.Equal(t, ptr.String("type=TypeError\nfile=src/app.tsx\nfunction=fetchUser"), result.Fingerprint)
+	assert.Equal(t, ptr.Int(2), result.RuleIndex)
+	assert.Len(t, result.StackTrace, 2)
+
+	// frames are ignored even when no rule groups the error
+	result = ApplyGroupingRules(rules, "TypeError", "Cannot read properties of undefined", stackTrace)
+	assert.Nil(t, result.Fingerprint)
+	assert.Nil(t, result.RuleIndex)
+	assert.Equal(t, stackTrace[1:], result.StackTrace)
+
+	// the first matching rule ends the evaluation
+	result = ApplyGroupingRules(rules, "RangeError", "Request failed with status 500", stackTrace)
+	assert.Equal(t, ptr.String("type=RangeError"), result.Fingerprint)
+	assert.Equal(t, ptr.Int(0), result.RuleIndex)
+	assert.Equal(t, stackTrace, result.StackTrace)
+
+	// invalid rules are skipped
+	result = ApplyGroupingRules([]*model.ErrorGroupingRule{
+		{MatchType: ptr.String("(unclosed"), GroupBy: []string{GroupByType}},
+	}, "TypeError", "", nil)
+	assert.Nil(t, result.Fingerprint)
+}
+
+func TestPreviewGroupingRules(t *testing.T) {
+	errorObjects := []*model.ErrorObject{
+		{
+			Model:        model.Model{ID: 1},
+			ErrorGroupID: 10,
+			Type:         "BACKEND",
+			Event:        "dial tcp: i/o timeout",
+			StackTrace:   ptr.String(`[{"fileName":"main.go","functionName":"connect"}]`),
+		},
+		{
+			Model:        model.Model{ID: 2},
+			ErrorGroupID: 11,
+			Type:         "BACKEND",
+			Event:        "panic: nil map",
+			StackTrace:   ptr.String("not a structured stacktrace"),
+		},
+	}
+	rules := []*model.ErrorGroupingRule{
+		{MatchEvent: ptr.String("timeout"), GroupBy: []string{GroupByFunction}},
+	}
+
+	previews := PreviewGroupingRules(errorObjects, rules)
+	assert.Equal(t, []*privateModel.ErrorGroupingPreview{
+		{ErrorObjectID: 1, ErrorGroupID: 10, Event: "dial tcp: i/o timeout", RuleIndex: ptr.Int(0), Fingerprint: ptr.String("function=connect")},
+		{ErrorObjectID: 2, ErrorGroupID: 11, Event: "panic: nil map", TargetErrorGroupID: ptr.Int(11)},
+	}, previews)
+}
+
+func TestCompileOptional(t *testing.T) {
+	re, err := compileOptional(ptr.String("timeout$"))
+	assert.NoError(t, err)
+	cached, err := compileOptional(ptr.String("timeout$"))
+	assert.NoError(t, err)
+	assert.Same(t, re, cached)
+
+	_, err = compileOptional(ptr.String("(unclosed"))
+	assert.Error(t, err)
+	_, err = compileOptional(ptr.String("(unclosed"))
+	assert.Error(t, err)
+
+	re, err = compileOptional(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, re)
+}
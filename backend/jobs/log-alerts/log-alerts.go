@@ -3,12 +3,14 @@ package log_alerts
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/highlight-run/highlight/backend/alerts"
 	"github.com/highlight-run/highlight/backend/clickhouse"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/highlight-run/highlight/backend/store"
 	"github.com/highlight-run/highlight/backend/util"
 	"github.com/highlight-run/workerpool"
 	"github.com/openlyinc/pointy"
@@ -27,7 +29,7 @@ import (
 const maxWorkers = 40
 const alertEvalFreq = 15 * time.Second
 
-func WatchLogAlerts(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Client, rh *resthooks.Resthook, redis *redis.Client, ccClient *clickhouse.Client) {
+func WatchLogAlerts(ctx context.Context, DB *gorm.DB, Store *store.Store, MailClient *sendgrid.Client, rh *resthooks.Resthook, redis *redis.Client, ccClient *clickhouse.Client) {
 	log.WithContext(ctx).Info("Starting to watch log alerts")
 
 	alertsByFrequency := &map[int64][]*model.LogAlert{}
@@ -72,7 +74,7 @@ func WatchLogAlerts(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Clien
 					alertWorkerpool.SubmitRecover(
 						func() {
 							ctx := context.Background()
-							err := processLogAlert(ctx, DB, MailClient, alert, rh, redis, ccClient)
+							err := processLogAlert(ctx, DB, Store, MailClient, alert, rh, redis, ccClient)
 							if err != nil {
 								log.WithContext(ctx).Error(err)
 							}
@@ -95,7 +97,7 @@ func getLogAlerts(ctx context.Context, DB *gorm.DB) []*model.LogAlert {
 	return alerts
 }
 
-func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Client, alert *model.LogAlert, rh *resthooks.Resthook, redis *redis.Client, ccClient *clickhouse.Client) error {
+func processLogAlert(ctx context.Context, DB *gorm.DB, Store *store.Store, MailClient *sendgrid.Client, alert *model.LogAlert, rh *resthooks.Resthook, redis *redis.Client, ccClient *clickhouse.Client) error {
 	end := time.Now().Add(-time.Minute)
 	start := end.Add(-time.Duration(alert.Frequency) * time.Second)
 
@@ -112,6 +114,14 @@ func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Clie
 	if alert.BelowThreshold {
 		alertCondition = count <= alert.CountThreshold
 	}
+	threshold := float64(alert.CountThreshold)
+	if alert.ThresholdType == modelInputs.ThresholdTypeAnomaly {
+		baseline, err := alerts.GetLogAlertBaseline(ctx, ccClient, redis, alert, start)
+		if err != nil {
+			return errors.Wrap(err, "error learning the baseline of the log alert")
+		}
+		alertCondition, threshold = baseline.IsAnomaly(float64(count), start, end.Sub(start), alert.AnomalyDeviations, alert.BelowThreshold)
+	}
 
 	log.WithContext(ctx).WithFields(log.Fields{
 		"id":        alert.ID,
@@ -120,11 +130,31 @@ func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Clie
 		"start":     start.Format(time.RFC3339),
 		"end":       end.Format(time.RFC3339),
 		"count":     count,
-		"threshold": alert.CountThreshold,
+		"threshold": threshold,
+		"anomaly":   alert.ThresholdType == modelInputs.ThresholdTypeAnomaly,
 		"alerting":  alertCondition,
 	}).Info("evaluated log alert")
 
-	if alertCondition {
+	evaluation, err := Store.EvaluateAlert(ctx, store.AlertEvaluationInput{
+		AlertType: model.AlertType.LOG,
+		AlertID:   alert.ID,
+		Settings:  alert.AlertEvaluationSettings,
+		Alerting:  alertCondition,
+		Value:     float64(count),
+		Threshold: threshold,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error evaluating log alert")
+	}
+
+	// only notify when the alert fires, re-notifies or resolves
+	if evaluation != nil && evaluation.Notified {
+		// silences mute the notifications without affecting the state of the alert
+		if model.IsAlertSilenced(ctx, DB, model.GetLogAlertSilenceMatch(alert), model.AllAlertDestinations) {
+			return nil
+		}
+		resolved := evaluation.State == modelInputs.AlertStateResolved
+
 		var project model.Project
 		if err := DB.Model(&model.Project{}).Where("id = ?", alert.ProjectID).Take(&project).Error; err != nil {
 			return errors.Wrap(err, "error querying project for processMetricMonitor")
@@ -141,7 +171,8 @@ func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Clie
 
 		hookPayload := zapier.HookPayload{
 			MetricValue:     pointy.Float64(float64(count)),
-			MetricThreshold: pointy.Float64(float64(alert.CountThreshold)),
+			MetricThreshold: pointy.Float64(threshold),
+			Resolved:        resolved,
 		}
 		if err := rh.Notify(project.ID, fmt.Sprintf("LogAlert_%d", alert.ID), hookPayload); err != nil {
 			log.WithContext(ctx).Error("error notifying zapier", err)
@@ -151,27 +182,38 @@ func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Clie
 		if alert.Query != "" {
 			queryStr = fmt.Sprintf(`for query *%s* `, alert.Query)
 		}
+		wasStr := "was"
+		if resolved {
+			wasStr = "is no longer"
+		}
 		body := fmt.Sprintf(
-			"Log count %swas %s the threshold.\n"+
-				"_Count_: %d | _Threshold_: %d",
+			"Log count %s%s %s the threshold.\n"+
+				"_Count_: %d | _Threshold_: %.0f",
 			queryStr,
+			wasStr,
 			aboveStr,
 			count,
-			alert.CountThreshold,
+			threshold,
 		)
 
-		log.WithContext(ctx).WithField("alert_id", alert.ID).Info(fmt.Sprintf("Firing alert for %s", alert.Name))
+		if resolved {
+			log.WithContext(ctx).WithField("alert_id", alert.ID).Info(fmt.Sprintf("Resolving alert for %s", alert.Name))
+		} else {
+			log.WithContext(ctx).WithField("alert_id", alert.ID).Info(fmt.Sprintf("Firing alert for %s", alert.Name))
+		}
 
-		if err := alert.SendSlackAlert(ctx, DB, &model.SendSlackAlertForLogAlertInput{Body: body, Workspace: &workspace, StartDate: start, EndDate: end}); err != nil {
+		if err := alert.SendSlackAlert(ctx, DB, &model.SendSlackAlertForLogAlertInput{Body: body, Workspace: &workspace, StartDate: start, EndDate: end, Resolved: resolved}); err != nil {
 			log.WithContext(ctx).Error("error sending slack alert for metric monitor", err)
 		}
 
-		if err = alerts.SendLogAlert(alerts.LogAlertEvent{
+		if err = alerts.SendLogAlert(ctx, DB, alerts.LogAlertEvent{
 			LogAlert:  alert,
 			Workspace: &workspace,
 			Count:     count,
+			Threshold: int(math.Round(threshold)),
 			StartDate: start,
 			EndDate:   end,
+			Resolved:  resolved,
 		}); err != nil {
 			log.WithContext(ctx).Error(err)
 		}
@@ -188,16 +230,22 @@ func processLogAlert(ctx context.Context, DB *gorm.DB, MailClient *sendgrid.Clie
 			if alert.Query != "" {
 				queryStr = fmt.Sprintf(`for query <b>%s</b> `, alert.Query)
 			}
+			firedStr, isStr := "fired!", "is currently"
+			if resolved {
+				firedStr, isStr = "resolved.", "is no longer"
+			}
 			message := fmt.Sprintf(
-				"<b>%s</b> fired! Log count %sis currently %s the threshold.<br>"+
-					"<em>Count</em>: %d | <em>Threshold</em>: %d"+
+				"<b>%s</b> %s Log count %s%s %s the threshold.<br>"+
+					"<em>Count</em>: %d | <em>Threshold</em>: %.0f"+
 					"<br><br>"+
 					"<a href=\"%s\">View Logs</a>",
 				alert.Name,
+				firedStr,
 				queryStr,
+				isStr,
 				aboveStr,
 				count,
-				alert.CountThreshold,
+				threshold,
 				alertUrl,
 			)
 			if err := Email.SendAlertEmail(ctx, MailClient, *email, message, "Log Alert", alert.Name); err != nil {
@@ -11,6 +11,7 @@ import (
 	"github.com/highlight-run/highlight/backend/alerts"
 	"github.com/highlight-run/highlight/backend/private-graph/graph"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/store"
 	"github.com/openlyinc/pointy"
 
 	"github.com/pkg/errors"
@@ -28,13 +29,13 @@ const (
 	sigFigs = 4
 )
 
-func WatchMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, MailClient *sendgrid.Client, rh *resthooks.Resthook) {
+func WatchMetricMonitors(ctx context.Context, DB *gorm.DB, Store *store.Store, ccClient *clickhouse.Client, MailClient *sendgrid.Client, rh *resthooks.Resthook) {
 	log.WithContext(ctx).Info("Starting to watch Metric Monitors")
 
 	for range time.Tick(time.Minute * 1) {
 		go func() {
 			metricMonitors := getMetricMonitors(ctx, DB)
-			processMetricMonitors(ctx, DB, ccClient, MailClient, metricMonitors, rh)
+			processMetricMonitors(ctx, DB, Store, ccClient, MailClient, metricMonitors, rh)
 		}()
 	}
 }
@@ -50,7 +51,7 @@ func getMetricMonitors(ctx context.Context, DB *gorm.DB) []*model.MetricMonitor
 	return metricMonitors
 }
 
-func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhouse.Client, MailClient *sendgrid.Client, metricMonitors []*model.MetricMonitor, rh *resthooks.Resthook) {
+func processMetricMonitors(ctx context.Context, DB *gorm.DB, Store *store.Store, ccClient *clickhouse.Client, MailClient *sendgrid.Client, metricMonitors []*model.MetricMonitor, rh *resthooks.Resthook) {
 	log.WithContext(ctx).Info("Number of Metric Monitors to Process: ", len(metricMonitors))
 	for _, metricMonitor := range metricMonitors {
 		var value float64
@@ -91,7 +92,32 @@ func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhous
 		log.WithContext(ctx).Infof("Processing %s for Project %d. ID: %d", metricMonitor.Name, metricMonitor.ProjectID, metricMonitor.ID)
 		log.WithContext(ctx).Infof("Current value: %f, Threshold: %f", value, metricMonitor.Threshold)
 
-		if value >= metricMonitor.Threshold {
+		evaluation, err := Store.EvaluateAlert(ctx, store.AlertEvaluationInput{
+			AlertType: model.AlertType.METRIC_MONITOR,
+			AlertID:   metricMonitor.ID,
+			Settings:  metricMonitor.AlertEvaluationSettings,
+			Alerting:  value >= metricMonitor.Threshold,
+			Value:     value,
+			Threshold: metricMonitor.Threshold,
+		})
+		if err != nil {
+			log.WithContext(ctx).Error(errors.Wrap(err, "error evaluating metric monitor"))
+			continue
+		}
+
+		// only notify when the monitor fires, re-notifies or resolves
+		if evaluation != nil && evaluation.Notified {
+			// silences mute the notifications without affecting the state of the monitor
+			if model.IsAlertSilenced(ctx, DB, model.AlertSilenceMatch{
+				ProjectID: metricMonitor.ProjectID,
+				AlertType: model.AlertType.METRIC_MONITOR,
+				AlertID:   metricMonitor.ID,
+				AlertName: metricMonitor.Name,
+			}, model.AllAlertDestinations) {
+				continue
+			}
+			resolved := evaluation.State == modelInputs.AlertStateResolved
+
 			var project model.Project
 			if err := DB.Model(&model.Project{}).Where("id = ?", metricMonitor.ProjectID).Take(&project).Error; err != nil {
 				log.WithContext(ctx).Error("error querying project for processMetricMonitor", err)
@@ -114,6 +140,7 @@ func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhous
 			hookPayload := zapier.HookPayload{
 				MetricValue:     &value,
 				MetricThreshold: &metricMonitor.Threshold,
+				Resolved:        resolved,
 			}
 			if err := rh.Notify(project.ID, fmt.Sprintf("MetricMonitor_%d", metricMonitor.ID), hookPayload); err != nil {
 				log.WithContext(ctx).Error("error notifying zapier", err)
@@ -131,6 +158,18 @@ func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhous
 				thresholdRepr,
 				unitsStr,
 			)
+			if resolved {
+				message = fmt.Sprintf(
+					"✅ *%s* resolved.\n*%s* is no longer over the threshold.\n"+
+						"_Value_: %s %s | _Threshold_: %s %s",
+					metricMonitor.Name,
+					metricMonitor.MetricToMonitor,
+					valueRepr,
+					unitsStr,
+					thresholdRepr,
+					unitsStr,
+				)
+			}
 
 			log.WithContext(ctx).Info(message)
 
@@ -138,13 +177,14 @@ func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhous
 				log.WithContext(ctx).Error("error sending slack alert for metric monitor", err)
 			}
 
-			if err = alerts.SendMetricMonitorAlert(alerts.MetricMonitorAlertEvent{
+			if err = alerts.SendMetricMonitorAlert(ctx, DB, alerts.MetricMonitorAlertEvent{
 				MetricMonitor: metricMonitor,
 				Workspace:     &workspace,
 				UnitsFormat:   unitsStr,
 				DiffOverValue: diffRepr,
 				Value:         valueRepr,
 				Threshold:     thresholdRepr,
+				Resolved:      resolved,
 			}); err != nil {
 				log.WithContext(ctx).Error(err)
 			}
@@ -157,15 +197,19 @@ func processMetricMonitors(ctx context.Context, DB *gorm.DB, ccClient *clickhous
 			frontendURL := os.Getenv("FRONTEND_URI")
 			monitorURL := fmt.Sprintf("%s/%d/alerts/monitor/%d", frontendURL, metricMonitor.ProjectID, metricMonitor.ID)
 
+			overStr := fmt.Sprintf("is currently <b>%s %s</b> over", diffRepr, unitsStr)
+			if resolved {
+				overStr = "is no longer over"
+			}
+
 			for _, email := range emailsToNotify {
 				message = fmt.Sprintf(
-					"<b>%s</b> is currently <b>%s %s</b> over the threshold.<br>"+
+					"<b>%s</b> %s the threshold.<br>"+
 						"<em>Value</em>: %s <em>%s</em> | <em>Threshold: %s <em>%s</em>"+
 						"<br><br>"+
 						"<a href=\"%s\">View Monitor</a>",
 					metricMonitor.Name,
-					diffRepr,
-					unitsStr,
+					overStr,
 					valueRepr,
 					unitsStr,
 					thresholdRepr,
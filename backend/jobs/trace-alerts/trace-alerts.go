@@ -0,0 +1,296 @@
+package trace_alerts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/alerts"
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/highlight-run/highlight/backend/store"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/highlight-run/workerpool"
+	"github.com/openlyinc/pointy"
+
+	"github.com/pkg/errors"
+	"github.com/sendgrid/sendgrid-go"
+
+	"github.com/highlight-run/go-resthooks"
+	Email "github.com/highlight-run/highlight/backend/email"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/zapier"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const maxWorkers = 40
+const alertEvalFreq = 15 * time.Second
+
+func WatchTraceAlerts(ctx context.Context, DB *gorm.DB, Store *store.Store, MailClient *sendgrid.Client, rh *resthooks.Resthook, redis *redis.Client, ccClient *clickhouse.Client) {
+	log.WithContext(ctx).Info("Starting to watch trace alerts")
+
+	alertsByFrequency := &map[int64][]*model.TraceAlert{}
+
+	getAlerts := func() {
+		bucketed := map[int64][]*model.TraceAlert{}
+
+		alerts := getTraceAlerts(ctx, DB)
+		for _, alert := range alerts {
+			freq := int64(alert.Frequency)
+			if freq > 0 {
+				bucketed[freq] = append(bucketed[freq], alert)
+			}
+		}
+
+		alertsByFrequency = &bucketed
+		log.WithContext(ctx).Infof("Watching %d trace alerts", len(alerts))
+	}
+
+	getAlerts()
+	go func() {
+		// Every minute, check for new alerts and bucket by frequency
+		for range time.Tick(time.Minute) {
+			getAlerts()
+		}
+	}()
+
+	alertWorkerpool := workerpool.New(maxWorkers)
+	alertWorkerpool.SetPanicHandler(util.Recover)
+
+	startTime := time.Now().Unix()
+	for range time.NewTicker(alertEvalFreq).C {
+		curTime := time.Now().Unix()
+		alerts := *alertsByFrequency
+		for freq, alerts := range alerts {
+			// If at least one tick has passed since the last loop,
+			// evaluate the alerts for this bucket
+			if (curTime / freq) > (startTime / freq) {
+				for _, alert := range alerts {
+					// copy `alert` by value so each call to processTraceAlert references a different alert
+					alert := alert
+					alertWorkerpool.SubmitRecover(
+						func() {
+							ctx := context.Background()
+							err := processTraceAlert(ctx, DB, Store, MailClient, alert, rh, redis, ccClient)
+							if err != nil {
+								log.WithContext(ctx).Error(err)
+							}
+						})
+				}
+			}
+		}
+		startTime = curTime
+	}
+}
+
+func getTraceAlerts(ctx context.Context, DB *gorm.DB) []*model.TraceAlert {
+	var alerts []*model.TraceAlert
+	if err := DB.Model(&model.TraceAlert{}).Where("disabled = ?", false).Find(&alerts).Error; err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.WithContext(ctx).Error("Error querying for trace alerts")
+		}
+	}
+
+	return alerts
+}
+
+func processTraceAlert(ctx context.Context, DB *gorm.DB, Store *store.Store, MailClient *sendgrid.Client, alert *model.TraceAlert, rh *resthooks.Resthook, redis *redis.Client, ccClient *clickhouse.Client) error {
+	end := time.Now().Add(-time.Minute)
+	start := end.Add(-time.Duration(alert.Frequency) * time.Second)
+
+	metrics, err := ccClient.ReadTracesMetrics(ctx, alert.ProjectID, modelInputs.QueryInput{Query: alert.Query, DateRange: &modelInputs.DateRangeRequiredInput{
+		StartDate: start,
+		EndDate:   end,
+	}}, alert.Column, []modelInputs.MetricAggregator{alert.Aggregator}, alert.GroupBy, 1)
+	if err != nil {
+		return errors.Wrap(err, "error querying clickhouse for trace metrics")
+	}
+
+	values := map[string]float64{}
+	for _, bucket := range metrics.Buckets {
+		values[model.GetTraceAlertGroupKey(alert.GroupBy, bucket.Group)] = bucket.MetricValue
+	}
+
+	// an alert without groups that sees no spans still evaluates below threshold conditions
+	if len(alert.GroupBy) == 0 {
+		if _, ok := values[""]; !ok {
+			values[""] = 0
+		}
+	}
+
+	// groups that stopped reporting spans are evaluated with no value so that they can resolve.
+	// A missing group would always be below the threshold, so below threshold groups expire instead.
+	expired := map[string]bool{}
+	statuses, err := Store.GetAlertStatuses(ctx, model.AlertType.TRACE, alert.ID)
+	if err != nil {
+		return errors.Wrap(err, "error querying trace alert statuses")
+	}
+	for _, status := range statuses {
+		if _, ok := values[status.GroupKey]; ok {
+			continue
+		}
+		if status.State == modelInputs.AlertStatePending || status.State == modelInputs.AlertStateFiring {
+			values[status.GroupKey] = 0
+			expired[status.GroupKey] = alert.BelowThreshold
+		}
+	}
+
+	for groupKey, value := range values {
+		if err := evaluateTraceAlertGroup(ctx, DB, Store, MailClient, alert, rh, groupKey, value, expired[groupKey], start, end); err != nil {
+			log.WithContext(ctx).WithField("alert_id", alert.ID).WithField("group_key", groupKey).Error(err)
+		}
+	}
+	return nil
+}
+
+// evaluateTraceAlertGroup evaluates the value of a group of a trace alert. Expired groups are absent
+// from the evaluated window and no longer meet the condition of the alert.
+func evaluateTraceAlertGroup(ctx context.Context, DB *gorm.DB, Store *store.Store, MailClient *sendgrid.Client, alert *model.TraceAlert, rh *resthooks.Resthook, groupKey string, value float64, expired bool, start time.Time, end time.Time) error {
+	alertCondition := value >= alert.Threshold
+	if alert.BelowThreshold {
+		alertCondition = value <= alert.Threshold
+	}
+	if expired {
+		alertCondition = false
+	}
+
+	log.WithContext(ctx).WithFields(log.Fields{
+		"id":        alert.ID,
+		"query":     alert.Query,
+		"group_key": groupKey,
+		"frequency": alert.Frequency,
+		"start":     start.Format(time.RFC3339),
+		"end":       end.Format(time.RFC3339),
+		"value":     value,
+		"threshold": alert.Threshold,
+		"expired":   expired,
+		"alerting":  alertCondition,
+	}).Info("evaluated trace alert")
+
+	evaluation, err := Store.EvaluateAlert(ctx, store.AlertEvaluationInput{
+		AlertType: model.AlertType.TRACE,
+		AlertID:   alert.ID,
+		GroupKey:  groupKey,
+		Settings:  alert.AlertEvaluationSettings,
+		Alerting:  alertCondition,
+		Value:     value,
+		Threshold: alert.Threshold,
+	})
+	if err != nil {
+		return errors.Wrap(err, "error evaluating trace alert")
+	}
+
+	// only notify when the alert fires, re-notifies or resolves
+	if evaluation == nil || !evaluation.Notified {
+		return nil
+	}
+	// silences mute the notifications without affecting the state of the alert
+	if model.IsAlertSilenced(ctx, DB, model.GetTraceAlertSilenceMatch(alert, groupKey), model.AllAlertDestinations) {
+		return nil
+	}
+	resolved := evaluation.State == modelInputs.AlertStateResolved
+
+	var project model.Project
+	if err := DB.Model(&model.Project{}).Where("id = ?", alert.ProjectID).Take(&project).Error; err != nil {
+		return errors.Wrap(err, "error querying project for processTraceAlert")
+	}
+	var workspace model.Workspace
+	if err := DB.Where(&model.Workspace{Model: model.Model{ID: project.WorkspaceID}}).Take(&workspace).Error; err != nil {
+		return errors.Wrap(err, "error querying workspace for processTraceAlert")
+	}
+
+	aboveStr := "above"
+	if alert.BelowThreshold {
+		aboveStr = "below"
+	}
+
+	hookPayload := zapier.HookPayload{
+		MetricValue:     pointy.Float64(value),
+		MetricThreshold: pointy.Float64(alert.Threshold),
+		Resolved:        resolved,
+	}
+	if err := rh.Notify(project.ID, fmt.Sprintf("TraceAlert_%d", alert.ID), hookPayload); err != nil {
+		log.WithContext(ctx).Error("error notifying zapier", err)
+	}
+
+	query := alert.GetGroupQuery(groupKey)
+	queryStr := ""
+	if query != "" {
+		queryStr = fmt.Sprintf(`for query *%s* `, query)
+	}
+	wasStr := "was"
+	if resolved {
+		wasStr = "is no longer"
+	}
+	body := fmt.Sprintf(
+		"%s %s%s %s the threshold.\n"+
+			"_Value_: %s | _Threshold_: %s",
+		alert.GetMetricName(),
+		queryStr,
+		wasStr,
+		aboveStr,
+		alert.FormatValue(value),
+		alert.FormatValue(alert.Threshold),
+	)
+
+	if resolved {
+		log.WithContext(ctx).WithField("alert_id", alert.ID).Info(fmt.Sprintf("Resolving alert for %s", alert.Name))
+	} else {
+		log.WithContext(ctx).WithField("alert_id", alert.ID).Info(fmt.Sprintf("Firing alert for %s", alert.Name))
+	}
+
+	if err := alert.SendSlackAlert(ctx, &model.SendSlackAlertForTraceAlertInput{Body: body, Workspace: &workspace, StartDate: start, EndDate: end, Query: query, Resolved: resolved}); err != nil {
+		log.WithContext(ctx).Error("error sending slack alert for trace alert", err)
+	}
+
+	if err = alerts.SendTraceAlert(ctx, DB, alerts.TraceAlertEvent{
+		TraceAlert: alert,
+		Workspace:  &workspace,
+		GroupKey:   groupKey,
+		Value:      value,
+		StartDate:  start,
+		EndDate:    end,
+		Resolved:   resolved,
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	emailsToNotify, err := model.GetEmailsToNotify(alert.EmailsToNotify)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	alertUrl := model.GetTraceAlertURL(alert.ProjectID, query, start, end)
+
+	for _, email := range emailsToNotify {
+		queryStr := ""
+		if query != "" {
+			queryStr = fmt.Sprintf(`for query <b>%s</b> `, query)
+		}
+		firedStr, isStr := "fired!", "is currently"
+		if resolved {
+			firedStr, isStr = "resolved.", "is no longer"
+		}
+		message := fmt.Sprintf(
+			"<b>%s</b> %s %s %s%s %s the threshold.<br>"+
+				"<em>Value</em>: %s | <em>Threshold</em>: %s"+
+				"<br><br>"+
+				"<a href=\"%s\">View Traces</a>",
+			alert.Name,
+			firedStr,
+			alert.GetMetricName(),
+			queryStr,
+			isStr,
+			aboveStr,
+			alert.FormatValue(value),
+			alert.FormatValue(alert.Threshold),
+			alertUrl,
+		)
+		if err := Email.SendAlertEmail(ctx, MailClient, *email, message, "Trace Alert", alert.Name); err != nil {
+			log.WithContext(ctx).Error(err)
+		}
+	}
+	return nil
+}
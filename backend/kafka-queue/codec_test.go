@@ -0,0 +1,112 @@
+package kafka_queue
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/public-graph/graph/model"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDecodeMessage(t *testing.T) {
+	msg := &Message{
+		Type:       PushPayload,
+		MaxRetries: TaskRetries,
+		PushPayload: &PushPayloadArgs{
+			SessionSecureID: "abc",
+			Events: model.ReplayEventsInput{
+				Events: []*model.ReplayEventInput{{
+					Type:      2,
+					Timestamp: 1700000000000,
+					Data: map[string]interface{}{
+						"id":   json.Number("12"),
+						"x":    json.Number("1.5"),
+						"text": "hello",
+					},
+				}},
+			},
+			Messages:  `{"messages":[]}`,
+			Resources: `{"resources":[]}`,
+			IsBeacon:  pointy.Bool(false),
+		},
+		PushLogs: &PushLogsArgs{LogRow: &clickhouse.LogRow{
+			Timestamp:     time.Unix(1700000000, 0),
+			ProjectId:     1,
+			Body:          "hello",
+			LogAttributes: map[string]string{"foo": "bar"},
+		}},
+	}
+
+	for name, encoding := range map[string]Encoding{
+		"legacy":         LegacyEncoding,
+		"json zstd":      {Codec: CodecJSON, Compression: CompressionZstd},
+		"msgpack":        {Codec: CodecMsgPack, Compression: CompressionNone},
+		"msgpack zstd":   {Codec: CodecMsgPack, Compression: CompressionZstd},
+		"msgpack snappy": {Codec: CodecMsgPack, Compression: CompressionSnappy},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := EncodeMessage(msg, encoding)
+			assert.NoError(t, err)
+
+			decoded, decodedEncoding, err := DecodeMessage(data)
+			assert.NoError(t, err)
+			assert.Equal(t, encoding, decodedEncoding)
+			assert.Equal(t, msg.Type, decoded.Type)
+			assert.Equal(t, msg.MaxRetries, decoded.MaxRetries)
+			assert.Nil(t, decoded.KafkaMessage)
+			assert.Equal(t, msg.PushPayload.SessionSecureID, decoded.PushPayload.SessionSecureID)
+			assert.Equal(t, msg.PushPayload.Messages, decoded.PushPayload.Messages)
+			assert.Equal(t, msg.PushPayload.IsBeacon, decoded.PushPayload.IsBeacon)
+			assert.Equal(t, msg.PushLogs.LogRow.LogAttributes, decoded.PushLogs.LogRow.LogAttributes)
+			assert.True(t, msg.PushLogs.LogRow.Timestamp.Equal(decoded.PushLogs.LogRow.Timestamp))
+
+			// event data is re-serialized as json by the worker and must keep numbers numeric
+			eventData, err := json.Marshal(decoded.PushPayload.Events.Events[0].Data)
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"id": 12, "x": 1.5, "text": "hello"}`, string(eventData))
+		})
+	}
+}
+
+func TestDecodeLegacyMessage(t *testing.T) {
+	msg, encoding, err := DecodeMessage([]byte(`{"Type":9,"Failures":1,"MaxRetries":5,"PushLogs":{"LogRow":{"ProjectId":1,"Body":"hello"}}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, LegacyEncoding, encoding)
+	assert.Equal(t, PushLogs, msg.Type)
+	assert.Equal(t, 1, msg.Failures)
+	assert.Equal(t, "hello", msg.PushLogs.LogRow.Body)
+
+	data, err := EncodeMessage(msg, LegacyEncoding)
+	assert.NoError(t, err)
+	assert.Equal(t, byte('{'), data[0])
+}
+
+func TestDecodeMessageErrors(t *testing.T) {
+	_, _, err := DecodeMessage([]byte{envelopeMagic, envelopeVersion})
+	assert.Error(t, err)
+	_, _, err = DecodeMessage([]byte{envelopeMagic, envelopeVersion + 1, byte(CodecJSON), byte(CompressionNone), '{', '}'})
+	assert.Error(t, err)
+	_, _, err = DecodeMessage([]byte{envelopeMagic, envelopeVersion, 42, byte(CompressionNone), '{', '}'})
+	assert.Error(t, err)
+	_, _, err = DecodeMessage([]byte{envelopeMagic, envelopeVersion, byte(CodecMsgPack), byte(CompressionZstd), 1, 2, 3})
+	assert.Error(t, err)
+}
+
+func TestGetEncoding(t *testing.T) {
+	encoding, err := GetEncoding()
+	assert.NoError(t, err)
+	assert.Equal(t, LegacyEncoding, encoding)
+
+	t.Setenv("KAFKA_MESSAGE_CODEC", "msgpack")
+	t.Setenv("KAFKA_MESSAGE_COMPRESSION", "zstd")
+	encoding, err = GetEncoding()
+	assert.NoError(t, err)
+	assert.Equal(t, Encoding{Codec: CodecMsgPack, Compression: CompressionZstd}, encoding)
+
+	t.Setenv("KAFKA_MESSAGE_COMPRESSION", "lz4")
+	_, err = GetEncoding()
+	assert.Error(t, err)
+}
@@ -0,0 +1,128 @@
+package kafka_queue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/pkg/errors"
+	"github.com/samber/lo"
+)
+
+const deadLetterSuffix = "dlq"
+
+// GetDeadLetterTopic returns the topic that holds messages of a topic which failed processing after all retries.
+func GetDeadLetterTopic(options GetTopicOptions) string {
+	return fmt.Sprintf("%s_%s", GetTopic(options), deadLetterSuffix)
+}
+
+type DeadLetterArgs struct {
+	Error     string
+	Failures  int
+	Topic     string
+	Partition int
+	Offset    int64
+	Timestamp time.Time
+}
+
+// NewDeadLetter returns a copy of a message that exhausted its retries, annotated with the failure metadata.
+func NewDeadLetter(msg *Message, err error) *Message {
+	deadLetter := *msg
+	deadLetter.KafkaMessage = nil
+	deadLetter.DeadLetter = &DeadLetterArgs{
+		Failures:  msg.Failures,
+		Timestamp: time.Now(),
+	}
+	if err != nil {
+		deadLetter.DeadLetter.Error = err.Error()
+	}
+	if msg.KafkaMessage != nil {
+		deadLetter.DeadLetter.Topic = msg.KafkaMessage.Topic
+		deadLetter.DeadLetter.Partition = msg.KafkaMessage.Partition
+		deadLetter.DeadLetter.Offset = msg.KafkaMessage.Offset
+	}
+	return &deadLetter
+}
+
+// NewReplay returns a copy of a dead-lettered message to submit back onto its source topic.
+func NewReplay(msg *Message) *Message {
+	replay := *msg
+	replay.KafkaMessage = nil
+	replay.DeadLetter = nil
+	replay.Failures = 0
+	return &replay
+}
+
+// GetProjectID returns the project of the message for payloads that reference one directly.
+// Payloads that only reference a session return false.
+func (m *Message) GetProjectID() (int, bool) {
+	switch m.Type {
+	case PushBackendPayload:
+		if m.PushBackendPayload != nil && m.PushBackendPayload.ProjectVerboseID != nil {
+			if projectID, err := model.FromVerboseID(*m.PushBackendPayload.ProjectVerboseID); err == nil {
+				return projectID, true
+			}
+		}
+	case PushMetrics:
+		if m.PushMetrics != nil {
+			return m.PushMetrics.ProjectID, true
+		}
+	case PushLogs:
+		if m.PushLogs != nil && m.PushLogs.LogRow != nil {
+			return int(m.PushLogs.LogRow.ProjectId), true
+		}
+	case PushTraces:
+		if m.PushTraces != nil && m.PushTraces.TraceRow != nil {
+			return int(m.PushTraces.TraceRow.ProjectId), true
+		}
+	case PushMetricRows:
+		if m.PushMetricRows != nil && m.PushMetricRows.MetricRow != nil {
+			return int(m.PushMetricRows.MetricRow.ProjectId), true
+		}
+	}
+	return 0, false
+}
+
+// DeadLetterFilter selects the dead-lettered messages to replay. Unset fields match all messages.
+type DeadLetterFilter struct {
+	PayloadTypes []PayloadType
+	ProjectID    *int
+}
+
+// ParseDeadLetterFilter builds a filter from a comma separated list of payload types and a project id.
+func ParseDeadLetterFilter(payloadTypes string, projectID string) (*DeadLetterFilter, error) {
+	filter := &DeadLetterFilter{}
+	for _, t := range strings.Split(payloadTypes, ",") {
+		if strings.TrimSpace(t) == "" {
+			continue
+		}
+		payloadType, err := strconv.Atoi(strings.TrimSpace(t))
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid payload type %s", t)
+		}
+		filter.PayloadTypes = append(filter.PayloadTypes, payloadType)
+	}
+	if projectID != "" {
+		id, err := strconv.Atoi(projectID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid project id %s", projectID)
+		}
+		filter.ProjectID = &id
+	}
+	return filter, nil
+}
+
+func (f *DeadLetterFilter) Matches(msg *Message) bool {
+	if len(f.PayloadTypes) > 0 && !lo.Contains(f.PayloadTypes, msg.Type) {
+		return false
+	}
+	if f.ProjectID != nil {
+		projectID, ok := msg.GetProjectID()
+		if !ok || projectID != *f.ProjectID {
+			return false
+		}
+	}
+	return true
+}
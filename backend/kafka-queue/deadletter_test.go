@@ -0,0 +1,99 @@
+package kafka_queue
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/public-graph/graph/model"
+	"github.com/openlyinc/pointy"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDeadLetter(t *testing.T) {
+	msg := &Message{
+		Type:       PushMetrics,
+		Failures:   6,
+		MaxRetries: TaskRetries,
+		KafkaMessage: &kafka.Message{
+			Topic:     "dev_topic",
+			Partition: 3,
+			Offset:    42,
+			Key:       []byte("key"),
+		},
+		PushMetrics: &PushMetricsArgs{ProjectID: 1},
+	}
+
+	deadLetter := NewDeadLetter(msg, errors.New("clickhouse is down"))
+	assert.Nil(t, deadLetter.KafkaMessage)
+	assert.Equal(t, msg.PushMetrics, deadLetter.PushMetrics)
+	assert.Equal(t, "clickhouse is down", deadLetter.DeadLetter.Error)
+	assert.Equal(t, 6, deadLetter.DeadLetter.Failures)
+	assert.Equal(t, "dev_topic", deadLetter.DeadLetter.Topic)
+	assert.Equal(t, 3, deadLetter.DeadLetter.Partition)
+	assert.Equal(t, int64(42), deadLetter.DeadLetter.Offset)
+	assert.NotNil(t, msg.KafkaMessage)
+
+	replay := NewReplay(deadLetter)
+	assert.Nil(t, replay.DeadLetter)
+	assert.Equal(t, 0, replay.Failures)
+	assert.Equal(t, msg.PushMetrics, replay.PushMetrics)
+	assert.NotNil(t, deadLetter.DeadLetter)
+}
+
+func TestDeadLetterFilter(t *testing.T) {
+	_, err := ParseDeadLetterFilter("1,foo", "")
+	assert.Error(t, err)
+	_, err = ParseDeadLetterFilter("", "foo")
+	assert.Error(t, err)
+
+	logs := &Message{Type: PushLogs, PushLogs: &PushLogsArgs{LogRow: &clickhouse.LogRow{ProjectId: 1}}}
+	traces := &Message{Type: PushTraces, PushTraces: &PushTracesArgs{TraceRow: &clickhouse.TraceRow{ProjectId: 2}}}
+	backend := &Message{Type: PushBackendPayload, PushBackendPayload: &PushBackendPayloadArgs{ProjectVerboseID: pointy.String("1")}}
+	session := &Message{Type: PushPayload, PushPayload: &PushPayloadArgs{SessionSecureID: "abc", Events: model.ReplayEventsInput{}}}
+
+	for name, tc := range map[string]struct {
+		payloadTypes string
+		projectID    string
+		expected     []bool
+	}{
+		"no filters": {
+			expected: []bool{true, true, true, true},
+		},
+		"payload types": {
+			payloadTypes: "9, 10",
+			expected:     []bool{true, true, false, false},
+		},
+		"project": {
+			projectID: "1",
+			expected:  []bool{true, false, true, false},
+		},
+		"payload types and project": {
+			payloadTypes: "9,10",
+			projectID:    "2",
+			expected:     []bool{false, true, false, false},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			filter, err := ParseDeadLetterFilter(tc.payloadTypes, tc.projectID)
+			assert.NoError(t, err)
+			for idx, msg := range []*Message{logs, traces, backend, session} {
+				assert.Equal(t, tc.expected[idx], filter.Matches(msg), "message %d", idx)
+			}
+		})
+	}
+}
+
+func TestDecodeError(t *testing.T) {
+	_, _, decodeErr := DecodeMessage([]byte("invalid"))
+	assert.Error(t, decodeErr)
+
+	err := fmt.Errorf("replay: %w", &DecodeError{KafkaMessage: &kafka.Message{Partition: 1, Offset: 2}, err: decodeErr})
+	var target *DecodeError
+	assert.True(t, errors.As(err, &target))
+	assert.Equal(t, int64(2), target.KafkaMessage.Offset)
+	assert.ErrorIs(t, err, decodeErr)
+	assert.Contains(t, err.Error(), "offset 2 of partition 1")
+}
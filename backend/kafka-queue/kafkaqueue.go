@@ -3,7 +3,6 @@ package kafka_queue
 import (
 	"context"
 	"crypto/tls"
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -25,6 +24,10 @@ const KafkaOperationTimeout = 25 * time.Second
 
 const ConsumerGroupName = "group-default"
 
+// DeadLetterConsumerGroupName is the consumer group replaying dead-lettered messages,
+// separate from the workers consuming the source topics.
+const DeadLetterConsumerGroupName = "group-dead-letter"
+
 const (
 	TaskRetries           = 5
 	prefetchQueueCapacity = 64
@@ -53,6 +56,7 @@ type Queue struct {
 	Topic         string
 	ConsumerGroup string
 	Client        *kafka.Client
+	Encoding      Encoding
 	kafkaP        *kafka.Writer
 	kafkaC        *kafka.Reader
 }
@@ -93,12 +97,18 @@ type ConfigOverride struct {
 	QueueCapacity *int
 	MinBytes      *int
 	MaxWait       *time.Duration
+	Encoding      *Encoding
+	// GroupID sets the consumer group, rather than ConsumerGroupName
+	GroupID *string
 }
 
 func New(ctx context.Context, topic string, mode Mode, configOverride *ConfigOverride) *Queue {
 	servers := os.Getenv("KAFKA_SERVERS")
 	brokers := strings.Split(servers, ",")
 	groupID := ConsumerGroupName
+	if configOverride != nil && configOverride.GroupID != nil {
+		groupID = *configOverride.GroupID
+	}
 
 	tlsConfig := &tls.Config{}
 	var mechanism sasl.Mechanism
@@ -162,6 +172,12 @@ func New(ctx context.Context, topic string, mode Mode, configOverride *ConfigOve
 
 	pool := &Queue{Topic: topic, ConsumerGroup: groupID, Client: client}
 	if mode&1 == 1 {
+		encoding, err := GetEncoding()
+		if err != nil {
+			log.WithContext(ctx).WithError(err).Error("invalid kafka message encoding, using legacy json")
+		}
+		pool.Encoding = encoding
+
 		pool.kafkaP = &kafka.Writer{
 			Addr:         kafka.TCP(brokers...),
 			Transport:    transport,
@@ -186,6 +202,9 @@ func New(ctx context.Context, topic string, mode Mode, configOverride *ConfigOve
 			if deref.Async != nil {
 				pool.kafkaP.Async = *deref.Async
 			}
+			if deref.Encoding != nil {
+				pool.Encoding = *deref.Encoding
+			}
 		}
 
 		if !util.IsDevOrTestEnv() {
@@ -280,7 +299,7 @@ func (p *Queue) Submit(ctx context.Context, partitionKey string, messages ...*Me
 	var kMessages []kafka.Message
 	for _, msg := range messages {
 		msg.MaxRetries = TaskRetries
-		msgBytes, err := p.serializeMessage(msg)
+		msgBytes, err := EncodeMessage(msg, p.Encoding)
 		if err != nil {
 			log.WithContext(ctx).Error(errors.Wrap(err, "failed to serialize message"))
 			return err
@@ -290,6 +309,7 @@ func (p *Queue) Submit(ctx context.Context, partitionKey string, messages ...*Me
 			Value: msgBytes,
 		})
 		hlog.Incr(p.metricPrefix()+"produceMessageCount", nil, 1)
+		hlog.Histogram(p.metricPrefix()+"produceMessageBytes", float64(len(msgBytes)), p.Encoding.tags(), 1)
 	}
 
 	ctx, cancel := context.WithTimeout(ctx, KafkaOperationTimeout)
@@ -303,26 +323,51 @@ func (p *Queue) Submit(ctx context.Context, partitionKey string, messages ...*Me
 	return nil
 }
 
-func (p *Queue) Receive(ctx context.Context) (msg *Message) {
+func (p *Queue) Receive(ctx context.Context) *Message {
+	msg, err := p.ReceiveMessage(ctx)
+	if err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+	return msg
+}
+
+// DecodeError is returned by ReceiveMessage for a kafka message that cannot be deserialized.
+// The kafka message can be committed to skip it.
+type DecodeError struct {
+	KafkaMessage *kafka.Message
+	err          error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("failed to deserialize message at offset %d of partition %d: %s", e.KafkaMessage.Offset, e.KafkaMessage.Partition, e.err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.err
+}
+
+// ReceiveMessage returns the next message of the queue, or nil once no message arrives within KafkaOperationTimeout.
+// A message that cannot be deserialized is returned as a *DecodeError.
+func (p *Queue) ReceiveMessage(ctx context.Context) (*Message, error) {
 	start := time.Now()
 	ctx, cancel := context.WithTimeout(ctx, KafkaOperationTimeout)
 	defer cancel()
 	m, err := p.kafkaC.FetchMessage(ctx)
 	if err != nil {
 		if err.Error() != "context deadline exceeded" {
-			log.WithContext(ctx).Error(errors.Wrap(err, "failed to receive message"))
+			return nil, errors.Wrap(err, "failed to receive message")
 		}
-		return nil
+		return nil, nil
 	}
-	msg, err = p.deserializeMessage(m.Value)
+	msg, encoding, err := DecodeMessage(m.Value)
 	if err != nil {
-		log.WithContext(ctx).Error(errors.Wrap(err, "failed to deserialize message"))
-		return nil
+		return nil, &DecodeError{KafkaMessage: &m, err: err}
 	}
 	msg.KafkaMessage = &m
 	hlog.Incr(p.metricPrefix()+"consumeMessageCount", nil, 1)
+	hlog.Histogram(p.metricPrefix()+"consumeMessageBytes", float64(len(m.Value)), encoding.tags(), 1)
 	hlog.Histogram(p.metricPrefix()+"receiveSec", time.Since(start).Seconds(), nil, 1)
-	return
+	return msg, nil
 }
 
 func (p *Queue) Rewind(ctx context.Context, dur time.Duration) error {
@@ -412,21 +457,6 @@ func (p *Queue) LogStats() {
 	}
 }
 
-func (p *Queue) serializeMessage(msg *Message) (compressed []byte, err error) {
-	compressed, err = json.Marshal(&msg)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshall json")
-	}
-	return
-}
-
-func (p *Queue) deserializeMessage(compressed []byte) (msg *Message, error error) {
-	if err := json.Unmarshal(compressed, &msg); err != nil {
-		return nil, errors.Wrap(err, "failed to unmarshall msg")
-	}
-	return
-}
-
 func (p *Queue) resetConsumerOffset(ctx context.Context, partitionOffsets map[int]int64) (error error) {
 	cfg := p.kafkaC.Config()
 	group, err := kafka.NewConsumerGroup(kafka.ConsumerGroupConfig{
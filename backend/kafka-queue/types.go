@@ -33,6 +33,7 @@ const (
 	SessionDataSync                        PayloadType = iota
 	ErrorGroupDataSync                     PayloadType = iota
 	ErrorObjectDataSync                    PayloadType = iota
+	PushMetricRows                         PayloadType = iota
 	HealthCheck                            PayloadType = math.MaxInt
 )
 
@@ -114,6 +115,10 @@ type PushTracesArgs struct {
 	TraceRow *clickhouse.TraceRow
 }
 
+type PushMetricRowsArgs struct {
+	MetricRow *clickhouse.MetricRow
+}
+
 type SessionDataSyncArgs struct {
 	SessionID int
 }
@@ -141,9 +146,11 @@ type Message struct {
 	AddSessionFeedback   *AddSessionFeedbackArgs   `json:",omitempty"`
 	PushLogs             *PushLogsArgs             `json:",omitempty"`
 	PushTraces           *PushTracesArgs           `json:",omitempty"`
+	PushMetricRows       *PushMetricRowsArgs       `json:",omitempty"`
 	SessionDataSync      *SessionDataSyncArgs      `json:",omitempty"`
 	ErrorGroupDataSync   *ErrorGroupDataSyncArgs   `json:",omitempty"`
 	ErrorObjectDataSync  *ErrorObjectDataSyncArgs  `json:",omitempty"`
+	DeadLetter           *DeadLetterArgs           `json:",omitempty"`
 }
 
 type PartitionMessage struct {
@@ -40,6 +40,7 @@ import (
 	"github.com/highlight-run/highlight/backend/phonehome"
 	private "github.com/highlight-run/highlight/backend/private-graph/graph"
 	privategen "github.com/highlight-run/highlight/backend/private-graph/graph/generated"
+	"github.com/highlight-run/highlight/backend/prometheus"
 	public "github.com/highlight-run/highlight/backend/public-graph/graph"
 	publicgen "github.com/highlight-run/highlight/backend/public-graph/graph/generated"
 	"github.com/highlight-run/highlight/backend/redis"
@@ -76,6 +77,7 @@ var (
 	stripeWebhookSecret = os.Getenv("STRIPE_WEBHOOK_SECRET")
 	slackSigningSecret  = os.Getenv("SLACK_SIGNING_SECRET")
 	otlpEndpoint        = os.Getenv("OTLP_ENDPOINT")
+	otlpGRPCPort        = os.Getenv("OTLP_GRPC_PORT")
 	runtimeFlag         = flag.String("runtime", "all", "the runtime of the backend; either 1) dev (all runtimes) 2) worker 3) public-graph 4) private-graph")
 	handlerFlag         = flag.String("worker-handler", "", "applies for runtime=worker; if specified, a handler function will be called instead of Start")
 )
@@ -495,6 +497,24 @@ func main() {
 		})
 		otelHandler := otel.New(publicResolver)
 		otelHandler.Listen(r)
+		// the OTLP/gRPC receiver is opt-in since the local collector binds the standard 4317 port
+		if otlpGRPCPort != "" {
+			go func() {
+				log.WithContext(ctx).Infof("otel grpc receiver listening on port %s", otlpGRPCPort)
+				if err := otelHandler.ListenGRPC(otlpGRPCPort); err != nil {
+					log.WithContext(ctx).WithError(err).Error("otel grpc receiver stopped")
+				}
+			}()
+		}
+		// sample the traces buffered by tail sampling once their timeout has passed
+		go func() {
+			for range time.Tick(time.Second) {
+				if err := publicResolver.FlushTailSampledTraces(ctx); err != nil {
+					log.WithContext(ctx).WithError(err).Error("failed to flush tail sampled traces")
+				}
+			}
+		}()
+		prometheus.New(publicResolver).Listen(r)
 		vercel.Listen(r)
 		highlightHttp.Listen(r)
 	}
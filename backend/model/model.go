@@ -6,6 +6,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/url"
 	"os"
 	"regexp"
@@ -20,6 +21,7 @@ import (
 	"github.com/aws/smithy-go/ptr"
 	Email "github.com/highlight-run/highlight/backend/email"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/highlight-run/highlight/backend/queryparser"
 	"github.com/highlight-run/highlight/backend/routing"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
@@ -78,6 +80,8 @@ const (
 // TODO(et) - replace this with generated SessionAlertType
 var AlertType = struct {
 	ERROR            string
+	ERROR_REGRESSION string
+	METRIC_MONITOR   string
 	NEW_USER         string
 	TRACK_PROPERTIES string
 	USER_PROPERTIES  string
@@ -85,8 +89,11 @@ var AlertType = struct {
 	RAGE_CLICK       string
 	NEW_SESSION      string
 	LOG              string
+	TRACE            string
 }{
 	ERROR:            "ERROR_ALERT",
+	ERROR_REGRESSION: "ERROR_REGRESSION_ALERT",
+	METRIC_MONITOR:   "METRIC_MONITOR_ALERT",
 	NEW_USER:         "NEW_USER_ALERT",
 	TRACK_PROPERTIES: "TRACK_PROPERTIES_ALERT",
 	USER_PROPERTIES:  "USER_PROPERTIES_ALERT",
@@ -94,6 +101,7 @@ var AlertType = struct {
 	RAGE_CLICK:       "RAGE_CLICK_ALERT",
 	NEW_SESSION:      "NEW_SESSION_ALERT",
 	LOG:              "LOG",
+	TRACE:            "TRACE_ALERT",
 }
 
 var AdminRole = struct {
@@ -173,6 +181,12 @@ var Models = []interface{}{
 	&SessionAlertEvent{},
 	&LogAlert{},
 	&LogAlertEvent{},
+	&TraceAlert{},
+	&AlertStatus{},
+	&AlertEvaluation{},
+	&AlertSilence{},
+	&SilencedAlertNotification{},
+	&WebhookDelivery{},
 	&Project{},
 	&RageClickEvent{},
 	&Workspace{},
@@ -212,6 +226,7 @@ var Models = []interface{}{
 	&SystemConfiguration{},
 	&SessionInsight{},
 	&ErrorTag{},
+	&ErrorGroupingRule{},
 }
 
 func init() {
@@ -409,6 +424,10 @@ type ProjectFilterSettings struct {
 	ErrorExclusionQuery               *string
 	LogExclusionQuery                 *string
 	TraceExclusionQuery               *string
+	// tail sampling rules: when any is set, spans are buffered and sampled per trace rather than per span
+	TraceKeepOnError          bool `gorm:"default:false"`
+	TraceLatencyThresholdMs   *int64
+	TraceServiceSamplingRates JSONB `gorm:"type:jsonb"`
 }
 
 type AllWorkspaceSettings struct {
@@ -423,6 +442,8 @@ type AllWorkspaceSettings struct {
 	ErrorEmbeddingsGroup bool `gorm:"default:true"`
 	// use embeddings to tag error groups in this workspace
 	ErrorEmbeddingsTagGroup bool `gorm:"default:true"`
+	// group chained errors by their root cause rather than the outermost error
+	ErrorGroupRootCause bool `gorm:"default:false"`
 
 	ErrorEmbeddingsThreshold  float64 `gorm:"default:0.2"`
 	ReplaceAssets             bool    `gorm:"default:false"`
@@ -926,6 +947,7 @@ type MetricMonitor struct {
 	LastAdminToEditID int                      `gorm:"last_admin_to_edit_id"`
 	Disabled          *bool                    `gorm:"default:false"`
 	Filters           []*DashboardMetricFilter `gorm:"foreignKey:MetricMonitorID"`
+	AlertEvaluationSettings
 	AlertIntegrations
 }
 
@@ -979,6 +1001,7 @@ const (
 	ErrorGroupingMethodClassic             ErrorGroupingMethod = "Classic"
 	ErrorGroupingMethodAdaEmbeddingV2      ErrorGroupingMethod = "AdaV2"
 	ErrorGroupingMethodGteLargeEmbeddingV2 ErrorGroupingMethod = "thenlper/gte-large"
+	ErrorGroupingMethodCustomRule          ErrorGroupingMethod = "CustomRule"
 )
 
 type ErrorObject struct {
@@ -1012,6 +1035,14 @@ type ErrorObject struct {
 	IsBeacon                bool    `gorm:"default:false"`
 	ServiceName             string
 	ServiceVersion          string
+	Causes                  *string // JSON array of ErrorCause for chained errors, outermost error first
+}
+
+// ErrorCause is a single error of a chained error, such as a java `Caused by:`.
+type ErrorCause struct {
+	Type       string `json:"type"`
+	Event      string `json:"event"`
+	StackTrace string `json:"stack_trace"`
 }
 
 type ErrorObjectEmbeddings struct {
@@ -1046,6 +1077,16 @@ type ErrorGroup struct {
 	LastOccurrence   *time.Time                           `gorm:"-"`
 	ErrorObjects     []ErrorObject
 	ServiceName      string
+	// MergedIntoID is set when the group was merged into another group, which new matching errors are grouped into
+	MergedIntoID *int `gorm:"index"`
+	// ResolvedInVersion is the service version of the latest error when the group was resolved
+	ResolvedInVersion *string
+	// ResolveInNextRelease keeps the group resolved for errors of ResolvedInVersion or older versions
+	ResolveInNextRelease bool
+	// RegressedAt is set when a resolved group reoccurred, until its state is changed again
+	RegressedAt *time.Time
+	// Regressed is set when grouping the error that caused the group to regress
+	Regressed bool `gorm:"-"`
 
 	// manually migrate as gorm wants to make this have a default value otherwise
 	ErrorTagID *int      `gorm:"-:migration"`
@@ -1063,6 +1104,24 @@ type ErrorTag struct {
 	Embedding   Vector `gorm:"type:vector(1024)"` // 1024 dimensions in the thenlper/gte-large
 }
 
+// ErrorGroupingRule customizes how the errors of a project are grouped. Rules are applied in order of priority
+// to the errors matching all of their conditions, each of which is a regular expression.
+type ErrorGroupingRule struct {
+	Model
+	ProjectID int `gorm:"index;not null"`
+	Priority  int
+	// Conditions
+	MatchType  *string
+	MatchEvent *string
+	// MatchFrame matches the file or function name of any stack frame
+	MatchFrame *string
+	// Actions
+	// IgnoreFrames removes the stack frames with a matching file or function name before the error is fingerprinted
+	IgnoreFrames *string
+	// GroupBy groups errors by these fields rather than by their stacktrace, eg. {type, function}
+	GroupBy pq.StringArray `gorm:"type:text[]"`
+}
+
 type MatchedErrorObject struct {
 	ErrorObject
 	Score float64 `json:"score"`
@@ -1071,9 +1130,12 @@ type MatchedErrorObject struct {
 type ErrorGroupEventType string
 
 const (
-	ErrorGroupResolvedEvent ErrorGroupEventType = "ErrorGroupResolved"
-	ErrorGroupIgnoredEvent  ErrorGroupEventType = "ErrorGroupIgnored"
-	ErrorGroupOpenedEvent   ErrorGroupEventType = "ErrorGroupOpened"
+	ErrorGroupResolvedEvent  ErrorGroupEventType = "ErrorGroupResolved"
+	ErrorGroupIgnoredEvent   ErrorGroupEventType = "ErrorGroupIgnored"
+	ErrorGroupOpenedEvent    ErrorGroupEventType = "ErrorGroupOpened"
+	ErrorGroupMergedEvent    ErrorGroupEventType = "ErrorGroupMerged"
+	ErrorGroupSplitEvent     ErrorGroupEventType = "ErrorGroupSplit"
+	ErrorGroupRegressedEvent ErrorGroupEventType = "ErrorGroupRegressed"
 )
 
 type ErrorGroupActivityLog struct {
@@ -1118,10 +1180,12 @@ var Fingerprint = struct {
 	StackFrameCode     FingerprintType
 	StackFrameMetadata FingerprintType
 	JsonResult         FingerprintType
+	CustomRule         FingerprintType
 }{
 	StackFrameCode:     "CODE",
 	StackFrameMetadata: "META",
 	JsonResult:         "JSON",
+	CustomRule:         "CUSTOM",
 }
 
 type ErrorFingerprint struct {
@@ -1131,6 +1195,9 @@ type ErrorFingerprint struct {
 	Type         FingerprintType
 	Value        string
 	Index        int
+	// MergedFromID is the error group the fingerprint was merged from.
+	// Merged fingerprints are kept when new errors replace the fingerprints of a group, so that matching errors follow the merge.
+	MergedFromID *int
 }
 
 type ExternalAttachment struct {
@@ -1493,6 +1560,22 @@ func MigrateDB(ctx context.Context, DB *gorm.DB) (bool, error) {
 		return false, e.Wrap(err, "Error dropping null constraint on error_fingerprints.error_group_id")
 	}
 
+	// AutoMigrate does not alter existing indexes, so the unique index of alert statuses created before
+	// grouped alerts is recreated to include group_key
+	if err := DB.Exec(`
+		DO $$
+		BEGIN
+			IF NOT EXISTS
+				(select * from pg_indexes where tablename = 'alert_statuses' and indexname = 'idx_alert_status_alert' and indexdef like '%group_key%')
+			THEN
+				DROP INDEX IF EXISTS idx_alert_status_alert;
+				CREATE UNIQUE INDEX idx_alert_status_alert ON alert_statuses (alert_type, alert_id, group_key);
+			END IF;
+		END $$;
+	`).Error; err != nil {
+		return false, e.Wrap(err, "Error recreating idx_alert_status_alert")
+	}
+
 	if err := DB.Exec(`
 		CREATE MATERIALIZED VIEW IF NOT EXISTS daily_session_counts_view AS
 			SELECT project_id, DATE_TRUNC('day', created_at, 'UTC') as date, COUNT(*) as count
@@ -1962,9 +2045,15 @@ type ErrorAlert struct {
 	Model
 	Alert
 	RegexGroups *string
+	AnomalyThresholdSettings
 	AlertIntegrations
 }
 
+// IsRegressionAlert returns whether the alert only notifies of resolved error groups that reoccurred.
+func (obj *ErrorAlert) IsRegressionAlert() bool {
+	return obj.Type != nil && *obj.Type == AlertType.ERROR_REGRESSION
+}
+
 type ErrorAlertEvent struct {
 	ID            int64 `gorm:"primary_key;type:bigserial" json:"id" deep:"-"`
 	ErrorAlertID  int   `gorm:"index:idx_error_alert_event"`
@@ -1972,6 +2061,8 @@ type ErrorAlertEvent struct {
 	SentAt        time.Time
 }
 
+// SendAlerts notifies the slack channels and emails of an error alert.
+// The caller checks the alert silences once for every destination of the alert, see GetErrorAlertSilenceMatch.
 func (obj *ErrorAlert) SendAlerts(ctx context.Context, db *gorm.DB, mailClient *sendgrid.Client, input *SendSlackAlertInput) {
 	defer func() {
 		db.Create(&ErrorAlertEvent{
@@ -1993,7 +2084,14 @@ func (obj *ErrorAlert) SendAlerts(ctx context.Context, db *gorm.DB, mailClient *
 	errorURL := fmt.Sprintf("%s/%d/errors/%s/instances/%d", frontendURL, obj.ProjectID, input.Group.SecureID, input.ErrorObject.ID)
 	errorURL = routing.AttachReferrer(ctx, errorURL, routing.Email)
 
-	message := fmt.Sprintf("<b>%s</b><br>The following error is being thrown on your app<br>%s<br><br><a href=\"%s\">View Error</a>", obj.Name, input.Group.Event, errorURL)
+	description := "The following error is being thrown on your app"
+	subjectLine := fmt.Sprintf("%s: %s", obj.Name, input.Group.Event)
+	if input.Regression {
+		description = "The following resolved error is being thrown on your app again"
+		subjectLine = fmt.Sprintf("%s: Regressed %s", obj.Name, input.Group.Event)
+	}
+
+	message := fmt.Sprintf("<b>%s</b><br>%s<br>%s<br><br><a href=\"%s\">View Error</a>", obj.Name, description, input.Group.Event, errorURL)
 	if input.SessionSecureID == "" || input.SessionExcluded {
 		message += " (No recorded session)"
 	} else {
@@ -2002,12 +2100,14 @@ func (obj *ErrorAlert) SendAlerts(ctx context.Context, db *gorm.DB, mailClient *
 	}
 
 	for _, email := range emailsToNotify {
-		if err := Email.SendAlertEmail(ctx, mailClient, *email, message, "Errors", fmt.Sprintf("%s: %s", obj.Name, input.Group.Event)); err != nil {
+		if err := Email.SendAlertEmail(ctx, mailClient, *email, message, "Errors", subjectLine); err != nil {
 			log.WithContext(ctx).Error(err)
 		}
 	}
 }
 
+// SendAlertFeedback notifies the slack channels of an error alert of user feedback.
+// The caller checks the alert silences, see GetErrorFeedbackAlertSilenceMatch.
 func (obj *ErrorAlert) SendAlertFeedback(ctx context.Context, db *gorm.DB, mailClient *sendgrid.Client, input *SendSlackAlertInput) {
 	obj.Type = ptr.String(AlertType.ERROR_FEEDBACK)
 	if err := obj.sendSlackAlert(ctx, db, obj.ID, input); err != nil {
@@ -2105,6 +2205,8 @@ type SessionAlertEvent struct {
 	SentAt          time.Time
 }
 
+// SendAlerts notifies the slack channels and emails of a session alert.
+// The caller checks the alert silences, see GetSessionAlertSilenceMatch.
 func (obj *SessionAlert) SendAlerts(ctx context.Context, db *gorm.DB, mailClient *sendgrid.Client, input *SendSlackAlertInput) {
 	defer func() {
 		db.Create(&SessionAlertEvent{
@@ -2202,9 +2304,316 @@ type LogAlert struct {
 	Alert
 	Query          string
 	BelowThreshold bool
+	AnomalyThresholdSettings
+	AlertEvaluationSettings
 	AlertIntegrations
 }
 
+// TraceAlert alerts on an aggregate of the traces matching Query, such as the p95 Duration or the error rate.
+type TraceAlert struct {
+	Model
+	Alert
+	Query          string
+	Column         modelInputs.TracesMetricColumn
+	Aggregator     modelInputs.MetricAggregator
+	Threshold      float64
+	BelowThreshold bool
+	// GroupBy lists the trace attributes the alert is evaluated for separately, e.g. one evaluation per service_name
+	GroupBy pq.StringArray `gorm:"type:text[]"`
+	AlertEvaluationSettings
+	AlertIntegrations
+}
+
+// AlertEvaluationSettings configure when log alerts, metric monitors and trace alerts notify.
+type AlertEvaluationSettings struct {
+	// PendingEvaluations is the number of consecutive evaluations meeting the condition before the alert fires
+	PendingEvaluations int
+	// RenotifyInterval is the number of seconds between notifications while the alert is firing, or 0 to notify once
+	RenotifyInterval int
+}
+
+// AnomalyThresholdSettings configure log and error alerts that fire when their count deviates from a seasonal baseline
+// rather than crossing a constant CountThreshold.
+type AnomalyThresholdSettings struct {
+	ThresholdType modelInputs.ThresholdType `gorm:"default:CONSTANT"`
+	// AnomalyDeviations is the number of standard deviations from the baseline at which the alert fires
+	AnomalyDeviations float64 `gorm:"default:3"`
+}
+
+// AlertStatus is the current state of an alert, identified by its AlertType and AlertID.
+// Grouped alerts have a status per group, identified by its GroupKey.
+type AlertStatus struct {
+	Model
+	AlertType string                 `gorm:"uniqueIndex:idx_alert_status_alert;not null"`
+	AlertID   int                    `gorm:"uniqueIndex:idx_alert_status_alert;not null"`
+	GroupKey  string                 `gorm:"uniqueIndex:idx_alert_status_alert;not null;default:''"`
+	State     modelInputs.AlertState `gorm:"default:OK"`
+	// PendingCount is the number of consecutive evaluations that met the condition while the alert was not firing
+	PendingCount   int
+	LastNotifiedAt *time.Time
+	// Version is incremented on every evaluation so that concurrent evaluations notify at most once
+	Version int
+}
+
+// AlertEvaluation is an evaluation of an alert, kept as the alert's history.
+type AlertEvaluation struct {
+	Model
+	AlertType string `gorm:"index:idx_alert_evaluation_alert"`
+	AlertID   int    `gorm:"index:idx_alert_evaluation_alert"`
+	GroupKey  string
+	State     modelInputs.AlertState
+	Value     float64
+	Threshold float64
+	// Notified is set when the evaluation notified the alert's channels
+	Notified bool
+}
+
+// Next returns the status of an alert after an evaluation, and whether the evaluation notifies the alert's channels.
+// Alerts are pending until the condition was met for PendingEvaluations consecutive evaluations,
+// notify every RenotifyInterval while firing, and notify once more when they resolve.
+func (status AlertStatus) Next(alerting bool, settings AlertEvaluationSettings, now time.Time) (AlertStatus, bool) {
+	next := status
+	if !alerting {
+		next.PendingCount = 0
+		if status.State == modelInputs.AlertStateFiring {
+			next.State = modelInputs.AlertStateResolved
+			next.LastNotifiedAt = &now
+			return next, true
+		}
+		next.State = modelInputs.AlertStateOk
+		return next, false
+	}
+
+	if status.State == modelInputs.AlertStateFiring {
+		renotifyInterval := time.Duration(settings.RenotifyInterval) * time.Second
+		if renotifyInterval > 0 && (status.LastNotifiedAt == nil || now.Sub(*status.LastNotifiedAt) >= renotifyInterval) {
+			next.LastNotifiedAt = &now
+			return next, true
+		}
+		return next, false
+	}
+
+	next.PendingCount = status.PendingCount + 1
+	if next.PendingCount < settings.PendingEvaluations {
+		next.State = modelInputs.AlertStatePending
+		return next, false
+	}
+	next.State = modelInputs.AlertStateFiring
+	next.LastNotifiedAt = &now
+	return next, true
+}
+
+// AlertSilence mutes the notifications of the alerts of a project between StartDate and EndDate,
+// such as during a deploy or an incident. A notification is silenced when it matches every non-empty filter.
+type AlertSilence struct {
+	Model
+	ProjectID        int `gorm:"index;not null"`
+	Reason           string
+	StartDate        time.Time
+	EndDate          time.Time      `gorm:"index"`
+	Environments     pq.StringArray `gorm:"type:text[]"`
+	ServiceNames     pq.StringArray `gorm:"type:text[]"`
+	AlertNames       pq.StringArray `gorm:"type:text[]"`
+	CreatedByAdminID int
+}
+
+// SilencedAlertNotification records a notification that was not sent because of an AlertSilence.
+type SilencedAlertNotification struct {
+	Model
+	ProjectID      int `gorm:"index"`
+	AlertSilenceID int
+	AlertType      string
+	AlertID        int
+	AlertName      string
+	Environment    string
+	ServiceName    string
+	// Destinations describes the channels that were not notified, e.g. "slack, email"
+	Destinations string
+}
+
+// AllAlertDestinations describes the channels of alerts that notify every destination from a single code path.
+const AllAlertDestinations = "slack, email, webhook, discord, pagerduty, opsgenie, microsoft teams, zapier"
+
+// AlertSilenceMatch describes an alert notification to match against the silences of its project.
+// Environment and ServiceName are empty when the notification is not specific to one.
+type AlertSilenceMatch struct {
+	ProjectID   int
+	AlertType   string
+	AlertID     int
+	AlertName   string
+	Environment string
+	ServiceName string
+}
+
+// Matches returns whether the silence is active at the given time and mutes the notification.
+func (silence *AlertSilence) Matches(match AlertSilenceMatch, now time.Time) bool {
+	if silence.ProjectID != match.ProjectID || now.Before(silence.StartDate) || !now.Before(silence.EndDate) {
+		return false
+	}
+	matchesFilter := func(filter []string, value string) bool {
+		if len(filter) == 0 {
+			return true
+		}
+		for _, f := range filter {
+			if f == value {
+				return true
+			}
+		}
+		return false
+	}
+	return matchesFilter(silence.Environments, match.Environment) &&
+		matchesFilter(silence.ServiceNames, match.ServiceName) &&
+		matchesFilter(silence.AlertNames, match.AlertName)
+}
+
+// GetAlertSilence returns the active silence of the project muting the notification, or nil if it should be sent.
+func GetAlertSilence(ctx context.Context, db *gorm.DB, match AlertSilenceMatch) (*AlertSilence, error) {
+	now := time.Now()
+	var silences []*AlertSilence
+	if err := db.WithContext(ctx).Model(&AlertSilence{}).
+		Where("project_id = ?", match.ProjectID).
+		Where("start_date <= ? AND end_date > ?", now, now).
+		Order("id").
+		Find(&silences).Error; err != nil {
+		return nil, e.Wrap(err, "error querying alert silences")
+	}
+	for _, silence := range silences {
+		if silence.Matches(match, now) {
+			return silence, nil
+		}
+	}
+	return nil, nil
+}
+
+// IsAlertSilenced returns whether the notification is muted by an active silence,
+// recording the suppressed notification so that it can be reviewed afterwards.
+// Notifications are sent if the silences cannot be queried.
+func IsAlertSilenced(ctx context.Context, db *gorm.DB, match AlertSilenceMatch, destinations string) bool {
+	silence, err := GetAlertSilence(ctx, db, match)
+	if err != nil {
+		log.WithContext(ctx).WithField("project_id", match.ProjectID).Error(err)
+		return false
+	}
+	if silence == nil {
+		return false
+	}
+
+	log.WithContext(ctx).WithFields(log.Fields{
+		"project_id":       match.ProjectID,
+		"alert_type":       match.AlertType,
+		"alert_id":         match.AlertID,
+		"alert_silence_id": silence.ID,
+	}).Info("alert notification silenced")
+	if err := db.WithContext(ctx).Create(&SilencedAlertNotification{
+		ProjectID:      match.ProjectID,
+		AlertSilenceID: silence.ID,
+		AlertType:      match.AlertType,
+		AlertID:        match.AlertID,
+		AlertName:      match.AlertName,
+		Environment:    match.Environment,
+		ServiceName:    match.ServiceName,
+		Destinations:   destinations,
+	}).Error; err != nil {
+		log.WithContext(ctx).Error(e.Wrap(err, "error recording silenced alert notification"))
+	}
+	return true
+}
+
+// GetQueryTermValue returns the value that a query requires the key to have, such as `api` for the service_name
+// of `service_name:api level:error`, or an empty string if the query does not restrict the key to a single value.
+func GetQueryTermValue(query string, key string) string {
+	expr, err := queryparser.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+	for _, conjunct := range queryparser.Conjuncts(expr) {
+		if term, ok := conjunct.(*queryparser.TermExpr); ok && term.Key == key && term.Op == queryparser.OpEqual && !term.HasWildcard() {
+			return term.Value
+		}
+	}
+	return ""
+}
+
+// GetErrorAlertSilenceMatch describes the notifications of an error alert for an error object,
+// which are specific to the environment and service of the error.
+func GetErrorAlertSilenceMatch(alert *ErrorAlert, errorObject *ErrorObject) AlertSilenceMatch {
+	alertType := AlertType.ERROR
+	if alert.IsRegressionAlert() {
+		alertType = AlertType.ERROR_REGRESSION
+	}
+	return AlertSilenceMatch{
+		ProjectID:   alert.ProjectID,
+		AlertType:   alertType,
+		AlertID:     alert.ID,
+		AlertName:   alert.Name,
+		Environment: errorObject.Environment,
+		ServiceName: errorObject.ServiceName,
+	}
+}
+
+// GetErrorFeedbackAlertSilenceMatch describes the feedback notifications of an error alert for a session,
+// which are specific to the environment of the session.
+func GetErrorFeedbackAlertSilenceMatch(alert *ErrorAlert, session *Session) AlertSilenceMatch {
+	return AlertSilenceMatch{
+		ProjectID:   alert.ProjectID,
+		AlertType:   AlertType.ERROR_FEEDBACK,
+		AlertID:     alert.ID,
+		AlertName:   alert.Name,
+		Environment: session.Environment,
+	}
+}
+
+// GetSessionAlertSilenceMatch describes the notifications of a session alert for a session,
+// which are specific to the environment of the session.
+func GetSessionAlertSilenceMatch(alert *SessionAlert, session *Session) AlertSilenceMatch {
+	return AlertSilenceMatch{
+		ProjectID:   alert.ProjectID,
+		AlertType:   ptr.ToString(alert.Type),
+		AlertID:     alert.ID,
+		AlertName:   alert.Name,
+		Environment: session.Environment,
+	}
+}
+
+// GetLogAlertSilenceMatch describes the notifications of a log alert, which are specific to
+// an environment or a service when its query is.
+func GetLogAlertSilenceMatch(alert *LogAlert) AlertSilenceMatch {
+	return AlertSilenceMatch{
+		ProjectID:   alert.ProjectID,
+		AlertType:   AlertType.LOG,
+		AlertID:     alert.ID,
+		AlertName:   alert.Name,
+		Environment: GetQueryTermValue(alert.Query, "environment"),
+		ServiceName: GetQueryTermValue(alert.Query, "service_name"),
+	}
+}
+
+// GetTraceAlertSilenceMatch describes the notifications of a group of a trace alert, which are specific to
+// an environment or a service when the query of the group is.
+func GetTraceAlertSilenceMatch(alert *TraceAlert, groupKey string) AlertSilenceMatch {
+	query := alert.GetGroupQuery(groupKey)
+	return AlertSilenceMatch{
+		ProjectID:   alert.ProjectID,
+		AlertType:   AlertType.TRACE,
+		AlertID:     alert.ID,
+		AlertName:   alert.Name,
+		Environment: GetQueryTermValue(query, "environment"),
+		ServiceName: GetQueryTermValue(query, "service_name"),
+	}
+}
+
+// WebhookDelivery records an attempt to send an alert to a WebhookDestination of a project.
+type WebhookDelivery struct {
+	Model
+	ProjectID int `gorm:"index"`
+	URL       string
+	Event     string
+	// StatusCode is 0 when the request could not be sent
+	StatusCode int
+	Error      string
+	DurationMs int
+}
+
 type LogAlertEvent struct {
 	ID         int64     `gorm:"primary_key;type:bigserial" json:"id" deep:"-"`
 	LogAlertID int       `gorm:"index:idx_log_alert_event"`
@@ -2223,6 +2632,66 @@ func GetLogAlertURL(projectId int, query string, startDate time.Time, endDate ti
 		projectId, queryStr, startDateStr, endDateStr)
 }
 
+func GetTraceAlertURL(projectId int, query string, startDate time.Time, endDate time.Time) string {
+	queryStr := url.QueryEscape(query)
+	startDateStr := url.QueryEscape(startDate.Format("2006-01-02T15:04:05.000Z"))
+	endDateStr := url.QueryEscape(endDate.Format("2006-01-02T15:04:05.000Z"))
+	frontendURL := os.Getenv("FRONTEND_URI")
+	return fmt.Sprintf("%s/%d/traces?query=%s&start_date=%s&end_date=%s", frontendURL,
+		projectId, queryStr, startDateStr, endDateStr)
+}
+
+// GetTraceAlertGroupKey returns the key of the group of a grouped trace alert as a query matching the group,
+// such as `service_name:"api"`, or an empty key for ungrouped alerts.
+func GetTraceAlertGroupKey(groupBy []string, group []string) string {
+	var terms []string
+	for idx, key := range groupBy {
+		if idx >= len(group) {
+			break
+		}
+		term := &queryparser.TermExpr{Key: key, Op: queryparser.OpEqual, Value: group[idx], Quoted: true}
+		terms = append(terms, term.String())
+	}
+	return strings.Join(terms, " ")
+}
+
+// GetGroupQuery returns the query of the traces in the group of the alert with the given key.
+func (obj *TraceAlert) GetGroupQuery(groupKey string) string {
+	switch {
+	case groupKey == "":
+		return obj.Query
+	case strings.TrimSpace(obj.Query) == "":
+		return groupKey
+	}
+	return fmt.Sprintf("(%s) %s", obj.Query, groupKey)
+}
+
+// GetMetricName describes the value the alert is evaluated on, such as `P95 Duration` or `Error rate`.
+func (obj *TraceAlert) GetMetricName() string {
+	switch {
+	case obj.Aggregator == modelInputs.MetricAggregatorCount:
+		return "Span count"
+	case obj.Column == modelInputs.TracesMetricColumnError && obj.Aggregator == modelInputs.MetricAggregatorAvg:
+		return "Error rate"
+	case obj.Column == modelInputs.TracesMetricColumnError && obj.Aggregator == modelInputs.MetricAggregatorSum:
+		return "Error count"
+	}
+	return fmt.Sprintf("%s %s", obj.Aggregator, obj.Column)
+}
+
+// FormatValue formats a value or threshold of the alert, as a duration for span durations
+// and as a percentage for error rates.
+func (obj *TraceAlert) FormatValue(value float64) string {
+	switch {
+	case obj.Aggregator == modelInputs.MetricAggregatorCount || obj.Aggregator == modelInputs.MetricAggregatorCountDistinctKey:
+	case obj.Column == modelInputs.TracesMetricColumnDuration:
+		return time.Duration(value).Round(time.Microsecond).String()
+	case obj.Column == modelInputs.TracesMetricColumnError && obj.Aggregator != modelInputs.MetricAggregatorSum:
+		return strconv.FormatFloat(math.Round(value*10000)/100, 'f', -1, 64) + "%"
+	}
+	return strconv.FormatFloat(math.Round(value*100)/100, 'f', -1, 64)
+}
+
 func (obj *Alert) GetExcludedEnvironments() ([]*string, error) {
 	if obj == nil {
 		return nil, e.New("empty session alert object for excluded environments")
@@ -2622,6 +3091,8 @@ type SendSlackAlertForLogAlertInput struct {
 	Workspace *Workspace
 	StartDate time.Time
 	EndDate   time.Time
+	// Resolved is set when notifying that the alert condition cleared
+	Resolved bool
 }
 
 func (obj *LogAlert) SendSlackAlert(ctx context.Context, db *gorm.DB, input *SendSlackAlertForLogAlertInput) error {
@@ -2657,9 +3128,16 @@ func (obj *LogAlert) SendSlackAlert(ctx context.Context, db *gorm.DB, input *Sen
 	alertUrl := GetLogAlertURL(obj.ProjectID, obj.Query, input.StartDate, input.EndDate)
 
 	previewText := fmt.Sprintf("%s fired!", obj.Name)
+	header := fmt.Sprintf("*%s* fired!", obj.Name)
+	color := RED_ALERT
+	if input.Resolved {
+		previewText = fmt.Sprintf("%s resolved", obj.Name)
+		header = fmt.Sprintf("*%s* resolved", obj.Name)
+		color = GREEN_ALERT
+	}
 
 	var headerBlockSet []slack.Block
-	headerBlock := slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*%s* fired!", obj.Name), false, false)
+	headerBlock := slack.NewTextBlockObject(slack.MarkdownType, header, false, false)
 	headerBlockSet = append(headerBlockSet, slack.NewSectionBlock(headerBlock, nil, nil))
 
 	var bodyBlockSet []slack.Block
@@ -2683,7 +3161,7 @@ func (obj *LogAlert) SendSlackAlert(ctx context.Context, db *gorm.DB, input *Sen
 	bodyBlockSet = append(bodyBlockSet, slack.NewActionBlock("", actionBlocks...))
 
 	attachment := &slack.Attachment{
-		Color:  RED_ALERT,
+		Color:  color,
 		Blocks: slack.Blocks{BlockSet: bodyBlockSet},
 	}
 
@@ -2722,6 +3200,113 @@ func (obj *LogAlert) SendSlackAlert(ctx context.Context, db *gorm.DB, input *Sen
 	return nil
 }
 
+type SendSlackAlertForTraceAlertInput struct {
+	Body      string
+	Workspace *Workspace
+	StartDate time.Time
+	EndDate   time.Time
+	// Query is the query of the traces that triggered the alert, including the group of grouped alerts
+	Query string
+	// Resolved is set when notifying that the alert condition cleared
+	Resolved bool
+}
+
+func (obj *TraceAlert) SendSlackAlert(ctx context.Context, input *SendSlackAlertForTraceAlertInput) error {
+	if obj == nil {
+		return e.New("trace alert needs to be defined.")
+	}
+	if input.Workspace == nil {
+		return e.New("workspace needs to be defined.")
+	}
+
+	channels, err := obj.GetChannelsToNotify()
+	if err != nil {
+		return e.Wrap(err, "error getting channels to send Slack trace alert")
+	}
+	if len(channels) <= 0 {
+		return nil
+	}
+
+	var slackClient *slack.Client
+	if input.Workspace.SlackAccessToken != nil {
+		slackClient = slack.New(*input.Workspace.SlackAccessToken)
+	}
+
+	alertUrl := GetTraceAlertURL(obj.ProjectID, input.Query, input.StartDate, input.EndDate)
+
+	previewText := fmt.Sprintf("%s fired!", obj.Name)
+	header := fmt.Sprintf("*%s* fired!", obj.Name)
+	color := RED_ALERT
+	if input.Resolved {
+		previewText = fmt.Sprintf("%s resolved", obj.Name)
+		header = fmt.Sprintf("*%s* resolved", obj.Name)
+		color = GREEN_ALERT
+	}
+
+	var headerBlockSet []slack.Block
+	headerBlock := slack.NewTextBlockObject(slack.MarkdownType, header, false, false)
+	headerBlockSet = append(headerBlockSet, slack.NewSectionBlock(headerBlock, nil, nil))
+
+	var bodyBlockSet []slack.Block
+	traceBlock := slack.NewTextBlockObject(slack.MarkdownType, input.Body, false, false)
+
+	var actionBlocks []slack.BlockElement
+	button := slack.NewButtonBlockElement(
+		"",
+		"click",
+		slack.NewTextBlockObject(
+			slack.PlainTextType,
+			"View Traces",
+			false,
+			false,
+		),
+	)
+	button.URL = alertUrl
+	actionBlocks = append(actionBlocks, button)
+
+	bodyBlockSet = append(bodyBlockSet, slack.NewSectionBlock(traceBlock, nil, nil))
+	bodyBlockSet = append(bodyBlockSet, slack.NewActionBlock("", actionBlocks...))
+
+	attachment := &slack.Attachment{
+		Color:  color,
+		Blocks: slack.Blocks{BlockSet: bodyBlockSet},
+	}
+
+	log.WithContext(ctx).Info("Sending Slack Alert for Trace Alert")
+
+	// send message
+	for _, channel := range channels {
+		if channel.WebhookChannel != nil {
+			slackChannelId := *channel.WebhookChannelID
+			slackChannelName := *channel.WebhookChannel
+
+			// The Highlight Slack bot needs to join the channel before it can send a message.
+			// Slack handles a bot trying to join a channel it already is a part of, we don't need to handle it.
+			if slackClient != nil {
+				if strings.Contains(slackChannelName, "#") {
+					_, _, _, err := slackClient.JoinConversation(slackChannelId)
+					if err != nil {
+						log.WithContext(ctx).WithFields(log.Fields{"project_id": obj.ProjectID}).Error(e.Wrap(err, "failed to join slack channel while sending trace alert"))
+					}
+				}
+				_, _, err := slackClient.PostMessage(slackChannelId, slack.MsgOptionText(previewText, false), slack.MsgOptionBlocks(headerBlockSet...), slack.MsgOptionAttachments(*attachment),
+					slack.MsgOptionDisableLinkUnfurl(),  /** Disables showing a preview of any links that are in the Slack message.*/
+					slack.MsgOptionDisableMediaUnfurl(), /** Disables showing a preview of any links that are in the Slack message.*/
+				)
+				if err != nil {
+					log.WithContext(ctx).WithFields(log.Fields{"workspace_id": input.Workspace.ID, "message": previewText}).
+						Error(e.Wrap(err, "error sending slack msg via bot api for trace alert"))
+				}
+
+			} else {
+				log.WithContext(ctx).Printf("Slack Bot Client was not defined for sending trace alert")
+			}
+		}
+	}
+
+	return nil
+}
+
 type SendSlackAlertInput struct {
 	// Workspace is a required parameter
 	Workspace *Workspace
@@ -2733,6 +3318,8 @@ type SendSlackAlertInput struct {
 	UserIdentifier string
 	// UserObject is a required parameter for alerts that relate to a session
 	UserObject JSONB
+	// Environment is an optional parameter for session and SessionFeedback alerts, used to match alert silences
+	Environment string
 	// Group is a required parameter for Error alerts
 	Group *ErrorGroup
 	// ErrorObject is a required parameter for Error alerts
@@ -2743,6 +3330,8 @@ type SendSlackAlertInput struct {
 	ErrorsCount *int64
 	// FirstErrorAlert is a required parameter for Error alerts
 	FirstErrorAlert bool
+	// Regression is an optional parameter for Error alerts, set when a resolved error group reoccurred
+	Regression bool
 	// Project is a required parameter for Error alerts
 	Project *Project
 	// MatchedFields is a required parameter for Track Properties and User Properties alerts
@@ -2802,7 +3391,7 @@ var BLUE_ALERT = "#1e40af"
 
 func getAlertColor(alertType string) string {
 	switch alertType {
-	case AlertType.ERROR, AlertType.RAGE_CLICK, AlertType.ERROR_FEEDBACK:
+	case AlertType.ERROR, AlertType.ERROR_REGRESSION, AlertType.RAGE_CLICK, AlertType.ERROR_FEEDBACK:
 		return RED_ALERT
 	case AlertType.NEW_USER, AlertType.NEW_SESSION:
 		return GREEN_ALERT
@@ -2815,7 +3404,7 @@ func getAlertColor(alertType string) string {
 
 func getPreviewText(alertType string) string {
 	switch alertType {
-	case AlertType.ERROR:
+	case AlertType.ERROR, AlertType.ERROR_REGRESSION:
 		return "Temporary - overwritten"
 	case AlertType.RAGE_CLICK:
 		return "Rage Clicks Alert"
@@ -2918,7 +3507,7 @@ func (obj *Alert) sendSlackAlert(ctx context.Context, db *gorm.DB, alertID int,
 	}
 
 	switch *obj.Type {
-	case AlertType.ERROR:
+	case AlertType.ERROR, AlertType.ERROR_REGRESSION:
 		previewEvent := input.Group.Event
 		if len(input.Group.Event) > 100 {
 			previewEvent = input.Group.Event[:100] + "..."
@@ -2929,7 +3518,10 @@ func (obj *Alert) sendSlackAlert(ctx context.Context, db *gorm.DB, alertID int,
 		// construct Slack message
 		// header
 		var headerBlock *slack.TextBlockObject
-		if input.FirstErrorAlert {
+		if input.Regression {
+			previewText = fmt.Sprintf("Regressed Error Alert: %s", previewEvent)
+			headerBlock = slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*Regressed Error Alert: %d Recent Occurrences 🔁*", *input.ErrorsCount), false, false)
+		} else if input.FirstErrorAlert {
 			previewText = fmt.Sprintf("New Error Alert: %s", previewEvent)
 			headerBlock = slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("*New Error Alert: %d Recent Occurrences ❇️*", *input.ErrorsCount), false, false)
 			attachmentColor = YELLOW_ALERT
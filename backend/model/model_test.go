@@ -1,11 +1,205 @@
 package model
 
 import (
-	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
+
+	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_FromVerboseID(t *testing.T) {
 	id, _ := FromVerboseID("1jdkoe52")
 	assert.Equal(t, 1, id)
 }
+
+func TestAlertStatusNext(t *testing.T) {
+	settings := AlertEvaluationSettings{PendingEvaluations: 2, RenotifyInterval: 60}
+	now := time.Now()
+
+	status, notify := AlertStatus{State: modelInputs.AlertStateOk}.Next(true, settings, now)
+	assert.Equal(t, modelInputs.AlertStatePending, status.State)
+	assert.False(t, notify)
+
+	status, notify = status.Next(true, settings, now)
+	assert.Equal(t, modelInputs.AlertStateFiring, status.State)
+	assert.True(t, notify)
+
+	// firing alerts only notify again after the renotify interval
+	status, notify = status.Next(true, settings, now.Add(30*time.Second))
+	assert.Equal(t, modelInputs.AlertStateFiring, status.State)
+	assert.False(t, notify)
+
+	status, notify = status.Next(true, settings, now.Add(time.Minute))
+	assert.Equal(t, modelInputs.AlertStateFiring, status.State)
+	assert.True(t, notify)
+
+	status, notify = status.Next(false, settings, now.Add(2*time.Minute))
+	assert.Equal(t, modelInputs.AlertStateResolved, status.State)
+	assert.True(t, notify)
+
+	status, notify = status.Next(false, settings, now.Add(3*time.Minute))
+	assert.Equal(t, modelInputs.AlertStateOk, status.State)
+	assert.False(t, notify)
+
+	// pending alerts reset when the condition clears
+	status, _ = status.Next(true, settings, now)
+	status, notify = status.Next(false, settings, now)
+	assert.Equal(t, modelInputs.AlertStateOk, status.State)
+	assert.Equal(t, 0, status.PendingCount)
+	assert.False(t, notify)
+}
+
+func TestAlertStatusNextDefaults(t *testing.T) {
+	now := time.Now()
+
+	status, notify := AlertStatus{}.Next(true, AlertEvaluationSettings{}, now)
+	assert.Equal(t, modelInputs.AlertStateFiring, status.State)
+	assert.True(t, notify)
+
+	// without a renotify interval, firing alerts notify once
+	status, notify = status.Next(true, AlertEvaluationSettings{}, now.Add(time.Hour))
+	assert.Equal(t, modelInputs.AlertStateFiring, status.State)
+	assert.False(t, notify)
+}
+
+func TestTraceAlertGroups(t *testing.T) {
+	groupKey := GetTraceAlertGroupKey([]string{"service_name", "span_name"}, []string{"api", "GET /users"})
+	assert.Equal(t, `service_name:"api" span_name:"GET /users"`, groupKey)
+	assert.Equal(t, "", GetTraceAlertGroupKey(nil, nil))
+
+	alert := TraceAlert{Query: "env:prod OR env:staging"}
+	assert.Equal(t, `(env:prod OR env:staging) service_name:"api" span_name:"GET /users"`, alert.GetGroupQuery(groupKey))
+	assert.Equal(t, "env:prod OR env:staging", alert.GetGroupQuery(""))
+
+	alert = TraceAlert{}
+	assert.Equal(t, groupKey, alert.GetGroupQuery(groupKey))
+}
+
+func TestTraceAlertFormatValue(t *testing.T) {
+	latency := TraceAlert{Column: modelInputs.TracesMetricColumnDuration, Aggregator: modelInputs.MetricAggregatorP95}
+	assert.Equal(t, "P95 Duration", latency.GetMetricName())
+	assert.Equal(t, "812.346ms", latency.FormatValue(812345678))
+
+	errorRate := TraceAlert{Column: modelInputs.TracesMetricColumnError, Aggregator: modelInputs.MetricAggregatorAvg}
+	assert.Equal(t, "Error rate", errorRate.GetMetricName())
+	assert.Equal(t, "2.35%", errorRate.FormatValue(0.02345))
+
+	errorCount := TraceAlert{Column: modelInputs.TracesMetricColumnError, Aggregator: modelInputs.MetricAggregatorSum}
+	assert.Equal(t, "Error count", errorCount.GetMetricName())
+	assert.Equal(t, "12", errorCount.FormatValue(12))
+
+	count := TraceAlert{Column: modelInputs.TracesMetricColumnDuration, Aggregator: modelInputs.MetricAggregatorCount}
+	assert.Equal(t, "Span count", count.GetMetricName())
+	assert.Equal(t, "1500", count.FormatValue(1500))
+}
+
+func TestAlertSilenceMatches(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	silence := AlertSilence{
+		ProjectID:    1,
+		StartDate:    now.Add(-time.Hour),
+		EndDate:      now.Add(time.Hour),
+		Environments: []string{"production"},
+	}
+	match := AlertSilenceMatch{ProjectID: 1, AlertType: AlertType.ERROR, AlertID: 1, AlertName: "Errors", Environment: "production"}
+	assert.True(t, silence.Matches(match, now))
+
+	// silences only apply to their project and time range
+	assert.False(t, silence.Matches(AlertSilenceMatch{ProjectID: 2, Environment: "production"}, now))
+	assert.False(t, silence.Matches(match, now.Add(-2*time.Hour)))
+	assert.False(t, silence.Matches(match, now.Add(time.Hour)))
+
+	// every non-empty filter must match
+	match.Environment = "staging"
+	assert.False(t, silence.Matches(match, now))
+	match.Environment = ""
+	assert.False(t, silence.Matches(match, now))
+
+	silence = AlertSilence{ProjectID: 1, StartDate: now, EndDate: now.Add(time.Hour), ServiceNames: []string{"api"}, AlertNames: []string{"Errors"}}
+	assert.True(t, silence.Matches(AlertSilenceMatch{ProjectID: 1, AlertName: "Errors", ServiceName: "api"}, now))
+	assert.False(t, silence.Matches(AlertSilenceMatch{ProjectID: 1, AlertName: "Errors", ServiceName: "web"}, now))
+	assert.False(t, silence.Matches(AlertSilenceMatch{ProjectID: 1, AlertName: "Latency", ServiceName: "api"}, now))
+}
+
+func TestAlertSilenceMatchFromQuery(t *testing.T) {
+	assert.Equal(t, "api", GetQueryTermValue(`service_name:api level:error`, "service_name"))
+	assert.Equal(t, "", GetQueryTermValue(`service_name:api OR service_name:web`, "service_name"))
+	assert.Equal(t, "", GetQueryTermValue(`service_name:api*`, "service_name"))
+	assert.Equal(t, "", GetQueryTermValue(`level:error`, "service_name"))
+
+	logAlert := &LogAlert{Alert: Alert{ProjectID: 1, Name: "Errors"}, Query: "environment:production level:error"}
+	match := GetLogAlertSilenceMatch(logAlert)
+	assert.Equal(t, "production", match.Environment)
+	assert.Equal(t, "", match.ServiceName)
+
+	traceAlert := &TraceAlert{Alert: Alert{ProjectID: 1, Name: "Latency"}, Query: "environment:production", GroupBy: []string{"service_name"}}
+	match = GetTraceAlertSilenceMatch(traceAlert, GetTraceAlertGroupKey(traceAlert.GroupBy, []string{"api"}))
+	assert.Equal(t, "production", match.Environment)
+	assert.Equal(t, "api", match.ServiceName)
+}
+
+func TestErrorAlertSilenceMatch(t *testing.T) {
+	errorObject := &ErrorObject{Environment: "production", ServiceName: "api"}
+
+	errorAlert := &ErrorAlert{Alert: Alert{ProjectID: 1, Name: "Errors", Type: lo.ToPtr(AlertType.ERROR)}}
+	assert.False(t, errorAlert.IsRegressionAlert())
+	match := GetErrorAlertSilenceMatch(errorAlert, errorObject)
+	assert.Equal(t, AlertType.ERROR, match.AlertType)
+	assert.Equal(t, "production", match.Environment)
+	assert.Equal(t, "api", match.ServiceName)
+
+	// alerts created before alert types were stored are error alerts
+	assert.False(t, (&ErrorAlert{}).IsRegressionAlert())
+
+	regressionAlert := &ErrorAlert{Alert: Alert{ProjectID: 1, Name: "Regressions", Type: lo.ToPtr(AlertType.ERROR_REGRESSION)}}
+	assert.True(t, regressionAlert.IsRegressionAlert())
+	assert.Equal(t, AlertType.ERROR_REGRESSION, GetErrorAlertSilenceMatch(regressionAlert, errorObject).AlertType)
+}
+
+func TestSessionAlertSilenceMatch(t *testing.T) {
+	session := &Session{Environment: "production"}
+
+	sessionAlert := &SessionAlert{Model: Model{ID: 2}, Alert: Alert{ProjectID: 1, Name: "New users", Type: lo.ToPtr(AlertType.NEW_USER)}}
+	match := GetSessionAlertSilenceMatch(sessionAlert, session)
+	assert.Equal(t, AlertSilenceMatch{ProjectID: 1, AlertType: AlertType.NEW_USER, AlertID: 2, AlertName: "New users", Environment: "production"}, match)
+
+	errorAlert := &ErrorAlert{Model: Model{ID: 3}, Alert: Alert{ProjectID: 1, Name: "Errors", Type: lo.ToPtr(AlertType.ERROR)}}
+	match = GetErrorFeedbackAlertSilenceMatch(errorAlert, session)
+	assert.Equal(t, AlertType.ERROR_FEEDBACK, match.AlertType)
+	assert.Equal(t, 3, match.AlertID)
+	assert.Equal(t, "production", match.Environment)
+}
+
+func TestAlertIntegrationsKeepCredentials(t *testing.T) {
+	existing := AlertIntegrations{
+		WebhookDestinations:    WebhookDestinations{{URL: "https://example.com/hook", Secret: lo.ToPtr("secret")}},
+		PagerDutyServices:      PagerDutyServices{{Name: "backend", RoutingKey: "routing-key"}},
+		OpsgenieTeams:          OpsgenieTeams{{Name: "backend", APIKey: "api-key"}},
+		MicrosoftTeamsChannels: MicrosoftTeamsChannels{{Name: "alerts", WebhookURL: "https://teams.example.com"}},
+	}
+	updated := AlertIntegrations{
+		WebhookDestinations: WebhookDestinations{
+			{URL: "https://example.com/hook"},
+			{URL: "https://example.com/other"},
+		},
+		PagerDutyServices:      PagerDutyServices{{Name: "backend"}, {Name: "frontend", RoutingKey: "new-key"}},
+		OpsgenieTeams:          OpsgenieTeams{{Name: "backend", APIKey: "rotated-key"}},
+		MicrosoftTeamsChannels: MicrosoftTeamsChannels{{Name: "alerts"}},
+	}
+	updated.KeepCredentials(existing)
+
+	assert.Equal(t, "secret", *updated.WebhookDestinations[0].Secret)
+	assert.Nil(t, updated.WebhookDestinations[1].Secret)
+	assert.Equal(t, "routing-key", updated.PagerDutyServices[0].RoutingKey)
+	assert.Equal(t, "new-key", updated.PagerDutyServices[1].RoutingKey)
+	assert.Equal(t, "rotated-key", updated.OpsgenieTeams[0].APIKey)
+	assert.Equal(t, "https://teams.example.com", updated.MicrosoftTeamsChannels[0].WebhookURL)
+	assert.True(t, updated.MicrosoftTeamsChannels[0].WebhookURLConfigured())
+
+	// an empty secret removes it
+	updated = AlertIntegrations{WebhookDestinations: WebhookDestinations{{URL: "https://example.com/hook", Secret: lo.ToPtr("")}}}
+	updated.KeepCredentials(existing)
+	assert.False(t, updated.WebhookDestinations[0].SecretConfigured())
+}
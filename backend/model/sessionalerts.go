@@ -29,9 +29,24 @@ func (dc DiscordChannels) Value() (driver.Value, error) {
 	return string(bytes), err
 }
 
+type WebhookHeader struct {
+	Name  string
+	Value string
+}
+
 type WebhookDestination struct {
 	URL           string
 	Authorization *string
+	// Secret signs the body of the requests with HMAC-SHA256 when set
+	Secret  *string
+	Headers []*WebhookHeader
+	// BodyTemplate is a text/template rendered from the alert payload to replace the default JSON body
+	BodyTemplate *string
+}
+
+// SecretConfigured is exposed instead of the secret so that the signing key is never read back.
+func (w *WebhookDestination) SecretConfigured() bool {
+	return w.Secret != nil && *w.Secret != ""
 }
 
 type WebhookDestinations []*WebhookDestination
@@ -51,7 +66,146 @@ func (dc WebhookDestinations) Value() (driver.Value, error) {
 	return string(bytes), err
 }
 
+// PagerDutyService is notified through the Events API v2 so that resolved alerts resolve their incident.
+type PagerDutyService struct {
+	Name string
+	// RoutingKey is the integration key of the Events API v2 integration of the service
+	RoutingKey string
+}
+
+// RoutingKeyConfigured is exposed instead of the routing key so that the credential is never read back.
+func (s *PagerDutyService) RoutingKeyConfigured() bool {
+	return s.RoutingKey != ""
+}
+
+type PagerDutyServices []*PagerDutyService
+
+// Scan scan value into Jsonb, implements sql.Scanner interface
+func (dc *PagerDutyServices) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, &dc)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (dc PagerDutyServices) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(dc)
+	return string(bytes), err
+}
+
+// OpsgenieTeam is notified through the Alerts API with the API key of an API integration of the team.
+type OpsgenieTeam struct {
+	Name   string
+	APIKey string
+	// Region is "eu" for teams hosted in the EU instance of Opsgenie, and "us" otherwise
+	Region string
+}
+
+// APIKeyConfigured is exposed instead of the API key so that the credential is never read back.
+func (t *OpsgenieTeam) APIKeyConfigured() bool {
+	return t.APIKey != ""
+}
+
+type OpsgenieTeams []*OpsgenieTeam
+
+// Scan scan value into Jsonb, implements sql.Scanner interface
+func (dc *OpsgenieTeams) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, &dc)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (dc OpsgenieTeams) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(dc)
+	return string(bytes), err
+}
+
+// MicrosoftTeamsChannel is notified with adaptive cards through an incoming webhook of the channel.
+type MicrosoftTeamsChannel struct {
+	Name       string
+	WebhookURL string
+}
+
+// WebhookURLConfigured is exposed instead of the webhook URL, which authorizes posting to the channel.
+func (c *MicrosoftTeamsChannel) WebhookURLConfigured() bool {
+	return c.WebhookURL != ""
+}
+
+type MicrosoftTeamsChannels []*MicrosoftTeamsChannel
+
+// Scan scan value into Jsonb, implements sql.Scanner interface
+func (dc *MicrosoftTeamsChannels) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New(fmt.Sprint("Failed to unmarshal JSONB value:", value))
+	}
+	return json.Unmarshal(bytes, &dc)
+}
+
+// Value return json value, implement driver.Valuer interface
+func (dc MicrosoftTeamsChannels) Value() (driver.Value, error) {
+	bytes, err := json.Marshal(dc)
+	return string(bytes), err
+}
+
 type AlertIntegrations struct {
-	DiscordChannelsToNotify DiscordChannels     `gorm:"type:jsonb;default:'[]'" json:"discord_channels_to_notify"`
-	WebhookDestinations     WebhookDestinations `gorm:"type:jsonb;default:'[]'" json:"webhook_destinations"`
+	DiscordChannelsToNotify DiscordChannels        `gorm:"type:jsonb;default:'[]'" json:"discord_channels_to_notify"`
+	WebhookDestinations     WebhookDestinations    `gorm:"type:jsonb;default:'[]'" json:"webhook_destinations"`
+	PagerDutyServices       PagerDutyServices      `gorm:"type:jsonb;default:'[]'" json:"pager_duty_services"`
+	OpsgenieTeams           OpsgenieTeams          `gorm:"type:jsonb;default:'[]'" json:"opsgenie_teams"`
+	MicrosoftTeamsChannels  MicrosoftTeamsChannels `gorm:"type:jsonb;default:'[]'" json:"microsoft_teams_channels"`
+}
+
+// KeepCredentials fills the credentials omitted from updated destinations with the ones of the
+// existing destinations of the same name, or of the same url for webhooks, since credentials are write-only in the API.
+func (ai *AlertIntegrations) KeepCredentials(existing AlertIntegrations) {
+	for _, destination := range ai.WebhookDestinations {
+		if destination.Secret != nil {
+			continue
+		}
+		for _, e := range existing.WebhookDestinations {
+			if e.URL == destination.URL {
+				destination.Secret = e.Secret
+				break
+			}
+		}
+	}
+	for _, service := range ai.PagerDutyServices {
+		if service.RoutingKey != "" {
+			continue
+		}
+		for _, e := range existing.PagerDutyServices {
+			if e.Name == service.Name {
+				service.RoutingKey = e.RoutingKey
+				break
+			}
+		}
+	}
+	for _, team := range ai.OpsgenieTeams {
+		if team.APIKey != "" {
+			continue
+		}
+		for _, e := range existing.OpsgenieTeams {
+			if e.Name == team.Name {
+				team.APIKey = e.APIKey
+				break
+			}
+		}
+	}
+	for _, channel := range ai.MicrosoftTeamsChannels {
+		if channel.WebhookURL != "" {
+			continue
+		}
+		for _, e := range existing.MicrosoftTeamsChannels {
+			if e.Name == channel.Name {
+				channel.WebhookURL = e.WebhookURL
+				break
+			}
+		}
+	}
 }
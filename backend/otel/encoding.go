@@ -0,0 +1,177 @@
+package otel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/klauspost/compress/zstd"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeJSON     = "application/json"
+)
+
+var ErrUnsupportedMediaType = e.New("unsupported otlp media type")
+
+// otlpRequest is implemented by the ExportRequest types of the OTLP signals
+type otlpRequest interface {
+	UnmarshalProto(data []byte) error
+	UnmarshalJSON(data []byte) error
+}
+
+// otlpResponse is implemented by the ExportResponse types of the OTLP signals
+type otlpResponse interface {
+	MarshalProto() ([]byte, error)
+	MarshalJSON() ([]byte, error)
+}
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// getContentType returns the OTLP encoding of the request, defaulting to protobuf when none is set.
+func getContentType(r *http.Request) (string, error) {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return ContentTypeProtobuf, nil
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return "", e.Wrapf(ErrUnsupportedMediaType, "invalid content type %s", header)
+	}
+	switch mediaType {
+	case ContentTypeProtobuf, "application/protobuf":
+		return ContentTypeProtobuf, nil
+	case ContentTypeJSON:
+		return ContentTypeJSON, nil
+	}
+	return "", e.Wrapf(ErrUnsupportedMediaType, "content type %s", mediaType)
+}
+
+// readBody reads the request body, decompressing it according to the `Content-Encoding` header.
+// Requests without the header are checked for a gzip payload since older highlight SDKs
+// send gzip data without setting the encoding.
+func readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, e.Wrap(err, "failed to read body")
+	}
+
+	encoding := r.Header.Get("Content-Encoding")
+	if encoding == "" && bytes.HasPrefix(body, gzipMagic) {
+		encoding = "gzip"
+	}
+
+	switch encoding {
+	case "", "identity":
+		return body, nil
+	case "gzip":
+		gz, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, e.Wrap(err, "invalid gzip format")
+		}
+		output, err := io.ReadAll(gz)
+		if err != nil {
+			return nil, e.Wrap(err, "invalid gzip stream")
+		}
+		return output, nil
+	case "zstd":
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return nil, e.Wrap(err, "invalid zstd format")
+		}
+		defer zr.Close()
+		output, err := io.ReadAll(zr)
+		if err != nil {
+			return nil, e.Wrap(err, "invalid zstd stream")
+		}
+		return output, nil
+	}
+	return nil, e.Errorf("unsupported content encoding %s", encoding)
+}
+
+// decodeRequest unmarshals an OTLP/HTTP request body into req, returning the negotiated content type.
+// On error, the returned status is the http status code to respond with.
+func decodeRequest(r *http.Request, req otlpRequest) (string, int, error) {
+	contentType, err := getContentType(r)
+	if err != nil {
+		return "", http.StatusUnsupportedMediaType, err
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		return contentType, http.StatusBadRequest, err
+	}
+
+	switch contentType {
+	case ContentTypeJSON:
+		err = req.UnmarshalJSON(body)
+	default:
+		err = req.UnmarshalProto(body)
+	}
+	if err != nil {
+		return contentType, http.StatusBadRequest, e.Wrapf(err, "invalid %s body", contentType)
+	}
+	return contentType, http.StatusOK, nil
+}
+
+// writeResponse responds with an OTLP export response in the same encoding as the request.
+func writeResponse(ctx context.Context, w http.ResponseWriter, contentType string, resp otlpResponse) {
+	var body []byte
+	var err error
+	switch contentType {
+	case ContentTypeJSON:
+		body, err = resp.MarshalJSON()
+	default:
+		body, err = resp.MarshalProto()
+	}
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to marshal otel export response")
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(body); err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to write otel export response")
+	}
+}
+
+// writeError responds with an OTLP error, a google.rpc.Status message in the same encoding as the request.
+func writeError(ctx context.Context, w http.ResponseWriter, contentType string, statusCode int, err error) {
+	code := codes.InvalidArgument
+	if statusCode == http.StatusServiceUnavailable {
+		code = codes.Unavailable
+	}
+	msg := status.New(code, err.Error()).Proto()
+
+	var body []byte
+	switch contentType {
+	case ContentTypeJSON:
+		body, err = protojson.Marshal(msg)
+	default:
+		contentType = ContentTypeProtobuf
+		body, err = proto.Marshal(msg)
+	}
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to marshal otel export error")
+		w.WriteHeader(statusCode)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	if _, err := w.Write(body); err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to write otel export error")
+	}
+}
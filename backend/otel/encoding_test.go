@@ -0,0 +1,153 @@
+package otel
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	e "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func newLogsRequest() plogotlp.ExportRequest {
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetStr("hello world")
+	return plogotlp.NewExportRequestFromLogs(logs)
+}
+
+func TestDecodeRequest(t *testing.T) {
+	req := newLogsRequest()
+	protoBody, err := req.MarshalProto()
+	assert.NoError(t, err)
+	jsonBody, err := req.MarshalJSON()
+	assert.NoError(t, err)
+
+	gzipped := bytes.Buffer{}
+	gz := gzip.NewWriter(&gzipped)
+	_, err = gz.Write(protoBody)
+	assert.NoError(t, err)
+	assert.NoError(t, gz.Close())
+
+	zstdWriter, err := zstd.NewWriter(nil)
+	assert.NoError(t, err)
+	zstdBody := zstdWriter.EncodeAll(protoBody, nil)
+
+	for name, tc := range map[string]struct {
+		body                []byte
+		contentType         string
+		contentEncoding     string
+		expectedContentType string
+		expectedStatus      int
+	}{
+		"uncompressed protobuf": {
+			body:                protoBody,
+			contentType:         "application/x-protobuf",
+			expectedContentType: ContentTypeProtobuf,
+			expectedStatus:      http.StatusOK,
+		},
+		"gzip protobuf": {
+			body:                gzipped.Bytes(),
+			contentType:         "application/x-protobuf",
+			contentEncoding:     "gzip",
+			expectedContentType: ContentTypeProtobuf,
+			expectedStatus:      http.StatusOK,
+		},
+		"gzip without encoding header": {
+			body:                gzipped.Bytes(),
+			expectedContentType: ContentTypeProtobuf,
+			expectedStatus:      http.StatusOK,
+		},
+		"zstd protobuf": {
+			body:                zstdBody,
+			contentType:         "application/x-protobuf",
+			contentEncoding:     "zstd",
+			expectedContentType: ContentTypeProtobuf,
+			expectedStatus:      http.StatusOK,
+		},
+		"json": {
+			body:                jsonBody,
+			contentType:         "application/json; charset=utf-8",
+			expectedContentType: ContentTypeJSON,
+			expectedStatus:      http.StatusOK,
+		},
+		"unsupported media type": {
+			body:           jsonBody,
+			contentType:    "text/plain",
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		"unsupported encoding": {
+			body:                protoBody,
+			contentEncoding:     "br",
+			expectedContentType: ContentTypeProtobuf,
+			expectedStatus:      http.StatusBadRequest,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/otel/v1/logs", bytes.NewReader(tc.body))
+			if tc.contentType != "" {
+				r.Header.Set("Content-Type", tc.contentType)
+			}
+			if tc.contentEncoding != "" {
+				r.Header.Set("Content-Encoding", tc.contentEncoding)
+			}
+
+			decoded := plogotlp.NewExportRequest()
+			contentType, status, err := decodeRequest(r, decoded)
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedContentType, contentType)
+			if tc.expectedStatus != http.StatusOK {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, decoded.Logs().LogRecordCount())
+		})
+	}
+}
+
+func TestWriteResponse(t *testing.T) {
+	resp := plogotlp.NewExportResponse()
+	resp.PartialSuccess().SetRejectedLogRecords(2)
+
+	w := httptest.NewRecorder()
+	writeResponse(context.TODO(), w, ContentTypeJSON, resp)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, ContentTypeJSON, w.Header().Get("Content-Type"))
+
+	decoded := plogotlp.NewExportResponse()
+	assert.NoError(t, decoded.UnmarshalJSON(w.Body.Bytes()))
+	assert.Equal(t, int64(2), decoded.PartialSuccess().RejectedLogRecords())
+}
+
+func TestWriteError(t *testing.T) {
+	w := httptest.NewRecorder()
+	writeError(context.TODO(), w, ContentTypeJSON, http.StatusBadRequest, e.New("invalid body"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, ContentTypeJSON, w.Header().Get("Content-Type"))
+
+	decoded := &spb.Status{}
+	assert.NoError(t, protojson.Unmarshal(w.Body.Bytes(), decoded))
+	assert.Equal(t, int32(codes.InvalidArgument), decoded.GetCode())
+	assert.Equal(t, "invalid body", decoded.GetMessage())
+
+	// requests with an unsupported media type are answered with protobuf
+	w = httptest.NewRecorder()
+	writeError(context.TODO(), w, "", http.StatusServiceUnavailable, e.New("unavailable"))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, ContentTypeProtobuf, w.Header().Get("Content-Type"))
+
+	decoded = &spb.Status{}
+	assert.NoError(t, proto.Unmarshal(w.Body.Bytes(), decoded))
+	assert.Equal(t, int32(codes.Unavailable), decoded.GetCode())
+}
@@ -15,10 +15,14 @@ import (
 	e "github.com/pkg/errors"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/baggage"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
 
+const baggageHeaderAttribute = "http.request.header.baggage"
+
 var ExternalHighlightData = e.New("dropping otel data from external highlight instance")
 var fluentProjectPattern = regexp.MustCompile(fmt.Sprintf(`%s=([\S]+)`, highlight.ProjectIDAttribute))
 
@@ -72,12 +76,16 @@ type extractFieldsParams struct {
 	event     *ptrace.SpanEvent
 	scopeLogs *plog.ScopeLogs
 	logRecord *plog.LogRecord
+
+	scopeMetrics *pmetric.ScopeMetrics
+	// attributes of a single metric data point
+	metricAttributes *pcommon.Map
 }
 
 func extractFields(ctx context.Context, params extractFieldsParams) (*extractedFields, error) {
 	fields := newExtractedFields()
 
-	var resourceAttributes, spanAttributes, eventAttributes, scopeAttributes, logAttributes map[string]any
+	var resourceAttributes, spanAttributes, eventAttributes, scopeAttributes, logAttributes, metricAttributes map[string]any
 	if params.resource != nil {
 		resourceAttributes = params.resource.Attributes().AsRaw()
 	}
@@ -121,6 +129,14 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 		scopeAttributes = params.scopeLogs.Scope().Attributes().AsRaw()
 	}
 
+	if params.scopeMetrics != nil {
+		scopeAttributes = params.scopeMetrics.Scope().Attributes().AsRaw()
+	}
+
+	if params.metricAttributes != nil {
+		metricAttributes = params.metricAttributes.AsRaw()
+	}
+
 	if params.logRecord != nil {
 		fields.timestamp = params.logRecord.Timestamp().AsTime()
 		fields.logSeverity = params.logRecord.SeverityText()
@@ -145,6 +161,7 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 		eventAttributes,
 		scopeAttributes,
 		logAttributes,
+		metricAttributes,
 	)
 
 	if val, ok := originalAttrs[highlight.DeprecatedSourceAttribute]; ok {
@@ -205,6 +222,19 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 		delete(fields.attrs, highlight.RequestIDAttribute)
 	}
 
+	// sessions propagated as W3C baggage are recorded by the highlight sdk on spans and resources,
+	// or found in the baggage request header when captured by the http instrumentation of a service
+	if fields.sessionID == "" || fields.requestID == "" {
+		sessionID, requestID := extractBaggage(originalAttrs[highlight.BaggageAttribute], originalAttrs[baggageHeaderAttribute])
+		if fields.sessionID == "" {
+			fields.sessionID = sessionID
+		}
+		if fields.requestID == "" {
+			fields.requestID = requestID
+		}
+	}
+	delete(fields.attrs, highlight.BaggageAttribute)
+
 	if val, ok := fields.attrs[highlight.LogSeverityAttribute]; ok {
 		fields.logSeverity = val
 		delete(fields.attrs, highlight.LogSeverityAttribute)
@@ -292,6 +322,37 @@ func extractFields(ctx context.Context, params extractFieldsParams) (*extractedF
 	return fields, err
 }
 
+// extractBaggage returns the highlight session and request of W3C baggage attribute values,
+// preferring the values found first.
+func extractBaggage(attrs ...any) (sessionID string, requestID string) {
+	var values []any
+	for _, attr := range attrs {
+		switch v := attr.(type) {
+		case string:
+			values = append(values, v)
+		case []any:
+			values = append(values, v...)
+		}
+	}
+	for _, value := range values {
+		str, ok := value.(string)
+		if !ok {
+			continue
+		}
+		bag, err := baggage.Parse(str)
+		if err != nil {
+			continue
+		}
+		if member := bag.Member(highlight.SessionIDAttribute); sessionID == "" && member.Value() != "" {
+			sessionID = member.Value()
+		}
+		if member := bag.Member(highlight.RequestIDAttribute); requestID == "" && member.Value() != "" {
+			requestID = member.Value()
+		}
+	}
+	return
+}
+
 func mergeMaps(maps ...map[string]any) map[string]any {
 	merged := make(map[string]any)
 
@@ -155,6 +155,42 @@ func TestExtractFields_ExtractRequestID(t *testing.T) {
 	assert.Equal(t, fields.attrs, map[string]string{})
 }
 
+func TestExtractFields_ExtractBaggage(t *testing.T) {
+	span := newSpan(map[string]string{
+		baggageHeaderAttribute: "other=value,highlight.session_id=session_abc,highlight.trace_id=cmVxdWVzdA%3D%3D",
+	})
+	fields, err := extractFields(context.TODO(), extractFieldsParams{span: &span})
+	assert.NoError(t, err)
+	assert.Equal(t, fields.sessionID, "session_abc")
+	assert.Equal(t, fields.requestID, "cmVxdWVzdA==")
+
+	span = newSpan(map[string]string{
+		highlight.SessionIDAttribute: "session_def",
+		baggageHeaderAttribute:       "highlight.session_id=session_abc",
+	})
+	fields, err = extractFields(context.TODO(), extractFieldsParams{span: &span})
+	assert.NoError(t, err)
+	assert.Equal(t, fields.sessionID, "session_def")
+
+	// the baggage recorded by the highlight sdk on spans is preferred over the baggage request header
+	span = newSpan(map[string]string{
+		highlight.BaggageAttribute: "highlight.session_id=session_ghi",
+		baggageHeaderAttribute:     "highlight.session_id=session_abc,highlight.trace_id=cmVxdWVzdA%3D%3D",
+	})
+	fields, err = extractFields(context.TODO(), extractFieldsParams{span: &span})
+	assert.NoError(t, err)
+	assert.Equal(t, fields.sessionID, "session_ghi")
+	assert.Equal(t, fields.requestID, "cmVxdWVzdA==")
+	assert.NotContains(t, fields.attrs, highlight.BaggageAttribute)
+
+	resource := newResource(t, map[string]any{
+		highlight.BaggageAttribute: "highlight.session_id=session_jkl",
+	})
+	fields, err = extractFields(context.TODO(), extractFieldsParams{resource: &resource})
+	assert.NoError(t, err)
+	assert.Equal(t, fields.sessionID, "session_jkl")
+}
+
 func TestExtractFields_ExtractMetricEventName(t *testing.T) {
 	resource := newResource(t, map[string]any{
 		highlight.MetricEventName: "metric_name",
@@ -0,0 +1,91 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	// registers the gzip compressor so that clients may compress requests
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
+)
+
+type traceServer struct {
+	handler *Handler
+}
+
+func (s *traceServer) Export(ctx context.Context, req ptraceotlp.ExportRequest) (ptraceotlp.ExportResponse, error) {
+	resp := ptraceotlp.NewExportResponse()
+	rejected, err := s.handler.processTraces(ctx, req.Traces())
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to process otel grpc traces")
+		return resp, status.Error(codes.Unavailable, err.Error())
+	}
+	if rejected > 0 {
+		resp.PartialSuccess().SetRejectedSpans(rejected)
+		resp.PartialSuccess().SetErrorMessage(fmt.Sprintf("rejected %d spans without a valid project", rejected))
+	}
+	return resp, nil
+}
+
+type logsServer struct {
+	handler *Handler
+}
+
+func (s *logsServer) Export(ctx context.Context, req plogotlp.ExportRequest) (plogotlp.ExportResponse, error) {
+	resp := plogotlp.NewExportResponse()
+	rejected, err := s.handler.processLogs(ctx, req.Logs())
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to process otel grpc logs")
+		return resp, status.Error(codes.Unavailable, err.Error())
+	}
+	if rejected > 0 {
+		resp.PartialSuccess().SetRejectedLogRecords(rejected)
+		resp.PartialSuccess().SetErrorMessage(fmt.Sprintf("rejected %d log records without a valid project", rejected))
+	}
+	return resp, nil
+}
+
+type metricsServer struct {
+	handler *Handler
+}
+
+func (s *metricsServer) Export(ctx context.Context, req pmetricotlp.ExportRequest) (pmetricotlp.ExportResponse, error) {
+	resp := pmetricotlp.NewExportResponse()
+	rejected, err := s.handler.processMetrics(ctx, req.Metrics())
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to process otel grpc metrics")
+		return resp, status.Error(codes.Unavailable, err.Error())
+	}
+	if rejected > 0 {
+		resp.PartialSuccess().SetRejectedDataPoints(rejected)
+		resp.PartialSuccess().SetErrorMessage(fmt.Sprintf("rejected %d data points without a valid project", rejected))
+	}
+	return resp, nil
+}
+
+// NewGRPCServer creates a grpc server implementing the OTLP trace, logs and metrics services.
+// The services share the span / log conversion of the OTLP/HTTP handlers.
+func (o *Handler) NewGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	ptraceotlp.RegisterGRPCServer(server, &traceServer{handler: o})
+	plogotlp.RegisterGRPCServer(server, &logsServer{handler: o})
+	pmetricotlp.RegisterGRPCServer(server, &metricsServer{handler: o})
+	return server
+}
+
+// ListenGRPC serves the OTLP/gRPC receiver on the provided port, blocking until the server stops.
+func (o *Handler) ListenGRPC(port string, opts ...grpc.ServerOption) error {
+	lis, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return e.Wrapf(err, "failed to listen for otel grpc on port %s", port)
+	}
+	return o.NewGRPCServer(opts...).Serve(lis)
+}
@@ -0,0 +1,281 @@
+package otel
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	e "github.com/pkg/errors"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	// MetricBucketAttribute is the upper bound of a histogram bucket, following the prometheus `le` label.
+	MetricBucketAttribute = "le"
+	// MetricQuantileAttribute is the quantile of a summary value, following the prometheus `quantile` label.
+	MetricQuantileAttribute = "quantile"
+)
+
+// metricDataPoint is a single value of an OTLP metric.
+// Aggregated data points (histograms, summaries) are expanded into
+// several values with a suffixed name, ie. `http.duration.count`.
+type metricDataPoint struct {
+	name       string
+	value      float64
+	timestamp  time.Time
+	attributes pcommon.Map
+	// extra attributes describing the value within the aggregated data point
+	tags map[string]string
+}
+
+func numberDataPointValue(dp pmetric.NumberDataPoint) float64 {
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeInt:
+		return float64(dp.IntValue())
+	case pmetric.NumberDataPointValueTypeDouble:
+		return dp.DoubleValue()
+	}
+	return 0
+}
+
+type cumulativeBucket struct {
+	le    string
+	count uint64
+}
+
+// getExponentialBuckets converts the buckets of an exponential histogram to cumulative `le` buckets.
+// Bucket i of the positive range holds the values in (base^i, base^(i+1)], where base = 2^(2^-scale),
+// and bucket i of the negative range holds the values in [-base^(i+1), -base^i).
+// Empty buckets are skipped as they do not change the cumulative count.
+func getExponentialBuckets(dp pmetric.ExponentialHistogramDataPoint) []cumulativeBucket {
+	bound := func(index int) float64 {
+		return math.Exp2(float64(index) * math.Exp2(-float64(dp.Scale())))
+	}
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+
+	var buckets []cumulativeBucket
+	var cumulative uint64
+	negative := dp.Negative().BucketCounts().AsRaw()
+	for b := len(negative) - 1; b >= 0; b-- {
+		if negative[b] == 0 {
+			continue
+		}
+		cumulative += negative[b]
+		buckets = append(buckets, cumulativeBucket{le: format(-bound(int(dp.Negative().Offset()) + b)), count: cumulative})
+	}
+	if dp.ZeroCount() > 0 {
+		cumulative += dp.ZeroCount()
+		buckets = append(buckets, cumulativeBucket{le: "0", count: cumulative})
+	}
+	for b, count := range dp.Positive().BucketCounts().AsRaw() {
+		if count == 0 {
+			continue
+		}
+		cumulative += count
+		buckets = append(buckets, cumulativeBucket{le: format(bound(int(dp.Positive().Offset()) + b + 1)), count: cumulative})
+	}
+	return append(buckets, cumulativeBucket{le: "+Inf", count: dp.Count()})
+}
+
+// getMetricTemporality returns the aggregation temporality of sums and histograms, and whether a sum is monotonic.
+// The temporality of gauges and summaries is empty.
+func getMetricTemporality(metric pmetric.Metric) (string, bool) {
+	switch metric.Type() {
+	case pmetric.MetricTypeSum:
+		return metric.Sum().AggregationTemporality().String(), metric.Sum().IsMonotonic()
+	case pmetric.MetricTypeHistogram:
+		return metric.Histogram().AggregationTemporality().String(), false
+	case pmetric.MetricTypeExponentialHistogram:
+		return metric.ExponentialHistogram().AggregationTemporality().String(), false
+	}
+	return "", false
+}
+
+func getMetricDataPoints(metric pmetric.Metric) []*metricDataPoint {
+	var points []*metricDataPoint
+	add := func(name string, value float64, ts pcommon.Timestamp, attributes pcommon.Map, tags map[string]string) {
+		timestamp := ts.AsTime()
+		if ts == 0 {
+			timestamp = time.Now()
+		}
+		points = append(points, &metricDataPoint{
+			name:       name,
+			value:      value,
+			timestamp:  timestamp,
+			attributes: attributes,
+			tags:       tags,
+		})
+	}
+
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			add(metric.Name(), numberDataPointValue(dp), dp.Timestamp(), dp.Attributes(), nil)
+		}
+	case pmetric.MetricTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			add(metric.Name(), numberDataPointValue(dp), dp.Timestamp(), dp.Attributes(), nil)
+		}
+	case pmetric.MetricTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			add(metric.Name()+".count", float64(dp.Count()), dp.Timestamp(), dp.Attributes(), nil)
+			if dp.HasSum() {
+				add(metric.Name()+".sum", dp.Sum(), dp.Timestamp(), dp.Attributes(), nil)
+			}
+			if dp.HasMin() {
+				add(metric.Name()+".min", dp.Min(), dp.Timestamp(), dp.Attributes(), nil)
+			}
+			if dp.HasMax() {
+				add(metric.Name()+".max", dp.Max(), dp.Timestamp(), dp.Attributes(), nil)
+			}
+			// `le` buckets count every value up to their bound, so the OTLP per-bucket counts are accumulated
+			bounds := dp.ExplicitBounds().AsRaw()
+			var cumulative uint64
+			for b, count := range dp.BucketCounts().AsRaw() {
+				cumulative += count
+				le := "+Inf"
+				if b < len(bounds) {
+					le = strconv.FormatFloat(bounds[b], 'f', -1, 64)
+				}
+				add(metric.Name()+".bucket", float64(cumulative), dp.Timestamp(), dp.Attributes(), map[string]string{
+					MetricBucketAttribute: le,
+				})
+			}
+		}
+	case pmetric.MetricTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			add(metric.Name()+".count", float64(dp.Count()), dp.Timestamp(), dp.Attributes(), nil)
+			if dp.HasSum() {
+				add(metric.Name()+".sum", dp.Sum(), dp.Timestamp(), dp.Attributes(), nil)
+			}
+			if dp.HasMin() {
+				add(metric.Name()+".min", dp.Min(), dp.Timestamp(), dp.Attributes(), nil)
+			}
+			if dp.HasMax() {
+				add(metric.Name()+".max", dp.Max(), dp.Timestamp(), dp.Attributes(), nil)
+			}
+			for _, bucket := range getExponentialBuckets(dp) {
+				add(metric.Name()+".bucket", float64(bucket.count), dp.Timestamp(), dp.Attributes(), map[string]string{
+					MetricBucketAttribute: bucket.le,
+				})
+			}
+		}
+	case pmetric.MetricTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			add(metric.Name()+".count", float64(dp.Count()), dp.Timestamp(), dp.Attributes(), nil)
+			add(metric.Name()+".sum", dp.Sum(), dp.Timestamp(), dp.Attributes(), nil)
+			quantiles := dp.QuantileValues()
+			for q := 0; q < quantiles.Len(); q++ {
+				quantile := quantiles.At(q)
+				add(metric.Name(), quantile.Value(), dp.Timestamp(), dp.Attributes(), map[string]string{
+					MetricQuantileAttribute: strconv.FormatFloat(quantile.Quantile(), 'f', -1, 64),
+				})
+			}
+		}
+	}
+	return points
+}
+
+// getMetricRows converts metric data points to metric rows by project,
+// returning the number of data points that were rejected rather than converted.
+func getMetricRows(ctx context.Context, metrics pmetric.Metrics) (map[string][]*clickhouse.MetricRow, int64) {
+	var projectMetrics = make(map[string][]*clickhouse.MetricRow)
+	var rejected int64
+
+	resourceMetrics := metrics.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		resource := resourceMetrics.At(i).Resource()
+		scopeMetrics := resourceMetrics.At(i).ScopeMetrics()
+		for j := 0; j < scopeMetrics.Len(); j++ {
+			scopeMetrics := scopeMetrics.At(j)
+			metrics := scopeMetrics.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+				temporality, isMonotonic := getMetricTemporality(metric)
+				for _, dp := range getMetricDataPoints(metric) {
+					fields, err := extractFields(ctx, extractFieldsParams{
+						resource:         &resource,
+						scopeMetrics:     &scopeMetrics,
+						metricAttributes: &dp.attributes,
+					})
+					if err != nil {
+						lg(ctx, fields).WithError(err).Info("failed to extract fields from metric")
+						rejected++
+						continue
+					}
+					if fields.external {
+						lg(ctx, fields).Info("dropping external metric")
+						rejected++
+						continue
+					}
+
+					for k, v := range dp.tags {
+						fields.attrs[k] = v
+					}
+
+					metricRow := clickhouse.NewMetricRow(dp.timestamp, fields.projectIDInt, dp.name, dp.value).
+						WithSecureSessionId(fields.sessionID).
+						WithServiceName(fields.serviceName).
+						WithServiceVersion(fields.serviceVersion).
+						WithMetricType(metric.Type().String()).
+						WithMetricUnit(metric.Unit()).
+						WithTemporality(temporality, isMonotonic).
+						WithAttributes(fields.attrs)
+					projectMetrics[fields.projectID] = append(projectMetrics[fields.projectID], metricRow)
+				}
+			}
+		}
+	}
+
+	return projectMetrics, rejected
+}
+
+// processMetrics converts otel metric data points to highlight metrics and submits them for processing.
+// It is shared by the OTLP/HTTP and OTLP/gRPC receivers.
+// It returns the number of data points rejected for not belonging to a valid project or being external.
+func (o *Handler) processMetrics(ctx context.Context, metrics pmetric.Metrics) (int64, error) {
+	projectMetrics, rejected := getMetricRows(ctx, metrics)
+	return rejected, o.submitProjectMetrics(ctx, projectMetrics)
+}
+
+func (o *Handler) submitProjectMetrics(ctx context.Context, projectMetrics map[string][]*clickhouse.MetricRow) error {
+	for _, metricRows := range projectMetrics {
+		var messages []*kafkaqueue.Message
+		for _, metricRow := range metricRows {
+			if !o.resolver.IsMetricIngested(ctx, metricRow) {
+				continue
+			}
+			messages = append(messages, &kafkaqueue.Message{
+				Type: kafkaqueue.PushMetricRows,
+				PushMetricRows: &kafkaqueue.PushMetricRowsArgs{
+					MetricRow: metricRow,
+				},
+			})
+		}
+		if len(messages) == 0 {
+			continue
+		}
+
+		err := o.resolver.TracesQueue.Submit(ctx, "", messages...)
+		if err != nil {
+			return e.Wrap(err, "failed to submit otel project metrics to public worker queue")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestGetMetricDataPoints(t *testing.T) {
+	ts := pcommon.NewTimestampFromTime(time.Unix(1690000000, 0))
+
+	gauge := pmetric.NewMetric()
+	gauge.SetName("cpu")
+	dp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(ts)
+	dp.SetDoubleValue(0.5)
+
+	sum := pmetric.NewMetric()
+	sum.SetName("requests")
+	sdp := sum.SetEmptySum().DataPoints().AppendEmpty()
+	sdp.SetTimestamp(ts)
+	sdp.SetIntValue(12)
+
+	histogram := pmetric.NewMetric()
+	histogram.SetName("latency")
+	hdp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(ts)
+	hdp.SetCount(3)
+	hdp.SetSum(30)
+	hdp.ExplicitBounds().FromRaw([]float64{10})
+	hdp.BucketCounts().FromRaw([]uint64{1, 2})
+
+	exponential := pmetric.NewMetric()
+	exponential.SetName("size")
+	edp := exponential.SetEmptyExponentialHistogram().DataPoints().AppendEmpty()
+	edp.SetTimestamp(ts)
+	edp.SetCount(5)
+	edp.SetSum(11)
+	edp.SetScale(0)
+	edp.SetZeroCount(1)
+	// with a scale of 0, buckets 0 to 2 hold the values in (1, 2], (2, 4] and (4, 8]
+	edp.Positive().BucketCounts().FromRaw([]uint64{1, 0, 2})
+	edp.Negative().SetOffset(1)
+	edp.Negative().BucketCounts().FromRaw([]uint64{1})
+
+	summary := pmetric.NewMetric()
+	summary.SetName("gc")
+	qdp := summary.SetEmptySummary().DataPoints().AppendEmpty()
+	qdp.SetTimestamp(ts)
+	qdp.SetCount(4)
+	qdp.SetSum(8)
+	q := qdp.QuantileValues().AppendEmpty()
+	q.SetQuantile(0.99)
+	q.SetValue(3)
+
+	for name, tc := range map[string]struct {
+		metric   pmetric.Metric
+		expected map[string]float64
+		tags     []map[string]string
+		// the values of the tagged data points, in order
+		tagged []float64
+	}{
+		"gauge": {
+			metric:   gauge,
+			expected: map[string]float64{"cpu": 0.5},
+		},
+		"sum": {
+			metric:   sum,
+			expected: map[string]float64{"requests": 12},
+		},
+		"histogram": {
+			metric:   histogram,
+			expected: map[string]float64{"latency.count": 3, "latency.sum": 30, "latency.bucket": 3},
+			tags:     []map[string]string{{MetricBucketAttribute: "10"}, {MetricBucketAttribute: "+Inf"}},
+			tagged:   []float64{1, 3},
+		},
+		"exponential histogram": {
+			metric:   exponential,
+			expected: map[string]float64{"size.count": 5, "size.sum": 11, "size.bucket": 5},
+			tags: []map[string]string{
+				{MetricBucketAttribute: "-2"},
+				{MetricBucketAttribute: "0"},
+				{MetricBucketAttribute: "2"},
+				{MetricBucketAttribute: "8"},
+				{MetricBucketAttribute: "+Inf"},
+			},
+			tagged: []float64{1, 2, 3, 5, 5},
+		},
+		"summary": {
+			metric:   summary,
+			expected: map[string]float64{"gc.count": 4, "gc.sum": 8, "gc": 3},
+			tags:     []map[string]string{{MetricQuantileAttribute: "0.99"}},
+			tagged:   []float64{3},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			points := getMetricDataPoints(tc.metric)
+			values := map[string]float64{}
+			var tags []map[string]string
+			var tagged []float64
+			for _, p := range points {
+				assert.Equal(t, ts.AsTime(), p.timestamp)
+				values[p.name] = p.value
+				if p.tags != nil {
+					tags = append(tags, p.tags)
+					tagged = append(tagged, p.value)
+				}
+			}
+			assert.Equal(t, tc.expected, values)
+			assert.Equal(t, tc.tags, tags)
+			assert.Equal(t, tc.tagged, tagged)
+		})
+	}
+}
+
+func TestGetMetricRows(t *testing.T) {
+	metrics := pmetric.NewMetrics()
+	resourceMetrics := metrics.ResourceMetrics().AppendEmpty()
+	resourceMetrics.Resource().Attributes().PutStr(highlight.ProjectIDAttribute, "1")
+	resourceMetrics.Resource().Attributes().PutStr("service.name", "my_service")
+	metric := resourceMetrics.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("cpu")
+	metric.SetUnit("%")
+	dp := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetDoubleValue(0.5)
+	dp.Attributes().PutStr(highlight.SessionIDAttribute, "abc123")
+	dp.Attributes().PutStr("host", "foo")
+
+	rows, rejected := getMetricRows(context.TODO(), metrics)
+	assert.Equal(t, int64(0), rejected)
+	assert.Len(t, rows["1"], 1)
+
+	row := rows["1"][0]
+	assert.Equal(t, uint32(1), row.ProjectId)
+	assert.Equal(t, "abc123", row.SecureSessionId)
+	assert.Equal(t, "my_service", row.ServiceName)
+	assert.Equal(t, "cpu", row.MetricName)
+	assert.Equal(t, 0.5, row.Value)
+	assert.Equal(t, "Gauge", row.MetricType)
+	assert.Equal(t, "%", row.MetricUnit)
+	assert.Equal(t, "", row.Temporality)
+	assert.Equal(t, map[string]string{"host": "foo"}, row.Attributes)
+}
+
+func TestGetMetricTemporality(t *testing.T) {
+	sum := pmetric.NewMetric()
+	sum.SetEmptySum().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	sum.Sum().SetIsMonotonic(true)
+	temporality, isMonotonic := getMetricTemporality(sum)
+	assert.Equal(t, "Delta", temporality)
+	assert.True(t, isMonotonic)
+
+	histogram := pmetric.NewMetric()
+	histogram.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	temporality, isMonotonic = getMetricTemporality(histogram)
+	assert.Equal(t, "Cumulative", temporality)
+	assert.False(t, isMonotonic)
+
+	gauge := pmetric.NewMetric()
+	gauge.SetEmptyGauge()
+	temporality, _ = getMetricTemporality(gauge)
+	assert.Equal(t, "", temporality)
+}
+
+func TestGetMetricRowsRejected(t *testing.T) {
+	metrics := pmetric.NewMetrics()
+	metric := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("cpu")
+	dps := metric.SetEmptyGauge().DataPoints()
+	dps.AppendEmpty().SetDoubleValue(0.5)
+	dps.AppendEmpty().SetDoubleValue(0.6)
+
+	rows, rejected := getMetricRows(context.TODO(), metrics)
+	assert.Equal(t, int64(2), rejected)
+	assert.Empty(t, rows)
+}
@@ -1,11 +1,9 @@
 package otel
 
 import (
-	"bytes"
-	"compress/gzip"
 	"context"
 	"encoding/json"
-	"io"
+	"fmt"
 	"net/http"
 	"strings"
 	"time"
@@ -24,7 +22,10 @@ import (
 	"github.com/openlyinc/pointy"
 	e "github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/plog/plogotlp"
+	"go.opentelemetry.io/collector/pdata/pmetric/pmetricotlp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 )
@@ -64,6 +65,7 @@ func getBackendError(ctx context.Context, ts time.Time, fields *extractedFields,
 		lg(ctx, fields).Warn("otel received exception with no stacktrace")
 		fields.exceptionStackTrace = ""
 	}
+	causes := getErrorCauses(ctx, fields)
 	fields.exceptionStackTrace = stacktraces.FormatStructureStackTrace(ctx, fields.exceptionStackTrace)
 	payloadBytes, _ := json.Marshal(fields.attrs)
 	err := &model.BackendErrorObjectInput{
@@ -83,6 +85,7 @@ func getBackendError(ctx context.Context, ts time.Time, fields *extractedFields,
 			Name:    fields.serviceName,
 			Version: fields.serviceVersion,
 		},
+		Causes: causes,
 	}
 	if fields.sessionID != "" {
 		return false, err
@@ -93,6 +96,37 @@ func getBackendError(ctx context.Context, ts time.Time, fields *extractedFields,
 	}
 }
 
+// getErrorCauses returns the chain of exceptions of the exception stacktrace, outermost exception first.
+// Stacktraces with a single exception have no causes.
+func getErrorCauses(ctx context.Context, fields *extractedFields) []*model.ErrorCauseInput {
+	causes, err := stacktraces.StructureOTELStackTraceCauses(fields.exceptionStackTrace)
+	if err != nil {
+		lg(ctx, fields).WithError(err).Warn("otel failed to structure stacktrace causes")
+		return nil
+	}
+	if len(causes) < 2 {
+		return nil
+	}
+	var inputs []*model.ErrorCauseInput
+	for _, cause := range causes {
+		frames := cause.Frames
+		if len(frames) > stacktraces.ERROR_STACK_MAX_FRAME_COUNT {
+			frames = frames[:stacktraces.ERROR_STACK_MAX_FRAME_COUNT]
+		}
+		stackTrace, err := json.Marshal(frames)
+		if err != nil {
+			lg(ctx, fields).WithError(err).Warn("otel failed to json stringify stacktrace cause")
+			return nil
+		}
+		inputs = append(inputs, &model.ErrorCauseInput{
+			Type:       cause.Type,
+			Event:      cause.Message,
+			StackTrace: string(stackTrace),
+		})
+	}
+	return inputs
+}
+
 func getMetric(ctx context.Context, ts time.Time, fields *extractedFields, traceID, spanID string) (*model.MetricInput, error) {
 	if fields.metricEventName == "" {
 		return nil, e.New("otel received metric with no name")
@@ -115,35 +149,34 @@ func getMetric(ctx context.Context, ts time.Time, fields *extractedFields, trace
 
 func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid trace logBody")
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	gz, err := gzip.NewReader(bytes.NewReader(body))
+	req := ptraceotlp.NewExportRequest()
+	contentType, status, err := decodeRequest(r, req)
 	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid gzip format for trace")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		log.WithContext(ctx).WithError(err).Error("invalid trace request")
+		writeError(ctx, w, contentType, status, err)
 		return
 	}
 
-	output, err := io.ReadAll(gz)
+	rejected, err := o.processTraces(ctx, req.Traces())
 	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid gzip stream for trace")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		log.WithContext(ctx).WithError(err).Error("failed to process otel traces")
+		writeError(ctx, w, contentType, http.StatusServiceUnavailable, err)
 		return
 	}
 
-	req := ptraceotlp.NewExportRequest()
-	err = req.UnmarshalProto(output)
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid trace protobuf")
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	resp := ptraceotlp.NewExportResponse()
+	if rejected > 0 {
+		resp.PartialSuccess().SetRejectedSpans(rejected)
+		resp.PartialSuccess().SetErrorMessage(fmt.Sprintf("rejected %d spans without a valid project", rejected))
 	}
+	writeResponse(ctx, w, contentType, resp)
+}
 
+// processTraces converts otel spans to highlight traces, logs, errors and metrics and submits them for processing.
+// It is shared by the OTLP/HTTP and OTLP/gRPC receivers.
+// It returns the number of spans rejected for not belonging to a valid project.
+func (o *Handler) processTraces(ctx context.Context, traces ptrace.Traces) (int64, error) {
+	var rejected int64
 	var projectErrors = make(map[string][]*model.BackendErrorObjectInput)
 	var traceErrors = make(map[string][]*model.BackendErrorObjectInput)
 
@@ -152,7 +185,7 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 	var traceSpans = make(map[string][]*clickhouse.TraceRow)
 	var traceMetrics = make(map[string][]*model.MetricInput)
 
-	spans := req.Traces().ResourceSpans()
+	spans := traces.ResourceSpans()
 	for i := 0; i < spans.Len(); i++ {
 		resource := spans.At(i).Resource()
 		scopeScans := spans.At(i).ScopeSpans()
@@ -180,6 +213,7 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 				})
 				if err != nil {
 					lg(ctx, fields).WithError(err).Info("failed to extract fields from span")
+					rejected++
 					continue
 				}
 				traceID := cast(fields.requestID, span.TraceID().String())
@@ -309,11 +343,9 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 					Errors:          []*model.BackendErrorObjectInput{errorObject},
 				}})
 		}
-		err = o.resolver.ProducerQueue.Submit(ctx, sessionID, messages...)
+		err := o.resolver.ProducerQueue.Submit(ctx, sessionID, messages...)
 		if err != nil {
-			log.WithContext(ctx).WithError(err).Error("failed to submit otel session errors to public worker queue")
-			w.WriteHeader(http.StatusServiceUnavailable)
-			return
+			return rejected, e.Wrap(err, "failed to submit otel session errors to public worker queue")
 		}
 	}
 
@@ -332,11 +364,9 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 					Errors:           []*model.BackendErrorObjectInput{errorObject},
 				}})
 		}
-		err = o.resolver.ProducerQueue.Submit(ctx, "", messages...)
+		err := o.resolver.ProducerQueue.Submit(ctx, "", messages...)
 		if err != nil {
-			log.WithContext(ctx).WithError(err).Error("failed to submit otel project errors to public worker queue")
-			w.WriteHeader(http.StatusServiceUnavailable)
-			return
+			return rejected, e.Wrap(err, "failed to submit otel project errors to public worker queue")
 		}
 	}
 
@@ -350,63 +380,56 @@ func (o *Handler) HandleTrace(w http.ResponseWriter, r *http.Request) {
 					Metrics:         []*model.MetricInput{metric},
 				}})
 		}
-		err = o.resolver.ProducerQueue.Submit(ctx, sessionID, messages...)
+		err := o.resolver.ProducerQueue.Submit(ctx, sessionID, messages...)
 		if err != nil {
-			log.WithContext(ctx).WithError(err).Error("failed to submit otel project metrics to public worker queue")
-			w.WriteHeader(http.StatusServiceUnavailable)
-			return
+			return rejected, e.Wrap(err, "failed to submit otel project metrics to public worker queue")
 		}
 	}
 
 	if err := o.submitTraceSpans(ctx, traceSpans); err != nil {
-		log.WithContext(ctx).WithError(err).Error("failed to submit otel project spans")
-		w.WriteHeader(http.StatusServiceUnavailable)
-		return
+		return rejected, e.Wrap(err, "failed to submit otel project spans")
 	}
 
 	if err := o.submitProjectLogs(ctx, projectLogs); err != nil {
-		log.WithContext(ctx).WithError(err).Error("failed to submit otel project logs")
-		w.WriteHeader(http.StatusServiceUnavailable)
-		return
+		return rejected, e.Wrap(err, "failed to submit otel project logs")
 	}
 
-	w.WriteHeader(http.StatusOK)
+	return rejected, nil
 }
 
 func (o *Handler) HandleLog(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid log logBody")
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
-	}
-
-	gz, err := gzip.NewReader(bytes.NewReader(body))
+	req := plogotlp.NewExportRequest()
+	contentType, status, err := decodeRequest(r, req)
 	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid gzip format for log")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		log.WithContext(ctx).WithError(err).Error("invalid log request")
+		writeError(ctx, w, contentType, status, err)
 		return
 	}
 
-	output, err := io.ReadAll(gz)
+	rejected, err := o.processLogs(ctx, req.Logs())
 	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid gzip stream for log")
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		log.WithContext(ctx).WithError(err).Error("failed to process otel logs")
+		writeError(ctx, w, contentType, http.StatusServiceUnavailable, err)
 		return
 	}
 
-	req := plogotlp.NewExportRequest()
-	err = req.UnmarshalProto(output)
-	if err != nil {
-		log.WithContext(ctx).WithError(err).Error("invalid log protobuf")
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+	resp := plogotlp.NewExportResponse()
+	if rejected > 0 {
+		resp.PartialSuccess().SetRejectedLogRecords(rejected)
+		resp.PartialSuccess().SetErrorMessage(fmt.Sprintf("rejected %d log records without a valid project", rejected))
 	}
+	writeResponse(ctx, w, contentType, resp)
+}
 
+// processLogs converts otel log records to highlight logs and submits them for processing.
+// It is shared by the OTLP/HTTP and OTLP/gRPC receivers.
+// It returns the number of log records rejected for not belonging to a valid project.
+func (o *Handler) processLogs(ctx context.Context, logs plog.Logs) (int64, error) {
+	var rejected int64
 	var projectLogs = make(map[string][]*clickhouse.LogRow)
 
-	resourceLogs := req.Logs().ResourceLogs()
+	resourceLogs := logs.ResourceLogs()
 	for i := 0; i < resourceLogs.Len(); i++ {
 		resource := resourceLogs.At(i).Resource()
 		scopeLogs := resourceLogs.At(i).ScopeLogs()
@@ -422,6 +445,7 @@ func (o *Handler) HandleLog(w http.ResponseWriter, r *http.Request) {
 				})
 				if err != nil {
 					lg(ctx, fields).WithError(err).Info("failed to extract fields from log")
+					rejected++
 					continue
 				}
 
@@ -445,28 +469,51 @@ func (o *Handler) HandleLog(w http.ResponseWriter, r *http.Request) {
 					projectLogs[fields.projectID] = append(projectLogs[fields.projectID], logRow)
 				} else {
 					lg(ctx, fields).Errorf("otel log got no project")
+					rejected++
 					continue
 				}
 			}
 		}
 	}
 
-	if err := o.submitProjectLogs(ctx, projectLogs); err != nil {
-		log.WithContext(ctx).WithError(err).Error("failed to submit otel project logs")
-		w.WriteHeader(http.StatusServiceUnavailable)
+	return rejected, o.submitProjectLogs(ctx, projectLogs)
+}
+
+func (o *Handler) HandleMetric(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	req := pmetricotlp.NewExportRequest()
+	contentType, status, err := decodeRequest(r, req)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid metric request")
+		writeError(ctx, w, contentType, status, err)
+		return
+	}
+
+	rejected, err := o.processMetrics(ctx, req.Metrics())
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to process otel metrics")
+		writeError(ctx, w, contentType, http.StatusServiceUnavailable, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
+	resp := pmetricotlp.NewExportResponse()
+	if rejected > 0 {
+		resp.PartialSuccess().SetRejectedDataPoints(rejected)
+		resp.PartialSuccess().SetErrorMessage(fmt.Sprintf("rejected %d data points without a valid project or from an external source", rejected))
+	}
+	writeResponse(ctx, w, contentType, resp)
 }
 
 func (o *Handler) submitProjectLogs(ctx context.Context, projectLogs map[string][]*clickhouse.LogRow) error {
 	for _, logRows := range projectLogs {
-		var messages []*kafkaqueue.Message
+		var ingested []*clickhouse.LogRow
 		for _, logRow := range logRows {
-			if !o.resolver.IsLogIngested(ctx, logRow) {
-				continue
+			if o.resolver.IsLogIngested(ctx, logRow) {
+				ingested = append(ingested, logRow)
 			}
+		}
+		var messages []*kafkaqueue.Message
+		for _, logRow := range o.resolver.TailSampleLogs(ctx, ingested) {
 			messages = append(messages, &kafkaqueue.Message{
 				Type: kafkaqueue.PushLogs,
 				PushLogs: &kafkaqueue.PushLogsArgs{
@@ -483,11 +530,16 @@ func (o *Handler) submitProjectLogs(ctx context.Context, projectLogs map[string]
 
 func (o *Handler) submitTraceSpans(ctx context.Context, traceRows map[string][]*clickhouse.TraceRow) error {
 	for traceID, traceRows := range traceRows {
-		var messages []*kafkaqueue.Message
+		var ingested []*clickhouse.TraceRow
 		for _, traceRow := range traceRows {
-			if !o.resolver.IsTraceIngested(ctx, traceRow) {
-				continue
+			if o.resolver.IsTraceIngested(ctx, traceRow) {
+				ingested = append(ingested, traceRow)
 			}
+		}
+		spans := o.resolver.TailSampleSpans(ctx, ingested)
+
+		var messages []*kafkaqueue.Message
+		for _, traceRow := range spans {
 			messages = append(messages, &kafkaqueue.Message{
 				Type: kafkaqueue.PushTraces,
 				PushTraces: &kafkaqueue.PushTracesArgs{
@@ -509,6 +561,7 @@ func (o *Handler) Listen(r *chi.Mux) {
 	r.Route("/otel/v1", func(r chi.Router) {
 		r.HandleFunc("/traces", o.HandleTrace)
 		r.HandleFunc("/logs", o.HandleLog)
+		r.HandleFunc("/metrics", o.HandleMetric)
 	})
 }
 
@@ -90,6 +90,24 @@ type AdminAndWorkspaceDetails struct {
 	PromoCode                   *string `json:"promo_code"`
 }
 
+type AlertBaselineBucket struct {
+	Timestamp  time.Time `json:"timestamp"`
+	Value      float64   `json:"value"`
+	Expected   float64   `json:"expected"`
+	LowerBound float64   `json:"lower_bound"`
+	UpperBound float64   `json:"upper_bound"`
+}
+
+type AlertSilenceInput struct {
+	ProjectID    int       `json:"project_id"`
+	Reason       string    `json:"reason"`
+	StartDate    time.Time `json:"start_date"`
+	EndDate      time.Time `json:"end_date"`
+	Environments []string  `json:"environments"`
+	ServiceNames []string  `json:"service_names"`
+	AlertNames   []string  `json:"alert_names"`
+}
+
 type AllProjectSettings struct {
 	ID                                int            `json:"id"`
 	VerboseID                         string         `json:"verbose_id"`
@@ -305,6 +323,23 @@ type ErrorGroupTagAggregationBucket struct {
 	Percent  float64 `json:"percent"`
 }
 
+type ErrorGroupingPreview struct {
+	ErrorObjectID      int     `json:"error_object_id"`
+	ErrorGroupID       int     `json:"error_group_id"`
+	Event              string  `json:"event"`
+	RuleIndex          *int    `json:"rule_index"`
+	Fingerprint        *string `json:"fingerprint"`
+	TargetErrorGroupID *int    `json:"target_error_group_id"`
+}
+
+type ErrorGroupingRuleInput struct {
+	MatchType    *string        `json:"match_type"`
+	MatchEvent   *string        `json:"match_event"`
+	MatchFrame   *string        `json:"match_frame"`
+	IgnoreFrames *string        `json:"ignore_frames"`
+	GroupBy      pq.StringArray `json:"group_by"`
+}
+
 type ErrorMetadata struct {
 	ErrorID         int        `json:"error_id"`
 	SessionID       int        `json:"session_id"`
@@ -491,19 +526,26 @@ type Log struct {
 }
 
 type LogAlertInput struct {
-	ProjectID           int                           `json:"project_id"`
-	Name                string                        `json:"name"`
-	CountThreshold      int                           `json:"count_threshold"`
-	BelowThreshold      bool                          `json:"below_threshold"`
-	ThresholdWindow     int                           `json:"threshold_window"`
-	SlackChannels       []*SanitizedSlackChannelInput `json:"slack_channels"`
-	DiscordChannels     []*DiscordChannelInput        `json:"discord_channels"`
-	WebhookDestinations []*WebhookDestinationInput    `json:"webhook_destinations"`
-	Emails              []string                      `json:"emails"`
-	Environments        []string                      `json:"environments"`
-	Disabled            bool                          `json:"disabled"`
-	Default             *bool                         `json:"default"`
-	Query               string                        `json:"query"`
+	ProjectID              int                           `json:"project_id"`
+	Name                   string                        `json:"name"`
+	CountThreshold         int                           `json:"count_threshold"`
+	BelowThreshold         bool                          `json:"below_threshold"`
+	ThresholdWindow        int                           `json:"threshold_window"`
+	SlackChannels          []*SanitizedSlackChannelInput `json:"slack_channels"`
+	DiscordChannels        []*DiscordChannelInput        `json:"discord_channels"`
+	WebhookDestinations    []*WebhookDestinationInput    `json:"webhook_destinations"`
+	Emails                 []string                      `json:"emails"`
+	Environments           []string                      `json:"environments"`
+	Disabled               bool                          `json:"disabled"`
+	Default                *bool                         `json:"default"`
+	Query                  string                        `json:"query"`
+	PendingEvaluations     *int                          `json:"pending_evaluations"`
+	RenotifyInterval       *int                          `json:"renotify_interval"`
+	ThresholdType          *ThresholdType                `json:"threshold_type"`
+	AnomalyDeviations      *float64                      `json:"anomaly_deviations"`
+	PagerDutyServices      []*PagerDutyServiceInput      `json:"pager_duty_services"`
+	OpsgenieTeams          []*OpsgenieTeamInput          `json:"opsgenie_teams"`
+	MicrosoftTeamsChannels []*MicrosoftTeamsChannelInput `json:"microsoft_teams_channels"`
 }
 
 type LogConnection struct {
@@ -563,6 +605,11 @@ type MetricTagFilterInput struct {
 	Value string            `json:"value"`
 }
 
+type MicrosoftTeamsChannelInput struct {
+	Name       string  `json:"name"`
+	WebhookURL *string `json:"webhook_url"`
+}
+
 type NamedCount struct {
 	Name  string `json:"name"`
 	Count int    `json:"count"`
@@ -583,6 +630,12 @@ type OAuthClient struct {
 	AppName   string    `json:"app_name"`
 }
 
+type OpsgenieTeamInput struct {
+	Name   string  `json:"name"`
+	APIKey *string `json:"api_key"`
+	Region *string `json:"region"`
+}
+
 type PageInfo struct {
 	HasNextPage     bool   `json:"hasNextPage"`
 	HasPreviousPage bool   `json:"hasPreviousPage"`
@@ -590,6 +643,11 @@ type PageInfo struct {
 	EndCursor       string `json:"endCursor"`
 }
 
+type PagerDutyServiceInput struct {
+	Name       string  `json:"name"`
+	RoutingKey *string `json:"routing_key"`
+}
+
 type Plan struct {
 	Type         PlanType             `json:"type"`
 	Interval     SubscriptionInterval `json:"interval"`
@@ -628,33 +686,39 @@ type S3File struct {
 }
 
 type Sampling struct {
-	SessionSamplingRate    float64 `json:"session_sampling_rate"`
-	ErrorSamplingRate      float64 `json:"error_sampling_rate"`
-	LogSamplingRate        float64 `json:"log_sampling_rate"`
-	TraceSamplingRate      float64 `json:"trace_sampling_rate"`
-	SessionMinuteRateLimit *int64  `json:"session_minute_rate_limit"`
-	ErrorMinuteRateLimit   *int64  `json:"error_minute_rate_limit"`
-	LogMinuteRateLimit     *int64  `json:"log_minute_rate_limit"`
-	TraceMinuteRateLimit   *int64  `json:"trace_minute_rate_limit"`
-	SessionExclusionQuery  *string `json:"session_exclusion_query"`
-	ErrorExclusionQuery    *string `json:"error_exclusion_query"`
-	LogExclusionQuery      *string `json:"log_exclusion_query"`
-	TraceExclusionQuery    *string `json:"trace_exclusion_query"`
+	SessionSamplingRate       float64                `json:"session_sampling_rate"`
+	ErrorSamplingRate         float64                `json:"error_sampling_rate"`
+	LogSamplingRate           float64                `json:"log_sampling_rate"`
+	TraceSamplingRate         float64                `json:"trace_sampling_rate"`
+	SessionMinuteRateLimit    *int64                 `json:"session_minute_rate_limit"`
+	ErrorMinuteRateLimit      *int64                 `json:"error_minute_rate_limit"`
+	LogMinuteRateLimit        *int64                 `json:"log_minute_rate_limit"`
+	TraceMinuteRateLimit      *int64                 `json:"trace_minute_rate_limit"`
+	SessionExclusionQuery     *string                `json:"session_exclusion_query"`
+	ErrorExclusionQuery       *string                `json:"error_exclusion_query"`
+	LogExclusionQuery         *string                `json:"log_exclusion_query"`
+	TraceExclusionQuery       *string                `json:"trace_exclusion_query"`
+	TraceKeepOnError          bool                   `json:"trace_keep_on_error"`
+	TraceLatencyThresholdMs   *int64                 `json:"trace_latency_threshold_ms"`
+	TraceServiceSamplingRates map[string]interface{} `json:"trace_service_sampling_rates"`
 }
 
 type SamplingInput struct {
-	SessionSamplingRate    *float64 `json:"session_sampling_rate"`
-	ErrorSamplingRate      *float64 `json:"error_sampling_rate"`
-	LogSamplingRate        *float64 `json:"log_sampling_rate"`
-	TraceSamplingRate      *float64 `json:"trace_sampling_rate"`
-	SessionMinuteRateLimit *int64   `json:"session_minute_rate_limit"`
-	ErrorMinuteRateLimit   *int64   `json:"error_minute_rate_limit"`
-	LogMinuteRateLimit     *int64   `json:"log_minute_rate_limit"`
-	TraceMinuteRateLimit   *int64   `json:"trace_minute_rate_limit"`
-	SessionExclusionQuery  *string  `json:"session_exclusion_query"`
-	ErrorExclusionQuery    *string  `json:"error_exclusion_query"`
-	LogExclusionQuery      *string  `json:"log_exclusion_query"`
-	TraceExclusionQuery    *string  `json:"trace_exclusion_query"`
+	SessionSamplingRate       *float64               `json:"session_sampling_rate"`
+	ErrorSamplingRate         *float64               `json:"error_sampling_rate"`
+	LogSamplingRate           *float64               `json:"log_sampling_rate"`
+	TraceSamplingRate         *float64               `json:"trace_sampling_rate"`
+	SessionMinuteRateLimit    *int64                 `json:"session_minute_rate_limit"`
+	ErrorMinuteRateLimit      *int64                 `json:"error_minute_rate_limit"`
+	LogMinuteRateLimit        *int64                 `json:"log_minute_rate_limit"`
+	TraceMinuteRateLimit      *int64                 `json:"trace_minute_rate_limit"`
+	SessionExclusionQuery     *string                `json:"session_exclusion_query"`
+	ErrorExclusionQuery       *string                `json:"error_exclusion_query"`
+	LogExclusionQuery         *string                `json:"log_exclusion_query"`
+	TraceExclusionQuery       *string                `json:"trace_exclusion_query"`
+	TraceKeepOnError          *bool                  `json:"trace_keep_on_error"`
+	TraceLatencyThresholdMs   *int64                 `json:"trace_latency_threshold_ms"`
+	TraceServiceSamplingRates map[string]interface{} `json:"trace_service_sampling_rates"`
 }
 
 type SanitizedAdmin struct {
@@ -729,21 +793,24 @@ type ServiceNode struct {
 }
 
 type SessionAlertInput struct {
-	ProjectID           int                           `json:"project_id"`
-	Name                string                        `json:"name"`
-	CountThreshold      int                           `json:"count_threshold"`
-	ThresholdWindow     int                           `json:"threshold_window"`
-	SlackChannels       []*SanitizedSlackChannelInput `json:"slack_channels"`
-	DiscordChannels     []*DiscordChannelInput        `json:"discord_channels"`
-	WebhookDestinations []*WebhookDestinationInput    `json:"webhook_destinations"`
-	Emails              []string                      `json:"emails"`
-	Environments        []string                      `json:"environments"`
-	Disabled            bool                          `json:"disabled"`
-	Default             *bool                         `json:"default"`
-	Type                SessionAlertType              `json:"type"`
-	UserProperties      []*UserPropertyInput          `json:"user_properties"`
-	ExcludeRules        []string                      `json:"exclude_rules"`
-	TrackProperties     []*TrackPropertyInput         `json:"track_properties"`
+	ProjectID              int                           `json:"project_id"`
+	Name                   string                        `json:"name"`
+	CountThreshold         int                           `json:"count_threshold"`
+	ThresholdWindow        int                           `json:"threshold_window"`
+	SlackChannels          []*SanitizedSlackChannelInput `json:"slack_channels"`
+	DiscordChannels        []*DiscordChannelInput        `json:"discord_channels"`
+	WebhookDestinations    []*WebhookDestinationInput    `json:"webhook_destinations"`
+	Emails                 []string                      `json:"emails"`
+	Environments           []string                      `json:"environments"`
+	Disabled               bool                          `json:"disabled"`
+	Default                *bool                         `json:"default"`
+	Type                   SessionAlertType              `json:"type"`
+	UserProperties         []*UserPropertyInput          `json:"user_properties"`
+	ExcludeRules           []string                      `json:"exclude_rules"`
+	TrackProperties        []*TrackPropertyInput         `json:"track_properties"`
+	PagerDutyServices      []*PagerDutyServiceInput      `json:"pager_duty_services"`
+	OpsgenieTeams          []*OpsgenieTeamInput          `json:"opsgenie_teams"`
+	MicrosoftTeamsChannels []*MicrosoftTeamsChannelInput `json:"microsoft_teams_channels"`
 }
 
 type SessionCommentTagInput struct {
@@ -829,6 +896,28 @@ type Trace struct {
 	Links           []*TraceLink           `json:"links"`
 }
 
+type TraceAlertInput struct {
+	ProjectID              int                           `json:"project_id"`
+	Name                   string                        `json:"name"`
+	Query                  string                        `json:"query"`
+	Column                 TracesMetricColumn            `json:"column"`
+	Aggregator             MetricAggregator              `json:"aggregator"`
+	Threshold              float64                       `json:"threshold"`
+	BelowThreshold         bool                          `json:"below_threshold"`
+	ThresholdWindow        int                           `json:"threshold_window"`
+	GroupBy                []string                      `json:"group_by"`
+	SlackChannels          []*SanitizedSlackChannelInput `json:"slack_channels"`
+	DiscordChannels        []*DiscordChannelInput        `json:"discord_channels"`
+	WebhookDestinations    []*WebhookDestinationInput    `json:"webhook_destinations"`
+	Emails                 []string                      `json:"emails"`
+	Disabled               bool                          `json:"disabled"`
+	PendingEvaluations     *int                          `json:"pending_evaluations"`
+	RenotifyInterval       *int                          `json:"renotify_interval"`
+	PagerDutyServices      []*PagerDutyServiceInput      `json:"pager_duty_services"`
+	OpsgenieTeams          []*OpsgenieTeamInput          `json:"opsgenie_teams"`
+	MicrosoftTeamsChannels []*MicrosoftTeamsChannelInput `json:"microsoft_teams_channels"`
+}
+
 type TraceConnection struct {
 	Edges    []*TraceEdge `json:"edges"`
 	PageInfo *PageInfo    `json:"pageInfo"`
@@ -942,8 +1031,16 @@ type WebSocketEvent struct {
 }
 
 type WebhookDestinationInput struct {
-	URL           string  `json:"url"`
-	Authorization *string `json:"authorization"`
+	URL           string                `json:"url"`
+	Authorization *string               `json:"authorization"`
+	Secret        *string               `json:"secret"`
+	Headers       []*WebhookHeaderInput `json:"headers"`
+	BodyTemplate  *string               `json:"body_template"`
+}
+
+type WebhookHeaderInput struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
 
 type WorkspaceForInviteLink struct {
@@ -955,6 +1052,51 @@ type WorkspaceForInviteLink struct {
 	ExistingAccount bool       `json:"existing_account"`
 }
 
+type AlertState string
+
+const (
+	AlertStateOk       AlertState = "OK"
+	AlertStatePending  AlertState = "PENDING"
+	AlertStateFiring   AlertState = "FIRING"
+	AlertStateResolved AlertState = "RESOLVED"
+)
+
+var AllAlertState = []AlertState{
+	AlertStateOk,
+	AlertStatePending,
+	AlertStateFiring,
+	AlertStateResolved,
+}
+
+func (e AlertState) IsValid() bool {
+	switch e {
+	case AlertStateOk, AlertStatePending, AlertStateFiring, AlertStateResolved:
+		return true
+	}
+	return false
+}
+
+func (e AlertState) String() string {
+	return string(e)
+}
+
+func (e *AlertState) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AlertState(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AlertState", str)
+	}
+	return nil
+}
+
+func (e AlertState) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type DashboardChartType string
 
 const (
@@ -2402,21 +2544,64 @@ func (e SubscriptionInterval) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
+type ThresholdType string
+
+const (
+	ThresholdTypeConstant ThresholdType = "CONSTANT"
+	ThresholdTypeAnomaly  ThresholdType = "ANOMALY"
+)
+
+var AllThresholdType = []ThresholdType{
+	ThresholdTypeConstant,
+	ThresholdTypeAnomaly,
+}
+
+func (e ThresholdType) IsValid() bool {
+	switch e {
+	case ThresholdTypeConstant, ThresholdTypeAnomaly:
+		return true
+	}
+	return false
+}
+
+func (e ThresholdType) String() string {
+	return string(e)
+}
+
+func (e *ThresholdType) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ThresholdType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ThresholdType", str)
+	}
+	return nil
+}
+
+func (e ThresholdType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
 type TracesMetricColumn string
 
 const (
 	TracesMetricColumnDuration    TracesMetricColumn = "Duration"
 	TracesMetricColumnMetricValue TracesMetricColumn = "MetricValue"
+	TracesMetricColumnError       TracesMetricColumn = "Error"
 )
 
 var AllTracesMetricColumn = []TracesMetricColumn{
 	TracesMetricColumnDuration,
 	TracesMetricColumnMetricValue,
+	TracesMetricColumnError,
 }
 
 func (e TracesMetricColumn) IsValid() bool {
 	switch e {
-	case TracesMetricColumnDuration, TracesMetricColumnMetricValue:
+	case TracesMetricColumnDuration, TracesMetricColumnMetricValue, TracesMetricColumnError:
 		return true
 	}
 	return false
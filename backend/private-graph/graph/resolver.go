@@ -63,6 +63,7 @@ import (
 
 	Email "github.com/highlight-run/highlight/backend/email"
 	"github.com/highlight-run/highlight/backend/embeddings"
+	"github.com/highlight-run/highlight/backend/errorgroups"
 	"github.com/highlight-run/highlight/backend/model"
 	"github.com/highlight-run/highlight/backend/pricing"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
@@ -1309,6 +1310,18 @@ func (r *Resolver) validateAdminRole(ctx context.Context, workspaceID int) error
 	return nil
 }
 
+// validateAlertEvaluationSettings checks the evaluation settings of an alert mutation, leaving omitted settings unchecked.
+// An alert fires after at least one evaluation, and a renotify interval of 0 notifies once.
+func validateAlertEvaluationSettings(pendingEvaluations *int, renotifyInterval *int) error {
+	if pendingEvaluations != nil && *pendingEvaluations < 1 {
+		return e.Errorf("pending evaluations must be at least 1, got %d", *pendingEvaluations)
+	}
+	if renotifyInterval != nil && *renotifyInterval < 0 {
+		return e.Errorf("renotify interval must not be negative, got %d", *renotifyInterval)
+	}
+	return nil
+}
+
 // GenerateRandomBytes returns securely generated random bytes.
 // It will return an error if the system's secure random
 // number generator fails to function correctly, in which
@@ -3370,6 +3383,80 @@ func (r *Resolver) FindSimilarErrors(ctx context.Context, query string) ([]*mode
 	return matchedErrorObjects, nil
 }
 
+// getErrorGroupingRules validates the error grouping rules of an input, in the order they are applied.
+func getErrorGroupingRules(inputs []*modelInputs.ErrorGroupingRuleInput) ([]*model.ErrorGroupingRule, error) {
+	var rules []*model.ErrorGroupingRule
+	for idx, input := range inputs {
+		rule := &model.ErrorGroupingRule{
+			MatchType:    input.MatchType,
+			MatchEvent:   input.MatchEvent,
+			MatchFrame:   input.MatchFrame,
+			IgnoreFrames: input.IgnoreFrames,
+			GroupBy:      input.GroupBy,
+		}
+		if err := errorgroups.ValidateGroupingRule(rule); err != nil {
+			return nil, e.Wrapf(err, "invalid error grouping rule %d", idx+1)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// PreviewErrorGroupingRules shows how the latest errors of a project would be grouped by the rules.
+func (r *Resolver) PreviewErrorGroupingRules(ctx context.Context, projectID int, rules []*model.ErrorGroupingRule, count int) ([]*modelInputs.ErrorGroupingPreview, error) {
+	var errorObjects []*model.ErrorObject
+	if err := r.DB.WithContext(ctx).
+		Where(&model.ErrorObject{ProjectID: projectID}).
+		Order("id DESC").
+		Limit(count).
+		Find(&errorObjects).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error objects")
+	}
+
+	previews := errorgroups.PreviewGroupingRules(errorObjects, rules)
+	fingerprints := lo.Uniq(lo.FilterMap(previews, func(preview *modelInputs.ErrorGroupingPreview, _ int) (string, bool) {
+		return lo.FromPtr(preview.Fingerprint), preview.Fingerprint != nil
+	}))
+	if len(fingerprints) == 0 {
+		return previews, nil
+	}
+
+	// errors grouped by a rule fingerprint join the latest error group with that fingerprint
+	var matches []struct {
+		Value        string
+		ErrorGroupID int
+	}
+	if err := r.DB.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (value) value, error_group_id
+		FROM error_fingerprints
+		WHERE project_id = @projectID
+		AND type = @type
+		AND value IN @values
+		AND error_group_id IS NOT NULL
+		ORDER BY value, id DESC`,
+		map[string]interface{}{
+			"projectID": projectID,
+			"type":      model.Fingerprint.CustomRule,
+			"values":    fingerprints,
+		}).
+		Scan(&matches).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error groups matching the rule fingerprints")
+	}
+	errorGroupIDs := map[string]int{}
+	for _, match := range matches {
+		errorGroupIDs[match.Value] = match.ErrorGroupID
+	}
+	for _, preview := range previews {
+		if preview.Fingerprint == nil {
+			continue
+		}
+		if errorGroupID, ok := errorGroupIDs[*preview.Fingerprint]; ok {
+			preview.TargetErrorGroupID = lo.ToPtr(errorGroupID)
+		}
+	}
+	return previews, nil
+}
+
 func (r *Resolver) GetJiraProjects(
 	ctx context.Context,
 	workspace *model.Workspace,
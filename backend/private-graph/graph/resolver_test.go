@@ -550,3 +550,12 @@ func TestGetSlackChannelsFromSlack(t *testing.T) {
 		assert.Greater(t, len(*channels), 0)
 	})
 }
+
+func TestValidateAlertEvaluationSettings(t *testing.T) {
+	assert.NoError(t, validateAlertEvaluationSettings(nil, nil))
+	assert.NoError(t, validateAlertEvaluationSettings(pointy.Int(1), pointy.Int(0)))
+	assert.NoError(t, validateAlertEvaluationSettings(pointy.Int(3), pointy.Int(300)))
+	assert.Error(t, validateAlertEvaluationSettings(pointy.Int(0), nil))
+	assert.Error(t, validateAlertEvaluationSettings(pointy.Int(-1), nil))
+	assert.Error(t, validateAlertEvaluationSettings(nil, pointy.Int(-60)))
+}
@@ -26,6 +26,9 @@ import (
 	"github.com/clearbit/clearbit-go/clearbit"
 	"github.com/highlight-run/highlight/backend/alerts"
 	"github.com/highlight-run/highlight/backend/alerts/integrations/discord"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/microsoftteams"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/opsgenie"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/pagerduty"
 	"github.com/highlight-run/highlight/backend/alerts/integrations/webhook"
 	"github.com/highlight-run/highlight/backend/apolloio"
 	"github.com/highlight-run/highlight/backend/clickhouse"
@@ -88,6 +91,21 @@ func (r *errorAlertResolver) WebhookDestinations(ctx context.Context, obj *model
 	return obj.WebhookDestinations, nil
 }
 
+// PagerDutyServices is the resolver for the PagerDutyServices field.
+func (r *errorAlertResolver) PagerDutyServices(ctx context.Context, obj *model.ErrorAlert) ([]*model.PagerDutyService, error) {
+	return obj.PagerDutyServices, nil
+}
+
+// OpsgenieTeams is the resolver for the OpsgenieTeams field.
+func (r *errorAlertResolver) OpsgenieTeams(ctx context.Context, obj *model.ErrorAlert) ([]*model.OpsgenieTeam, error) {
+	return obj.OpsgenieTeams, nil
+}
+
+// MicrosoftTeamsChannels is the resolver for the MicrosoftTeamsChannels field.
+func (r *errorAlertResolver) MicrosoftTeamsChannels(ctx context.Context, obj *model.ErrorAlert) ([]*model.MicrosoftTeamsChannel, error) {
+	return obj.MicrosoftTeamsChannels, nil
+}
+
 // EmailsToNotify is the resolver for the EmailsToNotify field.
 func (r *errorAlertResolver) EmailsToNotify(ctx context.Context, obj *model.ErrorAlert) ([]*string, error) {
 	return obj.GetEmailsToNotify()
@@ -255,6 +273,21 @@ func (r *logAlertResolver) WebhookDestinations(ctx context.Context, obj *model.L
 	return obj.WebhookDestinations, nil
 }
 
+// PagerDutyServices is the resolver for the PagerDutyServices field.
+func (r *logAlertResolver) PagerDutyServices(ctx context.Context, obj *model.LogAlert) ([]*model.PagerDutyService, error) {
+	return obj.PagerDutyServices, nil
+}
+
+// OpsgenieTeams is the resolver for the OpsgenieTeams field.
+func (r *logAlertResolver) OpsgenieTeams(ctx context.Context, obj *model.LogAlert) ([]*model.OpsgenieTeam, error) {
+	return obj.OpsgenieTeams, nil
+}
+
+// MicrosoftTeamsChannels is the resolver for the MicrosoftTeamsChannels field.
+func (r *logAlertResolver) MicrosoftTeamsChannels(ctx context.Context, obj *model.LogAlert) ([]*model.MicrosoftTeamsChannel, error) {
+	return obj.MicrosoftTeamsChannels, nil
+}
+
 // EmailsToNotify is the resolver for the EmailsToNotify field.
 func (r *logAlertResolver) EmailsToNotify(ctx context.Context, obj *model.LogAlert) ([]string, error) {
 	emails, err := obj.GetEmailsToNotify()
@@ -313,6 +346,21 @@ func (r *metricMonitorResolver) WebhookDestinations(ctx context.Context, obj *mo
 	return obj.WebhookDestinations, nil
 }
 
+// PagerDutyServices is the resolver for the pager_duty_services field.
+func (r *metricMonitorResolver) PagerDutyServices(ctx context.Context, obj *model.MetricMonitor) ([]*model.PagerDutyService, error) {
+	return obj.PagerDutyServices, nil
+}
+
+// OpsgenieTeams is the resolver for the opsgenie_teams field.
+func (r *metricMonitorResolver) OpsgenieTeams(ctx context.Context, obj *model.MetricMonitor) ([]*model.OpsgenieTeam, error) {
+	return obj.OpsgenieTeams, nil
+}
+
+// MicrosoftTeamsChannels is the resolver for the microsoft_teams_channels field.
+func (r *metricMonitorResolver) MicrosoftTeamsChannels(ctx context.Context, obj *model.MetricMonitor) ([]*model.MicrosoftTeamsChannel, error) {
+	return obj.MicrosoftTeamsChannels, nil
+}
+
 // EmailsToNotify is the resolver for the emails_to_notify field.
 func (r *metricMonitorResolver) EmailsToNotify(ctx context.Context, obj *model.MetricMonitor) ([]*string, error) {
 	if obj == nil {
@@ -586,18 +634,21 @@ func (r *mutationResolver) EditProjectSettings(ctx context.Context, projectID in
 	allProjectSettings.FilterSessionsWithoutError = projectFilterSettings.FilterSessionsWithoutError
 	allProjectSettings.AutoResolveStaleErrorsDayInterval = projectFilterSettings.AutoResolveStaleErrorsDayInterval
 	allProjectSettings.Sampling = &modelInputs.Sampling{
-		SessionSamplingRate:    projectFilterSettings.SessionSamplingRate,
-		ErrorSamplingRate:      projectFilterSettings.SessionSamplingRate,
-		LogSamplingRate:        projectFilterSettings.SessionSamplingRate,
-		TraceSamplingRate:      projectFilterSettings.SessionSamplingRate,
-		SessionMinuteRateLimit: projectFilterSettings.SessionMinuteRateLimit,
-		ErrorMinuteRateLimit:   projectFilterSettings.ErrorMinuteRateLimit,
-		LogMinuteRateLimit:     projectFilterSettings.LogMinuteRateLimit,
-		TraceMinuteRateLimit:   projectFilterSettings.TraceMinuteRateLimit,
-		SessionExclusionQuery:  projectFilterSettings.SessionExclusionQuery,
-		ErrorExclusionQuery:    projectFilterSettings.ErrorExclusionQuery,
-		LogExclusionQuery:      projectFilterSettings.LogExclusionQuery,
-		TraceExclusionQuery:    projectFilterSettings.TraceExclusionQuery,
+		SessionSamplingRate:       projectFilterSettings.SessionSamplingRate,
+		ErrorSamplingRate:         projectFilterSettings.SessionSamplingRate,
+		LogSamplingRate:           projectFilterSettings.SessionSamplingRate,
+		TraceSamplingRate:         projectFilterSettings.SessionSamplingRate,
+		SessionMinuteRateLimit:    projectFilterSettings.SessionMinuteRateLimit,
+		ErrorMinuteRateLimit:      projectFilterSettings.ErrorMinuteRateLimit,
+		LogMinuteRateLimit:        projectFilterSettings.LogMinuteRateLimit,
+		TraceMinuteRateLimit:      projectFilterSettings.TraceMinuteRateLimit,
+		SessionExclusionQuery:     projectFilterSettings.SessionExclusionQuery,
+		ErrorExclusionQuery:       projectFilterSettings.ErrorExclusionQuery,
+		LogExclusionQuery:         projectFilterSettings.LogExclusionQuery,
+		TraceExclusionQuery:       projectFilterSettings.TraceExclusionQuery,
+		TraceKeepOnError:          projectFilterSettings.TraceKeepOnError,
+		TraceLatencyThresholdMs:   projectFilterSettings.TraceLatencyThresholdMs,
+		TraceServiceSamplingRates: projectFilterSettings.TraceServiceSamplingRates,
 	}
 
 	return &allProjectSettings, nil
@@ -618,7 +669,7 @@ func (r *mutationResolver) EditWorkspace(ctx context.Context, id int, name *stri
 }
 
 // EditWorkspaceSettings is the resolver for the editWorkspaceSettings field.
-func (r *mutationResolver) EditWorkspaceSettings(ctx context.Context, workspaceID int, aiApplication *bool, aiInsights *bool) (*model.AllWorkspaceSettings, error) {
+func (r *mutationResolver) EditWorkspaceSettings(ctx context.Context, workspaceID int, aiApplication *bool, aiInsights *bool, errorGroupRootCause *bool) (*model.AllWorkspaceSettings, error) {
 	_, err := r.isAdminInWorkspace(ctx, workspaceID)
 	if err != nil {
 		return nil, err
@@ -629,9 +680,15 @@ func (r *mutationResolver) EditWorkspaceSettings(ctx context.Context, workspaceI
 	}
 
 	workspaceSettings := &model.AllWorkspaceSettings{}
-	workspaceSettingsUpdates := map[string]interface{}{
-		"AIApplication": *aiApplication,
-		"AIInsights":    *aiInsights,
+	workspaceSettingsUpdates := map[string]interface{}{}
+	if aiApplication != nil {
+		workspaceSettingsUpdates["AIApplication"] = *aiApplication
+	}
+	if aiInsights != nil {
+		workspaceSettingsUpdates["AIInsights"] = *aiInsights
+	}
+	if errorGroupRootCause != nil {
+		workspaceSettingsUpdates["ErrorGroupRootCause"] = *errorGroupRootCause
 	}
 
 	if err := r.DB.WithContext(ctx).Where(&model.AllWorkspaceSettings{WorkspaceID: workspaceID}).Take(&workspaceSettings).Updates(&workspaceSettingsUpdates).Error; err != nil {
@@ -826,7 +883,7 @@ func (r *mutationResolver) MarkSessionAsViewed(ctx context.Context, secureID str
 }
 
 // UpdateErrorGroupState is the resolver for the updateErrorGroupState field.
-func (r *mutationResolver) UpdateErrorGroupState(ctx context.Context, secureID string, state modelInputs.ErrorState, snoozedUntil *time.Time) (*model.ErrorGroup, error) {
+func (r *mutationResolver) UpdateErrorGroupState(ctx context.Context, secureID string, state modelInputs.ErrorState, snoozedUntil *time.Time, resolveInNextRelease *bool) (*model.ErrorGroup, error) {
 	errorGroup, err := r.canAdminModifyErrorGroup(ctx, secureID)
 	if err != nil {
 		return nil, err
@@ -834,14 +891,51 @@ func (r *mutationResolver) UpdateErrorGroupState(ctx context.Context, secureID s
 	admin, err := r.getCurrentAdmin(ctx)
 
 	updatedErrorGroup, err := r.Store.UpdateErrorGroupStateByAdmin(ctx, *admin, store.UpdateErrorGroupParams{
-		ID:           errorGroup.ID,
-		State:        state,
-		SnoozedUntil: snoozedUntil,
+		ID:                   errorGroup.ID,
+		State:                state,
+		SnoozedUntil:         snoozedUntil,
+		ResolveInNextRelease: lo.FromPtr(resolveInNextRelease),
 	})
 
 	return &updatedErrorGroup, err
 }
 
+// MergeErrorGroups is the resolver for the mergeErrorGroups field.
+func (r *mutationResolver) MergeErrorGroups(ctx context.Context, errorGroupSecureID string, mergedErrorGroupSecureIds []string) (*model.ErrorGroup, error) {
+	errorGroup, err := r.canAdminModifyErrorGroup(ctx, errorGroupSecureID)
+	if err != nil {
+		return nil, err
+	}
+	var mergedErrorGroups []*model.ErrorGroup
+	for _, secureID := range mergedErrorGroupSecureIds {
+		mergedErrorGroup, err := r.canAdminModifyErrorGroup(ctx, secureID)
+		if err != nil {
+			return nil, err
+		}
+		mergedErrorGroups = append(mergedErrorGroups, mergedErrorGroup)
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Store.MergeErrorGroups(ctx, *admin, errorGroup, mergedErrorGroups)
+}
+
+// SplitErrorGroup is the resolver for the splitErrorGroup field.
+func (r *mutationResolver) SplitErrorGroup(ctx context.Context, errorGroupSecureID string, errorObjectIds []int) (*model.ErrorGroup, error) {
+	errorGroup, err := r.canAdminModifyErrorGroup(ctx, errorGroupSecureID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Store.SplitErrorGroup(ctx, *admin, errorGroup, errorObjectIds)
+}
+
 // DeleteProject is the resolver for the deleteProject field.
 func (r *mutationResolver) DeleteProject(ctx context.Context, id int) (*bool, error) {
 	_, err := r.isAdminInProject(ctx, id)
@@ -2478,13 +2572,19 @@ func (r *mutationResolver) SyncSlackIntegration(ctx context.Context, projectID i
 }
 
 // CreateMetricMonitor is the resolver for the createMetricMonitor field.
-func (r *mutationResolver) CreateMetricMonitor(ctx context.Context, projectID int, name string, aggregator modelInputs.MetricAggregator, periodMinutes *int, threshold float64, units *string, metricToMonitor string, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, filters []*modelInputs.MetricTagFilterInput) (*model.MetricMonitor, error) {
+func (r *mutationResolver) CreateMetricMonitor(ctx context.Context, projectID int, name string, aggregator modelInputs.MetricAggregator, periodMinutes *int, threshold float64, units *string, metricToMonitor string, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, filters []*modelInputs.MetricTagFilterInput, pendingEvaluations *int, renotifyInterval *int, pagerDutyServices []*modelInputs.PagerDutyServiceInput, opsgenieTeams []*modelInputs.OpsgenieTeamInput, microsoftTeamsChannels []*modelInputs.MicrosoftTeamsChannelInput) (*model.MetricMonitor, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
 	admin, _ := r.getCurrentAdmin(ctx)
 	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateAlertEvaluationSettings(pendingEvaluations, renotifyInterval); err != nil {
+		return nil, err
+	}
+	if err := webhook.ValidateInput(webhookDestinations); err != nil {
+		return nil, err
+	}
 
 	channelsString, err := r.MarshalSlackChannelsToSanitizedSlackChannels(slackChannels)
 	if err != nil {
@@ -2517,9 +2617,16 @@ func (r *mutationResolver) CreateMetricMonitor(ctx context.Context, projectID in
 		EmailsToNotify:    emailsString,
 		LastAdminToEditID: admin.ID,
 		Filters:           mmFilters,
+		AlertEvaluationSettings: model.AlertEvaluationSettings{
+			PendingEvaluations: lo.FromPtr(pendingEvaluations),
+			RenotifyInterval:   lo.FromPtr(renotifyInterval),
+		},
 		AlertIntegrations: model.AlertIntegrations{
 			DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
 			WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
+			PagerDutyServices:       pagerduty.GQLInputToGo(pagerDutyServices),
+			OpsgenieTeams:           opsgenie.GQLInputToGo(opsgenieTeams),
+			MicrosoftTeamsChannels:  microsoftteams.GQLInputToGo(microsoftTeamsChannels),
 		},
 	}
 
@@ -2543,13 +2650,19 @@ func (r *mutationResolver) CreateMetricMonitor(ctx context.Context, projectID in
 }
 
 // UpdateMetricMonitor is the resolver for the updateMetricMonitor field.
-func (r *mutationResolver) UpdateMetricMonitor(ctx context.Context, metricMonitorID int, projectID int, name *string, aggregator *modelInputs.MetricAggregator, periodMinutes *int, threshold *float64, units *string, metricToMonitor *string, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, disabled *bool, filters []*modelInputs.MetricTagFilterInput) (*model.MetricMonitor, error) {
+func (r *mutationResolver) UpdateMetricMonitor(ctx context.Context, metricMonitorID int, projectID int, name *string, aggregator *modelInputs.MetricAggregator, periodMinutes *int, threshold *float64, units *string, metricToMonitor *string, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, disabled *bool, filters []*modelInputs.MetricTagFilterInput, pendingEvaluations *int, renotifyInterval *int, pagerDutyServices []*modelInputs.PagerDutyServiceInput, opsgenieTeams []*modelInputs.OpsgenieTeamInput, microsoftTeamsChannels []*modelInputs.MicrosoftTeamsChannelInput) (*model.MetricMonitor, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
 	admin, _ := r.getCurrentAdmin(ctx)
 	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
+	if err := validateAlertEvaluationSettings(pendingEvaluations, renotifyInterval); err != nil {
+		return nil, err
+	}
+	if err := webhook.ValidateInput(webhookDestinations); err != nil {
+		return nil, err
+	}
 
 	metricMonitor := &model.MetricMonitor{}
 	if err := r.DB.WithContext(ctx).Where(&model.MetricMonitor{Model: model.Model{ID: metricMonitorID}, ProjectID: projectID}).Find(&metricMonitor).Error; err != nil {
@@ -2585,10 +2698,15 @@ func (r *mutationResolver) UpdateMetricMonitor(ctx context.Context, metricMonito
 		metricMonitor.ChannelsToNotify = channelsString
 	}
 
-	metricMonitor.AlertIntegrations = model.AlertIntegrations{
+	alertIntegrations := model.AlertIntegrations{
 		DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
 		WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
+		PagerDutyServices:       pagerduty.GQLInputToGo(pagerDutyServices),
+		OpsgenieTeams:           opsgenie.GQLInputToGo(opsgenieTeams),
+		MicrosoftTeamsChannels:  microsoftteams.GQLInputToGo(microsoftTeamsChannels),
 	}
+	alertIntegrations.KeepCredentials(metricMonitor.AlertIntegrations)
+	metricMonitor.AlertIntegrations = alertIntegrations
 
 	if emails != nil {
 		emailsString, err := r.MarshalAlertEmails(emails)
@@ -2619,6 +2737,13 @@ func (r *mutationResolver) UpdateMetricMonitor(ctx context.Context, metricMonito
 		metricMonitor.Disabled = disabled
 	}
 
+	if pendingEvaluations != nil {
+		metricMonitor.PendingEvaluations = *pendingEvaluations
+	}
+	if renotifyInterval != nil {
+		metricMonitor.RenotifyInterval = *renotifyInterval
+	}
+
 	if err := r.DB.Save(&metricMonitor).Error; err != nil {
 		return nil, e.Wrap(err, "error updating metric monitor")
 	}
@@ -2639,13 +2764,16 @@ func (r *mutationResolver) UpdateMetricMonitor(ctx context.Context, metricMonito
 }
 
 // CreateErrorAlert is the resolver for the createErrorAlert field.
-func (r *mutationResolver) CreateErrorAlert(ctx context.Context, projectID int, name string, countThreshold int, thresholdWindow int, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, environments []*string, regexGroups []*string, frequency int, defaultArg *bool) (*model.ErrorAlert, error) {
+func (r *mutationResolver) CreateErrorAlert(ctx context.Context, projectID int, name string, countThreshold int, thresholdWindow int, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, environments []*string, regexGroups []*string, frequency int, defaultArg *bool, thresholdType *modelInputs.ThresholdType, anomalyDeviations *float64, pagerDutyServices []*modelInputs.PagerDutyServiceInput, opsgenieTeams []*modelInputs.OpsgenieTeamInput, microsoftTeamsChannels []*modelInputs.MicrosoftTeamsChannelInput, regression *bool) (*model.ErrorAlert, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
 	admin, _ := r.getCurrentAdmin(ctx)
 	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
+	if err := webhook.ValidateInput(webhookDestinations); err != nil {
+		return nil, err
+	}
 
 	envString, err := r.MarshalEnvironments(environments)
 	if err != nil {
@@ -2672,6 +2800,12 @@ func (r *mutationResolver) CreateErrorAlert(ctx context.Context, projectID int,
 		defaultArg = pointy.Bool(false)
 	}
 
+	// regression alerts only notify of resolved error groups that reoccurred
+	alertType := model.AlertType.ERROR
+	if lo.FromPtr(regression) {
+		alertType = model.AlertType.ERROR_REGRESSION
+	}
+
 	newAlert := &model.ErrorAlert{
 		Alert: model.Alert{
 			ProjectID:            projectID,
@@ -2679,7 +2813,7 @@ func (r *mutationResolver) CreateErrorAlert(ctx context.Context, projectID int,
 			ExcludedEnvironments: envString,
 			CountThreshold:       countThreshold,
 			ThresholdWindow:      &thresholdWindow,
-			Type:                 &model.AlertType.ERROR,
+			Type:                 &alertType,
 			ChannelsToNotify:     channelsString,
 			EmailsToNotify:       emailsString,
 			Name:                 name,
@@ -2688,9 +2822,16 @@ func (r *mutationResolver) CreateErrorAlert(ctx context.Context, projectID int,
 			Default:              *defaultArg,
 		},
 		RegexGroups: &regexGroupsString,
+		AnomalyThresholdSettings: model.AnomalyThresholdSettings{
+			ThresholdType:     lo.FromPtr(thresholdType),
+			AnomalyDeviations: lo.FromPtr(anomalyDeviations),
+		},
 		AlertIntegrations: model.AlertIntegrations{
 			DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
 			WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
+			PagerDutyServices:       pagerduty.GQLInputToGo(pagerDutyServices),
+			OpsgenieTeams:           opsgenie.GQLInputToGo(opsgenieTeams),
+			MicrosoftTeamsChannels:  microsoftteams.GQLInputToGo(microsoftTeamsChannels),
 		},
 	}
 
@@ -2714,13 +2855,16 @@ func (r *mutationResolver) CreateErrorAlert(ctx context.Context, projectID int,
 }
 
 // UpdateErrorAlert is the resolver for the updateErrorAlert field.
-func (r *mutationResolver) UpdateErrorAlert(ctx context.Context, projectID int, name *string, errorAlertID int, countThreshold *int, thresholdWindow *int, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, environments []*string, regexGroups []*string, frequency *int, disabled *bool) (*model.ErrorAlert, error) {
+func (r *mutationResolver) UpdateErrorAlert(ctx context.Context, projectID int, name *string, errorAlertID int, countThreshold *int, thresholdWindow *int, slackChannels []*modelInputs.SanitizedSlackChannelInput, discordChannels []*modelInputs.DiscordChannelInput, webhookDestinations []*modelInputs.WebhookDestinationInput, emails []*string, environments []*string, regexGroups []*string, frequency *int, disabled *bool, thresholdType *modelInputs.ThresholdType, anomalyDeviations *float64, pagerDutyServices []*modelInputs.PagerDutyServiceInput, opsgenieTeams []*modelInputs.OpsgenieTeamInput, microsoftTeamsChannels []*modelInputs.MicrosoftTeamsChannelInput) (*model.ErrorAlert, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
 	admin, _ := r.getCurrentAdmin(ctx)
 	workspace, _ := r.GetWorkspace(project.WorkspaceID)
 	if err != nil {
 		return nil, err
 	}
+	if err := webhook.ValidateInput(webhookDestinations); err != nil {
+		return nil, err
+	}
 
 	projectAlert := &model.ErrorAlert{}
 	if err := r.DB.WithContext(ctx).Where(&model.ErrorAlert{Model: model.Model{ID: errorAlertID}}).Find(&projectAlert).Error; err != nil {
@@ -2779,11 +2923,22 @@ func (r *mutationResolver) UpdateErrorAlert(ctx context.Context, projectID int,
 	if disabled != nil {
 		projectAlert.Disabled = disabled
 	}
+	if thresholdType != nil {
+		projectAlert.ThresholdType = *thresholdType
+	}
+	if anomalyDeviations != nil {
+		projectAlert.AnomalyDeviations = *anomalyDeviations
+	}
 
-	projectAlert.AlertIntegrations = model.AlertIntegrations{
+	alertIntegrations := model.AlertIntegrations{
 		DiscordChannelsToNotify: discord.GQLInputToGo(discordChannels),
 		WebhookDestinations:     webhook.GQLInputToGo(webhookDestinations),
+		PagerDutyServices:       pagerduty.GQLInputToGo(pagerDutyServices),
+		OpsgenieTeams:           opsgenie.GQLInputToGo(opsgenieTeams),
+		MicrosoftTeamsChannels:  microsoftteams.GQLInputToGo(microsoftTeamsChannels),
 	}
+	alertIntegrations.KeepCredentials(projectAlert.AlertIntegrations)
+	projectAlert.AlertIntegrations = alertIntegrations
 
 	if err := r.DB.WithContext(ctx).Model(&model.ErrorAlert{
 		Model: model.Model{
@@ -2961,6 +3116,12 @@ func (r *mutationResolver) UpdateSessionAlert(ctx context.Context, id int, input
 		return nil, e.Wrap(err, "failed to build session feedback alert")
 	}
 
+	existingAlert := &model.SessionAlert{}
+	if err := r.DB.WithContext(ctx).Where(&model.SessionAlert{Model: model.Model{ID: id}}).Where("project_id = ?", input.ProjectID).Take(existingAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying session alert")
+	}
+	sessionAlert.AlertIntegrations.KeepCredentials(existingAlert.AlertIntegrations)
+
 	if err := r.DB.WithContext(ctx).Model(&model.SessionAlert{
 		Model: model.Model{
 			ID: id,
@@ -3060,18 +3221,43 @@ func (r *mutationResolver) UpdateLogAlert(ctx context.Context, id int, input mod
 	if err != nil {
 		return nil, err
 	}
+	if err := validateAlertEvaluationSettings(input.PendingEvaluations, input.RenotifyInterval); err != nil {
+		return nil, err
+	}
 
 	alert, err := alerts.BuildLogAlert(project, workspace, admin, input)
 	if err != nil {
 		return nil, e.Wrap(err, "failed to build log alert")
 	}
 
+	existingAlert := &model.LogAlert{}
+	if err := r.DB.WithContext(ctx).Where(&model.LogAlert{Model: model.Model{ID: id}}).Where("project_id = ?", input.ProjectID).Take(existingAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying log alert")
+	}
+	alert.AlertIntegrations.KeepCredentials(existingAlert.AlertIntegrations)
+
 	if err := r.DB.WithContext(ctx).Model(&model.LogAlert{Model: model.Model{ID: id}}).
 		Where("project_id = ?", input.ProjectID).
 		Updates(alert).Error; err != nil {
 		return nil, e.Wrap(err, "error updating log alert")
 	}
 
+	// Updates skips zero values, so provided evaluation settings are set explicitly to allow resetting them
+	settings := map[string]interface{}{}
+	if input.PendingEvaluations != nil {
+		settings["pending_evaluations"] = *input.PendingEvaluations
+	}
+	if input.RenotifyInterval != nil {
+		settings["renotify_interval"] = *input.RenotifyInterval
+	}
+	if len(settings) > 0 {
+		if err := r.DB.WithContext(ctx).Model(&model.LogAlert{Model: model.Model{ID: id}}).
+			Where("project_id = ?", input.ProjectID).
+			Updates(settings).Error; err != nil {
+			return nil, e.Wrap(err, "error updating log alert evaluation settings")
+		}
+	}
+
 	if err := model.SendWelcomeSlackMessage(ctx, alert, &model.SendWelcomeSlackMessageInput{
 		Workspace:            workspace,
 		Admin:                admin,
@@ -3096,6 +3282,9 @@ func (r *mutationResolver) CreateLogAlert(ctx context.Context, input modelInputs
 	if err != nil {
 		return nil, err
 	}
+	if err := validateAlertEvaluationSettings(input.PendingEvaluations, input.RenotifyInterval); err != nil {
+		return nil, err
+	}
 
 	alert, err := alerts.BuildLogAlert(project, workspace, admin, input)
 	if err != nil {
@@ -3190,6 +3379,225 @@ func (r *mutationResolver) UpdateLogAlertIsDisabled(ctx context.Context, id int,
 	return alert, err
 }
 
+// UpdateTraceAlert is the resolver for the updateTraceAlert field.
+func (r *mutationResolver) UpdateTraceAlert(ctx context.Context, id int, input modelInputs.TraceAlertInput) (*model.TraceAlert, error) {
+	project, err := r.isAdminInProject(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAlertEvaluationSettings(input.PendingEvaluations, input.RenotifyInterval); err != nil {
+		return nil, err
+	}
+	admin, _ := r.getCurrentAdmin(ctx)
+	workspace, _ := r.GetWorkspace(project.WorkspaceID)
+
+	alert, err := alerts.BuildTraceAlert(project, workspace, admin, input)
+	if err != nil {
+		return nil, e.Wrap(err, "failed to build trace alert")
+	}
+
+	existingAlert := &model.TraceAlert{}
+	if err := r.DB.WithContext(ctx).Where(&model.TraceAlert{Model: model.Model{ID: id}}).Where("project_id = ?", input.ProjectID).Take(existingAlert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying trace alert")
+	}
+	alert.AlertIntegrations.KeepCredentials(existingAlert.AlertIntegrations)
+
+	if err := r.DB.WithContext(ctx).Model(&model.TraceAlert{Model: model.Model{ID: id}}).
+		Where("project_id = ?", input.ProjectID).
+		Updates(alert).Error; err != nil {
+		return nil, e.Wrap(err, "error updating trace alert")
+	}
+
+	// Updates skips zero values, so fields that can be cleared are set explicitly
+	fields := map[string]interface{}{
+		"query":           input.Query,
+		"threshold":       input.Threshold,
+		"below_threshold": input.BelowThreshold,
+		"group_by":        alert.GroupBy,
+	}
+	if input.PendingEvaluations != nil {
+		fields["pending_evaluations"] = *input.PendingEvaluations
+	}
+	if input.RenotifyInterval != nil {
+		fields["renotify_interval"] = *input.RenotifyInterval
+	}
+	if err := r.DB.WithContext(ctx).Model(&model.TraceAlert{Model: model.Model{ID: id}}).
+		Where("project_id = ?", input.ProjectID).
+		Updates(fields).Error; err != nil {
+		return nil, e.Wrap(err, "error updating trace alert fields")
+	}
+
+	if err := model.SendWelcomeSlackMessage(ctx, alert, &model.SendWelcomeSlackMessageInput{
+		Workspace:            workspace,
+		Admin:                admin,
+		OperationName:        "updated",
+		OperationDescription: "Trace alerts will now be sent to this channel.",
+		ID:                   id,
+		Project:              project,
+		IncludeEditLink:      true,
+		URLSlug:              "alerts/traces",
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	alert.ID = id
+	return alert, nil
+}
+
+// CreateTraceAlert is the resolver for the createTraceAlert field.
+func (r *mutationResolver) CreateTraceAlert(ctx context.Context, input modelInputs.TraceAlertInput) (*model.TraceAlert, error) {
+	project, err := r.isAdminInProject(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAlertEvaluationSettings(input.PendingEvaluations, input.RenotifyInterval); err != nil {
+		return nil, err
+	}
+	admin, _ := r.getCurrentAdmin(ctx)
+	workspace, _ := r.GetWorkspace(project.WorkspaceID)
+
+	alert, err := alerts.BuildTraceAlert(project, workspace, admin, input)
+	if err != nil {
+		return nil, e.Wrap(err, "failed to build trace alert")
+	}
+
+	if err := r.DB.WithContext(ctx).Create(alert).Error; err != nil {
+		return nil, e.Wrap(err, "error creating a new trace alert")
+	}
+
+	if err := model.SendWelcomeSlackMessage(ctx, alert, &model.SendWelcomeSlackMessageInput{
+		Workspace:            workspace,
+		Admin:                admin,
+		OperationName:        "created",
+		OperationDescription: "Trace alerts will now be sent to this channel.",
+		ID:                   alert.ID,
+		Project:              project,
+		IncludeEditLink:      true,
+		URLSlug:              "alerts/traces",
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	return alert, nil
+}
+
+// DeleteTraceAlert is the resolver for the deleteTraceAlert field.
+func (r *mutationResolver) DeleteTraceAlert(ctx context.Context, projectID int, id int) (*model.TraceAlert, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	alert := &model.TraceAlert{}
+	if err := r.DB.WithContext(ctx).Model(&model.TraceAlert{}).
+		Where("project_id = ?", projectID).
+		Where("id = ?", id).
+		Take(&alert).Error; err != nil {
+		return nil, e.Wrap(err, "this trace alert does not exist in this project.")
+	}
+
+	if err := r.DB.WithContext(ctx).Where("id = ?", id).Delete(&model.TraceAlert{}).Error; err != nil {
+		return nil, e.Wrap(err, "error trying to delete trace alert")
+	}
+
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	workspace, err := r.GetWorkspace(project.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := model.SendWelcomeSlackMessage(ctx, alert, &model.SendWelcomeSlackMessageInput{
+		Workspace:            workspace,
+		Admin:                admin,
+		OperationName:        "deleted",
+		OperationDescription: "Trace alerts will no longer be sent to this channel.",
+		ID:                   id,
+		Project:              project,
+		IncludeEditLink:      false,
+		URLSlug:              "alerts/traces",
+	}); err != nil {
+		log.WithContext(ctx).Error(err)
+	}
+
+	return alert, nil
+}
+
+// CreateAlertSilence is the resolver for the createAlertSilence field.
+func (r *mutationResolver) CreateAlertSilence(ctx context.Context, input modelInputs.AlertSilenceInput) (*model.AlertSilence, error) {
+	_, err := r.isAdminInProject(ctx, input.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	admin, err := r.getCurrentAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !input.EndDate.After(input.StartDate) {
+		return nil, e.New("the end date of an alert silence must be after its start date")
+	}
+
+	silence := &model.AlertSilence{
+		ProjectID:        input.ProjectID,
+		Reason:           input.Reason,
+		StartDate:        input.StartDate,
+		EndDate:          input.EndDate,
+		Environments:     input.Environments,
+		ServiceNames:     input.ServiceNames,
+		AlertNames:       input.AlertNames,
+		CreatedByAdminID: admin.ID,
+	}
+	if err := r.DB.WithContext(ctx).Create(silence).Error; err != nil {
+		return nil, e.Wrap(err, "error creating alert silence")
+	}
+
+	return silence, nil
+}
+
+// EndAlertSilence is the resolver for the endAlertSilence field.
+func (r *mutationResolver) EndAlertSilence(ctx context.Context, projectID int, id int) (*model.AlertSilence, error) {
+	_, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var silence model.AlertSilence
+	if err := r.DB.WithContext(ctx).Model(&model.AlertSilence{}).
+		Where("project_id = ?", projectID).
+		Where("id = ?", id).
+		Take(&silence).Error; err != nil {
+		return nil, e.Wrap(err, "this alert silence does not exist in this project.")
+	}
+
+	// the silence is kept so that its silenced notifications can still be reviewed
+	now := time.Now()
+	if silence.EndDate.After(now) {
+		silence.EndDate = now
+		if err := r.DB.WithContext(ctx).Model(&silence).Update("end_date", now).Error; err != nil {
+			return nil, e.Wrap(err, "error ending alert silence")
+		}
+	}
+
+	return &silence, nil
+}
+
+// SendTestWebhook is the resolver for the sendTestWebhook field.
+func (r *mutationResolver) SendTestWebhook(ctx context.Context, projectID int, destination modelInputs.WebhookDestinationInput) (*model.WebhookDelivery, error) {
+	_, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// a failed delivery is returned along with its error so that the receiver can be debugged
+	delivery, err := webhook.SendTestAlert(ctx, r.DB, projectID, webhook.GQLInputToGo([]*modelInputs.WebhookDestinationInput{&destination})[0])
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Warn("test webhook failed")
+	}
+	return delivery, nil
+}
+
 // UpdateSessionIsPublic is the resolver for the updateSessionIsPublic field.
 func (r *mutationResolver) UpdateSessionIsPublic(ctx context.Context, sessionSecureID string, isPublic bool) (*model.Session, error) {
 	session, err := r.canAdminModifySession(ctx, sessionSecureID)
@@ -3816,6 +4224,41 @@ func (r *mutationResolver) UpdateErrorTags(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// EditErrorGroupingRules is the resolver for the editErrorGroupingRules field.
+func (r *mutationResolver) EditErrorGroupingRules(ctx context.Context, projectID int, rules []*modelInputs.ErrorGroupingRuleInput) ([]*model.ErrorGroupingRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupingRules, err := getErrorGroupingRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Store.UpdateErrorGroupingRules(ctx, project.ID, groupingRules)
+}
+
+// PreviewErrorGroupingRules is the resolver for the previewErrorGroupingRules field.
+func (r *mutationResolver) PreviewErrorGroupingRules(ctx context.Context, projectID int, rules []*modelInputs.ErrorGroupingRuleInput, count *int) ([]*modelInputs.ErrorGroupingPreview, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	groupingRules, err := getErrorGroupingRules(rules)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 100
+	if count != nil {
+		limit = lo.Clamp(*count, 1, 1000)
+	}
+
+	return r.Resolver.PreviewErrorGroupingRules(ctx, project.ID, groupingRules, limit)
+}
+
 // UpsertSlackChannel is the resolver for the upsertSlackChannel field.
 func (r *mutationResolver) UpsertSlackChannel(ctx context.Context, projectID int, name string) (*modelInputs.SanitizedSlackChannel, error) {
 	project, err := r.isAdminInProject(ctx, projectID)
@@ -5823,6 +6266,36 @@ func (r *queryResolver) ErrorAlerts(ctx context.Context, projectID int) ([]*mode
 	return alerts, nil
 }
 
+// ErrorAlertBaseline is the resolver for the error_alert_baseline field.
+func (r *queryResolver) ErrorAlertBaseline(ctx context.Context, id int, errorGroupSecureID *string, dateRange modelInputs.DateRangeRequiredInput) ([]*modelInputs.AlertBaselineBucket, error) {
+	var alert model.ErrorAlert
+	if err := r.DB.WithContext(ctx).Model(&model.ErrorAlert{}).Where("id = ?", id).Take(&alert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error alert")
+	}
+	if _, err := r.isAdminInProjectOrDemoProject(ctx, alert.ProjectID); err != nil {
+		return nil, err
+	}
+
+	var errorGroupID *int
+	if errorGroupSecureID != nil {
+		var errorGroup model.ErrorGroup
+		if err := r.DB.WithContext(ctx).Model(&model.ErrorGroup{}).
+			Where("project_id = ?", alert.ProjectID).
+			Where("secure_id = ?", *errorGroupSecureID).
+			Take(&errorGroup).Error; err != nil {
+			return nil, e.Wrap(err, "error querying error group")
+		}
+		errorGroupID = &errorGroup.ID
+	}
+
+	times, counts, err := alerts.ReadErrorAlertHistory(ctx, r.ClickhouseClient, alert.ProjectID, errorGroupID, dateRange.StartDate.AddDate(0, 0, -7*alerts.AnomalyBaselineWeeks), dateRange.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	historyTimes, historyCounts, rangeTimes, rangeCounts := alerts.SplitHistory(times, counts, dateRange.StartDate)
+	return alerts.NewAlertBaseline(historyTimes, historyCounts).Buckets(rangeTimes, rangeCounts, alert.AnomalyDeviations), nil
+}
+
 // NewUserAlerts is the resolver for the new_user_alerts field.
 func (r *queryResolver) NewUserAlerts(ctx context.Context, projectID int) ([]*model.SessionAlert, error) {
 	_, err := r.isAdminInProjectOrDemoProject(ctx, projectID)
@@ -5919,6 +6392,135 @@ func (r *queryResolver) LogAlert(ctx context.Context, id int) (*model.LogAlert,
 	return alert, nil
 }
 
+// LogAlertEvaluations is the resolver for the log_alert_evaluations field.
+func (r *queryResolver) LogAlertEvaluations(ctx context.Context, id int, count *int) ([]*model.AlertEvaluation, error) {
+	var alert model.LogAlert
+	if err := r.DB.WithContext(ctx).Model(&model.LogAlert{}).Where("id = ?", id).Take(&alert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying log alert")
+	}
+	if _, err := r.isAdminInProjectOrDemoProject(ctx, alert.ProjectID); err != nil {
+		return nil, err
+	}
+	return r.Store.GetAlertEvaluations(ctx, model.AlertType.LOG, alert.ID, count)
+}
+
+// LogAlertBaseline is the resolver for the log_alert_baseline field.
+func (r *queryResolver) LogAlertBaseline(ctx context.Context, id int, dateRange modelInputs.DateRangeRequiredInput) ([]*modelInputs.AlertBaselineBucket, error) {
+	var alert model.LogAlert
+	if err := r.DB.WithContext(ctx).Model(&model.LogAlert{}).Where("id = ?", id).Take(&alert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying log alert")
+	}
+	if _, err := r.isAdminInProjectOrDemoProject(ctx, alert.ProjectID); err != nil {
+		return nil, err
+	}
+
+	times, counts, err := alerts.ReadLogAlertHistory(ctx, r.ClickhouseClient, &alert, dateRange.StartDate.AddDate(0, 0, -7*alerts.AnomalyBaselineWeeks), dateRange.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	historyTimes, historyCounts, rangeTimes, rangeCounts := alerts.SplitHistory(times, counts, dateRange.StartDate)
+	return alerts.NewAlertBaseline(historyTimes, historyCounts).Buckets(rangeTimes, rangeCounts, alert.AnomalyDeviations), nil
+}
+
+// TraceAlerts is the resolver for the trace_alerts field.
+func (r *queryResolver) TraceAlerts(ctx context.Context, projectID int) ([]*model.TraceAlert, error) {
+	_, err := r.isAdminInProjectOrDemoProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var alerts []*model.TraceAlert
+	if err := r.DB.WithContext(ctx).Model(&model.TraceAlert{}).Where("project_id = ?", projectID).Find(&alerts).Error; err != nil {
+		return nil, e.Wrap(err, "error querying trace alerts")
+	}
+	return alerts, nil
+}
+
+// TraceAlert is the resolver for the trace_alert field.
+func (r *queryResolver) TraceAlert(ctx context.Context, id int) (*model.TraceAlert, error) {
+	var alert model.TraceAlert
+	if err := r.DB.WithContext(ctx).Model(&model.TraceAlert{}).Where("id = ?", id).Take(&alert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying trace alert")
+	}
+	if _, err := r.isAdminInProjectOrDemoProject(ctx, alert.ProjectID); err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// TraceAlertEvaluations is the resolver for the trace_alert_evaluations field.
+func (r *queryResolver) TraceAlertEvaluations(ctx context.Context, id int, count *int) ([]*model.AlertEvaluation, error) {
+	var alert model.TraceAlert
+	if err := r.DB.WithContext(ctx).Model(&model.TraceAlert{}).Where("id = ?", id).Take(&alert).Error; err != nil {
+		return nil, e.Wrap(err, "error querying trace alert")
+	}
+	if _, err := r.isAdminInProjectOrDemoProject(ctx, alert.ProjectID); err != nil {
+		return nil, err
+	}
+	return r.Store.GetAlertEvaluations(ctx, model.AlertType.TRACE, alert.ID, count)
+}
+
+// AlertSilences is the resolver for the alert_silences field.
+func (r *queryResolver) AlertSilences(ctx context.Context, projectID int) ([]*model.AlertSilence, error) {
+	_, err := r.isAdminInProjectOrDemoProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	var silences []*model.AlertSilence
+	if err := r.DB.WithContext(ctx).Model(&model.AlertSilence{}).
+		Where("project_id = ?", projectID).
+		Order("end_date DESC, id DESC").
+		Find(&silences).Error; err != nil {
+		return nil, e.Wrap(err, "error querying alert silences")
+	}
+	return silences, nil
+}
+
+// SilencedAlertNotifications is the resolver for the silenced_alert_notifications field.
+func (r *queryResolver) SilencedAlertNotifications(ctx context.Context, projectID int, alertSilenceID *int, count *int) ([]*model.SilencedAlertNotification, error) {
+	_, err := r.isAdminInProjectOrDemoProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 100
+	if count != nil && *count > 0 {
+		limit = *count
+	}
+	query := r.DB.WithContext(ctx).Model(&model.SilencedAlertNotification{}).Where("project_id = ?", projectID)
+	if alertSilenceID != nil {
+		query = query.Where("alert_silence_id = ?", *alertSilenceID)
+	}
+
+	var notifications []*model.SilencedAlertNotification
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, e.Wrap(err, "error querying silenced alert notifications")
+	}
+	return notifications, nil
+}
+
+// WebhookDeliveries is the resolver for the webhook_deliveries field.
+func (r *queryResolver) WebhookDeliveries(ctx context.Context, projectID int, count *int) ([]*model.WebhookDelivery, error) {
+	_, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := 100
+	if count != nil && *count > 0 {
+		limit = *count
+	}
+
+	var deliveries []*model.WebhookDelivery
+	if err := r.DB.WithContext(ctx).Model(&model.WebhookDelivery{}).
+		Where("project_id = ?", projectID).
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, e.Wrap(err, "error querying webhook deliveries")
+	}
+	return deliveries, nil
+}
+
 // ProjectSuggestion is the resolver for the projectSuggestion field.
 func (r *queryResolver) ProjectSuggestion(ctx context.Context, query string) ([]*model.Project, error) {
 	projects := []*model.Project{}
@@ -6492,18 +7094,21 @@ func (r *queryResolver) ProjectSettings(ctx context.Context, projectID int) (*mo
 		FilterSessionsWithoutError:        projectFilterSettings.FilterSessionsWithoutError,
 		AutoResolveStaleErrorsDayInterval: projectFilterSettings.AutoResolveStaleErrorsDayInterval,
 		Sampling: &modelInputs.Sampling{
-			SessionSamplingRate:    projectFilterSettings.SessionSamplingRate,
-			ErrorSamplingRate:      projectFilterSettings.ErrorSamplingRate,
-			LogSamplingRate:        projectFilterSettings.LogSamplingRate,
-			TraceSamplingRate:      projectFilterSettings.TraceSamplingRate,
-			SessionMinuteRateLimit: projectFilterSettings.SessionMinuteRateLimit,
-			ErrorMinuteRateLimit:   projectFilterSettings.ErrorMinuteRateLimit,
-			LogMinuteRateLimit:     projectFilterSettings.LogMinuteRateLimit,
-			TraceMinuteRateLimit:   projectFilterSettings.TraceMinuteRateLimit,
-			SessionExclusionQuery:  projectFilterSettings.SessionExclusionQuery,
-			ErrorExclusionQuery:    projectFilterSettings.ErrorExclusionQuery,
-			LogExclusionQuery:      projectFilterSettings.LogExclusionQuery,
-			TraceExclusionQuery:    projectFilterSettings.TraceExclusionQuery,
+			SessionSamplingRate:       projectFilterSettings.SessionSamplingRate,
+			ErrorSamplingRate:         projectFilterSettings.ErrorSamplingRate,
+			LogSamplingRate:           projectFilterSettings.LogSamplingRate,
+			TraceSamplingRate:         projectFilterSettings.TraceSamplingRate,
+			SessionMinuteRateLimit:    projectFilterSettings.SessionMinuteRateLimit,
+			ErrorMinuteRateLimit:      projectFilterSettings.ErrorMinuteRateLimit,
+			LogMinuteRateLimit:        projectFilterSettings.LogMinuteRateLimit,
+			TraceMinuteRateLimit:      projectFilterSettings.TraceMinuteRateLimit,
+			SessionExclusionQuery:     projectFilterSettings.SessionExclusionQuery,
+			ErrorExclusionQuery:       projectFilterSettings.ErrorExclusionQuery,
+			LogExclusionQuery:         projectFilterSettings.LogExclusionQuery,
+			TraceExclusionQuery:       projectFilterSettings.TraceExclusionQuery,
+			TraceKeepOnError:          projectFilterSettings.TraceKeepOnError,
+			TraceLatencyThresholdMs:   projectFilterSettings.TraceLatencyThresholdMs,
+			TraceServiceSamplingRates: projectFilterSettings.TraceServiceSamplingRates,
 		},
 	}
 
@@ -7095,6 +7700,18 @@ func (r *queryResolver) MetricMonitors(ctx context.Context, projectID int, metri
 	return metricMonitors, nil
 }
 
+// MetricMonitorEvaluations is the resolver for the metric_monitor_evaluations field.
+func (r *queryResolver) MetricMonitorEvaluations(ctx context.Context, id int, count *int) ([]*model.AlertEvaluation, error) {
+	var metricMonitor model.MetricMonitor
+	if err := r.DB.WithContext(ctx).Model(&model.MetricMonitor{}).Where("id = ?", id).Take(&metricMonitor).Error; err != nil {
+		return nil, e.Wrap(err, "error querying metric monitor")
+	}
+	if _, err := r.isAdminInProjectOrDemoProject(ctx, metricMonitor.ProjectID); err != nil {
+		return nil, err
+	}
+	return r.Store.GetAlertEvaluations(ctx, model.AlertType.METRIC_MONITOR, metricMonitor.ID, count)
+}
+
 // EventChunkURL is the resolver for the event_chunk_url field.
 func (r *queryResolver) EventChunkURL(ctx context.Context, secureID string, index int) (string, error) {
 	session, err := r.canAdminViewSession(ctx, secureID)
@@ -7485,6 +8102,16 @@ func (r *queryResolver) FindSimilarErrors(ctx context.Context, query string) ([]
 	return r.Resolver.FindSimilarErrors(ctx, query)
 }
 
+// ErrorGroupingRules is the resolver for the error_grouping_rules field.
+func (r *queryResolver) ErrorGroupingRules(ctx context.Context, projectID int) ([]*model.ErrorGroupingRule, error) {
+	project, err := r.isAdminInProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Store.GetErrorGroupingRules(ctx, project.ID)
+}
+
 // Trace is the resolver for the trace field.
 func (r *queryResolver) Trace(ctx context.Context, projectID int, traceID string) (*modelInputs.TracePayload, error) {
 	project, err := r.isAdminInProjectOrDemoProject(ctx, projectID)
@@ -7718,6 +8345,21 @@ func (r *sessionAlertResolver) WebhookDestinations(ctx context.Context, obj *mod
 	return obj.WebhookDestinations, nil
 }
 
+// PagerDutyServices is the resolver for the PagerDutyServices field.
+func (r *sessionAlertResolver) PagerDutyServices(ctx context.Context, obj *model.SessionAlert) ([]*model.PagerDutyService, error) {
+	return obj.PagerDutyServices, nil
+}
+
+// OpsgenieTeams is the resolver for the OpsgenieTeams field.
+func (r *sessionAlertResolver) OpsgenieTeams(ctx context.Context, obj *model.SessionAlert) ([]*model.OpsgenieTeam, error) {
+	return obj.OpsgenieTeams, nil
+}
+
+// MicrosoftTeamsChannels is the resolver for the MicrosoftTeamsChannels field.
+func (r *sessionAlertResolver) MicrosoftTeamsChannels(ctx context.Context, obj *model.SessionAlert) ([]*model.MicrosoftTeamsChannel, error) {
+	return obj.MicrosoftTeamsChannels, nil
+}
+
 // EmailsToNotify is the resolver for the EmailsToNotify field.
 func (r *sessionAlertResolver) EmailsToNotify(ctx context.Context, obj *model.SessionAlert) ([]*string, error) {
 	return obj.GetEmailsToNotify()
@@ -7892,6 +8534,41 @@ func (r *timelineIndicatorEventResolver) Data(ctx context.Context, obj *model.Ti
 	return obj.Data, nil
 }
 
+// ChannelsToNotify is the resolver for the channels_to_notify field.
+func (r *traceAlertResolver) ChannelsToNotify(ctx context.Context, obj *model.TraceAlert) ([]*modelInputs.SanitizedSlackChannel, error) {
+	return obj.GetChannelsToNotify()
+}
+
+// DiscordChannelsToNotify is the resolver for the discord_channels_to_notify field.
+func (r *traceAlertResolver) DiscordChannelsToNotify(ctx context.Context, obj *model.TraceAlert) ([]*model.DiscordChannel, error) {
+	return obj.DiscordChannelsToNotify, nil
+}
+
+// WebhookDestinations is the resolver for the webhook_destinations field.
+func (r *traceAlertResolver) WebhookDestinations(ctx context.Context, obj *model.TraceAlert) ([]*model.WebhookDestination, error) {
+	return obj.WebhookDestinations, nil
+}
+
+// PagerDutyServices is the resolver for the pager_duty_services field.
+func (r *traceAlertResolver) PagerDutyServices(ctx context.Context, obj *model.TraceAlert) ([]*model.PagerDutyService, error) {
+	return obj.PagerDutyServices, nil
+}
+
+// OpsgenieTeams is the resolver for the opsgenie_teams field.
+func (r *traceAlertResolver) OpsgenieTeams(ctx context.Context, obj *model.TraceAlert) ([]*model.OpsgenieTeam, error) {
+	return obj.OpsgenieTeams, nil
+}
+
+// MicrosoftTeamsChannels is the resolver for the microsoft_teams_channels field.
+func (r *traceAlertResolver) MicrosoftTeamsChannels(ctx context.Context, obj *model.TraceAlert) ([]*model.MicrosoftTeamsChannel, error) {
+	return obj.MicrosoftTeamsChannels, nil
+}
+
+// EmailsToNotify is the resolver for the emails_to_notify field.
+func (r *traceAlertResolver) EmailsToNotify(ctx context.Context, obj *model.TraceAlert) ([]*string, error) {
+	return obj.GetEmailsToNotify()
+}
+
 // CommentReply returns generated.CommentReplyResolver implementation.
 func (r *Resolver) CommentReply() generated.CommentReplyResolver { return &commentReplyResolver{r} }
 
@@ -7952,6 +8629,9 @@ func (r *Resolver) TimelineIndicatorEvent() generated.TimelineIndicatorEventReso
 	return &timelineIndicatorEventResolver{r}
 }
 
+// TraceAlert returns generated.TraceAlertResolver implementation.
+func (r *Resolver) TraceAlert() generated.TraceAlertResolver { return &traceAlertResolver{r} }
+
 type commentReplyResolver struct{ *Resolver }
 type errorAlertResolver struct{ *Resolver }
 type errorCommentResolver struct{ *Resolver }
@@ -7970,3 +8650,4 @@ type sessionAlertResolver struct{ *Resolver }
 type sessionCommentResolver struct{ *Resolver }
 type subscriptionResolver struct{ *Resolver }
 type timelineIndicatorEventResolver struct{ *Resolver }
+type traceAlertResolver struct{ *Resolver }
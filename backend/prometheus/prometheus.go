@@ -0,0 +1,172 @@
+package prometheus
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/snappy"
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	highlightHttp "github.com/highlight-run/highlight/backend/http"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/public-graph/graph"
+	e "github.com/pkg/errors"
+	"github.com/prometheus/prometheus/prompb"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// MetricNameLabel is the label holding the name of a prometheus metric.
+	MetricNameLabel = "__name__"
+	// ServiceLabel is the label used as the service name, since prometheus targets are grouped by job.
+	ServiceLabel = "job"
+	// ProjectLabel sets the project of a time series when the request has no LogDrainProjectHeader,
+	// eg. from the `external_labels` of the prometheus config.
+	ProjectLabel = "highlight_project"
+)
+
+type Handler struct {
+	resolver *graph.Resolver
+}
+
+func readBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, e.Wrap(err, "failed to read body")
+	}
+	// remote-write requests are always snappy block compressed
+	body, err = snappy.Decode(nil, body)
+	if err != nil {
+		return nil, e.Wrap(err, "failed to decompress snappy body")
+	}
+	return body, nil
+}
+
+// getMetricRows converts the samples of prometheus time series to metric rows,
+// returning the number of samples rejected for not belonging to a valid project.
+func getMetricRows(ctx context.Context, projectVerboseID string, serviceName string, series []prompb.TimeSeries) ([]*clickhouse.MetricRow, int) {
+	var metricRows []*clickhouse.MetricRow
+	var rejected int
+	for _, ts := range series {
+		var name, projectID, service string
+		attributes := map[string]string{}
+		for _, label := range ts.Labels {
+			switch label.Name {
+			case MetricNameLabel:
+				name = label.Value
+			case ProjectLabel:
+				projectID = label.Value
+			case ServiceLabel:
+				service = label.Value
+				attributes[label.Name] = label.Value
+			default:
+				attributes[label.Name] = label.Value
+			}
+		}
+		if projectVerboseID != "" {
+			projectID = projectVerboseID
+		}
+		if serviceName != "" {
+			service = serviceName
+		}
+
+		project, err := model.FromVerboseID(projectID)
+		if name == "" || err != nil {
+			log.WithContext(ctx).
+				WithField("metric", name).
+				WithField("project", projectID).
+				Warn("dropping prometheus time series without a metric name or valid project")
+			rejected += len(ts.Samples)
+			continue
+		}
+
+		for _, sample := range ts.Samples {
+			// NaN values include the staleness markers written when a series disappears
+			if math.IsNaN(sample.Value) {
+				continue
+			}
+			timestamp := time.UnixMilli(sample.Timestamp)
+			if sample.Timestamp == 0 {
+				timestamp = time.Now()
+			}
+			metricRows = append(metricRows, clickhouse.NewMetricRow(timestamp, project, name, sample.Value).
+				WithServiceName(service).
+				WithAttributes(attributes))
+		}
+	}
+	return metricRows, rejected
+}
+
+func (h *Handler) HandleWrite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	body, err := readBody(r)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid prometheus remote-write request")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	series, err := ParseWriteRequest(body)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Error("invalid prometheus remote-write request")
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	metricRows, rejected := getMetricRows(ctx, r.Header.Get(highlightHttp.LogDrainProjectHeader), r.Header.Get(highlightHttp.LogDrainServiceHeader), series)
+	if err := h.submitMetrics(ctx, metricRows); err != nil {
+		log.WithContext(ctx).WithError(err).Error("failed to process prometheus metrics")
+		// a server error makes prometheus retry the request
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	// a client error is not retried by prometheus, which would otherwise resend the rejected samples forever
+	if rejected > 0 && len(metricRows) == 0 {
+		http.Error(w, "no samples with a valid project", http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// submitMetrics queues the metric rows that are ingested. Like OTLP metrics, they are written to the metrics table
+// and as highlight-metric trace rows, which `GetMetricTimeline` and metric monitors query.
+func (h *Handler) submitMetrics(ctx context.Context, metricRows []*clickhouse.MetricRow) error {
+	var messages []*kafkaqueue.Message
+	for _, metricRow := range metricRows {
+		if !h.resolver.IsMetricIngested(ctx, metricRow) {
+			continue
+		}
+		messages = append(messages, &kafkaqueue.Message{
+			Type: kafkaqueue.PushMetricRows,
+			PushMetricRows: &kafkaqueue.PushMetricRowsArgs{
+				MetricRow: metricRow,
+			},
+		})
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+
+	err := h.resolver.TracesQueue.Submit(ctx, "", messages...)
+	if err != nil {
+		return e.Wrap(err, "failed to submit prometheus metrics to public worker queue")
+	}
+	return nil
+}
+
+func (h *Handler) Listen(r *chi.Mux) {
+	r.Route("/prometheus/v1", func(r chi.Router) {
+		r.Post("/write", h.HandleWrite)
+	})
+}
+
+func New(resolver *graph.Resolver) *Handler {
+	return &Handler{
+		resolver: resolver,
+	}
+}
@@ -0,0 +1,179 @@
+package prometheus
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	highlightHttp "github.com/highlight-run/highlight/backend/http"
+	"github.com/highlight-run/highlight/backend/integrations"
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/model"
+	public "github.com/highlight-run/highlight/backend/public-graph/graph"
+	"github.com/highlight-run/highlight/backend/redis"
+	"github.com/highlight-run/highlight/backend/storage"
+	"github.com/highlight-run/highlight/backend/store"
+	"github.com/highlight-run/highlight/backend/util"
+	"github.com/openlyinc/pointy"
+	e "github.com/pkg/errors"
+	"github.com/prometheus/prometheus/prompb"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+type MockKafkaProducer struct {
+	messages []*kafkaqueue.Message
+}
+
+func (m *MockKafkaProducer) Stop(_ context.Context) {}
+
+func (m *MockKafkaProducer) Receive(_ context.Context) *kafkaqueue.Message { return nil }
+
+func (m *MockKafkaProducer) Submit(_ context.Context, _ string, messages ...*kafkaqueue.Message) error {
+	m.messages = append(m.messages, messages...)
+	return nil
+}
+
+func (m *MockKafkaProducer) LogStats() {}
+
+func writeRequest() []byte {
+	req := prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{{
+			Labels: []prompb.Label{
+				{Name: MetricNameLabel, Value: "http_requests_total"},
+				{Name: ServiceLabel, Value: "api"},
+				{Name: "instance", Value: "localhost:9090"},
+				{Name: ProjectLabel, Value: "1"},
+			},
+			Samples: []prompb.Sample{
+				{Value: 42, Timestamp: 1700000000000},
+				{Value: math.NaN(), Timestamp: 1700000015000},
+				{Value: 43.5, Timestamp: 1700000030000},
+			},
+		}, {
+			Labels:  []prompb.Label{{Name: MetricNameLabel, Value: "up"}},
+			Samples: []prompb.Sample{{Value: 1, Timestamp: 1700000000000}},
+		}},
+		// metadata, which is ignored
+		Metadata: []prompb.MetricMetadata{{MetricFamilyName: "up", Type: prompb.MetricMetadata_GAUGE}},
+	}
+	b, err := req.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+func TestParseWriteRequest(t *testing.T) {
+	series, err := ParseWriteRequest(writeRequest())
+	assert.NoError(t, err)
+	assert.Len(t, series, 2)
+	assert.Equal(t, []prompb.Label{
+		{Name: MetricNameLabel, Value: "http_requests_total"},
+		{Name: ServiceLabel, Value: "api"},
+		{Name: "instance", Value: "localhost:9090"},
+		{Name: ProjectLabel, Value: "1"},
+	}, series[0].Labels)
+	assert.Len(t, series[0].Samples, 3)
+	assert.Equal(t, prompb.Sample{Value: 42, Timestamp: 1700000000000}, series[0].Samples[0])
+	assert.True(t, math.IsNaN(series[0].Samples[1].Value))
+
+	_, err = ParseWriteRequest([]byte{0x0a, 0x10, 0x01})
+	assert.Error(t, err)
+}
+
+func TestGetMetricRows(t *testing.T) {
+	ctx := context.TODO()
+	series, err := ParseWriteRequest(writeRequest())
+	assert.NoError(t, err)
+
+	rows, rejected := getMetricRows(ctx, "", "", series)
+	assert.Equal(t, 1, rejected)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, uint32(1), rows[0].ProjectId)
+	assert.Equal(t, "api", rows[0].ServiceName)
+	assert.Equal(t, map[string]string{ServiceLabel: "api", "instance": "localhost:9090"}, rows[0].Attributes)
+	assert.Equal(t, time.UnixMilli(1700000030000), rows[1].Timestamp)
+
+	// samples are readable as metrics by the timelines and monitors querying highlight-metric trace rows
+	traceRow := rows[0].TraceRow()
+	assert.Equal(t, clickhouse.MetricSpanName, traceRow.SpanName)
+	assert.Equal(t, map[string]string{"metric.name": "http_requests_total", "metric.value": "42"}, traceRow.Events[0].Attributes)
+
+	// the project and service headers take precedence over labels
+	rows, rejected = getMetricRows(ctx, "2", "web", series)
+	assert.Equal(t, 0, rejected)
+	assert.Len(t, rows, 3)
+	for _, row := range rows {
+		assert.Equal(t, uint32(2), row.ProjectId)
+		assert.Equal(t, "web", row.ServiceName)
+	}
+}
+
+func TestHandleWriteInvalid(t *testing.T) {
+	h := &Handler{}
+	for _, body := range [][]byte{
+		[]byte("not snappy"),
+		snappy.Encode(nil, []byte{0x0a, 0x10, 0x01}),
+	} {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/prometheus/v1/write", bytes.NewReader(body))
+		h.HandleWrite(w, r)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestHandleWrite(t *testing.T) {
+	ctx := context.TODO()
+	dbName := "highlight_testing_db"
+	testLogger := log.WithContext(ctx).WithFields(log.Fields{"DB_HOST": os.Getenv("PSQL_HOST"), "DB_NAME": dbName})
+	db, err := util.CreateAndMigrateTestDB(dbName)
+	if err != nil {
+		testLogger.Error(e.Wrap(err, "error creating testdb"))
+	}
+	red := redis.NewClient()
+
+	project := model.Project{}
+	db.Create(&project)
+	excludedProject := model.Project{}
+	db.Create(&excludedProject)
+	db.Create(&model.ProjectFilterSettings{
+		ProjectID:           excludedProject.ID,
+		TraceExclusionQuery: pointy.String("service_name:api"),
+	})
+
+	// the project header applies to both time series, and the exclusion query drops the samples of the api service
+	for projectID, expectedServices := range map[int][]string{
+		project.ID:         {"api", "api", ""},
+		excludedProject.ID: {""},
+	} {
+		producer := MockKafkaProducer{}
+		h := New(&public.Resolver{
+			Redis:       red,
+			Store:       store.NewStore(db, red, integrations.NewIntegrationsClient(db), &storage.FilesystemClient{}, &producer, nil),
+			TracesQueue: &producer,
+		})
+
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodPost, "/prometheus/v1/write", bytes.NewReader(snappy.Encode(nil, writeRequest())))
+		r.Header.Set(highlightHttp.LogDrainProjectHeader, strconv.Itoa(projectID))
+		h.HandleWrite(w, r)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+
+		var services []string
+		for _, message := range producer.messages {
+			assert.Equal(t, kafkaqueue.PushMetricRows, message.Type)
+			assert.Equal(t, uint32(projectID), message.PushMetricRows.MetricRow.ProjectId)
+			services = append(services, message.PushMetricRows.MetricRow.ServiceName)
+		}
+		assert.Equal(t, expectedServices, services)
+	}
+}
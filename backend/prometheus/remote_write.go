@@ -0,0 +1,15 @@
+package prometheus
+
+import (
+	e "github.com/pkg/errors"
+	"github.com/prometheus/prometheus/prompb"
+)
+
+// ParseWriteRequest decodes the time series of an uncompressed remote-write request.
+func ParseWriteRequest(b []byte) ([]prompb.TimeSeries, error) {
+	var req prompb.WriteRequest
+	if err := req.Unmarshal(b); err != nil {
+		return nil, e.Wrap(err, "invalid remote-write request")
+	}
+	return req.Timeseries, nil
+}
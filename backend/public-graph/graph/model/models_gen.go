@@ -10,19 +10,26 @@ import (
 )
 
 type BackendErrorObjectInput struct {
-	SessionSecureID *string       `json:"session_secure_id"`
-	RequestID       *string       `json:"request_id"`
-	TraceID         *string       `json:"trace_id"`
-	SpanID          *string       `json:"span_id"`
-	LogCursor       *string       `json:"log_cursor"`
-	Event           string        `json:"event"`
-	Type            string        `json:"type"`
-	URL             string        `json:"url"`
-	Source          string        `json:"source"`
-	StackTrace      string        `json:"stackTrace"`
-	Timestamp       time.Time     `json:"timestamp"`
-	Payload         *string       `json:"payload"`
-	Service         *ServiceInput `json:"service"`
+	SessionSecureID *string            `json:"session_secure_id"`
+	RequestID       *string            `json:"request_id"`
+	TraceID         *string            `json:"trace_id"`
+	SpanID          *string            `json:"span_id"`
+	LogCursor       *string            `json:"log_cursor"`
+	Event           string             `json:"event"`
+	Type            string             `json:"type"`
+	URL             string             `json:"url"`
+	Source          string             `json:"source"`
+	StackTrace      string             `json:"stackTrace"`
+	Timestamp       time.Time          `json:"timestamp"`
+	Payload         *string            `json:"payload"`
+	Service         *ServiceInput      `json:"service"`
+	Causes          []*ErrorCauseInput `json:"causes"`
+}
+
+type ErrorCauseInput struct {
+	Type       string `json:"type"`
+	Event      string `json:"event"`
+	StackTrace string `json:"stackTrace"`
 }
 
 type ErrorObjectInput struct {
@@ -18,7 +18,6 @@ import (
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
 
-	"github.com/PaesslerAG/jsonpath"
 	"github.com/aws/smithy-go/ptr"
 	"github.com/google/uuid"
 	"github.com/highlight-run/go-resthooks"
@@ -433,14 +432,29 @@ func (r *Resolver) GetOrCreateErrorGroup(ctx context.Context, errorObj *model.Er
 			return nil, e.Wrap(err, "error retrieving top matched error group")
 		}
 
+		// errors matching a merged group are grouped into the group it was merged into.
+		// merges repoint groups that were merged into a merged group, so there is at most one hop.
+		if errorGroup.MergedIntoID != nil {
+			mergedErrorGroup := &model.ErrorGroup{}
+			if err := r.DB.WithContext(ctx).Where(&model.ErrorGroup{
+				Model: model.Model{ID: *errorGroup.MergedIntoID},
+			}).Take(&mergedErrorGroup).Error; err != nil {
+				return nil, e.Wrap(err, "error retrieving merged error group")
+			}
+			errorGroup = mergedErrorGroup
+		}
+
 		environmentsString := getIncrementedEnvironmentCount(ctx, errorGroup, errorObj)
 
 		updatedState := errorGroup.State
+		regressedAt := errorGroup.RegressedAt
 
-		// Reopen resolved errors
+		// Reopen resolved errors as regressed, unless they were resolved in the next release of the service
 		// Note that ignored errors do change state
-		if updatedState == privateModel.ErrorStateResolved {
+		regressed := errorgroups.IsRegression(errorGroup, errorObj.ServiceVersion)
+		if regressed {
 			updatedState = privateModel.ErrorStateOpen
+			regressedAt = pointy.Time(time.Now())
 		}
 
 		if errorGroup.ErrorTagID == nil && tagGroup {
@@ -455,9 +469,24 @@ func (r *Resolver) GetOrCreateErrorGroup(ctx context.Context, errorObj *model.Er
 			State:            updatedState,
 			ServiceName:      errorObj.ServiceName,
 			ErrorTagID:       errorGroup.ErrorTagID,
+			RegressedAt:      regressedAt,
 		}).Error; err != nil {
 			return nil, e.Wrap(err, "Error updating error group")
 		}
+
+		if regressed {
+			errorGroup.Regressed = true
+			if err := r.Store.CreateErrorGroupActivityLog(ctx, model.ErrorGroupActivityLog{
+				EventType:    model.ErrorGroupRegressedEvent,
+				ErrorGroupID: errorGroup.ID,
+				EventData: map[string]interface{}{
+					"ServiceVersion":    errorObj.ServiceVersion,
+					"ResolvedInVersion": errorGroup.ResolvedInVersion,
+				},
+			}); err != nil {
+				return nil, e.Wrap(err, "error creating error group regressed activity log")
+			}
+		}
 	}
 
 	if err := r.DataSyncQueue.Submit(ctx, strconv.Itoa(errorGroup.ID), &kafka_queue.Message{Type: kafka_queue.ErrorGroupDataSync, ErrorGroupDataSync: &kafka_queue.ErrorGroupDataSyncArgs{ErrorGroupID: errorGroup.ID}}); err != nil {
@@ -549,13 +578,6 @@ func (r *Resolver) GetTopErrorGroupMatch(event string, projectID int, fingerprin
 		Sum int
 	}{}
 
-	// temporarily disable error group matching
-	// for Gorillamind
-	// because their huge traceback
-	// slows this process down too much
-	if projectID == 356 {
-		return nil, nil
-	}
 	start := time.Now()
 	if err := r.DB.Raw(`
 		WITH json_results AS (
@@ -630,6 +652,49 @@ func (r *Resolver) GetTopErrorGroupMatch(event string, projectID int, fingerprin
 	}
 }
 
+// GetErrorGroupMatchByFingerprint matches the error group of the latest error grouped by the same custom grouping rule fingerprint
+func (r *Resolver) GetErrorGroupMatchByFingerprint(ctx context.Context, projectID int, fingerprint string) (*int, error) {
+	var errorGroupIDs []int
+	if err := r.DB.WithContext(ctx).Raw(`
+		SELECT error_group_id
+		FROM error_fingerprints
+		WHERE project_id = @projectID
+		AND type = @type
+		AND value = @value
+		AND error_group_id IS NOT NULL
+		ORDER BY id DESC
+		LIMIT 1`,
+		map[string]interface{}{
+			"projectID": projectID,
+			"type":      model.Fingerprint.CustomRule,
+			"value":     fingerprint,
+		}).
+		Scan(&errorGroupIDs).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error group match by fingerprint")
+	}
+	if len(errorGroupIDs) == 0 {
+		return nil, nil
+	}
+	return &errorGroupIDs[0], nil
+}
+
+// getEmbeddingsErrorObject returns the error object to embed with the event and stack frames the error is grouped by,
+// which are those of the root cause of chained errors, without the stack frames ignored by the grouping rules.
+func getEmbeddingsErrorObject(errorObj *model.ErrorObject, stackTrace []*privateModel.ErrorTrace, groupingEvent string, groupingStackTrace []*privateModel.ErrorTrace) *model.ErrorObject {
+	if groupingEvent == errorObj.Event && len(groupingStackTrace) == len(stackTrace) {
+		return errorObj
+	}
+	stackTraceBytes, err := json.Marshal(groupingStackTrace)
+	if err != nil {
+		return errorObj
+	}
+	embeddingsObj := *errorObj
+	embeddingsObj.Event = groupingEvent
+	embeddingsObj.StackTrace = ptr.String(string(stackTraceBytes))
+	embeddingsObj.MappedStackTrace = nil
+	return &embeddingsObj
+}
+
 // Matches the ErrorObject with an existing ErrorGroup, or creates a new one if the group does not exist
 func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.ErrorObject, structuredStackTrace []*privateModel.ErrorTrace, fields []*model.ErrorField, projectID int, workspace *model.Workspace) (*model.ErrorGroup, error) {
 	if errorObj == nil {
@@ -664,14 +729,6 @@ func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.Erro
 			return nil, ErrNoisyError
 		}
 	}
-	if project.ID == 356 {
-		if errorObj.Event == `["\"ReferenceError: Can't find variable: widgetContainerAttribute\""]` ||
-			errorObj.Event == `"ReferenceError: Can't find variable: widgetContainerAttribute"` ||
-			errorObj.Event == `"InvalidStateError: XMLHttpRequest.responseText getter: responseText is only available if responseType is '' or 'text'."` ||
-			errorObj.Event == `["\"InvalidStateError: XMLHttpRequest.responseText getter: responseText is only available if responseType is '' or 'text'.\""]` {
-			return nil, ErrNoisyError
-		}
-	}
 	if project.ID == 765 {
 		if errorObj.Event == `"Uncaught Error: PollingBlockTracker - encountered an error while attempting to update latest block:\nundefined"` ||
 			errorObj.Event == `["\"Uncaught Error: PollingBlockTracker - encountered an error while attempting to update latest block:\\nundefined\""]` {
@@ -734,34 +791,6 @@ func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.Erro
 		}
 	}
 
-	fingerprints := []*model.ErrorFingerprint{}
-	fingerprints = append(fingerprints, errorgroups.GetFingerprints(projectID, structuredStackTrace)...)
-
-	// Try unmarshalling the Event to JSON.
-	// If this works, create an error fingerprint for each of the project's JSON paths.
-	jsonStrings := []string{}
-	if err := json.Unmarshal([]byte(errorObj.Event), &jsonStrings); err == nil && len(jsonStrings) == 1 {
-		errorAsJson := interface{}(nil)
-		if err := json.Unmarshal([]byte(jsonStrings[0]), &errorAsJson); err == nil {
-			for _, path := range project.ErrorJsonPaths {
-				value, err := jsonpath.Get(path, errorAsJson)
-				if err == nil {
-					marshalled, err := json.Marshal(value)
-					if err == nil {
-						jsonResult := model.ErrorFingerprint{
-							ProjectID: projectID,
-							Type:      model.Fingerprint.JsonResult,
-							Value:     path + "=" + string(marshalled),
-						}
-						fingerprints = append(fingerprints, &jsonResult)
-					}
-				}
-			}
-		}
-	}
-
-	var errorGroup *model.ErrorGroup
-
 	var settings *model.AllWorkspaceSettings
 	if workspace != nil {
 		if settings, err = r.Store.GetAllWorkspaceSettings(ctx, workspace.ID); err != nil {
@@ -769,11 +798,50 @@ func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.Erro
 		}
 	}
 
+	// chained errors are matched on their root cause when the workspace groups by root cause
+	groupingEvent, groupingStackTrace := errorObj.Event, structuredStackTrace
+	if settings != nil && settings.ErrorGroupRootCause {
+		if event, rootCauseStackTrace, ok := errorgroups.GetRootCause(errorObj); ok {
+			groupingEvent, groupingStackTrace = event, rootCauseStackTrace
+		}
+	}
+
+	// the project's grouping rules drop ignored frames and may group the error by a custom fingerprint
+	rules, err := r.Store.GetErrorGroupingRules(ctx, projectID)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Error("failed to get error grouping rules, using default grouping")
+		rules = nil
+	}
+	grouping := errorgroups.ApplyGroupingRules(rules, errorObj.Type, groupingEvent, groupingStackTrace)
+
+	fingerprints := []*model.ErrorFingerprint{}
+	fingerprints = append(fingerprints, errorgroups.GetFingerprints(projectID, grouping.StackTrace)...)
+	if grouping.Fingerprint != nil {
+		fingerprints = append(fingerprints, &model.ErrorFingerprint{
+			ProjectID: projectID,
+			Type:      model.Fingerprint.CustomRule,
+			Value:     *grouping.Fingerprint,
+		})
+	}
+
+	// If the Event is JSON, create an error fingerprint for each of the project's JSON paths.
+	fingerprints = append(fingerprints, errorgroups.GetJsonFingerprints(projectID, project.ErrorJsonPaths, errorObj.Event)...)
+
+	var errorGroup *model.ErrorGroup
+
 	var embedding *model.ErrorObjectEmbeddings
-	if settings != nil && settings.ErrorEmbeddingsGroup {
+	if grouping.Fingerprint != nil {
+		errorGroup, err = r.GetOrCreateErrorGroup(ctx, errorObj, func() (*int, error) {
+			return r.GetErrorGroupMatchByFingerprint(ctx, errorObj.ProjectID, *grouping.Fingerprint)
+		}, settings != nil && settings.ErrorEmbeddingsTagGroup)
+		if err != nil {
+			return nil, e.Wrap(err, "Error getting or creating error group")
+		}
+		errorObj.ErrorGroupingMethod = model.ErrorGroupingMethodCustomRule
+	} else if settings != nil && settings.ErrorEmbeddingsGroup {
 		eCtx, cancel := context.WithTimeout(ctx, embeddings.InferenceTimeout)
 		defer cancel()
-		emb, err := r.EmbeddingsClient.GetEmbeddings(eCtx, []*model.ErrorObject{errorObj})
+		emb, err := r.EmbeddingsClient.GetEmbeddings(eCtx, []*model.ErrorObject{getEmbeddingsErrorObject(errorObj, structuredStackTrace, groupingEvent, grouping.StackTrace)})
 		if err != nil || len(emb) == 0 {
 			log.WithContext(ctx).WithError(err).WithField("error_object_id", errorObj.ID).Error("failed to get embeddings")
 			errorObj.ErrorGroupingMethod = model.ErrorGroupingMethodClassic
@@ -797,7 +865,7 @@ func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.Erro
 	if errorGroup == nil {
 		log.WithContext(ctx).WithError(err).WithField("error_object_id", errorObj.ID).Error("failed to create error group by embedding; using classic match")
 		errorGroup, err = r.GetOrCreateErrorGroup(ctx, errorObj, func() (*int, error) {
-			match, err := r.GetTopErrorGroupMatch(errorObj.Event, errorObj.ProjectID, fingerprints)
+			match, err := r.GetTopErrorGroupMatch(groupingEvent, errorObj.ProjectID, fingerprints)
 			if err != nil {
 				return nil, e.Wrap(err, "Error getting top error group match")
 			}
@@ -851,6 +919,7 @@ func (r *Resolver) HandleErrorAndGroup(ctx context.Context, errorObj *model.Erro
 				FROM error_fingerprints
 				WHERE id NOT IN (?)
 				AND error_group_id = ?
+				AND merged_from_id IS NULL
 				ORDER BY id
 				FOR UPDATE
 			)
@@ -1335,6 +1404,9 @@ func (r *Resolver) AddSessionFeedbackImpl(ctx context.Context, input *kafka_queu
 	}
 
 	for _, errorAlert := range errorAlerts {
+		if errorAlert.IsRegressionAlert() {
+			continue
+		}
 		excludedEnvironments, err := errorAlert.GetExcludedEnvironments()
 		if err != nil {
 			log.WithContext(ctx).Error(e.Wrapf(err, "error getting excluded environments from %s alert", model.AlertType.ERROR_FEEDBACK))
@@ -1377,6 +1449,10 @@ func (r *Resolver) AddSessionFeedbackImpl(ctx context.Context, input *kafka_queu
 				Error(e.Wrap(err, "error fetching workspace"))
 		}
 
+		if model.IsAlertSilenced(ctx, r.DB, model.GetErrorFeedbackAlertSilenceMatch(errorAlert, session), model.AllAlertDestinations) {
+			continue
+		}
+
 		errorAlert.SendAlertFeedback(ctx, r.DB, r.MailClient, &model.SendSlackAlertInput{
 			Workspace:       workspace,
 			SessionSecureID: session.SecureID,
@@ -1384,9 +1460,10 @@ func (r *Resolver) AddSessionFeedbackImpl(ctx context.Context, input *kafka_queu
 			UserIdentifier:  identifier,
 			CommentID:       &feedbackComment.ID,
 			CommentText:     feedbackComment.Text,
+			Environment:     session.Environment,
 		})
 
-		if err = alerts.SendErrorFeedbackAlert(alerts.ErrorFeedbackAlertEvent{
+		if err = alerts.SendErrorFeedbackAlert(ctx, r.DB, alerts.ErrorFeedbackAlertEvent{
 			Session:        session,
 			ErrorAlert:     errorAlert,
 			SessionComment: feedbackComment,
@@ -1718,7 +1795,12 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 		}
 
 		for _, errorAlert := range errorAlerts {
-			if errorAlert.CountThreshold < 1 {
+			// regression alerts notify of every regression, regardless of the count threshold and the alert frequency
+			regressionAlert := errorAlert.IsRegressionAlert()
+			if regressionAlert && !group.Regressed {
+				continue
+			}
+			if !regressionAlert && errorAlert.ThresholdType != privateModel.ThresholdTypeAnomaly && errorAlert.CountThreshold < 1 {
 				continue
 			}
 			excludedEnvironments, err := errorAlert.GetExcludedEnvironments()
@@ -1780,9 +1862,9 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				}
 			}
 
-			// Suppress alerts if ignored or snoozed.
+			// Suppress alerts if ignored, snoozed, or resolved in the next release.
 			snoozed := group.SnoozedUntil != nil && group.SnoozedUntil.After(time.Now())
-			if group == nil || group.State == privateModel.ErrorStateIgnored || snoozed {
+			if group == nil || group.State == privateModel.ErrorStateIgnored || group.State == privateModel.ErrorStateResolved || snoozed {
 				continue
 			}
 
@@ -1798,7 +1880,17 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				log.WithContext(ctx).Error(e.Wrapf(err, "error counting errors from past %d minutes", *errorAlert.ThresholdWindow))
 				continue
 			}
-			if numErrors+1 < int64(errorAlert.CountThreshold) {
+			if !regressionAlert && errorAlert.ThresholdType == privateModel.ThresholdTypeAnomaly {
+				window := time.Duration(*errorAlert.ThresholdWindow) * time.Minute
+				baseline, err := alerts.GetErrorAlertBaseline(ctx, r.Clickhouse, r.Redis, projectID, &group.ID, time.Now())
+				if err != nil {
+					log.WithContext(ctx).Error(e.Wrap(err, "error learning the baseline of the error alert"))
+					continue
+				}
+				if anomaly, _ := baseline.IsAnomaly(float64(numErrors+1), time.Now().Add(-window), window, errorAlert.AnomalyDeviations, false); !anomaly {
+					continue
+				}
+			} else if !regressionAlert && numErrors+1 < int64(errorAlert.CountThreshold) {
 				continue
 			}
 
@@ -1835,7 +1927,7 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				}
 			}
 
-			if recentAlertCount > 0 {
+			if recentAlertCount > 0 && !regressionAlert {
 				log.WithContext(ctx).Warnf("num alerts > 0 for project_id=%d, error_group_id=%d", projectID, group.ID)
 				continue
 			}
@@ -1846,6 +1938,11 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				continue
 			}
 
+			// silences mute every destination of the alert, so they are checked once for all of them
+			if model.IsAlertSilenced(ctx, r.DB, model.GetErrorAlertSilenceMatch(errorAlert, errorObject), model.AllAlertDestinations) {
+				continue
+			}
+
 			workspace, err := r.Store.GetWorkspace(ctx, project.WorkspaceID)
 			if err != nil {
 				log.WithContext(ctx).Error(err)
@@ -1860,7 +1957,7 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				log.WithContext(ctx).Error(e.Wrapf(err, "error sending error alert to Zapier (error alert id: %d)", errorAlert.ID))
 			}
 
-			if err := alerts.SendErrorAlert(ctx, alerts.SendErrorAlertEvent{
+			if err := alerts.SendErrorAlert(ctx, r.DB, alerts.SendErrorAlertEvent{
 				Session:         sessionObj,
 				ErrorAlert:      errorAlert,
 				ErrorGroup:      group,
@@ -1868,6 +1965,7 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				Workspace:       workspace,
 				ErrorCount:      numErrors,
 				FirstErrorAlert: totalAlertCount <= 0,
+				Regression:      regressionAlert,
 				VisitedURL:      visitedUrl,
 			}); err != nil {
 				log.WithContext(ctx).Error(err)
@@ -1884,6 +1982,7 @@ func (r *Resolver) sendErrorAlert(ctx context.Context, projectID int, sessionObj
 				URL:             &visitedUrl,
 				ErrorsCount:     &numErrors,
 				FirstErrorAlert: totalAlertCount <= 0,
+				Regression:      regressionAlert,
 				UserObject:      sessionObj.UserObject,
 			})
 		}
@@ -2222,6 +2321,25 @@ func (r *Resolver) ProcessBackendPayloadImpl(ctx context.Context, sessionSecureI
 			ServiceVersion: v.Service.Version,
 		}
 
+		if len(v.Causes) > 0 {
+			var causes []*model.ErrorCause
+			for _, c := range v.Causes {
+				cause := &model.ErrorCause{Type: c.Type, Event: c.Event, StackTrace: c.StackTrace}
+				// the frames of each cause are enhanced like those of the error so that the root cause groups the same way
+				if mappedCauseStackTrace, _, err := r.Store.EnhancedStackTrace(ctx, c.StackTrace, workspace, &project, errorToInsert, nil); err != nil {
+					log.WithContext(ctx).WithError(err).Warn("failed to enhance error cause stacktrace")
+				} else if mappedCauseStackTrace != nil {
+					cause.StackTrace = *mappedCauseStackTrace
+				}
+				causes = append(causes, cause)
+			}
+			if causesBytes, err := json.Marshal(causes); err != nil {
+				log.WithContext(ctx).WithError(err).Error("failed to marshal error causes")
+			} else {
+				errorToInsert.Causes = pointy.String(string(causesBytes))
+			}
+		}
+
 		var mappedStackTrace *string
 		var structuredStackTrace []*privateModel.ErrorTrace
 		mappedStackTrace, structuredStackTrace, err = r.Store.EnhancedStackTrace(ctx, v.StackTrace, workspace, &project, errorToInsert, nil)
@@ -2246,6 +2364,10 @@ func (r *Resolver) ProcessBackendPayloadImpl(ctx context.Context, sessionSecureI
 			continue
 		}
 
+		// alert on the regression even when later errors of the batch were grouped after the group reopened
+		if data, ok := groups[group.ID]; ok && data.Group.Regressed {
+			group.Regressed = true
+		}
 		groups[group.ID] = struct {
 			Group      *model.ErrorGroup
 			VisitedURL string
@@ -2724,6 +2846,10 @@ func (r *Resolver) ProcessPayload(ctx context.Context, sessionSecureID string, e
 				continue
 			}
 
+			// alert on the regression even when later errors of the batch were grouped after the group reopened
+			if data, ok := groups[group.ID]; ok && data.Group.Regressed {
+				group.Regressed = true
+			}
 			groups[group.ID] = struct {
 				Group      *model.ErrorGroup
 				VisitedURL string
@@ -2962,6 +3088,10 @@ func (r *Resolver) SendSessionInitAlert(ctx context.Context, workspace *model.Wo
 			}
 		}
 
+		if model.IsAlertSilenced(ctx, r.DB, model.GetSessionAlertSilenceMatch(sessionAlert, sessionObj), model.AllAlertDestinations) {
+			continue
+		}
+
 		hookPayload := zapier.HookPayload{
 			UserIdentifier: sessionObj.Identifier, UserObject: sessionObj.UserObject, UserProperties: userProperties, URL: visitedUrl,
 		}
@@ -2975,10 +3105,11 @@ func (r *Resolver) SendSessionInitAlert(ctx context.Context, workspace *model.Wo
 			SessionExcluded: sessionObj.Excluded && *sessionObj.Processed,
 			UserIdentifier:  sessionObj.Identifier,
 			UserObject:      sessionObj.UserObject,
+			Environment:     sessionObj.Environment,
 			UserProperties:  userProperties,
 			URL:             visitedUrl,
 		})
-		if err = alerts.SendNewSessionAlert(alerts.SendNewSessionAlertEvent{
+		if err = alerts.SendNewSessionAlert(ctx, r.DB, alerts.SendNewSessionAlertEvent{
 			Session:      sessionObj,
 			SessionAlert: sessionAlert,
 			Workspace:    workspace,
@@ -3114,6 +3245,10 @@ func (r *Resolver) SendSessionTrackPropertiesAlert(ctx context.Context, workspac
 			continue
 		}
 
+		if model.IsAlertSilenced(ctx, r.DB, model.GetSessionAlertSilenceMatch(sessionAlert, session), model.AllAlertDestinations) {
+			continue
+		}
+
 		hookPayload := zapier.HookPayload{
 			UserIdentifier: session.Identifier, MatchedFields: matchedFields, RelatedFields: relatedFields, UserObject: session.UserObject,
 		}
@@ -3129,8 +3264,9 @@ func (r *Resolver) SendSessionTrackPropertiesAlert(ctx context.Context, workspac
 			MatchedFields:   matchedFields,
 			RelatedFields:   relatedFields,
 			UserObject:      session.UserObject,
+			Environment:     session.Environment,
 		})
-		if err = alerts.SendTrackPropertiesAlert(alerts.TrackPropertiesAlertEvent{
+		if err = alerts.SendTrackPropertiesAlert(ctx, r.DB, alerts.TrackPropertiesAlertEvent{
 			Session:       session,
 			SessionAlert:  sessionAlert,
 			Workspace:     workspace,
@@ -3193,6 +3329,10 @@ func (r *Resolver) SendSessionIdentifiedAlert(ctx context.Context, workspace *mo
 			continue
 		}
 
+		if model.IsAlertSilenced(ctx, r.DB, model.GetSessionAlertSilenceMatch(sessionAlert, refetchedSession), model.AllAlertDestinations) {
+			continue
+		}
+
 		hookPayload := zapier.HookPayload{
 			UserIdentifier: session.Identifier, UserProperties: userProperties, UserObject: session.UserObject,
 		}
@@ -3207,8 +3347,9 @@ func (r *Resolver) SendSessionIdentifiedAlert(ctx context.Context, workspace *mo
 			UserIdentifier:  refetchedSession.Identifier,
 			UserProperties:  userProperties,
 			UserObject:      refetchedSession.UserObject,
+			Environment:     refetchedSession.Environment,
 		})
-		if err = alerts.SendNewUserAlert(alerts.SendNewUserAlertEvent{
+		if err = alerts.SendNewUserAlert(ctx, r.DB, alerts.SendNewUserAlertEvent{
 			Session:      session,
 			SessionAlert: sessionAlert,
 			Workspace:    workspace,
@@ -3282,6 +3423,10 @@ func (r *Resolver) SendSessionUserPropertiesAlert(ctx context.Context, workspace
 			continue
 		}
 
+		if model.IsAlertSilenced(ctx, r.DB, model.GetSessionAlertSilenceMatch(sessionAlert, session), model.AllAlertDestinations) {
+			continue
+		}
+
 		hookPayload := zapier.HookPayload{
 			UserIdentifier: session.Identifier, MatchedFields: matchedFields, UserObject: session.UserObject,
 		}
@@ -3296,8 +3441,9 @@ func (r *Resolver) SendSessionUserPropertiesAlert(ctx context.Context, workspace
 			UserIdentifier:  session.Identifier,
 			MatchedFields:   matchedFields,
 			UserObject:      session.UserObject,
+			Environment:     session.Environment,
 		})
-		if err = alerts.SendUserPropertiesAlert(alerts.UserPropertiesAlertEvent{
+		if err = alerts.SendUserPropertiesAlert(ctx, r.DB, alerts.UserPropertiesAlertEvent{
 			SessionAlert:  sessionAlert,
 			Session:       session,
 			Workspace:     workspace,
@@ -95,6 +95,7 @@ func TestMain(m *testing.M) {
 		EmbeddingsClient: &mockEmbeddingsClient{},
 		DataSyncQueue:    &kafka_queue.MockMessageQueue{},
 		TracesQueue:      &kafka_queue.MockMessageQueue{},
+		BatchedQueue:     &kafka_queue.MockMessageQueue{},
 	}
 	code := m.Run()
 	os.Exit(code)
@@ -470,6 +471,8 @@ func TestUpdatingErrorState(t *testing.T) {
 		errorGroup, err = resolver.HandleErrorAndGroup(ctx, &errorObject2, structuredStackTrace, nil, project.ID, nil)
 		assert.NoError(t, err)
 		assert.Equal(t, errorGroup.State, privateModel.ErrorStateOpen)
+		assert.True(t, errorGroup.Regressed)
+		assert.NotNil(t, errorGroup.RegressedAt)
 
 		// Ignore
 		_, err = resolver.Store.UpdateErrorGroupStateBySystem(ctx, store.UpdateErrorGroupParams{
@@ -491,6 +494,59 @@ func TestUpdatingErrorState(t *testing.T) {
 	})
 }
 
+func TestResolveInNextRelease(t *testing.T) {
+	ctx := context.TODO()
+
+	stacktrace := `[{"fileName":"/app/src/index.js","lineNumber":12,"functionName":"render","columnNumber":3}]`
+	var structuredStackTrace []*privateModel.ErrorTrace
+	if err := json.Unmarshal([]byte(stacktrace), &structuredStackTrace); err != nil {
+		t.Fatal("failed to generate structured stacktrace")
+	}
+
+	util.RunTestWithDBWipe(t, resolver.DB, func(t *testing.T) {
+		project := model.Project{}
+		resolver.DB.Create(&project)
+
+		newErrorObject := func(serviceVersion string) *model.ErrorObject {
+			return &model.ErrorObject{
+				Event:          "error",
+				ProjectID:      project.ID,
+				StackTrace:     &stacktrace,
+				ServiceVersion: serviceVersion,
+			}
+		}
+
+		errorGroup, err := resolver.HandleErrorAndGroup(ctx, newErrorObject("1.0.0"), structuredStackTrace, nil, project.ID, nil)
+		assert.NoError(t, err)
+
+		resolvedErrorGroup, err := resolver.Store.UpdateErrorGroupStateBySystem(ctx, store.UpdateErrorGroupParams{
+			ID:                   errorGroup.ID,
+			State:                privateModel.ErrorStateResolved,
+			ResolveInNextRelease: true,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0.0", *resolvedErrorGroup.ResolvedInVersion)
+
+		// errors of the release the group was resolved in keep it resolved
+		errorGroup, err = resolver.HandleErrorAndGroup(ctx, newErrorObject("1.0.0"), structuredStackTrace, nil, project.ID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, privateModel.ErrorStateResolved, errorGroup.State)
+		assert.False(t, errorGroup.Regressed)
+
+		errorGroup, err = resolver.HandleErrorAndGroup(ctx, newErrorObject("1.1.0"), structuredStackTrace, nil, project.ID, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, privateModel.ErrorStateOpen, errorGroup.State)
+		assert.True(t, errorGroup.Regressed)
+		assert.NotNil(t, errorGroup.RegressedAt)
+
+		activityLogs, err := resolver.Store.GetErrorGroupActivityLogs(errorGroup.ID)
+		assert.NoError(t, err)
+		assert.True(t, lo.SomeBy(activityLogs, func(activityLog model.ErrorGroupActivityLog) bool {
+			return activityLog.EventType == model.ErrorGroupRegressedEvent
+		}))
+	})
+}
+
 func TestResolver_isExcludedError(t *testing.T) {
 	assert.False(t, resolver.isExcludedError(context.Background(), 1, []string{}, ""))
 	assert.True(t, resolver.isExcludedError(context.Background(), 2, []string{}, "[{}]"))
@@ -643,3 +699,37 @@ func TestInitializeSessionImpl(t *testing.T) {
 		assert.NotNil(t, service.ID)
 	})
 }
+
+func TestGetEmbeddingsErrorObject(t *testing.T) {
+	stackTrace := []*privateModel.ErrorTrace{
+		{FileName: ptr.String("vendor/lib.go"), FunctionName: ptr.String("wrap")},
+		{FileName: ptr.String("main.go"), FunctionName: ptr.String("connect")},
+	}
+	errorObj := &model.ErrorObject{
+		Event:            "dial tcp: i/o timeout",
+		StackTrace:       ptr.String(`[{"fileName":"vendor/lib.go"},{"fileName":"main.go"}]`),
+		MappedStackTrace: ptr.String(`[{"fileName":"vendor/lib.go"},{"fileName":"main.go"}]`),
+	}
+
+	// without ignored frames, the error object is embedded as is
+	assert.Same(t, errorObj, getEmbeddingsErrorObject(errorObj, stackTrace, errorObj.Event, stackTrace))
+
+	embeddingsObj := getEmbeddingsErrorObject(errorObj, stackTrace, errorObj.Event, stackTrace[1:])
+	expected, err := json.Marshal(stackTrace[1:])
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), *embeddingsObj.StackTrace)
+	assert.Nil(t, embeddingsObj.MappedStackTrace)
+	assert.Equal(t, errorObj.Event, embeddingsObj.Event)
+	assert.NotNil(t, errorObj.MappedStackTrace)
+
+	// chained errors grouped by their root cause embed the event and frames of the root cause
+	rootCauseStackTrace := []*privateModel.ErrorTrace{
+		{FileName: ptr.String("net/dial.go"), FunctionName: ptr.String("dial")},
+	}
+	embeddingsObj = getEmbeddingsErrorObject(errorObj, stackTrace, "connection refused", rootCauseStackTrace)
+	expected, err = json.Marshal(rootCauseStackTrace)
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), *embeddingsObj.StackTrace)
+	assert.Equal(t, "connection refused", embeddingsObj.Event)
+	assert.Equal(t, "dial tcp: i/o timeout", errorObj.Event)
+}
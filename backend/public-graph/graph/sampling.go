@@ -50,6 +50,10 @@ func (r *Resolver) IsTraceIngested(ctx context.Context, trace *clickhouse.TraceR
 }
 
 func (r *Resolver) IsTraceIngestedBySample(ctx context.Context, trace *clickhouse.TraceRow) bool {
+	// traces of projects with tail sampling rules are sampled as a whole by TailSampleSpans
+	if r.getTailSamplingSettings(ctx, int(trace.ProjectId)) != nil {
+		return true
+	}
 	return r.isItemIngestedBySample(ctx, privateModel.ProductTypeTraces, int(trace.ProjectId), trace.TraceId)
 }
 
@@ -102,6 +106,38 @@ func (r *Resolver) IsLogIngestedByFilter(ctx context.Context, logRow *clickhouse
 	return r.isItemIngestedByFilter(ctx, privateModel.ProductTypeLogs, int(logRow.ProjectId), logRow)
 }
 
+// IsMetricIngested applies the trace exclusion, sampling and rate limit settings to metric data points,
+// which are billed as traces.
+func (r *Resolver) IsMetricIngested(ctx context.Context, metricRow *clickhouse.MetricRow) bool {
+	span := util.StartSpan(
+		"IsIngestedBy", util.ResourceName("sampling"), util.WithHighlightTracingDisabled(true),
+		util.Tag(highlight.ProjectIDAttribute, metricRow.ProjectId),
+		util.Tag(highlight.TraceTypeAttribute, highlight.TraceTypeHighlightInternal),
+		util.Tag(highlight.TraceKeyAttribute, metricRow.UUID),
+		util.Tag("product", privateModel.ProductTypeTraces),
+		util.Tag("ingested", true),
+	)
+	defer span.Finish()
+
+	if !r.isItemIngestedByFilter(ctx, privateModel.ProductTypeTraces, int(metricRow.ProjectId), metricRow) {
+		span.SetAttribute("ingested", false)
+		span.SetAttribute("reason", privateModel.IngestReasonFilter)
+		return false
+	}
+	// metric data points are not part of a trace, so each one is sampled on its own
+	if !r.isItemIngestedBySample(ctx, privateModel.ProductTypeTraces, int(metricRow.ProjectId), metricRow.UUID) {
+		span.SetAttribute("ingested", false)
+		span.SetAttribute("reason", privateModel.IngestReasonSample)
+		return false
+	}
+	if !r.isItemIngestedByRate(ctx, metricRow.Timestamp, privateModel.ProductTypeTraces, int(metricRow.ProjectId)) {
+		span.SetAttribute("ingested", false)
+		span.SetAttribute("reason", privateModel.IngestReasonRate)
+		return false
+	}
+	return true
+}
+
 func (r *Resolver) IsFrontendErrorIngested(ctx context.Context, projectID int, session *model.Session, frontendError *modelInputs.ErrorObjectInput) bool {
 	stack, _ := json.Marshal(frontendError.StackTrace)
 	errorObject := &modelInputs.BackendErrorObjectInput{
@@ -379,18 +415,26 @@ func (r *Resolver) isItemIngestedByFilter(ctx context.Context, product privateMo
 		return true
 	}
 
-	filters := queryparser.Parse(query)
+	filters, err := queryparser.ParseQuery(query)
+	if err != nil {
+		log.WithContext(ctx).WithError(err).WithField("project_id", projectID).Warn("invalid exclusion query")
+		return true
+	}
 
 	excluded := func() bool {
 		switch product {
 		case privateModel.ProductTypeSessions:
-			return clickhouse.SessionMatchesQuery(object.(*model.Session), &filters)
+			return clickhouse.SessionMatchesQuery(object.(*model.Session), filters)
 		case privateModel.ProductTypeErrors:
-			return clickhouse.ErrorMatchesQuery(object.(*modelInputs.BackendErrorObjectInput), &filters)
+			return clickhouse.ErrorMatchesQuery(object.(*modelInputs.BackendErrorObjectInput), filters)
 		case privateModel.ProductTypeLogs:
-			return clickhouse.LogMatchesQuery(object.(*clickhouse.LogRow), &filters)
+			return clickhouse.LogMatchesQuery(object.(*clickhouse.LogRow), filters)
 		case privateModel.ProductTypeTraces:
-			return clickhouse.TraceMatchesQuery(object.(*clickhouse.TraceRow), &filters)
+			// metrics are filtered by the trace exclusion query
+			if metricRow, ok := object.(*clickhouse.MetricRow); ok {
+				return clickhouse.MetricMatchesQuery(metricRow, filters)
+			}
+			return clickhouse.TraceMatchesQuery(object.(*clickhouse.TraceRow), filters)
 		}
 		return false
 	}()
@@ -3,6 +3,7 @@ package graph
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/highlight-run/highlight/backend/clickhouse"
 	"github.com/highlight-run/highlight/backend/model"
 	modelInputs "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	model2 "github.com/highlight-run/highlight/backend/public-graph/graph/model"
@@ -157,3 +158,91 @@ func Test_isExcludedError(t *testing.T) {
 	assert.False(t, resolver.IsErrorIngestedByFilter(ctx, p2.ID, &model2.BackendErrorObjectInput{Event: "foo bar baz"}))
 	assert.True(t, resolver.IsErrorIngestedByFilter(ctx, p3.ID, &model2.BackendErrorObjectInput{Event: "foo bar baz"}))
 }
+
+func Test_isSpanKept(t *testing.T) {
+	settings := &model.ProjectFilterSettings{
+		TraceSamplingRate:         0.5,
+		TraceKeepOnError:          true,
+		TraceLatencyThresholdMs:   pointy.Int64(500),
+		TraceServiceSamplingRates: model.JSONB{"api": 0.1},
+	}
+	assert.True(t, isTailSamplingEnabled(settings))
+	assert.False(t, isTailSamplingEnabled(&model.ProjectFilterSettings{TraceSamplingRate: 0.5}))
+
+	assert.True(t, isSpanKept(settings, &clickhouse.TraceRow{ParentSpanId: "abc", StatusCode: "Error"}))
+	assert.True(t, isSpanKept(settings, &clickhouse.TraceRow{ParentSpanId: "abc", Events: []*clickhouse.Event{{Name: "exception"}}}))
+	assert.False(t, isSpanKept(settings, &clickhouse.TraceRow{ParentSpanId: "abc", Duration: int64(time.Second)}))
+	assert.True(t, isSpanKept(settings, &clickhouse.TraceRow{Duration: int64(time.Second)}))
+	assert.False(t, isSpanKept(settings, &clickhouse.TraceRow{Duration: int64(time.Millisecond)}))
+
+	assert.Equal(t, 0.1, getServiceSamplingRate(settings, "api"))
+	assert.Equal(t, 0.5, getServiceSamplingRate(settings, "web"))
+}
+
+func Test_TailSampleSpans(t *testing.T) {
+	ctx := context.TODO()
+	if err := resolver.Redis.FlushDB(ctx); err != nil {
+		t.Error(err)
+	}
+
+	project := model.Project{}
+	resolver.DB.Create(&project)
+	resolver.DB.Create(&model.ProjectFilterSettings{
+		ProjectID:                 project.ID,
+		TraceKeepOnError:          true,
+		TraceServiceSamplingRates: model.JSONB{"api": 0.},
+	})
+	projectID := uint32(project.ID)
+
+	// spans are buffered until the root span of their trace arrives
+	assert.Empty(t, resolver.TailSampleSpans(ctx, []*clickhouse.TraceRow{
+		{ProjectId: projectID, TraceId: "kept", SpanId: "child", ParentSpanId: "root", ServiceName: "api"},
+		{ProjectId: projectID, TraceId: "dropped", SpanId: "child", ParentSpanId: "root", ServiceName: "api"},
+		{ProjectId: projectID, TraceId: "pending", SpanId: "child", ParentSpanId: "root", ServiceName: "api", StatusCode: "Error"},
+	}))
+	assert.Empty(t, resolver.TailSampleLogs(ctx, []*clickhouse.LogRow{{ProjectId: projectID, TraceId: "pending", Body: "hello"}}))
+	expired, err := resolver.Redis.GetExpiredTailSampledTraces(ctx, time.Now(), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+	expired, err = resolver.Redis.GetExpiredTailSampledTraces(ctx, time.Now().Add(TailSamplingTimeout), 10)
+	assert.NoError(t, err)
+	assert.Len(t, expired, 3)
+
+	// the trace with an error is kept along with its buffered spans once its root span arrives,
+	// and the trace without errors is dropped by the service sampling rate
+	spans := resolver.TailSampleSpans(ctx, []*clickhouse.TraceRow{
+		{ProjectId: projectID, TraceId: "kept", SpanId: "root", ServiceName: "api", StatusCode: "Error"},
+		{ProjectId: projectID, TraceId: "dropped", SpanId: "root", ServiceName: "api"},
+	})
+	assert.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, "kept", span.TraceId)
+	}
+
+	// spans and logs arriving after the decision follow it
+	assert.Len(t, resolver.TailSampleSpans(ctx, []*clickhouse.TraceRow{{ProjectId: projectID, TraceId: "kept", SpanId: "late", ParentSpanId: "root", ServiceName: "api"}}), 1)
+	assert.Len(t, resolver.TailSampleLogs(ctx, []*clickhouse.LogRow{{ProjectId: projectID, TraceId: "kept"}, {ProjectId: projectID}}), 2)
+	assert.Empty(t, resolver.TailSampleSpans(ctx, []*clickhouse.TraceRow{{ProjectId: projectID, TraceId: "dropped", SpanId: "late", ParentSpanId: "root", ServiceName: "api"}}))
+	assert.Len(t, resolver.TailSampleLogs(ctx, []*clickhouse.LogRow{{ProjectId: projectID, TraceId: "dropped"}, {ProjectId: projectID}}), 1)
+
+	// decided traces are no longer pending, and the trace without a root span is sampled by the sweeper
+	expired, err = resolver.Redis.GetExpiredTailSampledTraces(ctx, time.Now().Add(TailSamplingTimeout), 10)
+	assert.NoError(t, err)
+	assert.Len(t, expired, 1)
+	assert.Equal(t, "pending", expired[0].TraceID)
+	assert.Equal(t, project.ID, expired[0].ProjectID)
+
+	spans, logs, err := resolver.decideTailSampledTrace(ctx, project.ID, "pending")
+	assert.NoError(t, err)
+	assert.Len(t, spans, 1)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, "hello", logs[0].Body)
+
+	// a decided trace is not decided again
+	spans, _, err = resolver.decideTailSampledTrace(ctx, project.ID, "pending")
+	assert.NoError(t, err)
+	assert.Empty(t, spans)
+	expired, err = resolver.Redis.GetExpiredTailSampledTraces(ctx, time.Now().Add(TailSamplingTimeout), 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+}
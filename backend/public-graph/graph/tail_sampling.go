@@ -0,0 +1,252 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/clickhouse"
+	"github.com/highlight-run/highlight/backend/hlog"
+	kafka_queue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+const (
+	// a trace is sampled once its root span arrives, or after this timeout by FlushTailSampledTraces
+	// if the root span does not arrive, eg. when it is sent by a service that is not instrumented
+	TailSamplingTimeout      = 30 * time.Second
+	tailSamplingDecisionTTL  = time.Hour
+	tailSamplingFlushBatch   = 1000
+	tailSamplingFlushTimeout = time.Minute
+)
+
+func isTailSamplingEnabled(settings *model.ProjectFilterSettings) bool {
+	return settings.TraceKeepOnError || settings.TraceLatencyThresholdMs != nil || len(settings.TraceServiceSamplingRates) > 0
+}
+
+func isSpanError(span *clickhouse.TraceRow) bool {
+	if span.StatusCode == ptrace.StatusCodeError.String() {
+		return true
+	}
+	for _, event := range span.Events {
+		if event.Name == semconv.ExceptionEventName {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpanKept returns whether a span requires its whole trace to be kept.
+func isSpanKept(settings *model.ProjectFilterSettings, span *clickhouse.TraceRow) bool {
+	if settings.TraceKeepOnError && isSpanError(span) {
+		return true
+	}
+	if span.ParentSpanId == "" && settings.TraceLatencyThresholdMs != nil {
+		return span.Duration >= *settings.TraceLatencyThresholdMs*int64(time.Millisecond)
+	}
+	return false
+}
+
+func getServiceSamplingRate(settings *model.ProjectFilterSettings, service string) float64 {
+	if rate, ok := settings.TraceServiceSamplingRates[service].(float64); ok {
+		return rate
+	}
+	return settings.TraceSamplingRate
+}
+
+func (r *Resolver) getTailSamplingSettings(ctx context.Context, projectID int) *model.ProjectFilterSettings {
+	settings, err := r.getSettings(ctx, projectID, nil)
+	if err != nil || !isTailSamplingEnabled(settings) {
+		return nil
+	}
+	return settings
+}
+
+// TailSampleSpans buffers the spans of projects with tail sampling rules until their trace is sampled.
+// The root span ends after its children, so the trace is sampled as soon as it arrives, and the spans
+// arriving afterwards follow the decision. It returns the spans that can be written right away.
+func (r *Resolver) TailSampleSpans(ctx context.Context, spans []*clickhouse.TraceRow) []*clickhouse.TraceRow {
+	var ready []*clickhouse.TraceRow
+	for _, span := range spans {
+		settings := r.getTailSamplingSettings(ctx, int(span.ProjectId))
+		if settings == nil || span.TraceId == "" {
+			ready = append(ready, span)
+			continue
+		}
+
+		payload, err := json.Marshal(span)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to marshal tail sampled span")
+			ready = append(ready, span)
+			continue
+		}
+		decision, err := r.Redis.BufferTailSampledSpan(ctx, redis.TailSampledSpan{
+			ProjectID: int(span.ProjectId),
+			TraceID:   span.TraceId,
+			Service:   span.ServiceName,
+			Root:      span.ParentSpanId == "",
+			Keep:      isSpanKept(settings, span),
+		}, payload, time.Now().Add(TailSamplingTimeout))
+		if err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to buffer tail sampled span")
+			ready = append(ready, span)
+			continue
+		}
+		if decision != nil {
+			if *decision {
+				ready = append(ready, span)
+			}
+			continue
+		}
+
+		if span.ParentSpanId == "" {
+			// the trace stays pending to be sampled by FlushTailSampledTraces if it cannot be sampled now
+			kept, logs, err := r.decideTailSampledTrace(ctx, int(span.ProjectId), span.TraceId)
+			if err != nil {
+				log.WithContext(ctx).WithError(err).Error("failed to decide tail sampled trace")
+				continue
+			}
+			if err := r.submitTailSampledLogs(ctx, logs); err != nil {
+				log.WithContext(ctx).WithError(err).Error("failed to submit tail sampled logs")
+			}
+			ready = append(ready, kept...)
+		}
+	}
+	return ready
+}
+
+// TailSampleLogs buffers the logs linked to traces of projects with tail sampling rules until the trace is sampled.
+// It returns the logs that can be written right away.
+func (r *Resolver) TailSampleLogs(ctx context.Context, logRows []*clickhouse.LogRow) []*clickhouse.LogRow {
+	var ready []*clickhouse.LogRow
+	for _, logRow := range logRows {
+		if logRow.TraceId == "" || r.getTailSamplingSettings(ctx, int(logRow.ProjectId)) == nil {
+			ready = append(ready, logRow)
+			continue
+		}
+
+		payload, err := json.Marshal(logRow)
+		if err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to marshal tail sampled log")
+			ready = append(ready, logRow)
+			continue
+		}
+		decision, err := r.Redis.BufferTailSampledLog(ctx, int(logRow.ProjectId), logRow.TraceId, payload, time.Now().Add(TailSamplingTimeout))
+		if err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to buffer tail sampled log")
+			ready = append(ready, logRow)
+			continue
+		}
+		if decision != nil && *decision {
+			ready = append(ready, logRow)
+		}
+	}
+	return ready
+}
+
+// FlushTailSampledTraces samples the buffered traces whose TailSamplingTimeout has passed without their root span
+// arriving and writes the spans and logs of the kept ones.
+func (r *Resolver) FlushTailSampledTraces(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, tailSamplingFlushTimeout)
+	defer cancel()
+
+	traces, err := r.Redis.GetExpiredTailSampledTraces(ctx, time.Now(), tailSamplingFlushBatch)
+	if err != nil {
+		return err
+	}
+	for _, trace := range traces {
+		spans, logs, err := r.decideTailSampledTrace(ctx, trace.ProjectID, trace.TraceID)
+		if err != nil {
+			return err
+		}
+
+		var messages []*kafka_queue.Message
+		for _, span := range spans {
+			messages = append(messages, &kafka_queue.Message{
+				Type:       kafka_queue.PushTraces,
+				PushTraces: &kafka_queue.PushTracesArgs{TraceRow: span},
+			})
+		}
+		if err := r.TracesQueue.Submit(ctx, trace.TraceID, messages...); err != nil {
+			return e.Wrap(err, "failed to submit tail sampled spans")
+		}
+		if err := r.submitTailSampledLogs(ctx, logs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) submitTailSampledLogs(ctx context.Context, logs []*clickhouse.LogRow) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	var messages []*kafka_queue.Message
+	for _, logRow := range logs {
+		messages = append(messages, &kafka_queue.Message{
+			Type:     kafka_queue.PushLogs,
+			PushLogs: &kafka_queue.PushLogsArgs{LogRow: logRow},
+		})
+	}
+	if err := r.BatchedQueue.Submit(ctx, "", messages...); err != nil {
+		return e.Wrap(err, "failed to submit tail sampled logs")
+	}
+	return nil
+}
+
+func (r *Resolver) decideTailSampledTrace(ctx context.Context, projectID int, traceID string) ([]*clickhouse.TraceRow, []*clickhouse.LogRow, error) {
+	trace, err := r.Redis.GetTailSampledTrace(ctx, projectID, traceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keep := trace.Keep
+	if !keep {
+		// projects may have since disabled tail sampling, in which case the buffered spans are sampled as before
+		rate := 1.
+		if settings, err := r.getSettings(ctx, projectID, nil); err == nil {
+			rate = getServiceSamplingRate(settings, trace.Service)
+		}
+		keep = isIngestedBySample(ctx, traceID, rate)
+	}
+
+	decision, spanPayloads, logPayloads, err := r.Redis.DecideTailSampledTrace(ctx, projectID, traceID, keep, tailSamplingDecisionTTL)
+	if err != nil {
+		return nil, nil, err
+	}
+	// already decided by another caller, who is responsible for the buffered data
+	if decision == nil {
+		return nil, nil, nil
+	}
+	hlog.Incr("tail-sampling.decision", []string{"keep:" + strconv.FormatBool(*decision)}, 1)
+	hlog.Histogram("tail-sampling.bufferedSpans", float64(len(spanPayloads)), nil, 1)
+	if !*decision {
+		return nil, nil, nil
+	}
+
+	var spans []*clickhouse.TraceRow
+	for _, payload := range spanPayloads {
+		var span clickhouse.TraceRow
+		if err := json.Unmarshal(payload, &span); err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to unmarshal tail sampled span")
+			continue
+		}
+		spans = append(spans, &span)
+	}
+	var logs []*clickhouse.LogRow
+	for _, payload := range logPayloads {
+		var logRow clickhouse.LogRow
+		if err := json.Unmarshal(payload, &logRow); err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to unmarshal tail sampled log")
+			continue
+		}
+		logs = append(logs, &logRow)
+	}
+	return spans, logs, nil
+}
@@ -0,0 +1,165 @@
+package queryparser
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Operator string
+
+const (
+	OpEqual        Operator = ":"
+	OpExists       Operator = ":*"
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+)
+
+// IsComparison returns whether the operator compares numeric values.
+func (op Operator) IsComparison() bool {
+	return op == OpGreater || op == OpGreaterEqual || op == OpLess || op == OpLessEqual
+}
+
+// Compare applies a comparison operator to two numbers.
+func (op Operator) Compare(a, b float64) bool {
+	switch op {
+	case OpGreater:
+		return a > b
+	case OpGreaterEqual:
+		return a >= b
+	case OpLess:
+		return a < b
+	case OpLessEqual:
+		return a <= b
+	}
+	return false
+}
+
+// Expr is a node of a parsed query. A nil Expr is an empty query, which matches everything.
+type Expr interface {
+	String() string
+}
+
+type AndExpr struct {
+	Exprs []Expr
+}
+
+type OrExpr struct {
+	Exprs []Expr
+}
+
+type NotExpr struct {
+	Expr Expr
+}
+
+// TermExpr matches a single value. Terms without a key search the body of a row.
+type TermExpr struct {
+	Key   string
+	Op    Operator
+	Value string
+	// Number is the value of comparison operators, with durations such as 500ms converted to nanoseconds
+	Number float64
+	// Quoted body terms search for a phrase rather than for individual tokens
+	Quoted bool
+}
+
+func (expr *AndExpr) String() string {
+	return joinExprs(expr.Exprs, " AND ")
+}
+
+func (expr *OrExpr) String() string {
+	return joinExprs(expr.Exprs, " OR ")
+}
+
+func (expr *NotExpr) String() string {
+	return "NOT " + expr.Expr.String()
+}
+
+func (expr *TermExpr) String() string {
+	value := expr.Value
+	if expr.Quoted || strings.ContainsAny(value, " ()\"") {
+		value = strconv.Quote(value)
+	}
+	switch {
+	case expr.Key == "":
+		return value
+	case expr.Op == OpExists:
+		return expr.Key + ":*"
+	default:
+		return expr.Key + string(expr.Op) + value
+	}
+}
+
+// HasWildcard returns whether the value of the term contains a `*` wildcard.
+func (expr *TermExpr) HasWildcard() bool {
+	return strings.Contains(expr.Value, "*")
+}
+
+// LikePattern returns the value of the term as a SQL LIKE pattern.
+func (expr *TermExpr) LikePattern() string {
+	return strings.ReplaceAll(expr.Value, "*", "%")
+}
+
+func joinExprs(exprs []Expr, sep string) string {
+	var parts []string
+	for _, expr := range exprs {
+		parts = append(parts, expr.String())
+	}
+	return "(" + strings.Join(parts, sep) + ")"
+}
+
+// And combines expressions, skipping empty ones.
+func And(exprs ...Expr) Expr {
+	var and []Expr
+	for _, expr := range exprs {
+		switch expr := expr.(type) {
+		case nil:
+		case *AndExpr:
+			and = append(and, expr.Exprs...)
+		default:
+			and = append(and, expr)
+		}
+	}
+	switch len(and) {
+	case 0:
+		return nil
+	case 1:
+		return and[0]
+	}
+	return &AndExpr{Exprs: and}
+}
+
+// Conjuncts returns the expressions that must all match for expr to match.
+func Conjuncts(expr Expr) []Expr {
+	switch expr := expr.(type) {
+	case nil:
+		return nil
+	case *AndExpr:
+		return expr.Exprs
+	}
+	return []Expr{expr}
+}
+
+// HasKey returns whether any term of expr searches the given key.
+func HasKey(expr Expr, key string) bool {
+	switch expr := expr.(type) {
+	case *AndExpr:
+		for _, e := range expr.Exprs {
+			if HasKey(e, key) {
+				return true
+			}
+		}
+	case *OrExpr:
+		for _, e := range expr.Exprs {
+			if HasKey(e, key) {
+				return true
+			}
+		}
+	case *NotExpr:
+		return HasKey(expr.Expr, key)
+	case *TermExpr:
+		return expr.Key == key
+	}
+	return false
+}
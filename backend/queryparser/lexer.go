@@ -0,0 +1,272 @@
+package queryparser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenType int
+
+const (
+	tokenEOF tokenType = iota
+	tokenTerm
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLeftParen
+	tokenRightParen
+)
+
+type token struct {
+	typ tokenType
+	pos int
+	// set for tokenTerm
+	term *TermExpr
+	// a term written as `key:!value`, kept for compatibility with the previous query syntax
+	negated bool
+}
+
+func (t token) String() string {
+	switch t.typ {
+	case tokenEOF:
+		return "end of query"
+	case tokenTerm:
+		return strconv.Quote(t.term.String())
+	case tokenAnd:
+		return "AND"
+	case tokenOr:
+		return "OR"
+	case tokenNot:
+		return "NOT"
+	case tokenLeftParen:
+		return "("
+	case tokenRightParen:
+		return ")"
+	}
+	return "unknown token"
+}
+
+// SyntaxError is returned for invalid queries. Pos is the byte offset of the error in the query.
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at column %d: %s", e.Pos+1, e.Msg)
+}
+
+type lexer struct {
+	query string
+	pos   int
+	// the number of open parentheses, as a `)` outside a group is part of a term
+	depth int
+}
+
+func (l *lexer) errorf(pos int, format string, args ...interface{}) error {
+	return &SyntaxError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (l *lexer) peek() rune {
+	r, _ := utf8.DecodeRuneInString(l.query[l.pos:])
+	return r
+}
+
+func (l *lexer) skipSpace() {
+	for l.pos < len(l.query) {
+		r, size := utf8.DecodeRuneInString(l.query[l.pos:])
+		if !unicode.IsSpace(r) {
+			return
+		}
+		l.pos += size
+	}
+}
+
+// atTermEnd returns whether the current position ends an unquoted word.
+func (l *lexer) atTermEnd() bool {
+	if l.pos >= len(l.query) {
+		return true
+	}
+	r := l.peek()
+	return unicode.IsSpace(r) || (r == ')' && l.depth > 0)
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipSpace()
+	start := l.pos
+	if l.pos >= len(l.query) {
+		return token{typ: tokenEOF, pos: start}, nil
+	}
+
+	switch l.query[l.pos] {
+	case '(':
+		if l.closed() {
+			l.pos++
+			l.depth++
+			return token{typ: tokenLeftParen, pos: start}, nil
+		}
+	case ')':
+		if l.depth > 0 {
+			l.pos++
+			l.depth--
+			return token{typ: tokenRightParen, pos: start}, nil
+		}
+	case '-':
+		l.pos++
+		if !l.atTermEnd() && l.query[l.pos] != '-' {
+			return token{typ: tokenNot, pos: start}, nil
+		}
+		l.pos = start
+	case '"':
+		value, err := l.quoted()
+		if err != nil {
+			return token{}, err
+		}
+		return token{typ: tokenTerm, pos: start, term: &TermExpr{Op: OpEqual, Value: value, Quoted: true}}, nil
+	}
+
+	key := l.key()
+	if key == "" || l.atTermEnd() {
+		word := key + l.word()
+		switch word {
+		case "AND":
+			return token{typ: tokenAnd, pos: start}, nil
+		case "OR":
+			return token{typ: tokenOr, pos: start}, nil
+		case "NOT":
+			return token{typ: tokenNot, pos: start}, nil
+		}
+		return token{typ: tokenTerm, pos: start, term: &TermExpr{Op: OpEqual, Value: word}}, nil
+	}
+
+	term := &TermExpr{Key: key, Op: OpEqual}
+	switch {
+	case strings.HasPrefix(l.query[l.pos:], ">="):
+		term.Op = OpGreaterEqual
+	case strings.HasPrefix(l.query[l.pos:], "<="):
+		term.Op = OpLessEqual
+	case l.query[l.pos] == '>':
+		term.Op = OpGreater
+	case l.query[l.pos] == '<':
+		term.Op = OpLess
+	}
+	l.pos += len(term.Op)
+
+	valuePos := l.pos
+	quoted := l.pos < len(l.query) && l.query[l.pos] == '"'
+	if quoted {
+		value, err := l.quoted()
+		if err != nil {
+			return token{}, err
+		}
+		term.Value = value
+	} else {
+		term.Value = l.word()
+	}
+
+	tok := token{typ: tokenTerm, pos: start, term: term}
+	if term.Op.IsComparison() {
+		if term.Value == "" {
+			return token{}, l.errorf(valuePos, "expected a value after %s%s", key, term.Op)
+		}
+		number, err := parseNumber(term.Value)
+		if err != nil {
+			return token{}, l.errorf(valuePos, "expected a number or duration but found %q", term.Value)
+		}
+		term.Number = number
+	} else if !quoted && term.Value == "*" {
+		term.Op = OpExists
+	} else if !quoted && strings.HasPrefix(term.Value, "!") {
+		term.Value = term.Value[1:]
+		tok.negated = true
+	}
+	return tok, nil
+}
+
+// closed returns whether the `(` at the current position is closed later in the query.
+// A `(` that is never closed is part of a term, such as `(*`.
+func (l *lexer) closed() bool {
+	depth := 0
+	quoted := false
+	for i := l.pos; i < len(l.query); i++ {
+		switch c := l.query[i]; {
+		case quoted && c == '\\':
+			i++
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// key reads the key of a `key:value` term, stopping before the operator.
+// It returns an empty key and does not advance when the word is not followed by an operator.
+func (l *lexer) key() string {
+	for i, r := range l.query[l.pos:] {
+		if unicode.IsSpace(r) || (r == ')' && l.depth > 0) {
+			return ""
+		}
+		if r == ':' || r == '>' || r == '<' {
+			if i == 0 {
+				return ""
+			}
+			key := l.query[l.pos : l.pos+i]
+			l.pos += i
+			return key
+		}
+	}
+	return ""
+}
+
+// word reads an unquoted value up to the next whitespace or closing parenthesis.
+func (l *lexer) word() string {
+	start := l.pos
+	for !l.atTermEnd() {
+		_, size := utf8.DecodeRuneInString(l.query[l.pos:])
+		l.pos += size
+	}
+	return l.query[start:l.pos]
+}
+
+// quoted reads a double quoted string, in which `\"` and `\\` are escaped.
+func (l *lexer) quoted() (string, error) {
+	start := l.pos
+	var value strings.Builder
+	for l.pos++; l.pos < len(l.query); l.pos++ {
+		switch c := l.query[l.pos]; {
+		case c == '"':
+			l.pos++
+			return value.String(), nil
+		case c == '\\' && l.pos+1 < len(l.query) && (l.query[l.pos+1] == '"' || l.query[l.pos+1] == '\\'):
+			l.pos++
+			value.WriteByte(l.query[l.pos])
+		default:
+			value.WriteByte(c)
+		}
+	}
+	return "", l.errorf(start, "unterminated quoted string")
+}
+
+// parseNumber parses a number, or a duration such as 500ms as nanoseconds.
+func parseNumber(value string) (float64, error) {
+	if number, err := strconv.ParseFloat(value, 64); err == nil {
+		return number, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return float64(duration), nil
+}
@@ -0,0 +1,167 @@
+package queryparser
+
+// ParseQuery parses a search query into an expression, returning a *SyntaxError for invalid queries.
+// Terms are AND'd unless separated by OR, and can be negated with NOT or a `-` prefix and grouped with parentheses.
+// Terms of the same key are OR'd unless separated by AND, so `level:error level:warn` matches either level.
+// Example: (level:error OR level:warn) -service_name:worker duration>500ms status_code>=500 user_id:* timeout
+//
+// Grammar:
+// query   = [ or ]
+// or      = and { "OR" and }
+// and     = unary { [ "AND" ] unary }
+// unary   = ( "NOT" | "-" ) unary | primary
+// primary = "(" or ")" | term
+// term    = value | key ( ":" | ">" | ">=" | "<" | "<=" ) value
+func ParseQuery(query string) (Expr, error) {
+	p := &parser{lexer: &lexer{query: query}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.typ == tokenEOF {
+		return nil, nil
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.typ != tokenEOF {
+		return nil, p.errorf("unexpected %s", p.tok)
+	}
+	return expr, nil
+}
+
+type parser struct {
+	lexer *lexer
+	tok   token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return p.lexer.errorf(p.tok.pos, format, args...)
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	expr, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	or := []Expr{expr}
+	for p.tok.typ == tokenOr {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		expr, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		or = append(or, expr)
+	}
+	if len(or) == 1 {
+		return or[0], nil
+	}
+	return &OrExpr{Exprs: or}, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	and := []Expr{expr}
+	// the position in `and` of the last term of each key, as terms of the same key
+	// that are not joined by AND match any of their values, such as `level:error level:warn`
+	keys := map[string]int{}
+	addKey(keys, and, 0)
+	for {
+		explicit := false
+		switch p.tok.typ {
+		case tokenAnd:
+			explicit = true
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+		case tokenTerm, tokenNot, tokenLeftParen:
+		default:
+			return And(and...), nil
+		}
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if i, ok := keys[termKey(expr)]; ok && !explicit {
+			if or, ok := and[i].(*OrExpr); ok {
+				or.Exprs = append(or.Exprs, expr)
+			} else {
+				and[i] = &OrExpr{Exprs: []Expr{and[i], expr}}
+			}
+			continue
+		}
+		and = append(and, expr)
+		addKey(keys, and, len(and)-1)
+	}
+}
+
+// termKey returns the key of a `key:value` term, or an empty string for other expressions.
+func termKey(expr Expr) string {
+	if term, ok := expr.(*TermExpr); ok && term.Op == OpEqual {
+		return term.Key
+	}
+	return ""
+}
+
+func addKey(keys map[string]int, and []Expr, i int) {
+	if key := termKey(and[i]); key != "" {
+		keys[key] = i
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.tok.typ != tokenNot {
+		return p.parsePrimary()
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &NotExpr{Expr: expr}, nil
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	tok := p.tok
+	switch tok.typ {
+	case tokenLeftParen:
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		if p.tok.typ == tokenRightParen {
+			return nil, p.errorf("empty parentheses")
+		}
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.typ != tokenRightParen {
+			return nil, p.lexer.errorf(tok.pos, "missing closing parenthesis")
+		}
+		return expr, p.advance()
+	case tokenTerm:
+		var expr Expr = tok.term
+		if tok.negated {
+			expr = &NotExpr{Expr: expr}
+		}
+		return expr, p.advance()
+	}
+	return nil, p.errorf("expected a search term but found %s", tok)
+}
@@ -0,0 +1,76 @@
+package queryparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	for query, want := range map[string]string{
+		"email:foo@bar.com":                        "email:foo@bar.com",
+		"some message service:image-processor":     "(some AND message AND service:image-processor)",
+		"level:error OR level:warn":                "(level:error OR level:warn)",
+		"a b OR c":                                 "((a AND b) OR c)",
+		"a AND (b OR c)":                           "(a AND (b OR c))",
+		"-service_name:worker NOT timeout":         "(NOT service_name:worker AND NOT timeout)",
+		"-(a OR b)":                                "NOT (a OR b)",
+		"duration>500ms status_code>=500 size<=10": "(duration>500ms AND status_code>=500 AND size<=10)",
+		"user_id:* -trace_id:*":                    "(user_id:* AND NOT trace_id:*)",
+		"service:\"image processor\"":              "service:\"image processor\"",
+		"\"something went wrong\"":                 "\"something went wrong\"",
+		"service:foo:bar:buzz":                     "service:foo:bar:buzz",
+		"highlight.type:!highlight.internal":       "NOT highlight.type:highlight.internal",
+		"not this one":                             "(not AND this AND one)",
+		"foo(bar) baz)":                            "(\"foo(bar)\" AND \"baz)\")",
+		"(a) (b)":                                  "(a AND b)",
+		"email:":                                   "email:",
+		"a - b":                                    "(a AND - AND b)",
+		"level:error level:warn":                   "(level:error OR level:warn)",
+		"level:error AND level:warn":               "(level:error AND level:warn)",
+		"a level:error b level:warn -level:info":   "(a AND (level:error OR level:warn) AND b AND NOT level:info)",
+		"(*":                                       "\"(*\"",
+		"(a OR b":                                  "(\"(a\" OR b)",
+		"(a (b)":                                   "(\"(a\" AND b)",
+	} {
+		expr, err := ParseQuery(query)
+		assert.NoError(t, err, query)
+		assert.Equal(t, want, expr.String(), query)
+	}
+}
+
+func TestParseQueryEmpty(t *testing.T) {
+	expr, err := ParseQuery("  ")
+	assert.NoError(t, err)
+	assert.Nil(t, expr)
+}
+
+func TestParseQueryTerms(t *testing.T) {
+	expr, err := ParseQuery("duration>1.5s status_code<500 message:\"a \\\"quoted\\\" value\" *error*")
+	assert.NoError(t, err)
+	assert.Equal(t, &AndExpr{Exprs: []Expr{
+		&TermExpr{Key: "duration", Op: OpGreater, Value: "1.5s", Number: 1.5e9},
+		&TermExpr{Key: "status_code", Op: OpLess, Value: "500", Number: 500},
+		&TermExpr{Key: "message", Op: OpEqual, Value: "a \"quoted\" value"},
+		&TermExpr{Op: OpEqual, Value: "*error*"},
+	}}, expr)
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for query, want := range map[string]*SyntaxError{
+		"a OR":             {Pos: 4, Msg: "expected a search term but found end of query"},
+		"OR a":             {Pos: 0, Msg: "expected a search term but found OR"},
+		"a AND AND b":      {Pos: 6, Msg: "expected a search term but found AND"},
+		"a ()":             {Pos: 3, Msg: "empty parentheses"},
+		"NOT":              {Pos: 3, Msg: "expected a search term but found end of query"},
+		"message:\"oops":   {Pos: 8, Msg: "unterminated quoted string"},
+		"duration>":        {Pos: 9, Msg: "expected a value after duration>"},
+		"duration>=fast":   {Pos: 10, Msg: "expected a number or duration but found \"fast\""},
+		"(a OR (b c) OR )": {Pos: 15, Msg: "expected a search term but found )"},
+	} {
+		_, err := ParseQuery(query)
+		assert.Equal(t, want, err, query)
+	}
+	_, err := ParseQuery("a OR")
+	assert.EqualError(t, err, "syntax error at column 5: expected a search term but found end of query")
+}
@@ -15,60 +15,48 @@ type Filters struct {
 // =>
 // Body -> []string{"some", "message"}
 // Attributes -> map[string][]string{"email": {"foo@bar.com", "baz@buzz.com"}, "service": {"image-processor"}}
+// Boolean operators are ignored, use ParseQuery for a query expression.
 func Parse(query string) Filters {
 	filters := Filters{
 		Attributes: make(map[string][]string),
 	}
 
-	queries := splitQuery(query)
-
-	for _, q := range queries {
-		parts := strings.SplitN(q, ":", 2)
-
-		if len(parts) == 1 && len(parts[0]) > 0 {
-			body := parts[0]
+	l := &lexer{query: query}
+	for {
+		tok, err := l.next()
+		if err != nil || tok.typ == tokenEOF {
+			break
+		}
+		if tok.typ != tokenTerm {
+			continue
+		}
 
-			if strings.Contains(body, "*") {
-				body = strings.ReplaceAll(body, "*", "%")
-				filters.Body = append(filters.Body, body)
+		term := tok.term
+		if term.Key == "" {
+			if term.Quoted || term.HasWildcard() {
+				filters.Body = append(filters.Body, term.LikePattern())
 			} else {
-				splitBody := strings.FieldsFunc(body, isSeparator)
-				filters.Body = append(filters.Body, splitBody...)
+				filters.Body = append(filters.Body, SplitBody(term.Value)...)
 			}
-		} else if len(parts) == 2 {
-			key, value := parts[0], parts[1]
-
-			wildcardValue := strings.ReplaceAll(value, "*", "%")
-
-			filters.Attributes[key] = append(filters.Attributes[key], wildcardValue)
+		} else {
+			value := term.LikePattern()
+			if term.Op.IsComparison() {
+				value = string(term.Op) + value
+			} else if tok.negated {
+				value = "!" + value
+			}
+			filters.Attributes[term.Key] = append(filters.Attributes[term.Key], value)
 		}
 	}
 
 	return filters
 }
 
-func isSeparator(r rune) bool {
-	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+// SplitBody splits a body search value into the tokens that must be present in a message.
+func SplitBody(value string) []string {
+	return strings.FieldsFunc(value, isSeparator)
 }
 
-// Splits the query by spaces _unless_ it is quoted
-// "some thing" => ["some", "thing"]
-// "some thing 'spaced string' else" => ["some", "thing", "spaced string", "else"]
-func splitQuery(query string) []string {
-	var result []string
-	inquote := false
-	i := 0
-	for j, c := range query {
-		if c == '"' {
-			inquote = !inquote
-		} else if c == ' ' && !inquote {
-			result = append(result, unquoteAndTrim(query[i:j]))
-			i = j + 1
-		}
-	}
-	return append(result, unquoteAndTrim(query[i:]))
-}
-
-func unquoteAndTrim(s string) string {
-	return strings.ReplaceAll(strings.Trim(s, " "), `"`, "")
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
 }
@@ -78,14 +78,13 @@ func TestParseBodyWithWildcard(t *testing.T) {
 	assert.Equal(t, want, Parse("*email*"))
 }
 
-// Failing test to be fixed by https://github.com/highlight/highlight/issues/4713
-// func TestParseBodyWithQuotes(t *testing.T) {
-// 	want := Filters{
-// 		Body:       []string{"something went wrong"},
-// 		Attributes: map[string][]string{},
-// 	}
-// 	assert.Equal(t, want, Parse("\"something went wrong\""))
-// }
+func TestParseBodyWithQuotes(t *testing.T) {
+	want := Filters{
+		Body:       []string{"something went wrong"},
+		Attributes: map[string][]string{},
+	}
+	assert.Equal(t, want, Parse("\"something went wrong\""))
+}
 
 func TestParseBodyWithAttributes(t *testing.T) {
 	want := Filters{
@@ -0,0 +1,235 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+// buffered spans and logs outlive the decision deadline in case flushing falls behind
+const tailSamplingBufferExpiration = 10 * time.Minute
+
+type TailSampledTrace struct {
+	ProjectID int
+	TraceID   string
+	// Service is the service of the root span, or of the first buffered span if the root has not arrived.
+	Service string
+	// Keep is set when a buffered span requires the trace to be kept, eg. a span with an error.
+	Keep bool
+}
+
+type TailSampledSpan struct {
+	ProjectID int
+	TraceID   string
+	Service   string
+	Root      bool
+	Keep      bool
+}
+
+func tailSamplingPendingMember(projectId int, traceId string) string {
+	return fmt.Sprintf("%d-%s", projectId, traceId)
+}
+
+func getDecision(result interface{}, err error) (*bool, error) {
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	decision := result == "1"
+	return &decision, nil
+}
+
+// BufferTailSampledSpan buffers the span of a trace until the trace is sampled. If the trace was already sampled,
+// the span is not buffered and the decision is returned instead.
+func (r *Client) BufferTailSampledSpan(ctx context.Context, span TailSampledSpan, payload []byte, deadline time.Time) (*bool, error) {
+	var bufferSpan = redis.NewScript(`
+		local decisionKey = KEYS[1]
+		local spansKey = KEYS[2]
+		local traceKey = KEYS[3]
+		local payload = ARGV[1]
+		local keep = ARGV[2]
+		local service = ARGV[3]
+		local root = ARGV[4]
+		local expiration = ARGV[5]
+
+		local decision = redis.call("GET", decisionKey)
+		if decision then
+			return decision
+		end
+
+		redis.call("RPUSH", spansKey, payload)
+		redis.call("EXPIRE", spansKey, expiration)
+		if keep == "1" then
+			redis.call("HSET", traceKey, "keep", "1")
+		end
+		if root == "1" then
+			redis.call("HSET", traceKey, "service", service)
+		else
+			redis.call("HSETNX", traceKey, "service", service)
+		end
+		redis.call("EXPIRE", traceKey, expiration)
+
+		return false
+	`)
+
+	keys := []string{
+		TailSamplingDecisionKey(span.ProjectID, span.TraceID),
+		TailSamplingSpansKey(span.ProjectID, span.TraceID),
+		TailSamplingTraceKey(span.ProjectID, span.TraceID),
+	}
+	values := []interface{}{payload, span.Keep, span.Service, span.Root, int(tailSamplingBufferExpiration.Seconds())}
+	decision, err := getDecision(bufferSpan.Run(ctx, r.Client, keys, values...).Result())
+	if err != nil {
+		return nil, errors.Wrap(err, "error buffering tail sampled span")
+	}
+	if decision != nil {
+		return decision, nil
+	}
+	return nil, r.addPendingTailSampledTrace(ctx, span.ProjectID, span.TraceID, deadline)
+}
+
+// BufferTailSampledLog buffers a log linked to a trace until the trace is sampled. If the trace was already sampled,
+// the log is not buffered and the decision is returned instead.
+func (r *Client) BufferTailSampledLog(ctx context.Context, projectId int, traceId string, payload []byte, deadline time.Time) (*bool, error) {
+	var bufferLog = redis.NewScript(`
+		local decisionKey = KEYS[1]
+		local logsKey = KEYS[2]
+		local payload = ARGV[1]
+		local expiration = ARGV[2]
+
+		local decision = redis.call("GET", decisionKey)
+		if decision then
+			return decision
+		end
+
+		redis.call("RPUSH", logsKey, payload)
+		redis.call("EXPIRE", logsKey, expiration)
+
+		return false
+	`)
+
+	keys := []string{TailSamplingDecisionKey(projectId, traceId), TailSamplingLogsKey(projectId, traceId)}
+	values := []interface{}{payload, int(tailSamplingBufferExpiration.Seconds())}
+	decision, err := getDecision(bufferLog.Run(ctx, r.Client, keys, values...).Result())
+	if err != nil {
+		return nil, errors.Wrap(err, "error buffering tail sampled log")
+	}
+	if decision != nil {
+		return decision, nil
+	}
+	return nil, r.addPendingTailSampledTrace(ctx, projectId, traceId, deadline)
+}
+
+func (r *Client) addPendingTailSampledTrace(ctx context.Context, projectId int, traceId string, deadline time.Time) error {
+	if err := r.Client.ZAddNX(ctx, TailSamplingPendingKey, redis.Z{
+		Score:  float64(deadline.Unix()),
+		Member: tailSamplingPendingMember(projectId, traceId),
+	}).Err(); err != nil {
+		return errors.Wrap(err, "error adding pending tail sampled trace")
+	}
+	return nil
+}
+
+// GetTailSampledTrace returns what is known about a buffered trace.
+func (r *Client) GetTailSampledTrace(ctx context.Context, projectId int, traceId string) (*TailSampledTrace, error) {
+	values, err := r.Client.HGetAll(ctx, TailSamplingTraceKey(projectId, traceId)).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting tail sampled trace")
+	}
+	return &TailSampledTrace{
+		ProjectID: projectId,
+		TraceID:   traceId,
+		Service:   values["service"],
+		Keep:      values["keep"] == "1",
+	}, nil
+}
+
+// GetExpiredTailSampledTraces returns buffered traces whose decision deadline has passed.
+func (r *Client) GetExpiredTailSampledTraces(ctx context.Context, now time.Time, limit int64) ([]*TailSampledTrace, error) {
+	members, err := r.Client.ZRangeByScore(ctx, TailSamplingPendingKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(now.Unix(), 10),
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting expired tail sampled traces")
+	}
+
+	var traces []*TailSampledTrace
+	for _, member := range members {
+		project, traceId, _ := strings.Cut(member, "-")
+		projectId, err := strconv.Atoi(project)
+		if err != nil {
+			r.Client.ZRem(ctx, TailSamplingPendingKey, member)
+			continue
+		}
+		traces = append(traces, &TailSampledTrace{ProjectID: projectId, TraceID: traceId})
+	}
+	return traces, nil
+}
+
+// DecideTailSampledTrace records the sampling decision of a trace and returns its buffered spans and logs.
+// The trace is kept regardless of keep if a span requiring it was buffered since the trace was read.
+// Returns a nil decision if the trace was already decided by another caller.
+func (r *Client) DecideTailSampledTrace(ctx context.Context, projectId int, traceId string, keep bool, expiration time.Duration) (decision *bool, spans [][]byte, logs [][]byte, err error) {
+	var decide = redis.NewScript(`
+		local decisionKey = KEYS[1]
+		local spansKey = KEYS[2]
+		local logsKey = KEYS[3]
+		local traceKey = KEYS[4]
+		local keep = ARGV[1]
+		local expiration = ARGV[2]
+
+		if redis.call("EXISTS", decisionKey) == 1 then
+			return false
+		end
+		if redis.call("HGET", traceKey, "keep") == "1" then
+			keep = "1"
+		end
+
+		redis.call("SET", decisionKey, keep, "EX", expiration)
+		local spans = redis.call("LRANGE", spansKey, 0, -1)
+		local logs = redis.call("LRANGE", logsKey, 0, -1)
+		redis.call("DEL", spansKey, logsKey, traceKey)
+
+		return {keep, spans, logs}
+	`)
+
+	keys := []string{
+		TailSamplingDecisionKey(projectId, traceId),
+		TailSamplingSpansKey(projectId, traceId),
+		TailSamplingLogsKey(projectId, traceId),
+		TailSamplingTraceKey(projectId, traceId),
+	}
+	values := []interface{}{keep, int(expiration.Seconds())}
+	result, err := decide.Run(ctx, r.Client, keys, values...).Slice()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, nil, nil, errors.Wrap(err, "error deciding tail sampled trace")
+	}
+	// the trace is decided, either now or by another caller, so it is no longer pending
+	if err := r.Client.ZRem(ctx, TailSamplingPendingKey, tailSamplingPendingMember(projectId, traceId)).Err(); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "error removing pending tail sampled trace")
+	}
+	if errors.Is(err, redis.Nil) {
+		return nil, nil, nil, nil
+	} else if len(result) != 3 {
+		return nil, nil, nil, errors.Errorf("unexpected tail sampling decision result %+v", result)
+	}
+
+	decision, _ = getDecision(result[0], nil)
+	for idx, buffered := range []*[][]byte{&spans, &logs} {
+		items, _ := result[idx+1].([]interface{})
+		for _, item := range items {
+			if str, ok := item.(string); ok {
+				*buffered = append(*buffered, []byte(str))
+			}
+		}
+	}
+	return decision, spans, logs, nil
+}
@@ -23,6 +23,9 @@ import (
 
 const CacheKeyHubspotCompanies = "hubspot-companies"
 
+// TailSamplingPendingKey is a sorted set of the buffered traces scored by their decision deadline.
+const TailSamplingPendingKey = "tail-sampling-pending"
+
 type Client struct {
 	Client  redis.Cmdable
 	Cache   *cache.Cache
@@ -71,6 +74,24 @@ func GitHubFileErrorKey(gitHubRepo string, version string, fileName string) stri
 	return fmt.Sprintf("github-file-error-%s-%s-%s", gitHubRepo, version, fileName)
 }
 
+// The keys of a tail sampled trace share a hash tag so that the scripts buffering and deciding
+// the trace operate on a single cluster slot.
+func TailSamplingDecisionKey(projectId int, traceId string) string {
+	return fmt.Sprintf("tail-sampling-decision-{%d-%s}", projectId, traceId)
+}
+
+func TailSamplingSpansKey(projectId int, traceId string) string {
+	return fmt.Sprintf("tail-sampling-spans-{%d-%s}", projectId, traceId)
+}
+
+func TailSamplingLogsKey(projectId int, traceId string) string {
+	return fmt.Sprintf("tail-sampling-logs-{%d-%s}", projectId, traceId)
+}
+
+func TailSamplingTraceKey(projectId int, traceId string) string {
+	return fmt.Sprintf("tail-sampling-trace-{%d-%s}", projectId, traceId)
+}
+
 func NewClient() *Client {
 	var lfu cache.LocalCache
 	// disable lfu cache locally to allow flushing cache between test-cases
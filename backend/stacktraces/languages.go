@@ -0,0 +1,306 @@
+package stacktraces
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	publicModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/openlyinc/pointy"
+	"github.com/samber/lo"
+)
+
+var (
+	// at com.example.Foo.bar(Foo.java:123) / at java.base/java.lang.Thread.run(Thread.java:829) / at Foo.bar(Native Method)
+	javaFramePattern    = regexp.MustCompile(`^\s*at ([\w$./<>\-]+)\.([\w$<>\-]+)\(([^()]*)\)\s*$`)
+	javaLocationPattern = regexp.MustCompile(`^(.+?)(?::(\d+))?$`)
+	javaFilePattern     = regexp.MustCompile(`(\.(java|kt|scala|groovy|clj|php)(:\d+)?|Native Method|Unknown Source)$`)
+	javaCausePattern    = regexp.MustCompile(`^\s*(Caused by|Suppressed): (.+)$`)
+	javaMorePattern     = regexp.MustCompile(`^\s*\.\.\. \d+ (more|common frames omitted)\s*$`)
+
+	// at Ns.Type.Method(String arg) in /app/File.cs:line 42
+	dotnetFramePattern          = regexp.MustCompile(`^\s*at (.+?)\(([^()]*)\)(?: in (.+):line (\d+))?\s*$`)
+	dotnetLocationPattern       = regexp.MustCompile(` in .+:line \d+\s*$`)
+	dotnetSeparatorPattern      = regexp.MustCompile(`^\s*--- End of (inner exception stack trace|stack trace from previous location.*) ---\s*$`)
+	dotnetInnerSeparatorPattern = regexp.MustCompile(`^\s*--- End of inner exception stack trace ---\s*$`)
+
+	// app/models/user.rb:10:in `save': message (RuntimeError) / from app.rb:3:in '<main>'
+	rubyFramePattern = regexp.MustCompile("^\\s*(?:from )?(.+?):(\\d+):in [`'](.+?)'(?:: (.+))?\\s*$")
+
+	// #0 /app/src/Foo.php(12): App\Foo->bar('x') / #1 [internal function]: baz() / #2 {main}
+	phpFramePattern    = regexp.MustCompile(`^#\d+ (.+?)\((\d+)\): (.+)$`)
+	phpInternalPattern = regexp.MustCompile(`^#\d+ \[internal function\]: (.+)$`)
+	phpMainPattern     = regexp.MustCompile(`^#\d+ \{main\}\s*$`)
+	phpNextPattern     = regexp.MustCompile(`^Next (.+)$`)
+	phpCallPattern     = regexp.MustCompile(`^(.+?)\(.*\)$`)
+
+	//    3: core::option::Option<T>::unwrap
+	//              at /rustc/.../library/core/src/option.rs:778:21
+	rustHeaderPattern   = regexp.MustCompile(`(?i)^\s*stack backtrace:\s*$`)
+	rustFramePattern    = regexp.MustCompile(`^\s*(\d+):\s+(?:0x[0-9a-f]+ - )?(.+?)\s*$`)
+	rustLocationPattern = regexp.MustCompile(`^\s+at (.+?):(\d+)(?::(\d+))?\s*$`)
+	rustHashPattern     = regexp.MustCompile(`::h[0-9a-f]{16}$`)
+)
+
+// detectLanguage identifies stacktrace formats that have a dedicated parser.
+// JavaScript, Python and Go stacktraces are handled by StructureOTELStackTrace directly.
+func detectLanguage(lines []string) Language {
+	for idx, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if dotnetLocationPattern.MatchString(line) && dotnetFramePattern.MatchString(line) {
+			return DotNet
+		}
+		if dotnetSeparatorPattern.MatchString(line) {
+			return DotNet
+		}
+		if m := javaFramePattern.FindStringSubmatch(line); m != nil && javaFilePattern.MatchString(m[3]) {
+			if strings.HasSuffix(javaLocationPattern.FindStringSubmatch(m[3])[1], ".php") {
+				return PHP
+			}
+			return Java
+		}
+		if trimmed := strings.TrimSpace(line); phpFramePattern.MatchString(trimmed) || phpMainPattern.MatchString(trimmed) {
+			return PHP
+		}
+		if rubyFramePattern.MatchString(line) {
+			return Ruby
+		}
+		if rustHeaderPattern.MatchString(line) {
+			return Rust
+		}
+		if m := rustFramePattern.FindStringSubmatch(line); m != nil && strings.Contains(m[2], "::") &&
+			idx+1 < len(lines) && rustLocationPattern.MatchString(lines[idx+1]) {
+			return Rust
+		}
+	}
+	return ""
+}
+
+// structureLanguageStackTrace parses a stacktrace of a language detected by detectLanguage into its chain of exceptions,
+// outermost exception first. All of these languages print the deepest frame first, so the order of the frames is preserved.
+func structureLanguageStackTrace(language Language, lines []string) []*ErrorCause {
+	for idx := range lines {
+		lines[idx] = strings.TrimRight(lines[idx], "\r")
+	}
+	switch language {
+	case Java:
+		return structureJavaStackTrace(lines)
+	case DotNet:
+		return structureDotNetStackTrace(lines)
+	case Ruby:
+		return structureRubyStackTrace(lines)
+	case PHP:
+		return structurePHPStackTrace(lines)
+	case Rust:
+		return structureRustStackTrace(lines)
+	}
+	return []*ErrorCause{}
+}
+
+// chain accumulates the exceptions of a stacktrace in the order they are printed.
+// The (possibly multi-line) message of each exception precedes its frames.
+type chain struct {
+	causes   []*ErrorCause
+	messages []*string
+	open     bool
+}
+
+func (c *chain) start(line string) {
+	c.causes = append(c.causes, &ErrorCause{Frames: []*publicModel.ErrorTrace{}})
+	c.messages = append(c.messages, pointy.String(strings.TrimSpace(line)))
+	c.open = true
+}
+
+func (c *chain) add(line string) {
+	if len(c.messages) == 0 || !c.open {
+		c.start(line)
+		return
+	}
+	message := c.messages[len(c.messages)-1]
+	*message = *message + "\n" + strings.TrimSpace(line)
+}
+
+func (c *chain) empty() bool {
+	return len(c.causes) == 0
+}
+
+func (c *chain) frame() *publicModel.ErrorTrace {
+	if len(c.causes) == 0 {
+		c.start("")
+	}
+	c.open = false
+	frame := &publicModel.ErrorTrace{Error: c.messages[len(c.messages)-1]}
+	cause := c.causes[len(c.causes)-1]
+	cause.Frames = append(cause.Frames, frame)
+	return frame
+}
+
+// result returns the exceptions in the order they were printed
+func (c *chain) result() []*ErrorCause {
+	for idx, cause := range c.causes {
+		cause.Type, cause.Message = splitErrorMessage(*c.messages[idx])
+	}
+	return c.causes
+}
+
+func structureJavaStackTrace(lines []string) []*ErrorCause {
+	c := chain{}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" || javaMorePattern.MatchString(line) {
+			continue
+		}
+		if m := javaFramePattern.FindStringSubmatch(line); m != nil {
+			frame := c.frame()
+			frame.FunctionName = pointy.String(m[1] + "." + m[2])
+			location := javaLocationPattern.FindStringSubmatch(m[3])
+			frame.FileName = pointy.String(location[1])
+			if location[2] != "" {
+				l, _ := strconv.ParseInt(location[2], 10, 32)
+				frame.LineNumber = pointy.Int(int(l))
+			}
+		} else if m := javaCausePattern.FindStringSubmatch(line); m != nil {
+			c.start(m[2])
+		} else if c.open || c.empty() {
+			c.add(line)
+		}
+	}
+	// java prints the outermost exception first, followed by its causes
+	return c.result()
+}
+
+func structureDotNetStackTrace(lines []string) []*ErrorCause {
+	c := chain{}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if dotnetInnerSeparatorPattern.MatchString(line) {
+			c.start("")
+			c.open = false
+			continue
+		}
+		if dotnetSeparatorPattern.MatchString(line) {
+			continue
+		}
+		if m := dotnetFramePattern.FindStringSubmatch(line); m != nil {
+			frame := c.frame()
+			frame.FunctionName = pointy.String(m[1])
+			if m[3] != "" {
+				frame.FileName = pointy.String(m[3])
+				l, _ := strconv.ParseInt(m[4], 10, 32)
+				frame.LineNumber = pointy.Int(int(l))
+			}
+		} else if c.open || c.empty() {
+			c.add(line)
+		}
+	}
+	if c.empty() {
+		return []*ErrorCause{}
+	}
+	// the message of an exception with inner exceptions reads `Outer: message ---> Inner: message`,
+	// while the frames of the innermost exception are printed first.
+	if messages := strings.Split(*c.messages[0], " ---> "); len(messages) == len(c.messages) {
+		for idx, message := range c.messages {
+			*message = strings.TrimSpace(messages[len(messages)-1-idx])
+		}
+	}
+	return lo.Reverse(c.result())
+}
+
+func structureRubyStackTrace(lines []string) []*ErrorCause {
+	c := chain{}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if m := rubyFramePattern.FindStringSubmatch(line); m != nil {
+			if c.empty() {
+				// the first line carries the message when the trace comes from `Exception#full_message`
+				if m[4] != "" {
+					c.start(m[4])
+				} else {
+					c.start(line)
+				}
+			}
+			frame := c.frame()
+			frame.FileName = pointy.String(m[1])
+			l, _ := strconv.ParseInt(m[2], 10, 32)
+			frame.LineNumber = pointy.Int(int(l))
+			frame.FunctionName = pointy.String(m[3])
+		} else if c.open || c.empty() {
+			c.add(line)
+		}
+	}
+	return c.result()
+}
+
+func structurePHPStackTrace(lines []string) []*ErrorCause {
+	// the OpenTelemetry PHP SDK formats stacktraces like java
+	for _, line := range lines {
+		if javaFramePattern.MatchString(line) {
+			return structureJavaStackTrace(lines)
+		}
+	}
+
+	phpFunction := func(call string) *string {
+		if m := phpCallPattern.FindStringSubmatch(call); m != nil {
+			return pointy.String(m[1])
+		}
+		return pointy.String(call)
+	}
+
+	c := chain{}
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || trimmed == "Stack trace:" || phpMainPattern.MatchString(trimmed) || strings.HasPrefix(trimmed, "thrown in ") {
+			continue
+		}
+		if m := phpFramePattern.FindStringSubmatch(trimmed); m != nil {
+			frame := c.frame()
+			frame.FileName = pointy.String(m[1])
+			l, _ := strconv.ParseInt(m[2], 10, 32)
+			frame.LineNumber = pointy.Int(int(l))
+			frame.FunctionName = phpFunction(m[3])
+		} else if m := phpInternalPattern.FindStringSubmatch(trimmed); m != nil {
+			frame := c.frame()
+			frame.FileName = pointy.String("[internal function]")
+			frame.FunctionName = phpFunction(m[1])
+		} else if m := phpNextPattern.FindStringSubmatch(trimmed); m != nil {
+			c.start(m[1])
+		} else if c.open || c.empty() {
+			c.add(trimmed)
+		}
+	}
+	// php prints the previous exception first, followed by `Next` exceptions wrapping it
+	return lo.Reverse(c.result())
+}
+
+func structureRustStackTrace(lines []string) []*ErrorCause {
+	c := chain{}
+	var frame *publicModel.ErrorTrace
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "note: ") {
+			continue
+		}
+		if rustHeaderPattern.MatchString(line) {
+			c.open = false
+			continue
+		}
+		if m := rustLocationPattern.FindStringSubmatch(line); m != nil && frame != nil {
+			frame.FileName = pointy.String(m[1])
+			l, _ := strconv.ParseInt(m[2], 10, 32)
+			frame.LineNumber = pointy.Int(int(l))
+			if m[3] != "" {
+				col, _ := strconv.ParseInt(m[3], 10, 32)
+				frame.ColumnNumber = pointy.Int(int(col))
+			}
+		} else if m := rustFramePattern.FindStringSubmatch(line); m != nil {
+			frame = c.frame()
+			frame.FunctionName = pointy.String(rustHashPattern.ReplaceAllString(m[2], ""))
+		} else if c.open || c.empty() {
+			c.add(trimmed)
+		}
+	}
+	return c.result()
+}
@@ -9,6 +9,7 @@ import (
 
 	publicModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"github.com/openlyinc/pointy"
+	"github.com/samber/lo"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -17,16 +18,66 @@ type Language string
 const Javascript Language = "js"
 const Python Language = "python"
 const Golang Language = "golang"
+const Java Language = "java"
+const Ruby Language = "ruby"
+const DotNet Language = "dotnet"
+const PHP Language = "php"
+const Rust Language = "rust"
+
+// ErrorCause is a single exception of a chained error, such as a java `Caused by:`
+// or a python exception raised while handling another one.
+type ErrorCause struct {
+	Type    string
+	Message string
+	Frames  []*publicModel.ErrorTrace
+}
+
+var errorTypePattern = regexp.MustCompile(`^([\w$.\\]+): (.*)$`)
+
+// splitErrorMessage splits an exception header such as `java.io.IOException: disk full` into its type and message.
+func splitErrorMessage(header string) (string, string) {
+	if m := errorTypePattern.FindStringSubmatch(header); m != nil {
+		return m[1], m[2]
+	}
+	if errorTypePattern.MatchString(header + ": ") {
+		return header, ""
+	}
+	return "", header
+}
+
+func newErrorCause(header string, frames []*publicModel.ErrorTrace) *ErrorCause {
+	cause := &ErrorCause{Frames: []*publicModel.ErrorTrace{}}
+	cause.Type, cause.Message = splitErrorMessage(header)
+	for _, frame := range frames {
+		f := *frame
+		f.Error = pointy.String(header)
+		cause.Frames = append(cause.Frames, &f)
+	}
+	return cause
+}
 
 // StructureOTELStackTrace processes a backend opentelemetry stacktrace into a structured ErrorTraces.
 // The operation returns the deepest frame first (reversing the order of the incoming stacktrace).
+// The frames of all chained exceptions are returned; use StructureOTELStackTraceCauses to tell them apart.
 func StructureOTELStackTrace(stackTrace string) ([]*publicModel.ErrorTrace, error) {
+	frames, _ := structureOTELStackTrace(stackTrace)
+	return frames, nil
+}
+
+// StructureOTELStackTraceCauses processes a backend opentelemetry stacktrace into the chain of exceptions it contains,
+// ordered from the outermost exception to the root cause. The frames of each cause are returned deepest frame first.
+func StructureOTELStackTraceCauses(stackTrace string) ([]*ErrorCause, error) {
+	_, causes := structureOTELStackTrace(stackTrace)
+	return causes, nil
+}
+
+func structureOTELStackTrace(stackTrace string) ([]*publicModel.ErrorTrace, []*ErrorCause) {
 	jsPattern := regexp.MustCompile(` {4}at ((.+) )?\(?(.+):(\d+):(\d+)\)?`)
 	jsAnonPattern := regexp.MustCompile(` {4}at (.+) \((.+)\)`)
 	pyPattern := regexp.MustCompile(` {2}File "(.+)", line (\d+), in (\w+)`)
 	pyExcPattern := regexp.MustCompile(`^(\S.+)`)
 	pyUnderPattern := regexp.MustCompile(`^\s*[\^~]+\s*$`)
-	pyMultiPattern := regexp.MustCompile(`^During handling of the above exception, another exception occurred:$`)
+	pyMultiPattern := regexp.MustCompile(`^(During handling of the above exception, another exception occurred|The above exception was the direct cause of the following exception):$`)
 	goLinePattern := regexp.MustCompile(`\t(.+):(\d+)( 0x[0-f]+)?`)
 	goFuncPattern := regexp.MustCompile(`^(.+)\.(.+?)(\([^()]*\))?$`)
 	goRecoveredPanicPattern := regexp.MustCompile(`^\s*runtime\.gopanic\s*$`)
@@ -36,11 +87,29 @@ func StructureOTELStackTrace(stackTrace string) ([]*publicModel.ErrorTrace, erro
 	if err := json.Unmarshal([]byte(stackTrace), &jsonStr); err == nil {
 		stackTrace = jsonStr
 	}
+	lines := strings.Split(stackTrace, "\n")
+	if language := detectLanguage(lines); language != "" {
+		causes := structureLanguageStackTrace(language, lines)
+		frames := []*publicModel.ErrorTrace{}
+		for _, cause := range causes {
+			frames = append(frames, cause.Frames...)
+		}
+		return frames, causes
+	}
+
 	var language Language
 	var errMsg string
 	var frame *publicModel.ErrorTrace
 	frames := []*publicModel.ErrorTrace{}
-	lines := strings.Split(stackTrace, "\n")
+	// causes are closed when the stacktrace moves on to the next chained exception
+	var causes []*ErrorCause
+	causeStart := 0
+	closeCause := func() {
+		if errMsg != "" || len(frames) > causeStart {
+			causes = append(causes, newErrorCause(errMsg, frames[causeStart:]))
+		}
+		causeStart = len(frames)
+	}
 	for idx, line := range lines {
 		// frames explicitly set to nil means that this is part of a frame that is resetting the stacktrace
 		if frames == nil {
@@ -70,6 +139,7 @@ func StructureOTELStackTrace(stackTrace string) ([]*publicModel.ErrorTrace, erro
 			continue
 		}
 		if matches := pyMultiPattern.FindSubmatch([]byte(line)); language == Python && matches != nil {
+			closeCause()
 			continue
 		}
 		if errMsg == "" {
@@ -104,9 +174,13 @@ func StructureOTELStackTrace(stackTrace string) ([]*publicModel.ErrorTrace, erro
 			continue
 		} else if matches := goRecoveredPanicPattern.FindSubmatch([]byte(line)); matches != nil {
 			language = Golang
+			// the frames preceding the panic are those of the deferred function recovering it,
+			// which are not a cause of the panic and are dropped
 			frames = nil
 			frame = nil
 			errMsg = ""
+			causes = nil
+			causeStart = 0
 			continue
 		} else if matches := goLinePattern.FindSubmatch([]byte(line)); matches != nil {
 			language = Golang
@@ -131,14 +205,22 @@ func StructureOTELStackTrace(stackTrace string) ([]*publicModel.ErrorTrace, erro
 		frames = append(frames, frame)
 		frame = nil
 	}
+	closeCause()
 	// for otel non-go errors, stacktraces are sent top-down (top frame is most outer; bottom frame is most inner)
 	// our backend expects to store stack traces in the opposite order, so we have to reverse it before returning.
 	if language != Golang {
 		for i, j := 0, len(frames)-1; i < j; i, j = i+1, j-1 {
 			frames[i], frames[j] = frames[j], frames[i]
 		}
+		for _, cause := range causes {
+			lo.Reverse(cause.Frames)
+		}
 	}
-	return frames, nil
+	// python prints the exception that was being handled before the one raised while handling it
+	if language == Python {
+		lo.Reverse(causes)
+	}
+	return frames, causes
 }
 
 func FormatStructureStackTrace(ctx context.Context, stackTrace string) string {
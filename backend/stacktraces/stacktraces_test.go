@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -57,3 +58,171 @@ func TestFormatStructureStackTrace(t *testing.T) {
 		})
 	}
 }
+
+func TestStructureOTELStackTraceLanguages(t *testing.T) {
+	var inputs = []struct {
+		name                 string
+		stacktrace           string
+		expectedLanguage     Language
+		expectedFrameCount   int
+		expectedFrameErrors  []string
+		expectedFileName     string
+		expectedFunctionName string
+		expectedLineNumber   int
+	}{
+		{
+			name:                 "java",
+			stacktrace:           "java.lang.IllegalStateException: boom\n\tat com.example.orders.OrderService.place(OrderService.java:42)\n\tat com.example.orders.OrderController.create(OrderController.java:17)\n\tat java.base/java.lang.Thread.run(Thread.java:829)\n",
+			expectedLanguage:     Java,
+			expectedFrameCount:   3,
+			expectedFrameErrors:  []string{"java.lang.IllegalStateException: boom"},
+			expectedFileName:     "OrderService.java",
+			expectedFunctionName: "com.example.orders.OrderService.place",
+			expectedLineNumber:   42,
+		},
+		{
+			name:                 "java-caused-by",
+			stacktrace:           "java.lang.RuntimeException: wrapper\n\tat com.example.App.run(App.java:10)\n\tat com.example.App.main(App.java:5)\nCaused by: java.io.IOException: disk full\n\tat com.example.Store.write(Store.java:88)\n\tat sun.nio.ch.FileDispatcherImpl.write0(Native Method)\n\t... 2 more\n",
+			expectedLanguage:     Java,
+			expectedFrameCount:   4,
+			expectedFrameErrors:  []string{"java.lang.RuntimeException: wrapper", "java.io.IOException: disk full"},
+			expectedFileName:     "App.java",
+			expectedFunctionName: "com.example.App.run",
+			expectedLineNumber:   10,
+		},
+		{
+			name:                 "ruby",
+			stacktrace:           "app/models/user.rb:10:in `save': validation failed (RuntimeError)\n\tfrom app/controllers/users_controller.rb:22:in `create'\n\tfrom bin/rails:4:in `<main>'\n",
+			expectedLanguage:     Ruby,
+			expectedFrameCount:   3,
+			expectedFrameErrors:  []string{"validation failed (RuntimeError)"},
+			expectedFileName:     "app/models/user.rb",
+			expectedFunctionName: "save",
+			expectedLineNumber:   10,
+		},
+		{
+			name:                 "dotnet",
+			stacktrace:           "System.InvalidOperationException: Sequence contains no elements\n   at System.Linq.ThrowHelper.ThrowNoElementsException()\n   at Shop.Api.Services.CartService.GetTotal(Int32 cartId) in /src/Shop.Api/Services/CartService.cs:line 42\n   at Shop.Api.Controllers.CartController.Get(Int32 id) in /src/Shop.Api/Controllers/CartController.cs:line 18\n",
+			expectedLanguage:     DotNet,
+			expectedFrameCount:   3,
+			expectedFrameErrors:  []string{"System.InvalidOperationException: Sequence contains no elements"},
+			expectedFunctionName: "System.Linq.ThrowHelper.ThrowNoElementsException",
+		},
+		{
+			name:                 "php",
+			stacktrace:           "PHP Fatal error:  Uncaught Exception: boom in /app/src/Foo.php:12\nStack trace:\n#0 /app/src/Foo.php(20): App\\Foo->bar('x')\n#1 [internal function]: App\\Foo->run()\n#2 /app/public/index.php(3): call_user_func(Array)\n#3 {main}\n  thrown in /app/src/Foo.php on line 12\n",
+			expectedLanguage:     PHP,
+			expectedFrameCount:   3,
+			expectedFrameErrors:  []string{"PHP Fatal error:  Uncaught Exception: boom in /app/src/Foo.php:12"},
+			expectedFileName:     "/app/src/Foo.php",
+			expectedFunctionName: "App\\Foo->bar",
+			expectedLineNumber:   20,
+		},
+		{
+			name:                 "rust",
+			stacktrace:           "thread 'main' panicked at 'called `Option::unwrap()` on a `None` value', src/main.rs:4:37\nstack backtrace:\n   0: rust_begin_unwind\n             at /rustc/90c541806f23a127002de5b4038be731ba1458ca/library/std/src/panicking.rs:578:5\n   1: core::panicking::panic\n   2: core::option::Option<T>::unwrap::h0123456789abcdef\n             at /rustc/90c541806f23a127002de5b4038be731ba1458ca/library/core/src/option.rs:778:21\n   3: hello::main\n             at ./src/main.rs:4:5\nnote: Some details are omitted, run with `RUST_BACKTRACE=full` for a verbose backtrace.\n",
+			expectedLanguage:     Rust,
+			expectedFrameCount:   4,
+			expectedFrameErrors:  []string{"thread 'main' panicked at 'called `Option::unwrap()` on a `None` value', src/main.rs:4:37"},
+			expectedFileName:     "/rustc/90c541806f23a127002de5b4038be731ba1458ca/library/std/src/panicking.rs",
+			expectedFunctionName: "rust_begin_unwind",
+			expectedLineNumber:   578,
+		},
+	}
+	for _, input := range inputs {
+		t.Run(input.name, func(t *testing.T) {
+			assert.Equal(t, input.expectedLanguage, detectLanguage(strings.Split(input.stacktrace, "\n")))
+
+			frames, err := StructureOTELStackTrace(input.stacktrace)
+			assert.NoError(t, err)
+			assert.Equal(t, input.expectedFrameCount, len(frames))
+
+			var frameErrors []string
+			for _, frame := range frames {
+				assert.NotNil(t, frame.FunctionName)
+				if len(frameErrors) == 0 || frameErrors[len(frameErrors)-1] != *frame.Error {
+					frameErrors = append(frameErrors, *frame.Error)
+				}
+			}
+			assert.Equal(t, input.expectedFrameErrors, frameErrors)
+
+			assert.Equal(t, input.expectedFunctionName, *frames[0].FunctionName)
+			if input.expectedFileName != "" {
+				assert.Equal(t, input.expectedFileName, *frames[0].FileName)
+				assert.Equal(t, input.expectedLineNumber, *frames[0].LineNumber)
+			}
+		})
+	}
+}
+
+func TestStructureOTELStackTraceCauses(t *testing.T) {
+	type expectedCause struct {
+		errorType     string
+		message       string
+		frameCount    int
+		firstFunction string
+	}
+	var inputs = []struct {
+		name           string
+		stacktrace     string
+		expectedCauses []expectedCause
+	}{
+		{
+			name:       "python",
+			stacktrace: "Traceback (most recent call last):\n  File \"/app/db.py\", line 10, in connect\n    raise ConnectionError(\"refused\")\nConnectionError: refused\n\nThe above exception was the direct cause of the following exception:\n\nTraceback (most recent call last):\n  File \"/app/main.py\", line 3, in handler\n    load()\n  File \"/app/main.py\", line 7, in load\n    raise RuntimeError(\"could not load\") from e\nRuntimeError: could not load\n",
+			expectedCauses: []expectedCause{
+				{errorType: "RuntimeError", message: "could not load", frameCount: 2, firstFunction: "load"},
+				{errorType: "ConnectionError", message: "refused", frameCount: 1, firstFunction: "connect"},
+			},
+		},
+		{
+			name:       "golang-panic-recover",
+			stacktrace: "\ngithub.com/highlight/highlight/sdk/highlight-go.GraphQLRecoverFunc.func1\n\t/build/sdk/highlight-go/tracer.go:110\nruntime.gopanic\n\t/usr/local/go/src/runtime/panic.go:890\ngithub.com/highlight-run/highlight/backend/private-graph/graph.(*Resolver).isAdminInProject\n\t/build/backend/private-graph/graph/resolver.go:481\ngithub.com/highlight-run/highlight/backend/private-graph/graph.(*queryResolver).ErrorInstance\n\t/build/backend/private-graph/graph/schema.resolvers.go:4097",
+			expectedCauses: []expectedCause{
+				{message: "github.com/highlight-run/highlight/backend/private-graph/graph.(*Resolver).isAdminInProject", frameCount: 2, firstFunction: "isAdminInProject"},
+			},
+		},
+		{
+			name:       "java",
+			stacktrace: "java.lang.RuntimeException: wrapper\n\tat com.example.App.run(App.java:10)\n\tat com.example.App.main(App.java:5)\nCaused by: java.io.IOException: disk full\n\tat com.example.Store.write(Store.java:88)\n\tat sun.nio.ch.FileDispatcherImpl.write0(Native Method)\n\t... 2 more\n",
+			expectedCauses: []expectedCause{
+				{errorType: "java.lang.RuntimeException", message: "wrapper", frameCount: 2, firstFunction: "com.example.App.run"},
+				{errorType: "java.io.IOException", message: "disk full", frameCount: 2, firstFunction: "com.example.Store.write"},
+			},
+		},
+		{
+			name:       "dotnet",
+			stacktrace: "System.InvalidOperationException: could not save ---> System.IO.IOException: disk full\n   at Shop.Store.Write() in /src/Store.cs:line 12\n   --- End of inner exception stack trace ---\n   at Shop.Cart.Save() in /src/Cart.cs:line 30\n   at Shop.Api.Post() in /src/Api.cs:line 8\n",
+			expectedCauses: []expectedCause{
+				{errorType: "System.InvalidOperationException", message: "could not save", frameCount: 2, firstFunction: "Shop.Cart.Save"},
+				{errorType: "System.IO.IOException", message: "disk full", frameCount: 1, firstFunction: "Shop.Store.Write"},
+			},
+		},
+		{
+			name:       "php",
+			stacktrace: "PDOException: connection refused in /app/Db.php:5\nStack trace:\n#0 /app/Db.php(5): PDO->__construct('mysql:')\n#1 {main}\n\nNext RuntimeException: could not connect in /app/Db.php:9\nStack trace:\n#0 /app/index.php(3): Db->connect()\n#1 {main}\n",
+			expectedCauses: []expectedCause{
+				{errorType: "RuntimeException", message: "could not connect in /app/Db.php:9", frameCount: 1, firstFunction: "Db->connect"},
+				{errorType: "PDOException", message: "connection refused in /app/Db.php:5", frameCount: 1, firstFunction: "PDO->__construct"},
+			},
+		},
+	}
+	for _, input := range inputs {
+		t.Run(input.name, func(t *testing.T) {
+			causes, err := StructureOTELStackTraceCauses(input.stacktrace)
+			assert.NoError(t, err)
+			assert.Equal(t, len(input.expectedCauses), len(causes))
+
+			for idx, expected := range input.expectedCauses {
+				cause := causes[idx]
+				assert.Equal(t, expected.errorType, cause.Type)
+				assert.Equal(t, expected.message, cause.Message)
+				assert.Equal(t, expected.frameCount, len(cause.Frames))
+				assert.Equal(t, expected.firstFunction, *cause.Frames[0].FunctionName)
+				for _, frame := range cause.Frames {
+					assert.NotNil(t, frame.Error)
+				}
+			}
+		})
+	}
+}
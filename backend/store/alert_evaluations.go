@@ -0,0 +1,121 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	e "github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Number of alert evaluations returned when no count is requested
+const DefaultAlertEvaluationsCount = 50
+
+type AlertEvaluationInput struct {
+	AlertType string
+	AlertID   int
+	// GroupKey identifies the group evaluated by grouped alerts, or is empty for ungrouped alerts
+	GroupKey string
+	Settings model.AlertEvaluationSettings
+	// Alerting is whether the value met the alert condition
+	Alerting  bool
+	Value     float64
+	Threshold float64
+}
+
+// EvaluateAlert moves the persisted state of an alert forward by one evaluation, recording the evaluation
+// when it changes the state of the alert or notifies.
+// The status is only updated if it did not change since it was read, so when the same alert is evaluated
+// concurrently only one evaluation is applied and nil is returned for the others.
+// Callers should only notify the alert's channels when the returned evaluation is Notified.
+func (store *Store) EvaluateAlert(ctx context.Context, input AlertEvaluationInput) (*model.AlertEvaluation, error) {
+	if err := store.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&model.AlertStatus{
+		AlertType: input.AlertType,
+		AlertID:   input.AlertID,
+		GroupKey:  input.GroupKey,
+	}).Error; err != nil {
+		return nil, e.Wrap(err, "error creating alert status")
+	}
+
+	var status model.AlertStatus
+	if err := store.db.WithContext(ctx).
+		Where("alert_type = ? AND alert_id = ? AND group_key = ?", input.AlertType, input.AlertID, input.GroupKey).
+		Take(&status).Error; err != nil {
+		return nil, e.Wrap(err, "error querying alert status")
+	}
+
+	next, notify := status.Next(input.Alerting, input.Settings, time.Now())
+	evaluation := model.AlertEvaluation{
+		AlertType: input.AlertType,
+		AlertID:   input.AlertID,
+		GroupKey:  input.GroupKey,
+		State:     next.State,
+		Value:     input.Value,
+		Threshold: input.Threshold,
+		Notified:  notify,
+	}
+
+	var updated bool
+	if err := store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.AlertStatus{}).
+			Where("id = ? AND version = ?", status.ID, status.Version).
+			Updates(map[string]interface{}{
+				"state":            next.State,
+				"pending_count":    next.PendingCount,
+				"last_notified_at": next.LastNotifiedAt,
+				"version":          status.Version + 1,
+			})
+		if result.Error != nil {
+			return e.Wrap(result.Error, "error updating alert status")
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		updated = true
+		// evaluations run on every tick, so only the ones changing the state or notifying are kept as history
+		if next.State == status.State && !notify {
+			return nil
+		}
+		return e.Wrap(tx.Create(&evaluation).Error, "error creating alert evaluation")
+	}); err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, nil
+	}
+
+	return &evaluation, nil
+}
+
+// GetAlertStatuses returns the statuses of an alert, one per group for grouped alerts.
+func (store *Store) GetAlertStatuses(ctx context.Context, alertType string, alertID int) ([]*model.AlertStatus, error) {
+	var statuses []*model.AlertStatus
+	if err := store.db.WithContext(ctx).Where(&model.AlertStatus{
+		AlertType: alertType,
+		AlertID:   alertID,
+	}).Order("group_key").Find(&statuses).Error; err != nil {
+		return nil, e.Wrap(err, "error querying alert statuses")
+	}
+
+	return statuses, nil
+}
+
+// GetAlertEvaluations returns the most recent evaluations of an alert, newest first.
+func (store *Store) GetAlertEvaluations(ctx context.Context, alertType string, alertID int, count *int) ([]*model.AlertEvaluation, error) {
+	limit := DefaultAlertEvaluationsCount
+	if count != nil && *count > 0 {
+		limit = *count
+	}
+
+	var evaluations []*model.AlertEvaluation
+	if err := store.db.WithContext(ctx).Where(&model.AlertEvaluation{
+		AlertType: alertType,
+		AlertID:   alertID,
+	}).Order("created_at DESC, id DESC").Limit(limit).Find(&evaluations).Error; err != nil {
+		return nil, e.Wrap(err, "error querying alert evaluations")
+	}
+
+	return evaluations, nil
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
+	"github.com/stretchr/testify/assert"
+	_ "gorm.io/driver/postgres"
+)
+
+func TestEvaluateAlert(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	input := AlertEvaluationInput{
+		AlertType: model.AlertType.LOG,
+		AlertID:   1,
+		Settings:  model.AlertEvaluationSettings{PendingEvaluations: 2},
+		Alerting:  true,
+		Value:     10,
+		Threshold: 5,
+	}
+
+	evaluation, err := store.EvaluateAlert(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, privateModel.AlertStatePending, evaluation.State)
+	assert.False(t, evaluation.Notified)
+
+	evaluation, err = store.EvaluateAlert(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, privateModel.AlertStateFiring, evaluation.State)
+	assert.True(t, evaluation.Notified)
+
+	// firing alerts without a renotify interval only notify once
+	evaluation, err = store.EvaluateAlert(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, privateModel.AlertStateFiring, evaluation.State)
+	assert.False(t, evaluation.Notified)
+
+	input.Alerting = false
+	evaluation, err = store.EvaluateAlert(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, privateModel.AlertStateResolved, evaluation.State)
+	assert.True(t, evaluation.Notified)
+
+	// the status of other alerts is independent
+	other, err := store.EvaluateAlert(ctx, AlertEvaluationInput{
+		AlertType: model.AlertType.METRIC_MONITOR,
+		AlertID:   1,
+		Alerting:  true,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, privateModel.AlertStateFiring, other.State)
+
+	// the evaluation that kept the alert firing without notifying is not recorded
+	evaluations, err := store.GetAlertEvaluations(ctx, model.AlertType.LOG, 1, nil)
+	assert.NoError(t, err)
+	assert.Len(t, evaluations, 3)
+	assert.Equal(t, privateModel.AlertStateResolved, evaluations[0].State)
+	assert.Equal(t, privateModel.AlertStateFiring, evaluations[1].State)
+	assert.Equal(t, privateModel.AlertStatePending, evaluations[2].State)
+
+	evaluations, err = store.GetAlertEvaluations(ctx, model.AlertType.LOG, 1, ptr.Int(1))
+	assert.NoError(t, err)
+	assert.Len(t, evaluations, 1)
+}
+
+func TestEvaluateAlertGroups(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	for _, groupKey := range []string{"api", "web"} {
+		evaluation, err := store.EvaluateAlert(ctx, AlertEvaluationInput{
+			AlertType: model.AlertType.TRACE,
+			AlertID:   1,
+			GroupKey:  groupKey,
+			Alerting:  groupKey == "api",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, groupKey, evaluation.GroupKey)
+	}
+
+	statuses, err := store.GetAlertStatuses(ctx, model.AlertType.TRACE, 1)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "api", statuses[0].GroupKey)
+	assert.Equal(t, privateModel.AlertStateFiring, statuses[0].State)
+	assert.Equal(t, "web", statuses[1].GroupKey)
+	assert.Equal(t, privateModel.AlertStateOk, statuses[1].State)
+
+	// the web group stayed ok
+	evaluations, err := store.GetAlertEvaluations(ctx, model.AlertType.TRACE, 1, nil)
+	assert.NoError(t, err)
+	assert.Len(t, evaluations, 1)
+	assert.Equal(t, "api", evaluations[0].GroupKey)
+}
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/highlight-run/highlight/backend/redis"
+	e "github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+func getErrorGroupingRulesKey(projectID int) string {
+	return fmt.Sprintf("error-grouping-rules-%d", projectID)
+}
+
+// GetErrorGroupingRules returns the error grouping rules of a project, in the order they are applied.
+func (store *Store) GetErrorGroupingRules(ctx context.Context, projectID int, opts ...redis.Option) ([]*model.ErrorGroupingRule, error) {
+	rules, err := redis.CachedEval(ctx, store.redis, getErrorGroupingRulesKey(projectID), 250*time.Millisecond, time.Minute, func() (*[]*model.ErrorGroupingRule, error) {
+		var rules []*model.ErrorGroupingRule
+		if err := store.db.WithContext(ctx).Where(&model.ErrorGroupingRule{ProjectID: projectID}).Order("priority, id").Find(&rules).Error; err != nil {
+			return nil, err
+		}
+		return &rules, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return *rules, nil
+}
+
+// UpdateErrorGroupingRules replaces the error grouping rules of a project, prioritizing them in the given order.
+func (store *Store) UpdateErrorGroupingRules(ctx context.Context, projectID int, rules []*model.ErrorGroupingRule) ([]*model.ErrorGroupingRule, error) {
+	for idx, rule := range rules {
+		rule.ProjectID = projectID
+		rule.Priority = idx
+	}
+
+	if err := store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(&model.ErrorGroupingRule{ProjectID: projectID}).Delete(&model.ErrorGroupingRule{}).Error; err != nil {
+			return e.Wrap(err, "error deleting error grouping rules")
+		}
+		if len(rules) > 0 {
+			if err := tx.Create(&rules).Error; err != nil {
+				return e.Wrap(err, "error creating error grouping rules")
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return rules, store.redis.Del(ctx, getErrorGroupingRulesKey(projectID))
+}
@@ -0,0 +1,48 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/model"
+	"github.com/stretchr/testify/assert"
+	_ "gorm.io/driver/postgres"
+)
+
+func TestUpdateErrorGroupingRules(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	project := model.Project{}
+	store.db.Create(&project)
+
+	rules, err := store.GetErrorGroupingRules(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, rules)
+
+	_, err = store.UpdateErrorGroupingRules(ctx, project.ID, []*model.ErrorGroupingRule{
+		{IgnoreFrames: ptr.String("node_modules")},
+		{MatchType: ptr.String("TypeError"), GroupBy: []string{"type", "function"}},
+	})
+	assert.NoError(t, err)
+
+	rules, err = store.GetErrorGroupingRules(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+	assert.Equal(t, ptr.String("node_modules"), rules[0].IgnoreFrames)
+	assert.Equal(t, 1, rules[1].Priority)
+	assert.Equal(t, []string{"type", "function"}, []string(rules[1].GroupBy))
+
+	// updating the rules replaces the existing ones
+	_, err = store.UpdateErrorGroupingRules(ctx, project.ID, []*model.ErrorGroupingRule{
+		{MatchEvent: ptr.String("timeout"), GroupBy: []string{"event"}},
+	})
+	assert.NoError(t, err)
+
+	rules, err = store.GetErrorGroupingRules(ctx, project.ID)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 1)
+	assert.Equal(t, project.ID, rules[0].ProjectID)
+	assert.Equal(t, ptr.String("timeout"), rules[0].MatchEvent)
+}
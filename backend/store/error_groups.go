@@ -2,16 +2,23 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"sort"
 	"strconv"
 	"time"
 
+	"github.com/highlight-run/highlight/backend/alerts"
+	"github.com/highlight-run/highlight/backend/errorgroups"
 	kafka_queue "github.com/highlight-run/highlight/backend/kafka-queue"
 	"github.com/highlight-run/highlight/backend/model"
 	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"github.com/highlight-run/highlight/backend/queryparser"
+	"github.com/highlight-run/highlight/backend/util"
+	e "github.com/pkg/errors"
 	"github.com/samber/lo"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type ListErrorObjectsParams struct {
@@ -190,6 +197,8 @@ type UpdateErrorGroupParams struct {
 	ID           int
 	State        privateModel.ErrorState
 	SnoozedUntil *time.Time
+	// ResolveInNextRelease keeps a resolved group resolved for errors of the current service version
+	ResolveInNextRelease bool
 }
 
 func (store *Store) UpdateErrorGroupStateByAdmin(ctx context.Context,
@@ -207,14 +216,37 @@ func (store *Store) updateErrorGroupState(ctx context.Context,
 
 	var errorGroup model.ErrorGroup
 
+	updates := map[string]interface{}{
+		"State":                params.State,
+		"SnoozedUntil":         params.SnoozedUntil,
+		"RegressedAt":          nil,
+		"ResolvedInVersion":    nil,
+		"ResolveInNextRelease": false,
+	}
+
+	var resolvedInVersion *string
+	if params.State == privateModel.ErrorStateResolved {
+		var latestErrorObject model.ErrorObject
+		if err := store.db.WithContext(ctx).
+			Select("service_version").
+			Where(&model.ErrorObject{ErrorGroupID: params.ID}).
+			Order("id DESC").
+			Limit(1).
+			Find(&latestErrorObject).Error; err != nil {
+			return errorGroup, err
+		}
+		if latestErrorObject.ServiceVersion != "" {
+			resolvedInVersion = &latestErrorObject.ServiceVersion
+		}
+		updates["ResolvedInVersion"] = resolvedInVersion
+		updates["ResolveInNextRelease"] = params.ResolveInNextRelease
+	}
+
 	if err := store.db.Where(&model.ErrorGroup{
 		Model: model.Model{
 			ID: params.ID,
 		},
-	}).Take(&errorGroup).Updates(map[string]interface{}{
-		"State":        params.State,
-		"SnoozedUntil": params.SnoozedUntil,
-	}).Error; err != nil {
+	}).Take(&errorGroup).Updates(updates).Error; err != nil {
 		return errorGroup, err
 	}
 
@@ -229,6 +261,11 @@ func (store *Store) updateErrorGroupState(ctx context.Context,
 		eventData["SnoozedUntil"] = params.SnoozedUntil
 	}
 
+	if params.ResolveInNextRelease && params.State == privateModel.ErrorStateResolved {
+		eventData["ResolveInNextRelease"] = true
+		eventData["ResolvedInVersion"] = resolvedInVersion
+	}
+
 	err = store.CreateErrorGroupActivityLog(ctx, model.ErrorGroupActivityLog{
 		Admin:        admin,
 		EventType:    eventType,
@@ -253,6 +290,20 @@ func (store *Store) updateErrorGroupState(ctx context.Context,
 		return errorGroup, err
 	}
 
+	if params.State == privateModel.ErrorStateResolved {
+		// resolving incidents calls the alert integrations, so it does not delay the update
+		// and outlives the context of the request
+		resolvedErrorGroup := errorGroup
+		go func() {
+			defer util.Recover()
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			if err := alerts.ResolveErrorGroupIncidents(ctx, store.db, &resolvedErrorGroup); err != nil {
+				log.WithContext(ctx).WithError(err).WithField("error_group_id", resolvedErrorGroup.ID).Error("failed to resolve error group incidents")
+			}
+		}()
+	}
+
 	return errorGroup, nil
 
 }
@@ -271,3 +322,255 @@ func getEventType(state privateModel.ErrorState) (model.ErrorGroupEventType, err
 
 	return event, errors.New("unable to determine event type")
 }
+
+// MergeErrorGroups merges error groups into errorGroup, moving their error objects, fingerprints and comments.
+// Alert history follows the moved error objects. The merged groups are ignored and point to errorGroup,
+// so that new errors matching them are grouped into errorGroup.
+func (store *Store) MergeErrorGroups(ctx context.Context, admin model.Admin, errorGroup *model.ErrorGroup, mergedErrorGroups []*model.ErrorGroup) (*model.ErrorGroup, error) {
+	mergedErrorGroupIDs := lo.Map(mergedErrorGroups, func(eg *model.ErrorGroup, _ int) int {
+		return eg.ID
+	})
+	if len(mergedErrorGroupIDs) == 0 {
+		return nil, errors.New("no error groups to merge")
+	}
+	if lo.Contains(mergedErrorGroupIDs, errorGroup.ID) {
+		return nil, errors.New("cannot merge an error group into itself")
+	}
+	if errorGroup.MergedIntoID != nil {
+		return nil, errors.New("cannot merge into an error group that was merged")
+	}
+	for _, eg := range mergedErrorGroups {
+		if eg.ProjectID != errorGroup.ProjectID {
+			return nil, errors.New("cannot merge error groups of different projects")
+		}
+	}
+
+	var errorObjectIDs []int
+	if err := store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Raw(`
+			UPDATE error_objects
+			SET error_group_id = @errorGroupID
+			WHERE error_group_id IN @mergedErrorGroupIDs
+			RETURNING id`,
+			map[string]interface{}{
+				"errorGroupID":        errorGroup.ID,
+				"mergedErrorGroupIDs": mergedErrorGroupIDs,
+			}).Scan(&errorObjectIDs).Error; err != nil {
+			return e.Wrap(err, "error moving error objects")
+		}
+
+		if err := tx.Exec(`
+			UPDATE error_fingerprints
+			SET error_group_id = @errorGroupID, merged_from_id = COALESCE(merged_from_id, error_group_id)
+			WHERE error_group_id IN @mergedErrorGroupIDs`,
+			map[string]interface{}{
+				"errorGroupID":        errorGroup.ID,
+				"mergedErrorGroupIDs": mergedErrorGroupIDs,
+			}).Error; err != nil {
+			return e.Wrap(err, "error moving error fingerprints")
+		}
+
+		// issues are attached to the comments, so they move with them
+		if err := tx.Model(&model.ErrorComment{}).
+			Where("error_id IN ?", mergedErrorGroupIDs).
+			Updates(map[string]interface{}{
+				"ErrorId":       errorGroup.ID,
+				"ErrorSecureId": errorGroup.SecureID,
+			}).Error; err != nil {
+			return e.Wrap(err, "error moving error comments")
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO error_group_fields (error_group_id, error_field_id)
+			SELECT @errorGroupID, error_field_id
+			FROM error_group_fields
+			WHERE error_group_id IN @mergedErrorGroupIDs
+			ON CONFLICT DO NOTHING`,
+			map[string]interface{}{
+				"errorGroupID":        errorGroup.ID,
+				"mergedErrorGroupIDs": mergedErrorGroupIDs,
+			}).Error; err != nil {
+			return e.Wrap(err, "error merging error group fields")
+		}
+
+		if err := tx.Model(&model.ErrorGroup{}).
+			Where("id IN ? OR merged_into_id IN ?", mergedErrorGroupIDs, mergedErrorGroupIDs).
+			Updates(map[string]interface{}{
+				"MergedIntoID": errorGroup.ID,
+				"State":        privateModel.ErrorStateIgnored,
+			}).Error; err != nil {
+			return e.Wrap(err, "error updating merged error groups")
+		}
+
+		logs := []*model.ErrorGroupActivityLog{{
+			AdminID:      admin.ID,
+			EventType:    model.ErrorGroupMergedEvent,
+			ErrorGroupID: errorGroup.ID,
+			EventData:    map[string]interface{}{"MergedErrorGroupIDs": mergedErrorGroupIDs},
+		}}
+		for _, id := range mergedErrorGroupIDs {
+			logs = append(logs, &model.ErrorGroupActivityLog{
+				AdminID:      admin.ID,
+				EventType:    model.ErrorGroupMergedEvent,
+				ErrorGroupID: id,
+				EventData:    map[string]interface{}{"MergedIntoID": errorGroup.ID},
+			})
+		}
+		return tx.Create(&logs).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := store.syncErrors(ctx, append([]int{errorGroup.ID}, mergedErrorGroupIDs...), errorObjectIDs); err != nil {
+		return nil, err
+	}
+
+	return errorGroup, nil
+}
+
+// SplitErrorGroup moves error objects of an error group into a new error group, fingerprinted by the latest of them.
+func (store *Store) SplitErrorGroup(ctx context.Context, admin model.Admin, errorGroup *model.ErrorGroup, errorObjectIDs []int) (*model.ErrorGroup, error) {
+	var errorObjects []*model.ErrorObject
+	if err := store.db.WithContext(ctx).
+		Where(&model.ErrorObject{ErrorGroupID: errorGroup.ID}).
+		Where("id IN ?", errorObjectIDs).
+		Order("id DESC").
+		Find(&errorObjects).Error; err != nil {
+		return nil, e.Wrap(err, "error querying error objects")
+	}
+	if len(errorObjects) == 0 || len(errorObjects) != len(lo.Uniq(errorObjectIDs)) {
+		return nil, errors.New("error objects must belong to the error group")
+	}
+
+	var count int64
+	if err := store.db.WithContext(ctx).Model(&model.ErrorObject{}).
+		Where(&model.ErrorObject{ErrorGroupID: errorGroup.ID}).
+		Count(&count).Error; err != nil {
+		return nil, e.Wrap(err, "error counting error objects")
+	}
+	if int(count) == len(errorObjects) {
+		return nil, errors.New("cannot split all error objects off an error group")
+	}
+
+	latest := errorObjects[0]
+	fingerprints, err := store.getSplitFingerprints(ctx, errorGroup.ProjectID, latest)
+	if err != nil {
+		return nil, err
+	}
+
+	newErrorGroup := &model.ErrorGroup{
+		ProjectID:        errorGroup.ProjectID,
+		Event:            latest.Event,
+		StackTrace:       lo.FromPtr(latest.StackTrace),
+		MappedStackTrace: latest.MappedStackTrace,
+		Type:             latest.Type,
+		State:            privateModel.ErrorStateOpen,
+		ServiceName:      latest.ServiceName,
+	}
+
+	if err := store.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(newErrorGroup).Error; err != nil {
+			return e.Wrap(err, "error creating error group")
+		}
+
+		if err := tx.Model(&model.ErrorObject{}).
+			Where("id IN ?", lo.Map(errorObjects, func(eo *model.ErrorObject, _ int) int {
+				return eo.ID
+			})).
+			Update("ErrorGroupID", newErrorGroup.ID).Error; err != nil {
+			return e.Wrap(err, "error moving error objects")
+		}
+
+		if len(fingerprints) > 0 {
+			// the fingerprints move to the new group, so that matching errors are grouped with the split errors
+			if err := tx.Where(&model.ErrorFingerprint{ErrorGroupId: errorGroup.ID}).
+				Where("(type, value, index) IN ?", lo.Map(fingerprints, func(fingerprint *model.ErrorFingerprint, _ int) []interface{} {
+					return []interface{}{fingerprint.Type, fingerprint.Value, fingerprint.Index}
+				})).
+				Delete(&model.ErrorFingerprint{}).Error; err != nil {
+				return e.Wrap(err, "error deleting error fingerprints")
+			}
+			for _, fingerprint := range fingerprints {
+				fingerprint.ErrorGroupId = newErrorGroup.ID
+			}
+			if err := tx.Create(&fingerprints).Error; err != nil {
+				return e.Wrap(err, "error creating error fingerprints")
+			}
+		}
+
+		eventData := map[string]interface{}{
+			"ErrorObjectIDs":    errorObjectIDs,
+			"SplitErrorGroupID": errorGroup.ID,
+			"NewErrorGroupID":   newErrorGroup.ID,
+		}
+		return tx.Create([]*model.ErrorGroupActivityLog{{
+			AdminID:      admin.ID,
+			EventType:    model.ErrorGroupSplitEvent,
+			ErrorGroupID: errorGroup.ID,
+			EventData:    eventData,
+		}, {
+			AdminID:      admin.ID,
+			EventType:    model.ErrorGroupSplitEvent,
+			ErrorGroupID: newErrorGroup.ID,
+			EventData:    eventData,
+		}}).Error
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := store.syncErrors(ctx, []int{errorGroup.ID, newErrorGroup.ID}, lo.Map(errorObjects, func(eo *model.ErrorObject, _ int) int {
+		return eo.ID
+	})); err != nil {
+		return nil, err
+	}
+
+	return newErrorGroup, nil
+}
+
+// getSplitFingerprints returns the fingerprints of an error object split off its group,
+// as they are created when the error is grouped: by stacktrace, by the project's JSON paths and by its grouping rules.
+func (store *Store) getSplitFingerprints(ctx context.Context, projectID int, errorObject *model.ErrorObject) ([]*model.ErrorFingerprint, error) {
+	project, err := store.GetProject(ctx, projectID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying project")
+	}
+	rules, err := store.GetErrorGroupingRules(ctx, projectID)
+	if err != nil {
+		return nil, e.Wrap(err, "error querying error grouping rules")
+	}
+
+	stackTrace := lo.FromPtr(errorObject.MappedStackTrace)
+	if stackTrace == "" {
+		stackTrace = lo.FromPtr(errorObject.StackTrace)
+	}
+	var errorTraces []*privateModel.ErrorTrace
+	// unstructured stacktraces have no frames to fingerprint
+	_ = json.Unmarshal([]byte(stackTrace), &errorTraces)
+
+	grouping := errorgroups.ApplyGroupingRules(rules, errorObject.Type, errorObject.Event, errorTraces)
+	fingerprints := errorgroups.GetFingerprints(projectID, grouping.StackTrace)
+	if grouping.Fingerprint != nil {
+		fingerprints = append(fingerprints, &model.ErrorFingerprint{
+			ProjectID: projectID,
+			Type:      model.Fingerprint.CustomRule,
+			Value:     *grouping.Fingerprint,
+		})
+	}
+	fingerprints = append(fingerprints, errorgroups.GetJsonFingerprints(projectID, project.ErrorJsonPaths, errorObject.Event)...)
+	return fingerprints, nil
+}
+
+// syncErrors writes moved error groups and objects to Clickhouse through the data sync queue.
+func (store *Store) syncErrors(ctx context.Context, errorGroupIDs []int, errorObjectIDs []int) error {
+	for _, id := range errorGroupIDs {
+		if err := store.dataSyncQueue.Submit(ctx, strconv.Itoa(id), &kafka_queue.Message{Type: kafka_queue.ErrorGroupDataSync, ErrorGroupDataSync: &kafka_queue.ErrorGroupDataSyncArgs{ErrorGroupID: id}}); err != nil {
+			return err
+		}
+	}
+	for _, id := range errorObjectIDs {
+		if err := store.dataSyncQueue.Submit(ctx, strconv.Itoa(id), &kafka_queue.Message{Type: kafka_queue.ErrorObjectDataSync, ErrorObjectDataSync: &kafka_queue.ErrorObjectDataSyncArgs{ErrorObjectID: id}}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
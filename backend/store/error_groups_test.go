@@ -2,11 +2,15 @@ package store
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
 
 	"github.com/aws/smithy-go/ptr"
+	"github.com/highlight-run/highlight/backend/alerts/integrations/pagerduty"
 	"github.com/highlight-run/highlight/backend/model"
 	privateModel "github.com/highlight-run/highlight/backend/private-graph/graph/model"
 	"github.com/samber/lo"
@@ -412,3 +416,172 @@ func TestUpdateErrorGroupStateBySystem(t *testing.T) {
 	assert.Equal(t, activityLogs[0].EventType, model.ErrorGroupIgnoredEvent)
 	assert.NotNil(t, activityLogs[0].EventData)
 }
+
+func TestUpdateErrorGroupStateResolvesIncidents(t *testing.T) {
+	defer teardown(t)
+
+	var events []pagerduty.Event
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event pagerduty.Event
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		events = append(events, event)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	pagerduty.EventsURL = server.URL
+
+	project := model.Project{}
+	store.db.Create(&project)
+	errorGroup := model.ErrorGroup{ProjectID: project.ID, State: privateModel.ErrorStateOpen, Event: "boom"}
+	store.db.Create(&errorGroup)
+	errorObject := model.ErrorObject{ProjectID: project.ID, ErrorGroupID: errorGroup.ID, Event: "boom"}
+	store.db.Create(&errorObject)
+
+	services := model.PagerDutyServices{{Name: "backend", RoutingKey: "routing-key"}}
+	sentAlert := model.ErrorAlert{Alert: model.Alert{ProjectID: project.ID}, AlertIntegrations: model.AlertIntegrations{PagerDutyServices: services}}
+	store.db.Create(&sentAlert)
+	store.db.Create(&model.ErrorAlertEvent{ErrorAlertID: sentAlert.ID, ErrorObjectID: errorObject.ID, SentAt: time.Now()})
+	// an alert that never fired for the error group has no incident to resolve
+	store.db.Create(&model.ErrorAlert{Alert: model.Alert{ProjectID: project.ID}, AlertIntegrations: model.AlertIntegrations{PagerDutyServices: services}})
+
+	_, err := store.UpdateErrorGroupStateBySystem(context.TODO(), UpdateErrorGroupParams{
+		ID:    errorGroup.ID,
+		State: privateModel.ErrorStateResolved,
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, pagerduty.EventActionResolve, events[0].EventAction)
+	assert.Equal(t, "routing-key", events[0].RoutingKey)
+	assert.Equal(t, "highlight-ERROR_ALERT-"+strconv.Itoa(sentAlert.ID)+"-error-group-"+strconv.Itoa(errorGroup.ID), events[0].DedupKey)
+}
+
+func TestMergeErrorGroups(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	admin := model.Admin{}
+	store.db.Create(&admin)
+
+	errorGroup := model.ErrorGroup{ProjectID: 1, State: privateModel.ErrorStateOpen}
+	store.db.Create(&errorGroup)
+	mergedErrorGroup := model.ErrorGroup{ProjectID: 1, State: privateModel.ErrorStateOpen}
+	store.db.Create(&mergedErrorGroup)
+	otherProjectErrorGroup := model.ErrorGroup{ProjectID: 2}
+	store.db.Create(&otherProjectErrorGroup)
+
+	errorObject := model.ErrorObject{ProjectID: 1, ErrorGroupID: mergedErrorGroup.ID}
+	store.db.Create(&errorObject)
+	fingerprint := model.ErrorFingerprint{ProjectID: 1, ErrorGroupId: mergedErrorGroup.ID, Type: model.Fingerprint.StackFrameCode, Value: "code"}
+	store.db.Create(&fingerprint)
+	comment := model.ErrorComment{ProjectID: 1, ErrorId: mergedErrorGroup.ID, ErrorSecureId: mergedErrorGroup.SecureID}
+	store.db.Create(&comment)
+
+	_, err := store.MergeErrorGroups(ctx, admin, &errorGroup, []*model.ErrorGroup{&errorGroup})
+	assert.Error(t, err)
+	_, err = store.MergeErrorGroups(ctx, admin, &errorGroup, []*model.ErrorGroup{&otherProjectErrorGroup})
+	assert.Error(t, err)
+
+	_, err = store.MergeErrorGroups(ctx, admin, &errorGroup, []*model.ErrorGroup{&mergedErrorGroup})
+	assert.NoError(t, err)
+
+	store.db.Take(&errorObject, errorObject.ID)
+	assert.Equal(t, errorGroup.ID, errorObject.ErrorGroupID)
+
+	store.db.Take(&fingerprint, fingerprint.ID)
+	assert.Equal(t, errorGroup.ID, fingerprint.ErrorGroupId)
+	assert.Equal(t, ptr.Int(mergedErrorGroup.ID), fingerprint.MergedFromID)
+
+	store.db.Take(&comment, comment.ID)
+	assert.Equal(t, errorGroup.ID, comment.ErrorId)
+	assert.Equal(t, errorGroup.SecureID, comment.ErrorSecureId)
+
+	store.db.Take(&mergedErrorGroup, mergedErrorGroup.ID)
+	assert.Equal(t, ptr.Int(errorGroup.ID), mergedErrorGroup.MergedIntoID)
+	assert.Equal(t, privateModel.ErrorStateIgnored, mergedErrorGroup.State)
+
+	for _, id := range []int{errorGroup.ID, mergedErrorGroup.ID} {
+		activityLogs, err := store.GetErrorGroupActivityLogs(id)
+		assert.NoError(t, err)
+		assert.Len(t, activityLogs, 1)
+		assert.Equal(t, admin.ID, activityLogs[0].AdminID)
+		assert.Equal(t, model.ErrorGroupMergedEvent, activityLogs[0].EventType)
+	}
+}
+
+func TestSplitErrorGroup(t *testing.T) {
+	ctx := context.TODO()
+	defer teardown(t)
+
+	admin := model.Admin{}
+	store.db.Create(&admin)
+
+	project := model.Project{ErrorJsonPaths: []string{"$.code"}}
+	store.db.Create(&project)
+	store.db.Create(&model.ErrorGroupingRule{ProjectID: project.ID, MatchEvent: ptr.String("code"), GroupBy: []string{"type"}})
+
+	errorGroup := model.ErrorGroup{ProjectID: project.ID, State: privateModel.ErrorStateOpen}
+	store.db.Create(&errorGroup)
+
+	var errorObjects []*model.ErrorObject
+	for i := 0; i < 3; i++ {
+		errorObject := &model.ErrorObject{
+			ProjectID:    project.ID,
+			ErrorGroupID: errorGroup.ID,
+			Event:        `["{\"code\":` + strconv.Itoa(i) + `}"]`,
+			Type:         "BACKEND",
+			StackTrace:   ptr.String(`[{"fileName":"main.go","functionName":"main","lineNumber":10}]`),
+		}
+		store.db.Create(errorObject)
+		errorObjects = append(errorObjects, errorObject)
+	}
+
+	// the original group has the fingerprint of the split errors, and one of its remaining error
+	store.db.Create([]*model.ErrorFingerprint{
+		{ProjectID: project.ID, ErrorGroupId: errorGroup.ID, Type: model.Fingerprint.StackFrameMetadata, Value: "main.go;main;10;"},
+		{ProjectID: project.ID, ErrorGroupId: errorGroup.ID, Type: model.Fingerprint.JsonResult, Value: "$.code=0"},
+	})
+
+	_, err := store.SplitErrorGroup(ctx, admin, &errorGroup, []int{-1})
+	assert.Error(t, err)
+	_, err = store.SplitErrorGroup(ctx, admin, &errorGroup, lo.Map(errorObjects, func(eo *model.ErrorObject, _ int) int {
+		return eo.ID
+	}))
+	assert.Error(t, err)
+
+	newErrorGroup, err := store.SplitErrorGroup(ctx, admin, &errorGroup, []int{errorObjects[1].ID, errorObjects[2].ID})
+	assert.NoError(t, err)
+	assert.NotEqual(t, errorGroup.ID, newErrorGroup.ID)
+	assert.Equal(t, `["{\"code\":2}"]`, newErrorGroup.Event)
+	assert.Equal(t, privateModel.ErrorStateOpen, newErrorGroup.State)
+
+	for idx, errorObject := range errorObjects {
+		var eo model.ErrorObject
+		store.db.Take(&eo, errorObject.ID)
+		if idx == 0 {
+			assert.Equal(t, errorGroup.ID, eo.ErrorGroupID)
+		} else {
+			assert.Equal(t, newErrorGroup.ID, eo.ErrorGroupID)
+		}
+	}
+
+	var fingerprints []*model.ErrorFingerprint
+	store.db.Where(&model.ErrorFingerprint{ErrorGroupId: newErrorGroup.ID}).Order("id").Find(&fingerprints)
+	assert.Equal(t, []string{"main.go;main;10;", "type=BACKEND", "$.code=2"}, lo.Map(fingerprints, func(fingerprint *model.ErrorFingerprint, _ int) string {
+		return fingerprint.Value
+	}))
+	assert.Equal(t, []model.FingerprintType{model.Fingerprint.StackFrameMetadata, model.Fingerprint.CustomRule, model.Fingerprint.JsonResult}, lo.Map(fingerprints, func(fingerprint *model.ErrorFingerprint, _ int) model.FingerprintType {
+		return fingerprint.Type
+	}))
+
+	// identical fingerprints no longer match the original group
+	var originalFingerprints []*model.ErrorFingerprint
+	store.db.Where(&model.ErrorFingerprint{ErrorGroupId: errorGroup.ID}).Find(&originalFingerprints)
+	assert.Len(t, originalFingerprints, 1)
+	assert.Equal(t, "$.code=0", originalFingerprints[0].Value)
+
+	activityLogs, err := store.GetErrorGroupActivityLogs(newErrorGroup.ID)
+	assert.NoError(t, err)
+	assert.Len(t, activityLogs, 1)
+	assert.Equal(t, model.ErrorGroupSplitEvent, activityLogs[0].EventType)
+}
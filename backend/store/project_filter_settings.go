@@ -75,6 +75,15 @@ func (store *Store) UpdateProjectFilterSettings(ctx context.Context, projectID i
 			if updates.Sampling.TraceMinuteRateLimit != nil {
 				projectFilterSettings.TraceMinuteRateLimit = updates.Sampling.TraceMinuteRateLimit
 			}
+			if updates.Sampling.TraceKeepOnError != nil {
+				projectFilterSettings.TraceKeepOnError = *updates.Sampling.TraceKeepOnError
+			}
+			if updates.Sampling.TraceLatencyThresholdMs != nil {
+				projectFilterSettings.TraceLatencyThresholdMs = updates.Sampling.TraceLatencyThresholdMs
+			}
+			if updates.Sampling.TraceServiceSamplingRates != nil {
+				projectFilterSettings.TraceServiceSamplingRates = updates.Sampling.TraceServiceSamplingRates
+			}
 		}
 		if updates.Sampling.SessionExclusionQuery != nil {
 			projectFilterSettings.SessionExclusionQuery = updates.Sampling.SessionExclusionQuery
@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"os"
+	"time"
+
+	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
+	"github.com/openlyinc/pointy"
+	e "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// ReplayDeadLetters submits dead-lettered messages back onto their source topic.
+// The topic is selected by DEAD_LETTER_TOPIC_TYPE (defaulting to the main topic) and messages
+// can be filtered by DEAD_LETTER_PAYLOAD_TYPES (comma separated) and DEAD_LETTER_PROJECT_ID.
+// Messages that do not match the filters are kept in the dead-letter queue,
+// while messages that cannot be deserialized are logged and skipped.
+func (w *Worker) ReplayDeadLetters(ctx context.Context) {
+	topicType := kafkaqueue.TopicType(os.Getenv("DEAD_LETTER_TOPIC_TYPE"))
+	if topicType == "" {
+		topicType = kafkaqueue.TopicTypeDefault
+	}
+	filter, err := kafkaqueue.ParseDeadLetterFilter(os.Getenv("DEAD_LETTER_PAYLOAD_TYPES"), os.Getenv("DEAD_LETTER_PROJECT_ID"))
+	if err != nil {
+		log.WithContext(ctx).WithError(err).Fatal("invalid dead-letter replay filter")
+	}
+
+	// the replay consumes in its own group to not take partitions or commit offsets of the workers
+	config := &kafkaqueue.ConfigOverride{GroupID: pointy.String(kafkaqueue.DeadLetterConsumerGroupName)}
+	source := kafkaqueue.New(ctx, kafkaqueue.GetTopic(kafkaqueue.GetTopicOptions{Type: topicType}), kafkaqueue.Producer, config)
+	defer source.Stop(ctx)
+	deadLetters := kafkaqueue.New(ctx, kafkaqueue.GetDeadLetterTopic(kafkaqueue.GetTopicOptions{Type: topicType}), kafkaqueue.Producer|kafkaqueue.Consumer, config)
+	defer deadLetters.Stop(ctx)
+
+	// messages dead-lettered after the replay started (including the ones kept by the filters)
+	// are left uncommitted for the next replay.
+	start := time.Now()
+	var replayed, kept, skipped int
+	for {
+		msg, err := deadLetters.ReceiveMessage(ctx)
+		var decodeErr *kafkaqueue.DecodeError
+		if e.As(err, &decodeErr) {
+			log.WithContext(ctx).WithError(err).Error("skipping invalid dead-lettered message")
+			deadLetters.Commit(ctx, decodeErr.KafkaMessage)
+			skipped += 1
+			continue
+		} else if err != nil {
+			log.WithContext(ctx).WithError(err).Error("failed to receive dead-lettered message")
+			return
+		} else if msg == nil {
+			break
+		}
+		if msg.KafkaMessage.Time.After(start) {
+			continue
+		}
+
+		partitionKey := string(msg.KafkaMessage.Key)
+		if filter.Matches(msg) {
+			if err := source.Submit(ctx, partitionKey, kafkaqueue.NewReplay(msg)); err != nil {
+				log.WithContext(ctx).WithError(err).Error("failed to replay dead-lettered message")
+				return
+			}
+			replayed += 1
+		} else {
+			keep := *msg
+			keep.KafkaMessage = nil
+			if err := deadLetters.Submit(ctx, partitionKey, &keep); err != nil {
+				log.WithContext(ctx).WithError(err).Error("failed to keep dead-lettered message")
+				return
+			}
+			kept += 1
+		}
+		deadLetters.Commit(ctx, msg.KafkaMessage)
+	}
+
+	log.WithContext(ctx).
+		WithField("topic", source.Topic).
+		WithField("replayed", replayed).
+		WithField("kept", kept).
+		WithField("skipped", skipped).
+		Info("finished replaying dead-lettered messages")
+}
@@ -43,6 +43,27 @@ func (k *KafkaWorker) processWorkerError(ctx context.Context, task *kafkaqueue.M
 	task.Failures += 1
 }
 
+// submitDeadLetter writes a task that failed after all retries to the dead-letter queue so that it can be replayed.
+// The task must not be committed when it returns an error, as it would be lost.
+func (k *KafkaWorker) submitDeadLetter(ctx context.Context, task *kafkaqueue.Message, err error) error {
+	if k.DeadLetterQueue == nil {
+		return nil
+	}
+	var partitionKey string
+	if task.KafkaMessage != nil {
+		partitionKey = string(task.KafkaMessage.Key)
+	}
+	if err := k.DeadLetterQueue.Submit(ctx, partitionKey, kafkaqueue.NewDeadLetter(task, err)); err != nil {
+		log.WithContext(ctx).
+			WithError(err).
+			WithField("type", task.Type).
+			Error("failed to submit task to the dead-letter queue")
+		return err
+	}
+	hlog.Incr("worker.kafka.deadLetter.total", nil, 1)
+	return nil
+}
+
 func (k *KafkaWorker) ProcessMessages(ctx context.Context) {
 	for {
 		func() {
@@ -77,6 +98,13 @@ func (k *KafkaWorker) ProcessMessages(ctx context.Context) {
 			s.SetAttribute("taskFailures", task.Failures)
 			s2.Finish(err)
 
+			if err != nil {
+				if err := k.submitDeadLetter(sCtx, task, err); err != nil {
+					// the task is not committed when it could not be dead-lettered
+					return
+				}
+			}
+
 			s3, _ := util.StartSpanFromContext(sCtx, "worker.kafka.commitMessage")
 			k.KafkaQueue.Commit(ctx, task.KafkaMessage)
 			s3.Finish()
@@ -95,9 +123,10 @@ const ErrorObjectsMaxRowsPostgres = 500
 const MinRetryDelay = 250 * time.Millisecond
 
 type KafkaWorker struct {
-	KafkaQueue   *kafkaqueue.Queue
-	Worker       *Worker
-	WorkerThread int
+	KafkaQueue      *kafkaqueue.Queue
+	DeadLetterQueue *kafkaqueue.Queue
+	Worker          *Worker
+	WorkerThread    int
 }
 
 func (k *KafkaBatchWorker) flush(ctx context.Context) error {
@@ -110,6 +139,7 @@ func (k *KafkaBatchWorker) flush(ctx context.Context) error {
 	var syncErrorObjectIds []int
 	var logRows []*clickhouse.LogRow
 	var traceRows []*clickhouse.TraceRow
+	var metricRows []*clickhouse.MetricRow
 
 	var lastMsg *kafkaqueue.Message
 	var oldestMsg = time.Now()
@@ -135,9 +165,13 @@ func (k *KafkaBatchWorker) flush(ctx context.Context) error {
 			if traceRow != nil {
 				traceRows = append(traceRows, traceRow)
 			}
+		case kafkaqueue.PushMetricRows:
+			metricRow := lastMsg.PushMetricRows.MetricRow
+			if metricRow != nil {
+				metricRows = append(metricRows, metricRow)
+			}
 		}
 	}
-	k.messages = []*kafkaqueue.Message{}
 
 	readSpan.SetAttribute("MaxIngestDelay", time.Since(oldestMsg).Seconds())
 	readSpan.Finish()
@@ -161,6 +195,12 @@ func (k *KafkaBatchWorker) flush(ctx context.Context) error {
 			return err
 		}
 	}
+	if len(metricRows) > 0 {
+		if err := k.flushMetrics(wCtx, metricRows); err != nil {
+			workSpan.Finish(err)
+			return err
+		}
+	}
 	workSpan.Finish()
 
 	commitSpan, cCtx := util.StartSpanFromContext(ctx, util.KafkaBatchWorkerOp, util.ResourceName(fmt.Sprintf("worker.kafka.%s.flush.commit", k.Name)))
@@ -383,6 +423,44 @@ func (k *KafkaBatchWorker) flushTraces(ctx context.Context, traceRows []*clickho
 	return nil
 }
 
+// flushMetrics writes metric data points to the metrics table, and as trace rows to the traces table
+// read by metric timelines and monitors. They are billed as traces.
+func (k *KafkaBatchWorker) flushMetrics(ctx context.Context, metricRows []*clickhouse.MetricRow) error {
+	projectIds := map[uint32]struct{}{}
+	for _, metric := range metricRows {
+		projectIds[metric.ProjectId] = struct{}{}
+	}
+
+	quotaExceededByProject, err := k.getQuotaExceededByProject(ctx, projectIds, model.PricingProductTypeTraces)
+	if err != nil {
+		return err
+	}
+
+	filteredMetricRows := []*clickhouse.MetricRow{}
+	metricTraceRows := []*clickhouse.TraceRow{}
+	for _, metric := range metricRows {
+		if quotaExceededByProject[metric.ProjectId] {
+			continue
+		}
+		filteredMetricRows = append(filteredMetricRows, metric)
+		metricTraceRows = append(metricTraceRows, metric.TraceRow())
+	}
+
+	span, ctxT := util.StartSpanFromContext(ctx, util.KafkaBatchWorkerOp, util.ResourceName(fmt.Sprintf("worker.kafka.%s.flush.clickhouse.metrics", k.Name)), util.WithHighlightTracingDisabled(true))
+	span.SetAttribute("NumMetricRows", len(metricRows))
+	span.SetAttribute("NumFilteredRows", len(filteredMetricRows))
+	err = k.Worker.PublicResolver.Clickhouse.BatchWriteMetricRows(ctxT, filteredMetricRows)
+	if err == nil {
+		err = k.Worker.PublicResolver.Clickhouse.BatchWriteTraceRows(ctxT, metricTraceRows)
+	}
+	span.Finish(err)
+	if err != nil {
+		log.WithContext(ctxT).WithError(err).Error("failed to batch write metrics to clickhouse")
+		return err
+	}
+	return nil
+}
+
 func (k *KafkaBatchWorker) flushDataSync(ctx context.Context, sessionIds []int, errorGroupIds []int, errorObjectIds []int) error {
 	sessionIdChunks := lo.Chunk(lo.Uniq(sessionIds), SessionsMaxRowsPostgres)
 	if len(sessionIdChunks) > 0 {
@@ -533,6 +611,36 @@ func (k *KafkaBatchWorker) processWorkerError(ctx context.Context, attempt int,
 	time.Sleep(MinRetryDelay * time.Duration(math.Pow(2, float64(attempt))))
 }
 
+// submitDeadLetters writes the batch that failed to flush after all retries to the dead-letter queue
+// so that it can be replayed, and commits it. The batch is not committed when it returns an error.
+func (k *KafkaBatchWorker) submitDeadLetters(ctx context.Context, err error) error {
+	if len(k.messages) == 0 {
+		return nil
+	}
+	if k.DeadLetterQueue != nil {
+		// keep the partition keys of the messages so that replayed messages are ordered as before
+		byKey := lo.GroupBy(k.messages, func(msg *kafkaqueue.Message) string {
+			return string(msg.KafkaMessage.Key)
+		})
+		for partitionKey, messages := range byKey {
+			deadLetters := lo.Map(messages, func(msg *kafkaqueue.Message, _ int) *kafkaqueue.Message {
+				msg.Failures = kafkaqueue.TaskRetries + 1
+				return kafkaqueue.NewDeadLetter(msg, err)
+			})
+			if err := k.DeadLetterQueue.Submit(ctx, partitionKey, deadLetters...); err != nil {
+				log.WithContext(ctx).
+					WithError(err).
+					WithField("worker_name", k.Name).
+					Error("failed to submit batch to the dead-letter queue")
+				return err
+			}
+		}
+		hlog.Histogram("worker.kafka.deadLetter.batchSize", float64(len(k.messages)), []string{"worker_name:" + k.Name}, 1)
+	}
+	k.KafkaQueue.Commit(ctx, k.messages[len(k.messages)-1].KafkaMessage)
+	return nil
+}
+
 func (k *KafkaBatchWorker) ProcessMessages(ctx context.Context) {
 	for {
 		func() {
@@ -556,14 +664,22 @@ func (k *KafkaBatchWorker) ProcessMessages(ctx context.Context) {
 			if time.Since(k.lastFlush) > k.BatchedFlushTimeout || len(k.messages) >= k.BatchFlushSize {
 				s.SetAttribute("FlushDelay", time.Since(k.lastFlush).Seconds())
 
+				var err error
 				for i := 0; i <= kafkaqueue.TaskRetries; i++ {
-					if err := k.flush(ctx); err != nil {
+					if err = k.flush(ctx); err != nil {
 						k.processWorkerError(ctx, i, err)
 					} else {
 						break
 					}
 				}
 				k.lastFlush = time.Now()
+				if err != nil {
+					if err := k.submitDeadLetters(ctx, err); err != nil {
+						// keep the batch to flush it again with the next messages
+						return
+					}
+				}
+				k.messages = []*kafkaqueue.Message{}
 			}
 		}()
 	}
@@ -571,6 +687,7 @@ func (k *KafkaBatchWorker) ProcessMessages(ctx context.Context) {
 
 type KafkaBatchWorker struct {
 	KafkaQueue          *kafkaqueue.Queue
+	DeadLetterQueue     *kafkaqueue.Queue
 	Worker              *Worker
 	WorkerThread        int
 	BatchFlushSize      int
@@ -22,6 +22,7 @@ import (
 	"github.com/highlight-run/highlight/backend/hlog"
 	log_alerts "github.com/highlight-run/highlight/backend/jobs/log-alerts"
 	metric_monitor "github.com/highlight-run/highlight/backend/jobs/metric-monitor"
+	trace_alerts "github.com/highlight-run/highlight/backend/jobs/trace-alerts"
 	kafkaqueue "github.com/highlight-run/highlight/backend/kafka-queue"
 	journey_handlers "github.com/highlight-run/highlight/backend/lambda-functions/journeys/handlers"
 	"github.com/highlight-run/highlight/backend/model"
@@ -431,6 +432,8 @@ func (w *Worker) PublicWorker(ctx context.Context, topic kafkaqueue.TopicType) {
 		return cfg.Topic == topic
 	})
 
+	deadLetterQueue := kafkaqueue.New(ctx, kafkaqueue.GetDeadLetterTopic(kafkaqueue.GetTopicOptions{Type: topic}), kafkaqueue.Producer, nil)
+
 	wg := sync.WaitGroup{}
 	for _, cfg := range kafkaWorkerConfigs {
 		if cfg.FlushSize == 0 {
@@ -447,9 +450,10 @@ func (w *Worker) PublicWorker(ctx context.Context, topic kafkaqueue.TopicType) {
 			if cfg.Topic == kafkaqueue.TopicTypeDefault {
 				go func(workerId int) {
 					k := KafkaWorker{
-						KafkaQueue:   kafkaqueue.New(ctx, kafkaqueue.GetTopic(kafkaqueue.GetTopicOptions{Type: kafkaqueue.TopicTypeDefault}), kafkaqueue.Consumer, nil),
-						Worker:       w,
-						WorkerThread: workerId,
+						KafkaQueue:      kafkaqueue.New(ctx, kafkaqueue.GetTopic(kafkaqueue.GetTopicOptions{Type: kafkaqueue.TopicTypeDefault}), kafkaqueue.Consumer, nil),
+						DeadLetterQueue: deadLetterQueue,
+						Worker:          w,
+						WorkerThread:    workerId,
 					}
 					k.ProcessMessages(ctx)
 					wg.Done()
@@ -463,6 +467,7 @@ func (w *Worker) PublicWorker(ctx context.Context, topic kafkaqueue.TopicType) {
 							kafkaqueue.GetTopic(kafkaqueue.GetTopicOptions{Type: config.Topic}),
 							kafkaqueue.Consumer, &kafkaqueue.ConfigOverride{QueueCapacity: pointy.Int(config.QueueSize)},
 						),
+						DeadLetterQueue:     deadLetterQueue,
 						Worker:              w,
 						BatchFlushSize:      config.FlushSize,
 						BatchedFlushTimeout: config.FlushTimeout,
@@ -940,6 +945,11 @@ func (w *Worker) processSession(ctx context.Context, s *model.Session) error {
 				return nil
 			}
 
+			// silences mute every destination of the alert, so they are checked once for all of them
+			if model.IsAlertSilenced(ctx, w.Resolver.DB, model.GetSessionAlertSilenceMatch(sessionAlert, s), model.AllAlertDestinations) {
+				return nil
+			}
+
 			count64 := int64(count)
 			slackAlertPayload := model.SendSlackAlertInput{
 				Workspace:       workspace,
@@ -947,6 +957,7 @@ func (w *Worker) processSession(ctx context.Context, s *model.Session) error {
 				SessionExcluded: s.Excluded && *s.Processed,
 				UserIdentifier:  s.Identifier,
 				UserObject:      s.UserObject,
+				Environment:     s.Environment,
 				RageClicksCount: &count64,
 				QueryParams:     map[string]string{"tsAbs": fmt.Sprintf("%d", accumulator.RageClickSets[0].StartTimestamp.UnixNano()/int64(time.Millisecond))},
 			}
@@ -960,7 +971,7 @@ func (w *Worker) processSession(ctx context.Context, s *model.Session) error {
 			}
 			sessionAlert.SendAlerts(ctx, w.Resolver.DB, w.Resolver.MailClient, &slackAlertPayload)
 
-			if err = alerts.SendRageClicksAlert(alerts.RageClicksAlertEvent{
+			if err = alerts.SendRageClicksAlert(ctx, w.Resolver.DB, alerts.RageClicksAlertEvent{
 				Session:         s,
 				SessionAlert:    sessionAlert,
 				Workspace:       workspace,
@@ -1152,11 +1163,15 @@ func (w *Worker) MigrateDB(ctx context.Context) {
 }
 
 func (w *Worker) StartMetricMonitorWatcher(ctx context.Context) {
-	metric_monitor.WatchMetricMonitors(ctx, w.Resolver.DB, w.Resolver.ClickhouseClient, w.Resolver.MailClient, w.Resolver.RH)
+	metric_monitor.WatchMetricMonitors(ctx, w.Resolver.DB, w.Resolver.Store, w.Resolver.ClickhouseClient, w.Resolver.MailClient, w.Resolver.RH)
 }
 
 func (w *Worker) StartLogAlertWatcher(ctx context.Context) {
-	log_alerts.WatchLogAlerts(ctx, w.Resolver.DB, w.Resolver.MailClient, w.Resolver.RH, w.Resolver.Redis, w.Resolver.ClickhouseClient)
+	log_alerts.WatchLogAlerts(ctx, w.Resolver.DB, w.Resolver.Store, w.Resolver.MailClient, w.Resolver.RH, w.Resolver.Redis, w.Resolver.ClickhouseClient)
+}
+
+func (w *Worker) StartTraceAlertWatcher(ctx context.Context) {
+	trace_alerts.WatchTraceAlerts(ctx, w.Resolver.DB, w.Resolver.Store, w.Resolver.MailClient, w.Resolver.RH, w.Resolver.Redis, w.Resolver.ClickhouseClient)
 }
 
 func (w *Worker) RefreshMaterializedViews(ctx context.Context) {
@@ -1358,6 +1373,8 @@ func (w *Worker) GetHandler(ctx context.Context, handlerFlag string) func(ctx co
 		return w.StartMetricMonitorWatcher
 	case "log-alerts":
 		return w.StartLogAlertWatcher
+	case "trace-alerts":
+		return w.StartTraceAlertWatcher
 	case "backfill-stack-frames":
 		return w.BackfillStackFrames
 	case "refresh-materialized-views":
@@ -1374,6 +1391,8 @@ func (w *Worker) GetHandler(ctx context.Context, handlerFlag string) func(ctx co
 		return w.GetPublicWorker(kafkaqueue.TopicTypeTraces)
 	case "auto-resolve-stale-errors":
 		return w.AutoResolveStaleErrors
+	case "replay-dead-letters":
+		return w.ReplayDeadLetters
 	default:
 		log.WithContext(ctx).Fatalf("unrecognized worker-handler [%s]", handlerFlag)
 		return nil
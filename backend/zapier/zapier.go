@@ -87,6 +87,8 @@ type HookPayload struct {
 	MetricValue *float64 `json:"metric_value"`
 	// MetricValue is a required parameter for MetricMonitor alerts
 	MetricThreshold *float64 `json:"metric_threshold"`
+	// Resolved is set for MetricMonitor and Log alerts when the alert condition cleared
+	Resolved bool `json:"resolved"`
 	// Timestamp is an optional value for all session alerts.
 	Timestamp *time.Time `json:"timestamp"`
 }
@@ -1686,15 +1686,18 @@ export const EditWorkspaceSettingsDocument = gql`
 		$workspace_id: ID!
 		$ai_application: Boolean
 		$ai_insights: Boolean
+		$error_group_root_cause: Boolean
 	) {
 		editWorkspaceSettings(
 			workspace_id: $workspace_id
 			ai_application: $ai_application
 			ai_insights: $ai_insights
+			error_group_root_cause: $error_group_root_cause
 		) {
 			workspace_id
 			ai_application
 			ai_insights
+			error_group_root_cause
 		}
 	}
 `
@@ -1719,6 +1722,7 @@ export type EditWorkspaceSettingsMutationFn = Apollo.MutationFunction<
  *      workspace_id: // value for 'workspace_id'
  *      ai_application: // value for 'ai_application'
  *      ai_insights: // value for 'ai_insights'
+ *      error_group_root_cause: // value for 'error_group_root_cause'
  *   },
  * });
  */
@@ -13487,6 +13491,7 @@ export const GetWorkspaceSettingsDocument = gql`
 			enable_session_export
 			enable_unlisted_sharing
 			enable_ingest_sampling
+			error_group_root_cause
 		}
 	}
 `
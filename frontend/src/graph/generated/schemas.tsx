@@ -121,6 +121,58 @@ export enum AdminRole {
 	Member = 'MEMBER',
 }
 
+export type AlertBaselineBucket = {
+	__typename?: 'AlertBaselineBucket'
+	expected: Scalars['Float']
+	lower_bound: Scalars['Float']
+	timestamp: Scalars['Timestamp']
+	upper_bound: Scalars['Float']
+	value: Scalars['Float']
+}
+
+export type AlertEvaluation = {
+	__typename?: 'AlertEvaluation'
+	alert_id: Scalars['ID']
+	created_at: Scalars['Timestamp']
+	group_key: Scalars['String']
+	id: Scalars['ID']
+	notified: Scalars['Boolean']
+	state: AlertState
+	threshold: Scalars['Float']
+	value: Scalars['Float']
+}
+
+export type AlertSilence = {
+	__typename?: 'AlertSilence'
+	alert_names?: Maybe<Scalars['StringArray']>
+	created_at: Scalars['Timestamp']
+	created_by_admin_id: Scalars['ID']
+	end_date: Scalars['Timestamp']
+	environments?: Maybe<Scalars['StringArray']>
+	id: Scalars['ID']
+	project_id: Scalars['ID']
+	reason: Scalars['String']
+	service_names?: Maybe<Scalars['StringArray']>
+	start_date: Scalars['Timestamp']
+}
+
+export type AlertSilenceInput = {
+	alert_names: Array<Scalars['String']>
+	end_date: Scalars['Timestamp']
+	environments: Array<Scalars['String']>
+	project_id: Scalars['ID']
+	reason: Scalars['String']
+	service_names: Array<Scalars['String']>
+	start_date: Scalars['Timestamp']
+}
+
+export enum AlertState {
+	Firing = 'FIRING',
+	Ok = 'OK',
+	Pending = 'PENDING',
+	Resolved = 'RESOLVED',
+}
+
 export type AllProjectSettings = {
 	__typename?: 'AllProjectSettings'
 	autoResolveStaleErrorsDayInterval: Scalars['Int']
@@ -148,6 +200,7 @@ export type AllWorkspaceSettings = {
 	enable_ingest_sampling: Scalars['Boolean']
 	enable_session_export: Scalars['Boolean']
 	enable_unlisted_sharing: Scalars['Boolean']
+	error_group_root_cause: Scalars['Boolean']
 	workspace_id: Scalars['ID']
 }
 
@@ -418,14 +471,19 @@ export type ErrorAlert = {
 	ExcludedEnvironments: Array<Maybe<Scalars['String']>>
 	Frequency: Scalars['Int']
 	LastAdminToEditID?: Maybe<Scalars['ID']>
+	MicrosoftTeamsChannels: Array<MicrosoftTeamsChannel>
 	Name?: Maybe<Scalars['String']>
+	OpsgenieTeams: Array<OpsgenieTeam>
+	PagerDutyServices: Array<PagerDutyService>
 	RegexGroups: Array<Maybe<Scalars['String']>>
 	ThresholdWindow?: Maybe<Scalars['Int']>
 	Type: Scalars['String']
 	WebhookDestinations: Array<WebhookDestination>
+	anomaly_deviations: Scalars['Float']
 	default: Scalars['Boolean']
 	disabled: Scalars['Boolean']
 	id: Scalars['ID']
+	threshold_type: ThresholdType
 	updated_at: Scalars['Timestamp']
 }
 
@@ -474,6 +532,9 @@ export type ErrorGroup = {
 	mapped_stack_trace?: Maybe<Scalars['String']>
 	metadata_log: Array<Maybe<ErrorMetadata>>
 	project_id: Scalars['Int']
+	regressed_at?: Maybe<Scalars['Timestamp']>
+	resolve_in_next_release: Scalars['Boolean']
+	resolved_in_version?: Maybe<Scalars['String']>
 	secure_id: Scalars['String']
 	serviceName?: Maybe<Scalars['String']>
 	snoozed_until?: Maybe<Scalars['Timestamp']>
@@ -503,6 +564,35 @@ export type ErrorGroupTagAggregationBucket = {
 	percent: Scalars['Float']
 }
 
+export type ErrorGroupingPreview = {
+	__typename?: 'ErrorGroupingPreview'
+	error_group_id: Scalars['ID']
+	error_object_id: Scalars['ID']
+	event: Scalars['String']
+	fingerprint?: Maybe<Scalars['String']>
+	rule_index?: Maybe<Scalars['Int']>
+	target_error_group_id?: Maybe<Scalars['ID']>
+}
+
+export type ErrorGroupingRule = {
+	__typename?: 'ErrorGroupingRule'
+	group_by?: Maybe<Scalars['StringArray']>
+	id: Scalars['ID']
+	ignore_frames?: Maybe<Scalars['String']>
+	match_event?: Maybe<Scalars['String']>
+	match_frame?: Maybe<Scalars['String']>
+	match_type?: Maybe<Scalars['String']>
+	project_id: Scalars['ID']
+}
+
+export type ErrorGroupingRuleInput = {
+	group_by?: InputMaybe<Scalars['StringArray']>
+	ignore_frames?: InputMaybe<Scalars['String']>
+	match_event?: InputMaybe<Scalars['String']>
+	match_frame?: InputMaybe<Scalars['String']>
+	match_type?: InputMaybe<Scalars['String']>
+}
+
 export type ErrorInstance = {
 	__typename?: 'ErrorInstance'
 	error_object: ErrorObject
@@ -854,18 +944,26 @@ export type LogAlert = {
 	EmailsToNotify: Array<Scalars['String']>
 	ExcludedEnvironments: Array<Scalars['String']>
 	LastAdminToEditID?: Maybe<Scalars['ID']>
+	MicrosoftTeamsChannels: Array<MicrosoftTeamsChannel>
 	Name: Scalars['String']
+	OpsgenieTeams: Array<OpsgenieTeam>
+	PagerDutyServices: Array<PagerDutyService>
 	ThresholdWindow: Scalars['Int']
 	Type: Scalars['String']
 	WebhookDestinations: Array<WebhookDestination>
+	anomaly_deviations: Scalars['Float']
 	default: Scalars['Boolean']
 	disabled: Scalars['Boolean']
 	id: Scalars['ID']
+	pending_evaluations: Scalars['Int']
 	query: Scalars['String']
+	renotify_interval: Scalars['Int']
+	threshold_type: ThresholdType
 	updated_at: Scalars['Timestamp']
 }
 
 export type LogAlertInput = {
+	anomaly_deviations?: InputMaybe<Scalars['Float']>
 	below_threshold: Scalars['Boolean']
 	count_threshold: Scalars['Int']
 	default?: InputMaybe<Scalars['Boolean']>
@@ -873,10 +971,16 @@ export type LogAlertInput = {
 	discord_channels: Array<DiscordChannelInput>
 	emails: Array<Scalars['String']>
 	environments: Array<Scalars['String']>
+	microsoft_teams_channels?: InputMaybe<Array<MicrosoftTeamsChannelInput>>
 	name: Scalars['String']
+	opsgenie_teams?: InputMaybe<Array<OpsgenieTeamInput>>
+	pager_duty_services?: InputMaybe<Array<PagerDutyServiceInput>>
+	pending_evaluations?: InputMaybe<Scalars['Int']>
 	project_id: Scalars['ID']
 	query: Scalars['String']
+	renotify_interval?: InputMaybe<Scalars['Int']>
 	slack_channels: Array<SanitizedSlackChannelInput>
+	threshold_type?: InputMaybe<ThresholdType>
 	threshold_window: Scalars['Int']
 	webhook_destinations: Array<WebhookDestinationInput>
 }
@@ -974,8 +1078,13 @@ export type MetricMonitor = {
 	id: Scalars['ID']
 	last_admin_to_edit_id: Scalars['ID']
 	metric_to_monitor: Scalars['String']
+	microsoft_teams_channels: Array<MicrosoftTeamsChannel>
 	name: Scalars['String']
+	opsgenie_teams: Array<OpsgenieTeam>
+	pager_duty_services: Array<PagerDutyService>
+	pending_evaluations: Scalars['Int']
 	period_minutes?: Maybe<Scalars['Int']>
+	renotify_interval: Scalars['Int']
 	threshold: Scalars['Float']
 	units?: Maybe<Scalars['String']>
 	updated_at: Scalars['Timestamp']
@@ -1016,6 +1125,17 @@ export enum MetricViewComponentType {
 	TopRoutesTable = 'TopRoutesTable',
 }
 
+export type MicrosoftTeamsChannel = {
+	__typename?: 'MicrosoftTeamsChannel'
+	name: Scalars['String']
+	webhook_url_configured: Scalars['Boolean']
+}
+
+export type MicrosoftTeamsChannelInput = {
+	name: Scalars['String']
+	webhook_url?: InputMaybe<Scalars['String']>
+}
+
 export type Mutation = {
 	__typename?: 'Mutation'
 	addAdminToWorkspace?: Maybe<Scalars['ID']>
@@ -1023,6 +1143,7 @@ export type Mutation = {
 	addIntegrationToWorkspace: Scalars['Boolean']
 	changeAdminRole: Scalars['Boolean']
 	createAdmin: Admin
+	createAlertSilence: AlertSilence
 	createErrorAlert?: Maybe<ErrorAlert>
 	createErrorComment?: Maybe<ErrorComment>
 	createErrorSegment?: Maybe<ErrorSegment>
@@ -1036,6 +1157,7 @@ export type Mutation = {
 	createSegment?: Maybe<Segment>
 	createSessionAlert?: Maybe<SessionAlert>
 	createSessionComment?: Maybe<SessionComment>
+	createTraceAlert?: Maybe<TraceAlert>
 	createWorkspace?: Maybe<Workspace>
 	deleteAdminFromProject?: Maybe<Scalars['ID']>
 	deleteAdminFromWorkspace?: Maybe<Scalars['ID']>
@@ -1051,6 +1173,8 @@ export type Mutation = {
 	deleteSessionAlert?: Maybe<SessionAlert>
 	deleteSessionComment?: Maybe<Scalars['Boolean']>
 	deleteSessions: Scalars['Boolean']
+	deleteTraceAlert?: Maybe<TraceAlert>
+	editErrorGroupingRules: Array<ErrorGroupingRule>
 	editErrorSegment?: Maybe<Scalars['Boolean']>
 	editProject?: Maybe<Project>
 	editProjectSettings?: Maybe<AllProjectSettings>
@@ -1059,13 +1183,16 @@ export type Mutation = {
 	editWorkspace?: Maybe<Workspace>
 	editWorkspaceSettings?: Maybe<AllWorkspaceSettings>
 	emailSignup: Scalars['String']
+	endAlertSilence: AlertSilence
 	exportSession: Scalars['Boolean']
 	joinWorkspace?: Maybe<Scalars['ID']>
 	markErrorGroupAsViewed?: Maybe<ErrorGroup>
 	markSessionAsViewed?: Maybe<Session>
+	mergeErrorGroups?: Maybe<ErrorGroup>
 	modifyClearbitIntegration?: Maybe<Scalars['Boolean']>
 	muteErrorCommentThread?: Maybe<Scalars['Boolean']>
 	muteSessionCommentThread?: Maybe<Scalars['Boolean']>
+	previewErrorGroupingRules: Array<ErrorGroupingPreview>
 	removeErrorIssue?: Maybe<Scalars['Boolean']>
 	removeIntegrationFromProject: Scalars['Boolean']
 	removeIntegrationFromWorkspace: Scalars['Boolean']
@@ -1074,6 +1201,8 @@ export type Mutation = {
 	requestAccess?: Maybe<Scalars['Boolean']>
 	saveBillingPlan?: Maybe<Scalars['Boolean']>
 	sendAdminWorkspaceInvite?: Maybe<Scalars['String']>
+	sendTestWebhook: WebhookDelivery
+	splitErrorGroup?: Maybe<ErrorGroup>
 	submitRegistrationForm?: Maybe<Scalars['Boolean']>
 	syncSlackIntegration: SlackSyncResponse
 	testErrorEnhancement?: Maybe<ErrorObject>
@@ -1097,6 +1226,7 @@ export type Mutation = {
 	updateSessionAlert?: Maybe<SessionAlert>
 	updateSessionAlertIsDisabled?: Maybe<SessionAlert>
 	updateSessionIsPublic?: Maybe<Session>
+	updateTraceAlert?: Maybe<TraceAlert>
 	updateVercelProjectMappings: Scalars['Boolean']
 	upsertDashboard: Scalars['ID']
 	upsertDiscordChannel: DiscordChannel
@@ -1126,17 +1256,27 @@ export type MutationChangeAdminRoleArgs = {
 	workspace_id: Scalars['ID']
 }
 
+export type MutationCreateAlertSilenceArgs = {
+	input: AlertSilenceInput
+}
+
 export type MutationCreateErrorAlertArgs = {
+	anomaly_deviations?: InputMaybe<Scalars['Float']>
 	count_threshold: Scalars['Int']
 	default?: InputMaybe<Scalars['Boolean']>
 	discord_channels: Array<DiscordChannelInput>
 	emails: Array<InputMaybe<Scalars['String']>>
 	environments: Array<InputMaybe<Scalars['String']>>
 	frequency: Scalars['Int']
+	microsoft_teams_channels?: InputMaybe<Array<MicrosoftTeamsChannelInput>>
 	name: Scalars['String']
+	opsgenie_teams?: InputMaybe<Array<OpsgenieTeamInput>>
+	pager_duty_services?: InputMaybe<Array<PagerDutyServiceInput>>
 	project_id: Scalars['ID']
 	regex_groups: Array<InputMaybe<Scalars['String']>>
+	regression?: InputMaybe<Scalars['Boolean']>
 	slack_channels: Array<InputMaybe<SanitizedSlackChannelInput>>
+	threshold_type?: InputMaybe<ThresholdType>
 	threshold_window: Scalars['Int']
 	webhook_destinations: Array<WebhookDestinationInput>
 }
@@ -1202,9 +1342,14 @@ export type MutationCreateMetricMonitorArgs = {
 	emails: Array<InputMaybe<Scalars['String']>>
 	filters?: InputMaybe<Array<MetricTagFilterInput>>
 	metric_to_monitor: Scalars['String']
+	microsoft_teams_channels?: InputMaybe<Array<MicrosoftTeamsChannelInput>>
 	name: Scalars['String']
+	opsgenie_teams?: InputMaybe<Array<OpsgenieTeamInput>>
+	pager_duty_services?: InputMaybe<Array<PagerDutyServiceInput>>
+	pending_evaluations?: InputMaybe<Scalars['Int']>
 	periodMinutes?: InputMaybe<Scalars['Int']>
 	project_id: Scalars['ID']
+	renotify_interval?: InputMaybe<Scalars['Int']>
 	slack_channels: Array<InputMaybe<SanitizedSlackChannelInput>>
 	threshold: Scalars['Float']
 	units?: InputMaybe<Scalars['String']>
@@ -1255,6 +1400,10 @@ export type MutationCreateSessionCommentArgs = {
 	y_coordinate: Scalars['Float']
 }
 
+export type MutationCreateTraceAlertArgs = {
+	input: TraceAlertInput
+}
+
 export type MutationCreateWorkspaceArgs = {
 	name: Scalars['String']
 	promo_code?: InputMaybe<Scalars['String']>
@@ -1325,6 +1474,16 @@ export type MutationDeleteSessionsArgs = {
 	sessionCount: Scalars['Int']
 }
 
+export type MutationDeleteTraceAlertArgs = {
+	id: Scalars['ID']
+	project_id: Scalars['ID']
+}
+
+export type MutationEditErrorGroupingRulesArgs = {
+	project_id: Scalars['ID']
+	rules: Array<ErrorGroupingRuleInput>
+}
+
 export type MutationEditErrorSegmentArgs = {
 	id: Scalars['ID']
 	name: Scalars['String']
@@ -1384,6 +1543,7 @@ export type MutationEditWorkspaceArgs = {
 export type MutationEditWorkspaceSettingsArgs = {
 	ai_application?: InputMaybe<Scalars['Boolean']>
 	ai_insights?: InputMaybe<Scalars['Boolean']>
+	error_group_root_cause?: InputMaybe<Scalars['Boolean']>
 	workspace_id: Scalars['ID']
 }
 
@@ -1391,6 +1551,11 @@ export type MutationEmailSignupArgs = {
 	email: Scalars['String']
 }
 
+export type MutationEndAlertSilenceArgs = {
+	id: Scalars['ID']
+	project_id: Scalars['ID']
+}
+
 export type MutationExportSessionArgs = {
 	session_secure_id: Scalars['String']
 }
@@ -1409,6 +1574,11 @@ export type MutationMarkSessionAsViewedArgs = {
 	viewed?: InputMaybe<Scalars['Boolean']>
 }
 
+export type MutationMergeErrorGroupsArgs = {
+	error_group_secure_id: Scalars['String']
+	merged_error_group_secure_ids: Array<Scalars['String']>
+}
+
 export type MutationModifyClearbitIntegrationArgs = {
 	enabled: Scalars['Boolean']
 	workspace_id: Scalars['ID']
@@ -1424,6 +1594,12 @@ export type MutationMuteSessionCommentThreadArgs = {
 	id: Scalars['ID']
 }
 
+export type MutationPreviewErrorGroupingRulesArgs = {
+	count?: InputMaybe<Scalars['Int']>
+	project_id: Scalars['ID']
+	rules: Array<ErrorGroupingRuleInput>
+}
+
 export type MutationRemoveErrorIssueArgs = {
 	error_issue_id: Scalars['ID']
 }
@@ -1477,6 +1653,16 @@ export type MutationSendAdminWorkspaceInviteArgs = {
 	workspace_id: Scalars['ID']
 }
 
+export type MutationSendTestWebhookArgs = {
+	destination: WebhookDestinationInput
+	project_id: Scalars['ID']
+}
+
+export type MutationSplitErrorGroupArgs = {
+	error_group_secure_id: Scalars['String']
+	error_object_ids: Array<Scalars['ID']>
+}
+
 export type MutationSubmitRegistrationFormArgs = {
 	heard_about: Scalars['String']
 	pun?: InputMaybe<Scalars['String']>
@@ -1534,6 +1720,7 @@ export type MutationUpdateEmailOptOutArgs = {
 }
 
 export type MutationUpdateErrorAlertArgs = {
+	anomaly_deviations?: InputMaybe<Scalars['Float']>
 	count_threshold?: InputMaybe<Scalars['Int']>
 	disabled?: InputMaybe<Scalars['Boolean']>
 	discord_channels: Array<DiscordChannelInput>
@@ -1541,10 +1728,14 @@ export type MutationUpdateErrorAlertArgs = {
 	environments?: InputMaybe<Array<InputMaybe<Scalars['String']>>>
 	error_alert_id: Scalars['ID']
 	frequency?: InputMaybe<Scalars['Int']>
+	microsoft_teams_channels?: InputMaybe<Array<MicrosoftTeamsChannelInput>>
 	name?: InputMaybe<Scalars['String']>
+	opsgenie_teams?: InputMaybe<Array<OpsgenieTeamInput>>
+	pager_duty_services?: InputMaybe<Array<PagerDutyServiceInput>>
 	project_id: Scalars['ID']
 	regex_groups?: InputMaybe<Array<InputMaybe<Scalars['String']>>>
 	slack_channels?: InputMaybe<Array<InputMaybe<SanitizedSlackChannelInput>>>
+	threshold_type?: InputMaybe<ThresholdType>
 	threshold_window?: InputMaybe<Scalars['Int']>
 	webhook_destinations: Array<WebhookDestinationInput>
 }
@@ -1561,6 +1752,7 @@ export type MutationUpdateErrorGroupIsPublicArgs = {
 }
 
 export type MutationUpdateErrorGroupStateArgs = {
+	resolve_in_next_release?: InputMaybe<Scalars['Boolean']>
 	secure_id: Scalars['String']
 	snoozed_until?: InputMaybe<Scalars['Timestamp']>
 	state: ErrorState
@@ -1591,9 +1783,14 @@ export type MutationUpdateMetricMonitorArgs = {
 	filters?: InputMaybe<Array<MetricTagFilterInput>>
 	metric_monitor_id: Scalars['ID']
 	metric_to_monitor?: InputMaybe<Scalars['String']>
+	microsoft_teams_channels?: InputMaybe<Array<MicrosoftTeamsChannelInput>>
 	name?: InputMaybe<Scalars['String']>
+	opsgenie_teams?: InputMaybe<Array<OpsgenieTeamInput>>
+	pager_duty_services?: InputMaybe<Array<PagerDutyServiceInput>>
+	pending_evaluations?: InputMaybe<Scalars['Int']>
 	periodMinutes?: InputMaybe<Scalars['Int']>
 	project_id: Scalars['ID']
+	renotify_interval?: InputMaybe<Scalars['Int']>
 	slack_channels?: InputMaybe<Array<InputMaybe<SanitizedSlackChannelInput>>>
 	threshold?: InputMaybe<Scalars['Float']>
 	units?: InputMaybe<Scalars['String']>
@@ -1622,6 +1819,11 @@ export type MutationUpdateSessionIsPublicArgs = {
 	session_secure_id: Scalars['String']
 }
 
+export type MutationUpdateTraceAlertArgs = {
+	id: Scalars['ID']
+	input: TraceAlertInput
+}
+
 export type MutationUpdateVercelProjectMappingsArgs = {
 	project_id: Scalars['ID']
 	project_mappings: Array<VercelProjectMappingInput>
@@ -1691,6 +1893,19 @@ export enum OpenSearchCalendarInterval {
 	Year = 'year',
 }
 
+export type OpsgenieTeam = {
+	__typename?: 'OpsgenieTeam'
+	api_key_configured: Scalars['Boolean']
+	name: Scalars['String']
+	region: Scalars['String']
+}
+
+export type OpsgenieTeamInput = {
+	api_key?: InputMaybe<Scalars['String']>
+	name: Scalars['String']
+	region?: InputMaybe<Scalars['String']>
+}
+
 export type PageInfo = {
 	__typename?: 'PageInfo'
 	endCursor: Scalars['String']
@@ -1699,6 +1914,17 @@ export type PageInfo = {
 	startCursor: Scalars['String']
 }
 
+export type PagerDutyService = {
+	__typename?: 'PagerDutyService'
+	name: Scalars['String']
+	routing_key_configured: Scalars['Boolean']
+}
+
+export type PagerDutyServiceInput = {
+	name: Scalars['String']
+	routing_key?: InputMaybe<Scalars['String']>
+}
+
 export type Plan = {
 	__typename?: 'Plan'
 	errorsLimit: Scalars['Int']
@@ -1751,6 +1977,7 @@ export type Query = {
 	adminHasCreatedComment?: Maybe<Scalars['Boolean']>
 	admin_role?: Maybe<WorkspaceAdminRole>
 	admin_role_by_project?: Maybe<WorkspaceAdminRole>
+	alert_silences: Array<AlertSilence>
 	api_key_to_org_id?: Maybe<Scalars['ID']>
 	app_version_suggestion: Array<Maybe<Scalars['String']>>
 	averageSessionLength?: Maybe<AverageSessionLength>
@@ -1772,6 +1999,7 @@ export type Query = {
 	environment_suggestion?: Maybe<Array<Maybe<Field>>>
 	errorGroupFrequencies: Array<Maybe<ErrorDistributionItem>>
 	errorGroupTags: Array<ErrorGroupTagAggregation>
+	error_alert_baseline: Array<AlertBaselineBucket>
 	error_alerts: Array<Maybe<ErrorAlert>>
 	error_comments: Array<Maybe<ErrorComment>>
 	error_comments_for_admin: Array<Maybe<ErrorComment>>
@@ -1779,6 +2007,7 @@ export type Query = {
 	error_field_suggestion?: Maybe<Array<Maybe<ErrorField>>>
 	error_fields_clickhouse: Array<Scalars['String']>
 	error_group?: Maybe<ErrorGroup>
+	error_grouping_rules: Array<ErrorGroupingRule>
 	error_groups_clickhouse: ErrorResults
 	error_instance?: Maybe<ErrorInstance>
 	error_issue: Array<Maybe<ExternalAttachment>>
@@ -1816,6 +2045,8 @@ export type Query = {
 	linear_teams?: Maybe<Array<LinearTeam>>
 	liveUsersCount?: Maybe<Scalars['Int64']>
 	log_alert: LogAlert
+	log_alert_baseline: Array<AlertBaselineBucket>
+	log_alert_evaluations: Array<AlertEvaluation>
 	log_alerts: Array<Maybe<LogAlert>>
 	logs: LogConnection
 	logsIntegration: IntegrationStatus
@@ -1825,6 +2056,7 @@ export type Query = {
 	logs_keys: Array<QueryKey>
 	logs_total_count: Scalars['UInt64']
 	match_error_tag?: Maybe<Array<Maybe<MatchedErrorTag>>>
+	metric_monitor_evaluations: Array<AlertEvaluation>
 	metric_monitors: Array<Maybe<MetricMonitor>>
 	metric_tag_values: Array<Scalars['String']>
 	metric_tags: Array<Scalars['String']>
@@ -1860,6 +2092,7 @@ export type Query = {
 	session_intervals: Array<SessionInterval>
 	sessions_clickhouse: SessionResults
 	sessions_histogram_clickhouse: SessionsHistogram
+	silenced_alert_notifications: Array<SilencedAlertNotification>
 	slack_channel_suggestion: Array<SanitizedSlackChannel>
 	sourcemap_files: Array<S3File>
 	sourcemap_versions: Array<Scalars['String']>
@@ -1869,6 +2102,9 @@ export type Query = {
 	timeline_indicator_events: Array<TimelineIndicatorEvent>
 	topUsers: Array<Maybe<TopUsersPayload>>
 	trace?: Maybe<TracePayload>
+	trace_alert: TraceAlert
+	trace_alert_evaluations: Array<AlertEvaluation>
+	trace_alerts: Array<Maybe<TraceAlert>>
 	traces: TraceConnection
 	tracesIntegration: IntegrationStatus
 	traces_key_values: Array<Scalars['String']>
@@ -1881,6 +2117,7 @@ export type Query = {
 	vercel_project_mappings: Array<VercelProjectMapping>
 	vercel_projects: Array<VercelProject>
 	web_vitals: Array<Metric>
+	webhook_deliveries: Array<WebhookDelivery>
 	websocket_events?: Maybe<Array<Maybe<Scalars['Any']>>>
 	workspace?: Maybe<Workspace>
 	workspacePendingInvites: Array<Maybe<WorkspaceInviteLink>>
@@ -1910,6 +2147,10 @@ export type QueryAdmin_Role_By_ProjectArgs = {
 	project_id: Scalars['ID']
 }
 
+export type QueryAlert_SilencesArgs = {
+	project_id: Scalars['ID']
+}
+
 export type QueryApi_Key_To_Org_IdArgs = {
 	api_key: Scalars['String']
 }
@@ -2005,6 +2246,12 @@ export type QueryErrorGroupTagsArgs = {
 	use_clickhouse?: InputMaybe<Scalars['Boolean']>
 }
 
+export type QueryError_Alert_BaselineArgs = {
+	date_range: DateRangeRequiredInput
+	error_group_secure_id?: InputMaybe<Scalars['String']>
+	id: Scalars['ID']
+}
+
 export type QueryError_AlertsArgs = {
 	project_id: Scalars['ID']
 }
@@ -2038,6 +2285,10 @@ export type QueryError_GroupArgs = {
 	use_clickhouse?: InputMaybe<Scalars['Boolean']>
 }
 
+export type QueryError_Grouping_RulesArgs = {
+	project_id: Scalars['ID']
+}
+
 export type QueryError_Groups_ClickhouseArgs = {
 	count: Scalars['Int']
 	page?: InputMaybe<Scalars['Int']>
@@ -2205,6 +2456,16 @@ export type QueryLog_AlertArgs = {
 	id: Scalars['ID']
 }
 
+export type QueryLog_Alert_BaselineArgs = {
+	date_range: DateRangeRequiredInput
+	id: Scalars['ID']
+}
+
+export type QueryLog_Alert_EvaluationsArgs = {
+	count?: InputMaybe<Scalars['Int']>
+	id: Scalars['ID']
+}
+
 export type QueryLog_AlertsArgs = {
 	project_id: Scalars['ID']
 }
@@ -2251,6 +2512,11 @@ export type QueryMatch_Error_TagArgs = {
 	query: Scalars['String']
 }
 
+export type QueryMetric_Monitor_EvaluationsArgs = {
+	count?: InputMaybe<Scalars['Int']>
+	id: Scalars['ID']
+}
+
 export type QueryMetric_MonitorsArgs = {
 	metric_name?: InputMaybe<Scalars['String']>
 	project_id: Scalars['ID']
@@ -2407,6 +2673,12 @@ export type QuerySessions_Histogram_ClickhouseArgs = {
 	query: ClickhouseQuery
 }
 
+export type QuerySilenced_Alert_NotificationsArgs = {
+	alert_silence_id?: InputMaybe<Scalars['ID']>
+	count?: InputMaybe<Scalars['Int']>
+	project_id: Scalars['ID']
+}
+
 export type QuerySlack_Channel_SuggestionArgs = {
 	project_id: Scalars['ID']
 }
@@ -2443,6 +2715,19 @@ export type QueryTraceArgs = {
 	trace_id: Scalars['String']
 }
 
+export type QueryTrace_AlertArgs = {
+	id: Scalars['ID']
+}
+
+export type QueryTrace_Alert_EvaluationsArgs = {
+	count?: InputMaybe<Scalars['Int']>
+	id: Scalars['ID']
+}
+
+export type QueryTrace_AlertsArgs = {
+	project_id: Scalars['ID']
+}
+
 export type QueryTracesArgs = {
 	after?: InputMaybe<Scalars['String']>
 	at?: InputMaybe<Scalars['String']>
@@ -2504,6 +2789,11 @@ export type QueryWeb_VitalsArgs = {
 	session_secure_id: Scalars['String']
 }
 
+export type QueryWebhook_DeliveriesArgs = {
+	count?: InputMaybe<Scalars['Int']>
+	project_id: Scalars['ID']
+}
+
 export type QueryWebsocket_EventsArgs = {
 	session_secure_id: Scalars['String']
 }
@@ -2652,8 +2942,11 @@ export type Sampling = {
 	session_minute_rate_limit?: Maybe<Scalars['Int64']>
 	session_sampling_rate: Scalars['Float']
 	trace_exclusion_query?: Maybe<Scalars['String']>
+	trace_keep_on_error: Scalars['Boolean']
+	trace_latency_threshold_ms?: Maybe<Scalars['Int64']>
 	trace_minute_rate_limit?: Maybe<Scalars['Int64']>
 	trace_sampling_rate: Scalars['Float']
+	trace_service_sampling_rates?: Maybe<Scalars['Map']>
 }
 
 export type SamplingInput = {
@@ -2667,8 +2960,11 @@ export type SamplingInput = {
 	session_minute_rate_limit?: InputMaybe<Scalars['Int64']>
 	session_sampling_rate?: InputMaybe<Scalars['Float']>
 	trace_exclusion_query?: InputMaybe<Scalars['String']>
+	trace_keep_on_error?: InputMaybe<Scalars['Boolean']>
+	trace_latency_threshold_ms?: InputMaybe<Scalars['Int64']>
 	trace_minute_rate_limit?: InputMaybe<Scalars['Int64']>
 	trace_sampling_rate?: InputMaybe<Scalars['Float']>
+	trace_service_sampling_rates?: InputMaybe<Scalars['Map']>
 }
 
 export type SanitizedAdmin = {
@@ -2856,7 +3152,10 @@ export type SessionAlert = {
 	ExcludeRules: Array<Maybe<Scalars['String']>>
 	ExcludedEnvironments: Array<Maybe<Scalars['String']>>
 	LastAdminToEditID?: Maybe<Scalars['ID']>
+	MicrosoftTeamsChannels: Array<MicrosoftTeamsChannel>
 	Name?: Maybe<Scalars['String']>
+	OpsgenieTeams: Array<OpsgenieTeam>
+	PagerDutyServices: Array<PagerDutyService>
 	ThresholdWindow?: Maybe<Scalars['Int']>
 	TrackProperties: Array<Maybe<TrackProperty>>
 	Type: Scalars['String']
@@ -2876,7 +3175,10 @@ export type SessionAlertInput = {
 	emails: Array<Scalars['String']>
 	environments: Array<Scalars['String']>
 	exclude_rules: Array<Scalars['String']>
+	microsoft_teams_channels?: InputMaybe<Array<MicrosoftTeamsChannelInput>>
 	name: Scalars['String']
+	opsgenie_teams?: InputMaybe<Array<OpsgenieTeamInput>>
+	pager_duty_services?: InputMaybe<Array<PagerDutyServiceInput>>
 	project_id: Scalars['ID']
 	slack_channels: Array<SanitizedSlackChannelInput>
 	threshold_window: Scalars['Int']
@@ -3008,6 +3310,19 @@ export type SessionsHistogram = {
 	total_sessions: Array<Scalars['Int64']>
 }
 
+export type SilencedAlertNotification = {
+	__typename?: 'SilencedAlertNotification'
+	alert_id: Scalars['ID']
+	alert_name: Scalars['String']
+	alert_silence_id: Scalars['ID']
+	alert_type: Scalars['String']
+	created_at: Scalars['Timestamp']
+	destinations: Scalars['String']
+	environment: Scalars['String']
+	id: Scalars['ID']
+	service_name: Scalars['String']
+}
+
 export type SlackSyncResponse = {
 	__typename?: 'SlackSyncResponse'
 	newChannelsAddedCount: Scalars['Int']
@@ -3094,6 +3409,11 @@ export type SystemConfiguration = {
 	maintenance_start?: Maybe<Scalars['Timestamp']>
 }
 
+export enum ThresholdType {
+	Anomaly = 'ANOMALY',
+	Constant = 'CONSTANT',
+}
+
 export type TimelineIndicatorEvent = {
 	__typename?: 'TimelineIndicatorEvent'
 	data?: Maybe<Scalars['Any']>
@@ -3134,6 +3454,54 @@ export type Trace = {
 	traceState: Scalars['String']
 }
 
+export type TraceAlert = {
+	__typename?: 'TraceAlert'
+	aggregator: MetricAggregator
+	below_threshold: Scalars['Boolean']
+	channels_to_notify: Array<Maybe<SanitizedSlackChannel>>
+	column: TracesMetricColumn
+	disabled: Scalars['Boolean']
+	discord_channels_to_notify: Array<DiscordChannel>
+	emails_to_notify: Array<Maybe<Scalars['String']>>
+	group_by?: Maybe<Scalars['StringArray']>
+	id: Scalars['ID']
+	last_admin_to_edit_id: Scalars['ID']
+	microsoft_teams_channels: Array<MicrosoftTeamsChannel>
+	name: Scalars['String']
+	opsgenie_teams: Array<OpsgenieTeam>
+	pager_duty_services: Array<PagerDutyService>
+	pending_evaluations: Scalars['Int']
+	project_id: Scalars['ID']
+	query: Scalars['String']
+	renotify_interval: Scalars['Int']
+	threshold: Scalars['Float']
+	threshold_window: Scalars['Int']
+	updated_at: Scalars['Timestamp']
+	webhook_destinations: Array<WebhookDestination>
+}
+
+export type TraceAlertInput = {
+	aggregator: MetricAggregator
+	below_threshold: Scalars['Boolean']
+	column: TracesMetricColumn
+	disabled: Scalars['Boolean']
+	discord_channels: Array<DiscordChannelInput>
+	emails: Array<Scalars['String']>
+	group_by: Array<Scalars['String']>
+	microsoft_teams_channels?: InputMaybe<Array<MicrosoftTeamsChannelInput>>
+	name: Scalars['String']
+	opsgenie_teams?: InputMaybe<Array<OpsgenieTeamInput>>
+	pager_duty_services?: InputMaybe<Array<PagerDutyServiceInput>>
+	pending_evaluations?: InputMaybe<Scalars['Int']>
+	project_id: Scalars['ID']
+	query: Scalars['String']
+	renotify_interval?: InputMaybe<Scalars['Int']>
+	slack_channels: Array<SanitizedSlackChannelInput>
+	threshold: Scalars['Float']
+	threshold_window: Scalars['Int']
+	webhook_destinations: Array<WebhookDestinationInput>
+}
+
 export type TraceConnection = Connection & {
 	__typename?: 'TraceConnection'
 	edges: Array<TraceEdge>
@@ -3191,6 +3559,7 @@ export type TracesMetricBucket = {
 
 export enum TracesMetricColumn {
 	Duration = 'Duration',
+	Error = 'Error',
 	MetricValue = 'MetricValue',
 }
 
@@ -3273,17 +3642,46 @@ export type WebSocketEvent = {
 	type: Scalars['String']
 }
 
+export type WebhookDelivery = {
+	__typename?: 'WebhookDelivery'
+	created_at: Scalars['Timestamp']
+	duration_ms: Scalars['Int']
+	error: Scalars['String']
+	event: Scalars['String']
+	id: Scalars['ID']
+	project_id: Scalars['ID']
+	status_code: Scalars['Int']
+	url: Scalars['String']
+}
+
 export type WebhookDestination = {
 	__typename?: 'WebhookDestination'
 	authorization?: Maybe<Scalars['String']>
+	body_template?: Maybe<Scalars['String']>
+	headers?: Maybe<Array<WebhookHeader>>
+	secret_configured: Scalars['Boolean']
 	url: Scalars['String']
 }
 
 export type WebhookDestinationInput = {
 	authorization?: InputMaybe<Scalars['String']>
+	body_template?: InputMaybe<Scalars['String']>
+	headers?: InputMaybe<Array<WebhookHeaderInput>>
+	secret?: InputMaybe<Scalars['String']>
 	url: Scalars['String']
 }
 
+export type WebhookHeader = {
+	__typename?: 'WebhookHeader'
+	name: Scalars['String']
+	value: Scalars['String']
+}
+
+export type WebhookHeaderInput = {
+	name: Scalars['String']
+	value: Scalars['String']
+}
+
 export type Workspace = {
 	__typename?: 'Workspace'
 	allow_meter_overage: Scalars['Boolean']
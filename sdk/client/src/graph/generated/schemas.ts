@@ -22,6 +22,7 @@ export type Scalars = {
 }
 
 export type BackendErrorObjectInput = {
+	causes?: InputMaybe<Array<ErrorCauseInput>>
 	event: Scalars['String']
 	log_cursor?: InputMaybe<Scalars['String']>
 	payload?: InputMaybe<Scalars['String']>
@@ -37,6 +38,12 @@ export type BackendErrorObjectInput = {
 	url: Scalars['String']
 }
 
+export type ErrorCauseInput = {
+	event: Scalars['String']
+	stackTrace: Scalars['String']
+	type: Scalars['String']
+}
+
 export type ErrorObjectInput = {
 	columnNumber: Scalars['Int']
 	event: Scalars['String']
@@ -2,6 +2,7 @@ package highlight
 
 import (
 	"context"
+	"crypto/tls"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,14 +12,27 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 )
 
 type config struct {
-	otlpEndpoint       string
-	projectID          string
-	resourceAttributes []attribute.KeyValue
+	otlpEndpoint         string
+	projectID            string
+	resourceAttributes   []attribute.KeyValue
+	samplingRate         float64
+	exporterProtocol     ExporterProtocol
+	exporterHeaders      map[string]string
+	tlsConfig            *tls.Config
+	batchTimeout         time.Duration
+	maxExportBatchSize   int
+	maxQueueSize         int
+	metricExportInterval time.Duration
+	tracerProvider       *sdktrace.TracerProvider
+	spool                *Spool
 }
 
 var (
@@ -51,6 +65,86 @@ func WithServiceVersion(serviceVersion string) Option {
 	})
 }
 
+// WithSamplingRate sets the ratio of traces that are sampled, between 0 and 1.
+// Below 1, spans with a parent follow the sampling decision of the parent.
+// The default is 1, sampling every span.
+func WithSamplingRate(samplingRate float64) Option {
+	return option(func(conf *config) {
+		conf.samplingRate = samplingRate
+	})
+}
+
+// WithExporterProtocol sets the protocol used to export traces and metrics.
+// The default is ExporterProtocolHTTP.
+func WithExporterProtocol(protocol ExporterProtocol) Option {
+	return option(func(conf *config) {
+		conf.exporterProtocol = protocol
+	})
+}
+
+// WithExporterHeaders sets headers sent with every export request.
+func WithExporterHeaders(headers map[string]string) Option {
+	return option(func(conf *config) {
+		conf.exporterHeaders = headers
+	})
+}
+
+// WithTLSConfig sets the TLS configuration of the exporters for https otlp endpoints.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return option(func(conf *config) {
+		conf.tlsConfig = tlsConfig
+	})
+}
+
+// WithBatchTimeout sets the maximum delay before a batch of spans is exported.
+// The default is 1 second.
+func WithBatchTimeout(batchTimeout time.Duration) Option {
+	return option(func(conf *config) {
+		conf.batchTimeout = batchTimeout
+	})
+}
+
+// WithMaxExportBatchSize sets the maximum number of spans exported in a batch.
+// The default is 128.
+func WithMaxExportBatchSize(maxExportBatchSize int) Option {
+	return option(func(conf *config) {
+		conf.maxExportBatchSize = maxExportBatchSize
+	})
+}
+
+// WithMaxQueueSize sets the maximum number of spans buffered before they are exported.
+// Spans are dropped when the queue is full. The default is 1024.
+func WithMaxQueueSize(maxQueueSize int) Option {
+	return option(func(conf *config) {
+		conf.maxQueueSize = maxQueueSize
+	})
+}
+
+// WithMetricExportInterval sets the interval at which metrics are exported.
+// The default is 10 seconds.
+func WithMetricExportInterval(metricExportInterval time.Duration) Option {
+	return option(func(conf *config) {
+		conf.metricExportInterval = metricExportInterval
+	})
+}
+
+// WithTracerProvider exports the spans of a tracer provider set up by the application
+// instead of creating a new one. The provider keeps its own sampler and resource,
+// and is not shut down when highlight is stopped.
+func WithTracerProvider(tracerProvider *sdktrace.TracerProvider) Option {
+	return option(func(conf *config) {
+		conf.tracerProvider = tracerProvider
+	})
+}
+
+// WithSpool exports spans through a Spool, buffering them on disk
+// while the otlp endpoint is unreachable.
+func WithSpool(spool *Spool) Option {
+	return option(func(conf *config) {
+		conf.spool = spool
+	})
+}
+
 // contextKey represents the keys that highlight may store in the users' context
 // we append every contextKey with Highlight to avoid collisions
 type contextKey string
@@ -112,7 +206,14 @@ func (d deadLog) Errorf(_ string, _ ...interface{}) {}
 func init() {
 	interruptChan = make(chan bool, 1)
 	signalChan = make(chan os.Signal, 1)
-	conf = &config{}
+	conf = &config{
+		samplingRate:         1,
+		exporterProtocol:     ExporterProtocolHTTP,
+		batchTimeout:         1000 * time.Millisecond,
+		maxExportBatchSize:   128,
+		maxQueueSize:         1024,
+		metricExportInterval: 10 * time.Second,
+	}
 
 	signal.Notify(signalChan, syscall.SIGABRT, syscall.SIGTERM, syscall.SIGINT)
 	SetOTLPEndpoint(OTLPDefaultEndpoint)
@@ -174,8 +275,8 @@ func IsRunning() bool {
 // Deprecated - this is managed by the opentelemetry SDK.
 func SetFlushInterval(_ time.Duration) {}
 
-// SetOTLPEndpoint allows you to override the otlp address used for sending errors and traces.
-// Use the root http url. Eg: https://otel.highlight.io:4318
+// SetOTLPEndpoint allows you to override the otlp address used for sending errors, traces and metrics.
+// Use the root http url. Eg: https://otel.highlight.io:4318, or https://otel.highlight.io:4317 with ExporterProtocolGRPC.
 func SetOTLPEndpoint(newOtlpEndpoint string) {
 	conf.otlpEndpoint = newOtlpEndpoint
 }
@@ -192,6 +293,19 @@ func GetProjectID() string {
 	return conf.projectID
 }
 
+// RequestHeader is the header carrying the highlight session and request IDs, formatted as <session>/<request>.
+const RequestHeader = "X-Highlight-Request"
+
+// GetRequestHeader returns the RequestHeader value propagating the session and request of the context
+// to another service, or an empty string if the context has no session.
+func GetRequestHeader(ctx context.Context) string {
+	sessionSecureID, requestID, _ := validateRequest(ctx)
+	if sessionSecureID == "" {
+		return ""
+	}
+	return sessionSecureID + "/" + requestID
+}
+
 // InterceptRequest calls InterceptRequestWithContext using the request object's context
 func InterceptRequest(r *http.Request) context.Context {
 	return InterceptRequestWithContext(r.Context(), r)
@@ -199,8 +313,15 @@ func InterceptRequest(r *http.Request) context.Context {
 
 // InterceptRequestWithContext captures the highlight session and request ID
 // for a particular request from the request headers, adding the values to the provided context.
+// The trace context and baggage propagated by the global propagator are extracted as well.
 func InterceptRequestWithContext(ctx context.Context, r *http.Request) context.Context {
-	highlightReqDetails := r.Header.Get("X-Highlight-Request")
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(r.Header))
+	return InterceptRequestHeader(ctx, r.Header.Get(RequestHeader))
+}
+
+// InterceptRequestHeader captures the highlight session and request ID from a RequestHeader value,
+// such as one received in gRPC metadata, adding the values to the provided context.
+func InterceptRequestHeader(ctx context.Context, highlightReqDetails string) context.Context {
 	ids := strings.Split(highlightReqDetails, "/")
 	if len(ids) < 2 {
 		return ctx
@@ -229,6 +350,14 @@ func validateRequest(ctx context.Context) (sessionSecureID string, requestID str
 	if v := ctx.Value(ContextKeys.RequestID); v != nil {
 		requestID = v.(string)
 	}
+	// fall back to the session and request propagated in W3C baggage
+	baggageSessionSecureID, baggageRequestID := baggageRequest(ctx)
+	if sessionSecureID == "" {
+		sessionSecureID = baggageSessionSecureID
+	}
+	if requestID == "" {
+		requestID = baggageRequestID
+	}
 	return
 }
 
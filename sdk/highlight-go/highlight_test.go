@@ -5,7 +5,14 @@ import (
 	"fmt"
 	"github.com/99designs/gqlgen/graphql"
 	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -97,3 +104,106 @@ func TestTracer(t *testing.T) {
 	})
 	Stop()
 }
+
+func TestRecordInstruments(t *testing.T) {
+	ctx := context.Background()
+	ctx = context.WithValue(ctx, ContextKeys.SessionSecureID, "0")
+	Start()
+	RecordCounter(ctx, "myCounter", 1)
+	RecordCounter(ctx, "myCounter", 2, attribute.String("key", "value"))
+	RecordGauge(ctx, "myGauge", 123.456)
+	RecordHistogram(ctx, "myHistogram", 123.456)
+	Stop()
+}
+
+func TestNewSampler(t *testing.T) {
+	if d := newSampler(1).Description(); d != "AlwaysOnSampler" {
+		t.Errorf("got sampler %s for a sampling rate of 1", d)
+	}
+	if d := newSampler(0.25).Description(); !strings.HasPrefix(d, "ParentBased{root:TraceIDRatioBased{0.25}") {
+		t.Errorf("got sampler %s for a sampling rate of 0.25", d)
+	}
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer func() {
+		conf.tracerProvider = nil
+	}()
+
+	Start(WithTracerProvider(tracerProvider))
+	span, _ := StartTrace(context.Background(), "my-span")
+	EndTrace(span)
+	Stop()
+
+	if spans := recorder.Ended(); len(spans) != 1 || spans[0].Name() != "my-span" {
+		t.Errorf("expected my-span to be recorded by the tracer provider, got %d spans", len(spans))
+	}
+}
+
+func TestPropagator(t *testing.T) {
+	tracerProvider := sdktrace.NewTracerProvider()
+	defer func() {
+		conf.tracerProvider = nil
+	}()
+	Start(WithTracerProvider(tracerProvider))
+	defer Stop()
+
+	ctx := context.WithValue(context.Background(), ContextKeys.SessionSecureID, "session")
+	ctx = context.WithValue(ctx, ContextKeys.RequestID, "++//++//++//++//++//+w==")
+	span, ctx := StartTrace(ctx, "my-span")
+	defer EndTrace(span)
+
+	header := http.Header{}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+	if header.Get("traceparent") == "" {
+		t.Errorf("expected a traceparent header")
+	}
+
+	ctx = NewPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+	sessionSecureID, requestID, err := validateRequest(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sessionSecureID != "session" || requestID != "++//++//++//++//++//+w==" {
+		t.Errorf("got session %q and request %q from baggage %q", sessionSecureID, requestID, header.Get("baggage"))
+	}
+	if trace.SpanContextFromContext(ctx).TraceID() != span.SpanContext().TraceID() {
+		t.Errorf("expected the trace of my-span to be propagated")
+	}
+}
+
+func TestBaggageSpanProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer func() {
+		conf.tracerProvider = nil
+	}()
+	Start(WithTracerProvider(tracerProvider))
+
+	// the spans of other instrumentation record the highlight members of the baggage
+	header := http.Header{"Baggage": []string{"other=value,highlight.session_id=session"}}
+	ctx := NewPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+	_, span := tracerProvider.Tracer("other").Start(ctx, "other-span")
+	span.End()
+	_, span = tracerProvider.Tracer("other").Start(context.Background(), "no-baggage-span")
+	span.End()
+	Stop()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	var baggageAttributes []string
+	for _, s := range spans {
+		for _, attr := range s.Attributes() {
+			if attr.Key == BaggageAttribute {
+				baggageAttributes = append(baggageAttributes, attr.Value.AsString())
+			}
+		}
+	}
+	if len(baggageAttributes) != 1 || baggageAttributes[0] != "highlight.session_id=session" {
+		t.Errorf("expected the highlight baggage to be recorded on other-span only, got %v", baggageAttributes)
+	}
+}
@@ -0,0 +1,119 @@
+package hlog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// logEvent is a log entry of any of the supported loggers, ready to be added to a span.
+type logEvent struct {
+	severity string
+	message  string
+	caller   *runtime.Frame
+	attrs    []attribute.KeyValue
+	// errored marks the span as errored
+	errored bool
+}
+
+// addLogEvent adds the log to the active span of the context as an event,
+// or to a new highlight-go/log span when the context has no active span.
+func addLogEvent(ctx context.Context, event logEvent) {
+	if ctx == nil {
+		ctx = context.TODO()
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		span, _ = highlight.StartTrace(ctx, "highlight-go/log")
+		defer highlight.EndTrace(span)
+	}
+
+	attrs := []attribute.KeyValue{
+		LogSeverityKey.String(event.severity),
+		LogMessageKey.String(event.message),
+	}
+	if event.caller != nil {
+		if event.caller.Function != "" {
+			attrs = append(attrs, semconv.CodeFunctionKey.String(event.caller.Function))
+		}
+		if event.caller.File != "" {
+			attrs = append(attrs, semconv.CodeFilepathKey.String(event.caller.File))
+			attrs = append(attrs, semconv.CodeLineNumberKey.Int(event.caller.Line))
+		}
+	}
+	attrs = append(attrs, event.attrs...)
+
+	span.AddEvent(highlight.LogEvent, trace.WithAttributes(attrs...))
+
+	if event.errored {
+		span.SetStatus(codes.Error, event.message)
+	}
+}
+
+// callerFrame returns the frame of a program counter, or nil if it is unknown.
+func callerFrame(pc uintptr) *runtime.Frame {
+	if pc == 0 {
+		return nil
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	return &frame
+}
+
+// attributeKey prefixes a key with the groups it belongs to, separated by dots.
+func attributeKey(groups []string, key string) string {
+	if len(groups) == 0 {
+		return key
+	}
+	return strings.Join(groups, ".") + "." + key
+}
+
+// flattenAttributes converts a structured field to attributes, flattening nested maps
+// into one attribute per value with keys joined by dots.
+func flattenAttributes(key string, value interface{}) []attribute.KeyValue {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case map[string]interface{}:
+		var attrs []attribute.KeyValue
+		for k, nested := range v {
+			attrs = append(attrs, flattenAttributes(key+"."+k, nested)...)
+		}
+		return attrs
+	case string:
+		return []attribute.KeyValue{attribute.String(key, v)}
+	case bool:
+		return []attribute.KeyValue{attribute.Bool(key, v)}
+	case int:
+		return []attribute.KeyValue{attribute.Int(key, v)}
+	case int64:
+		return []attribute.KeyValue{attribute.Int64(key, v)}
+	case float64:
+		return []attribute.KeyValue{attribute.Float64(key, v)}
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return []attribute.KeyValue{attribute.Int64(key, i)}
+		}
+		f, _ := v.Float64()
+		return []attribute.KeyValue{attribute.Float64(key, f)}
+	case time.Duration:
+		return []attribute.KeyValue{attribute.String(key, v.String())}
+	case time.Time:
+		return []attribute.KeyValue{attribute.String(key, v.Format(time.RFC3339Nano))}
+	case error:
+		return []attribute.KeyValue{attribute.String(key, v.Error())}
+	case fmt.Stringer:
+		return []attribute.KeyValue{attribute.String(key, v.String())}
+	default:
+		return []attribute.KeyValue{attribute.String(key, fmt.Sprintf("%+v", v))}
+	}
+}
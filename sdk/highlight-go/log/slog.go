@@ -0,0 +1,153 @@
+//go:build go1.21
+
+package hlog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// SlogOption applies a configuration to the given slog handler.
+type SlogOption func(h *SlogHandler)
+
+// WithSlogLevel sets the minimum level of the records handled.
+//
+// The default is slog.LevelInfo.
+func WithSlogLevel(level slog.Leveler) SlogOption {
+	return func(h *SlogHandler) {
+		h.level = level
+	}
+}
+
+// WithSlogErrorStatusLevel sets the minimum level of the records that mark their span as errored.
+//
+// The default is slog.LevelError.
+func WithSlogErrorStatusLevel(level slog.Level) SlogOption {
+	return func(h *SlogHandler) {
+		h.errorStatusLevel = level
+	}
+}
+
+// SlogHandler is an slog handler that adds logs to the active span as events.
+type SlogHandler struct {
+	level            slog.Leveler
+	errorStatusLevel slog.Level
+	attrs            []attribute.KeyValue
+	groups           []string
+}
+
+var _ slog.Handler = (*SlogHandler)(nil)
+
+// NewSlogHandler returns an slog handler.
+func NewSlogHandler(opts ...SlogOption) *SlogHandler {
+	handler := &SlogHandler{
+		level:            slog.LevelInfo,
+		errorStatusLevel: slog.LevelError,
+	}
+
+	for _, fn := range opts {
+		fn(handler)
+	}
+
+	return handler
+}
+
+// Enabled reports whether records of the level are handled.
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle adds the record to the active span of the context.
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	attrs := append([]attribute.KeyValue{}, h.attrs...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, slogAttributes(h.groups, attr)...)
+		return true
+	})
+
+	addLogEvent(ctx, logEvent{
+		severity: slogLevelString(record.Level),
+		message:  record.Message,
+		caller:   callerFrame(record.PC),
+		attrs:    attrs,
+		errored:  record.Level >= h.errorStatusLevel,
+	})
+	return nil
+}
+
+// WithAttrs returns a handler adding the attributes to every record.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handler := *h
+	handler.attrs = append([]attribute.KeyValue{}, h.attrs...)
+	for _, attr := range attrs {
+		handler.attrs = append(handler.attrs, slogAttributes(h.groups, attr)...)
+	}
+	return &handler
+}
+
+// WithGroup returns a handler prefixing the keys of the following attributes with the group.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	handler := *h
+	handler.groups = append(append([]string{}, h.groups...), name)
+	return &handler
+}
+
+func slogAttributes(groups []string, attr slog.Attr) []attribute.KeyValue {
+	if attr.Equal(slog.Attr{}) {
+		return nil
+	}
+
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		// groups without a key are inlined in their parent
+		if attr.Key != "" {
+			groups = append(append([]string{}, groups...), attr.Key)
+		}
+		var attrs []attribute.KeyValue
+		for _, nested := range value.Group() {
+			attrs = append(attrs, slogAttributes(groups, nested)...)
+		}
+		return attrs
+	}
+
+	key := attributeKey(groups, attr.Key)
+	switch value.Kind() {
+	case slog.KindString:
+		return []attribute.KeyValue{attribute.String(key, value.String())}
+	case slog.KindInt64:
+		return []attribute.KeyValue{attribute.Int64(key, value.Int64())}
+	case slog.KindUint64:
+		return []attribute.KeyValue{attribute.Int64(key, int64(value.Uint64()))}
+	case slog.KindFloat64:
+		return []attribute.KeyValue{attribute.Float64(key, value.Float64())}
+	case slog.KindBool:
+		return []attribute.KeyValue{attribute.Bool(key, value.Bool())}
+	case slog.KindDuration:
+		return []attribute.KeyValue{attribute.String(key, value.Duration().String())}
+	case slog.KindTime:
+		return []attribute.KeyValue{attribute.String(key, value.Time().Format(time.RFC3339Nano))}
+	default:
+		return flattenAttributes(key, value.Any())
+	}
+}
+
+func slogLevelString(level slog.Level) string {
+	switch {
+	case level < slog.LevelDebug:
+		return "TRACE"
+	case level < slog.LevelInfo:
+		return "DEBUG"
+	case level < slog.LevelWarn:
+		return "INFO"
+	case level < slog.LevelError:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
@@ -0,0 +1,39 @@
+//go:build go1.21
+
+package hlog
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestSlogHandler(t *testing.T) {
+	ctx, recorder, end := startTestSpan()
+
+	logger := slog.New(NewSlogHandler(WithSlogErrorStatusLevel(slog.LevelWarn))).With("service", "api").WithGroup("request")
+	logger.DebugContext(ctx, "not enabled")
+	logger.InfoContext(ctx, "handled", "method", "GET", slog.Duration("latency", time.Second), slog.Group("user", "id", 1, "admin", true))
+	logger.WarnContext(ctx, "slow")
+	end()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	events := eventAttributes(spans[0])
+	assert.Len(t, events, 2)
+
+	assert.Equal(t, "INFO", events[0][LogSeverityKey].AsString())
+	assert.Equal(t, "handled", events[0][LogMessageKey].AsString())
+	assert.Equal(t, "api", events[0]["service"].AsString())
+	assert.Equal(t, "GET", events[0]["request.method"].AsString())
+	assert.Equal(t, "1s", events[0]["request.latency"].AsString())
+	assert.Equal(t, int64(1), events[0]["request.user.id"].AsInt64())
+	assert.True(t, events[0]["request.user.admin"].AsBool())
+	assert.Contains(t, events[0]["code.filepath"].AsString(), "slog_test.go")
+
+	assert.Equal(t, "WARN", events[1][LogSeverityKey].AsString())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
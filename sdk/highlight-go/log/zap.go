@@ -0,0 +1,129 @@
+package hlog
+
+import (
+	"context"
+	"runtime"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap/zapcore"
+)
+
+const zapContextKey = "context"
+
+// ZapOption applies a configuration to the given zap core.
+type ZapOption func(c *ZapCore)
+
+// WithZapErrorStatusLevel sets the minimum level of the entries that mark their span as errored.
+//
+// The default is zapcore.ErrorLevel.
+func WithZapErrorStatusLevel(level zapcore.Level) ZapOption {
+	return func(c *ZapCore) {
+		c.errorStatusLevel = level
+	}
+}
+
+// ZapContext returns a field setting the context of an entry, so that the entry is added to the active span of the context.
+// Other zap cores skip the field.
+func ZapContext(ctx context.Context) zapcore.Field {
+	return zapcore.Field{Key: zapContextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// ZapCore is a zap core that adds logs to the active span as events.
+//
+// The caller of entries is only captured by loggers created with zap.AddCaller.
+type ZapCore struct {
+	zapcore.LevelEnabler
+	errorStatusLevel zapcore.Level
+	fields           []zapcore.Field
+}
+
+var _ zapcore.Core = (*ZapCore)(nil)
+
+// NewZapCore returns a zap core handling the entries of the levels enabled by enabler.
+func NewZapCore(enabler zapcore.LevelEnabler, opts ...ZapOption) *ZapCore {
+	core := &ZapCore{
+		LevelEnabler:     enabler,
+		errorStatusLevel: zapcore.ErrorLevel,
+	}
+
+	for _, fn := range opts {
+		fn(core)
+	}
+
+	return core
+}
+
+// With returns a core adding the fields to every entry.
+func (c *ZapCore) With(fields []zapcore.Field) zapcore.Core {
+	core := *c
+	core.fields = append(append([]zapcore.Field{}, c.fields...), fields...)
+	return &core
+}
+
+// Check adds the core to the checked entry if the level of the entry is enabled.
+func (c *ZapCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+// Write adds the entry to the active span of the context set with ZapContext.
+func (c *ZapCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	ctx := context.TODO()
+	encoder := zapcore.NewMapObjectEncoder()
+	for _, fields := range [][]zapcore.Field{c.fields, fields} {
+		for _, field := range fields {
+			if fieldCtx, ok := field.Interface.(context.Context); ok && field.Type == zapcore.SkipType && field.Key == zapContextKey {
+				ctx = fieldCtx
+				continue
+			}
+			field.AddTo(encoder)
+		}
+	}
+
+	var attrs []attribute.KeyValue
+	for k, v := range encoder.Fields {
+		attrs = append(attrs, flattenAttributes(k, v)...)
+	}
+
+	var caller *runtime.Frame
+	if entry.Caller.Defined {
+		caller = &runtime.Frame{
+			Function: entry.Caller.Function,
+			File:     entry.Caller.File,
+			Line:     entry.Caller.Line,
+		}
+	}
+
+	addLogEvent(ctx, logEvent{
+		severity: zapLevelString(entry.Level),
+		message:  entry.Message,
+		caller:   caller,
+		attrs:    attrs,
+		errored:  entry.Level >= c.errorStatusLevel,
+	})
+	return nil
+}
+
+// Sync is a noop as entries are added to spans when they are written.
+func (c *ZapCore) Sync() error {
+	return nil
+}
+
+func zapLevelString(level zapcore.Level) string {
+	switch level {
+	case zapcore.DebugLevel:
+		return "DEBUG"
+	case zapcore.WarnLevel:
+		return "WARN"
+	case zapcore.ErrorLevel:
+		return "ERROR"
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return "PANIC"
+	case zapcore.FatalLevel:
+		return "FATAL"
+	default:
+		return "INFO"
+	}
+}
@@ -0,0 +1,61 @@
+package hlog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// startTestSpan returns a context with an active span recorded by the returned recorder.
+func startTestSpan() (context.Context, *tracetest.SpanRecorder, func()) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "test")
+	return ctx, recorder, func() { span.End() }
+}
+
+func eventAttributes(span sdktrace.ReadOnlySpan) []map[attribute.Key]attribute.Value {
+	var events []map[attribute.Key]attribute.Value
+	for _, event := range span.Events() {
+		attrs := map[attribute.Key]attribute.Value{}
+		for _, attr := range event.Attributes {
+			attrs[attr.Key] = attr.Value
+		}
+		events = append(events, attrs)
+	}
+	return events
+}
+
+func TestZapCore(t *testing.T) {
+	ctx, recorder, end := startTestSpan()
+
+	logger := zap.New(NewZapCore(zapcore.InfoLevel), zap.AddCaller()).With(zap.String("service", "api"))
+	logger.Debug("not enabled", ZapContext(ctx))
+	logger.Info("logged in", ZapContext(ctx), zap.Int("attempts", 2), zap.Namespace("user"), zap.String("email", "alice@example.com"))
+	logger.Error("failed", ZapContext(ctx), zap.Error(errors.New("boom")))
+	end()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	events := eventAttributes(spans[0])
+	assert.Len(t, events, 2)
+
+	assert.Equal(t, "INFO", events[0][LogSeverityKey].AsString())
+	assert.Equal(t, "logged in", events[0][LogMessageKey].AsString())
+	assert.Equal(t, "api", events[0]["service"].AsString())
+	assert.Equal(t, int64(2), events[0]["attempts"].AsInt64())
+	assert.Equal(t, "alice@example.com", events[0]["user.email"].AsString())
+	assert.Contains(t, events[0]["code.filepath"].AsString(), "zap_test.go")
+
+	assert.Equal(t, "ERROR", events[1][LogSeverityKey].AsString())
+	assert.Equal(t, "boom", events[1]["error"].AsString())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
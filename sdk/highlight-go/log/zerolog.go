@@ -0,0 +1,183 @@
+package hlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ZerologEventIDFieldName is the field used to match the events run by the hook with the events it writes.
+// It is removed from the events before they are written to the output of the hook.
+var ZerologEventIDFieldName = "highlight_event_id"
+
+// ZerologOption applies a configuration to the given zerolog hook.
+type ZerologOption func(h *ZerologHook)
+
+// WithZerologErrorStatusLevel sets the minimum level of the events that mark their span as errored.
+//
+// The default is zerolog.ErrorLevel.
+func WithZerologErrorStatusLevel(level zerolog.Level) ZerologOption {
+	return func(h *ZerologHook) {
+		h.errorStatusLevel = level
+	}
+}
+
+// ZerologHook is a zerolog hook that adds logs to the active span as events.
+//
+// Zerolog hooks cannot read the fields of events, so the hook is also the writer of the logger
+// and adds the events to their span once they are written:
+//
+//	hook := hlog.NewZerologHook(os.Stdout)
+//	logger := zerolog.New(hook).Hook(hook)
+//	logger.Info().Ctx(ctx).Str("user", "alice").Msg("logged in")
+type ZerologHook struct {
+	out              io.Writer
+	errorStatusLevel zerolog.Level
+	nextID           atomic.Uint64
+	events           sync.Map
+}
+
+var (
+	_ zerolog.Hook        = (*ZerologHook)(nil)
+	_ zerolog.LevelWriter = (*ZerologHook)(nil)
+)
+
+type zerologEvent struct {
+	ctx     context.Context
+	level   zerolog.Level
+	message string
+	caller  *runtime.Frame
+}
+
+// NewZerologHook returns a zerolog hook writing the events to out, which may be nil to discard them.
+func NewZerologHook(out io.Writer, opts ...ZerologOption) *ZerologHook {
+	if out == nil {
+		out = io.Discard
+	}
+	hook := &ZerologHook{
+		out:              out,
+		errorStatusLevel: zerolog.ErrorLevel,
+	}
+
+	for _, fn := range opts {
+		fn(hook)
+	}
+
+	return hook
+}
+
+// Run is fired on a new event, before the event is written.
+func (h *ZerologHook) Run(e *zerolog.Event, level zerolog.Level, message string) {
+	if !e.Enabled() {
+		return
+	}
+
+	id := strconv.FormatUint(h.nextID.Add(1), 10)
+	h.events.Store(id, &zerologEvent{
+		ctx:     e.GetCtx(),
+		level:   level,
+		message: message,
+		caller:  zerologCaller(),
+	})
+	e.Str(ZerologEventIDFieldName, id)
+}
+
+// Write adds the event to the active span of its context and writes it to the output of the hook.
+func (h *ZerologHook) Write(p []byte) (int, error) {
+	return h.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel adds the event to the active span of its context and writes it to the output of the hook.
+func (h *ZerologHook) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return h.writeLevel(level, p)
+	}
+
+	id, _ := fields[ZerologEventIDFieldName].(string)
+	value, ok := h.events.LoadAndDelete(id)
+	if !ok {
+		return h.writeLevel(level, p)
+	}
+	event := value.(*zerologEvent)
+
+	var attrs []attribute.KeyValue
+	for k, v := range fields {
+		switch k {
+		case ZerologEventIDFieldName, zerolog.LevelFieldName, zerolog.MessageFieldName, zerolog.TimestampFieldName, zerolog.CallerFieldName:
+			continue
+		}
+		attrs = append(attrs, flattenAttributes(k, v)...)
+	}
+
+	addLogEvent(event.ctx, logEvent{
+		severity: zerologLevelString(event.level),
+		message:  event.message,
+		caller:   event.caller,
+		attrs:    attrs,
+		errored:  event.level >= h.errorStatusLevel && event.level < zerolog.NoLevel,
+	})
+
+	n, err := h.writeLevel(level, removeZerologEventID(p, id))
+	if err != nil {
+		return n, err
+	}
+	return len(p), nil
+}
+
+func (h *ZerologHook) writeLevel(level zerolog.Level, p []byte) (int, error) {
+	if lw, ok := h.out.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return h.out.Write(p)
+}
+
+// removeZerologEventID removes the event id field added by the hook from an event.
+func removeZerologEventID(p []byte, id string) []byte {
+	field := []byte(fmt.Sprintf(`"%s":"%s"`, ZerologEventIDFieldName, id))
+	idx := bytes.Index(p, field)
+	if idx < 0 {
+		return p
+	}
+	start, end := idx, idx+len(field)
+	if start > 0 && p[start-1] == ',' {
+		start--
+	} else if end < len(p) && p[end] == ',' {
+		end++
+	}
+	return append(append([]byte{}, p[:start]...), p[end:]...)
+}
+
+// zerologCaller returns the first frame outside of zerolog, which is the caller of Msg, Msgf or Send.
+func zerologCaller() *runtime.Frame {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/rs/zerolog") {
+			return &frame
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+func zerologLevelString(level zerolog.Level) string {
+	if level == zerolog.NoLevel {
+		return "INFO"
+	}
+	return strings.ToUpper(level.String())
+}
@@ -0,0 +1,41 @@
+package hlog
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestZerologHook(t *testing.T) {
+	ctx, recorder, end := startTestSpan()
+
+	var out bytes.Buffer
+	hook := NewZerologHook(&out, WithZerologErrorStatusLevel(zerolog.WarnLevel))
+	logger := zerolog.New(hook).Hook(hook).Level(zerolog.InfoLevel)
+	logger.Debug().Ctx(ctx).Msg("not enabled")
+	logger.Info().Ctx(ctx).Str("user", "alice").Int("attempts", 2).Dict("request", zerolog.Dict().Str("method", "GET")).Msg("logged in")
+	logger.Warn().Ctx(ctx).Msg("slow")
+	end()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	events := eventAttributes(spans[0])
+	assert.Len(t, events, 2)
+
+	assert.Equal(t, "INFO", events[0][LogSeverityKey].AsString())
+	assert.Equal(t, "logged in", events[0][LogMessageKey].AsString())
+	assert.Equal(t, "alice", events[0]["user"].AsString())
+	assert.Equal(t, int64(2), events[0]["attempts"].AsInt64())
+	assert.Equal(t, "GET", events[0]["request.method"].AsString())
+	assert.Contains(t, events[0]["code.filepath"].AsString(), "zerolog_test.go")
+	assert.NotContains(t, events[0], ZerologEventIDFieldName)
+
+	assert.Equal(t, "WARN", events[1][LogSeverityKey].AsString())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+	// the output of the hook does not contain the event ids
+	assert.Equal(t, `{"level":"info","user":"alice","attempts":2,"request":{"method":"GET"},"message":"logged in"}`+"\n"+`{"level":"warn","message":"slow"}`+"\n", out.String())
+}
@@ -0,0 +1,111 @@
+package highlight
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// instruments caches the instruments created by name, as an instrument may only be created once per meter.
+var instruments = struct {
+	sync.Mutex
+	counters   map[string]metric.Float64Counter
+	histograms map[string]metric.Float64Histogram
+	gauges     map[string]*gauge
+}{
+	counters:   map[string]metric.Float64Counter{},
+	histograms: map[string]metric.Float64Histogram{},
+	gauges:     map[string]*gauge{},
+}
+
+// gauge holds the last value recorded for each set of attributes, observed when metrics are collected.
+type gauge struct {
+	sync.Mutex
+	values map[attribute.Distinct]gaugeValue
+}
+
+type gaugeValue struct {
+	value float64
+	attrs []attribute.KeyValue
+}
+
+func (g *gauge) observe(_ context.Context, observer metric.Float64Observer) error {
+	g.Lock()
+	defer g.Unlock()
+	for _, v := range g.values {
+		observer.Observe(v.value, metric.WithAttributes(v.attrs...))
+	}
+	return nil
+}
+
+// metricAttributes adds the session of the context to the tags, as metrics are not recorded on a span.
+func metricAttributes(ctx context.Context, tags []attribute.KeyValue) []attribute.KeyValue {
+	sessionID, _, _ := validateRequest(ctx)
+	if sessionID == "" {
+		return tags
+	}
+	return append([]attribute.KeyValue{attribute.String(SessionIDAttribute, sessionID)}, tags...)
+}
+
+// RecordCounter adds value to the OpenTelemetry counter called name.
+// Counters are monotonic, so value should not be negative.
+func RecordCounter(ctx context.Context, name string, value float64, tags ...attribute.KeyValue) {
+	instruments.Lock()
+	counter, ok := instruments.counters[name]
+	if !ok {
+		var err error
+		if counter, err = meter.Float64Counter(name); err != nil {
+			instruments.Unlock()
+			logger.Errorf("failed to create counter %s: %s", name, err)
+			return
+		}
+		instruments.counters[name] = counter
+	}
+	instruments.Unlock()
+
+	counter.Add(ctx, value, metric.WithAttributes(metricAttributes(ctx, tags)...))
+}
+
+// RecordHistogram records value in the OpenTelemetry histogram called name,
+// such as the latency of a DB query.
+func RecordHistogram(ctx context.Context, name string, value float64, tags ...attribute.KeyValue) {
+	instruments.Lock()
+	histogram, ok := instruments.histograms[name]
+	if !ok {
+		var err error
+		if histogram, err = meter.Float64Histogram(name); err != nil {
+			instruments.Unlock()
+			logger.Errorf("failed to create histogram %s: %s", name, err)
+			return
+		}
+		instruments.histograms[name] = histogram
+	}
+	instruments.Unlock()
+
+	histogram.Record(ctx, value, metric.WithAttributes(metricAttributes(ctx, tags)...))
+}
+
+// RecordGauge sets the current value of the OpenTelemetry gauge called name, such as the size of a queue.
+// The last value recorded for each set of tags is exported until the SDK is stopped.
+func RecordGauge(ctx context.Context, name string, value float64, tags ...attribute.KeyValue) {
+	instruments.Lock()
+	g, ok := instruments.gauges[name]
+	if !ok {
+		g = &gauge{values: map[attribute.Distinct]gaugeValue{}}
+		if _, err := meter.Float64ObservableGauge(name, metric.WithFloat64Callback(g.observe)); err != nil {
+			instruments.Unlock()
+			logger.Errorf("failed to create gauge %s: %s", name, err)
+			return
+		}
+		instruments.gauges[name] = g
+	}
+	instruments.Unlock()
+
+	attrs := metricAttributes(ctx, tags)
+	set := attribute.NewSet(attrs...)
+	g.Lock()
+	defer g.Unlock()
+	g.values[set.Equivalent()] = gaugeValue{value: value, attrs: set.ToSlice()}
+}
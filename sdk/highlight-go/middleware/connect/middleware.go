@@ -0,0 +1,172 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/highlight/highlight/sdk/highlight-go/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var (
+	rpcSystemConnect    = semconv.RPCSystemKey.String("connect_rpc")
+	rpcConnectErrorCode = attribute.Key("rpc.connect_rpc.error_code")
+)
+
+type interceptor struct{}
+
+// NewInterceptor returns a Connect interceptor for handlers and clients
+// use as follows:
+//
+// import highlightconnect "github.com/highlight/highlight/sdk/highlight-go/middleware/connect"
+// ...
+// path, handler := greetv1connect.NewGreetServiceHandler(greeter, connect.WithInterceptors(highlightconnect.NewInterceptor()))
+// client := greetv1connect.NewGreetServiceClient(http.DefaultClient, url, connect.WithInterceptors(highlightconnect.NewInterceptor()))
+func NewInterceptor() connect.Interceptor {
+	middleware.CheckStatus()
+	return interceptor{}
+}
+
+func (interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		var span trace.Span
+		if req.Spec().IsClient {
+			span, ctx = startClientSpan(ctx, req.Spec())
+			setRequestHeader(ctx, req.Header())
+			defer highlight.EndTrace(span)
+		} else {
+			span, ctx = startHandlerSpan(ctx, req.Spec(), req.Peer(), req.Header())
+			defer highlight.EndTrace(span)
+			defer recordPanic(span)
+		}
+
+		resp, err := next(ctx, req)
+		setStatus(span, err)
+		return resp, err
+	}
+}
+
+func (interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		span, ctx := startClientSpan(ctx, spec)
+		conn := next(ctx, spec)
+		setRequestHeader(ctx, conn.RequestHeader())
+		return &streamingClientConn{StreamingClientConn: conn, span: span}
+	}
+}
+
+func (interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		span, ctx := startHandlerSpan(ctx, conn.Spec(), conn.Peer(), conn.RequestHeader())
+		defer highlight.EndTrace(span)
+		defer recordPanic(span)
+
+		err := next(ctx, conn)
+		setStatus(span, err)
+		return err
+	}
+}
+
+// GetRequestAttributes returns the attributes of a Connect call received by a handler.
+func GetRequestAttributes(spec connect.Spec, peer connect.Peer, header http.Header) []attribute.KeyValue {
+	attrs := append(procedureAttributes(spec.Procedure),
+		semconv.HTTPClientIP(middleware.GetIPAddress(&http.Request{Header: header, RemoteAddr: peer.Addr})),
+	)
+	if peer.Protocol != "" {
+		attrs = append(attrs, semconv.NetAppProtocolName(peer.Protocol))
+	}
+	return attrs
+}
+
+// procedureAttributes returns the attributes of a procedure formatted as /package.service/method.
+func procedureAttributes(procedure string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{rpcSystemConnect}
+	if service, method, ok := strings.Cut(strings.TrimPrefix(procedure, "/"), "/"); ok {
+		attrs = append(attrs, semconv.RPCService(service), semconv.RPCMethod(method))
+	}
+	return attrs
+}
+
+func startHandlerSpan(ctx context.Context, spec connect.Spec, peer connect.Peer, header http.Header) (trace.Span, context.Context) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+	ctx = highlight.InterceptRequestHeader(ctx, header.Get(highlight.RequestHeader))
+
+	span, ctx := highlight.StartTraceWithSpanKind(ctx, spec.Procedure, trace.SpanKindServer)
+	span.SetAttributes(attribute.String(highlight.SourceAttribute, "GoConnectMiddleware"))
+	span.SetAttributes(GetRequestAttributes(spec, peer, header)...)
+	return span, ctx
+}
+
+// setRequestHeader propagates the trace, highlight session and request of the context to the handler of a call.
+func setRequestHeader(ctx context.Context, header http.Header) {
+	if value := highlight.GetRequestHeader(ctx); value != "" {
+		header.Set(highlight.RequestHeader, value)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+func startClientSpan(ctx context.Context, spec connect.Spec) (trace.Span, context.Context) {
+	span, ctx := highlight.StartTraceWithSpanKind(ctx, spec.Procedure, trace.SpanKindClient)
+	span.SetAttributes(procedureAttributes(spec.Procedure)...)
+	return span, ctx
+}
+
+// setStatus records the error of calls that did not succeed.
+func setStatus(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	code := connect.CodeOf(err)
+	span.SetAttributes(rpcConnectErrorCode.String(code.String()))
+	highlight.RecordSpanError(span, err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// recordPanic records a panic of the handler on its span before resuming it.
+func recordPanic(span trace.Span) {
+	if rec := recover(); rec != nil {
+		setStatus(span, connect.NewError(connect.CodeInternal, fmt.Errorf("panic: %v", rec)))
+		panic(rec)
+	}
+}
+
+type streamingClientConn struct {
+	connect.StreamingClientConn
+	span trace.Span
+	once sync.Once
+}
+
+func (c *streamingClientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+	if errors.Is(err, io.EOF) {
+		c.end(nil)
+	} else if err != nil {
+		c.end(err)
+	}
+	return err
+}
+
+func (c *streamingClientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	c.end(nil)
+	return err
+}
+
+func (c *streamingClientConn) end(err error) {
+	c.once.Do(func() {
+		setStatus(c.span, err)
+		highlight.EndTrace(c.span)
+	})
+}
@@ -0,0 +1,116 @@
+package connect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/connect-go"
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const checkProcedure = "/grpc.health.v1.Health/Check"
+
+func startTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	highlight.Start(highlight.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	t.Cleanup(highlight.Stop)
+	return recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+// check reports the session of the call as the status of the service,
+// and fails the check of other services.
+func check(ctx context.Context, req *connect.Request[grpc_health_v1.HealthCheckRequest]) (*connect.Response[grpc_health_v1.HealthCheckResponse], error) {
+	if req.Msg.Service == "panic" {
+		panic("boom")
+	}
+	if ctx.Value(highlight.ContextKeys.SessionSecureID) != "session" {
+		return nil, connect.NewError(connect.CodeNotFound, errors.New("unknown session"))
+	}
+	return connect.NewResponse(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}), nil
+}
+
+func startTestServer(t *testing.T) *connect.Client[grpc_health_v1.HealthCheckRequest, grpc_health_v1.HealthCheckResponse] {
+	mux := http.NewServeMux()
+	mux.Handle(checkProcedure, connect.NewUnaryHandler(checkProcedure, check, connect.WithInterceptors(NewInterceptor())))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return connect.NewClient[grpc_health_v1.HealthCheckRequest, grpc_health_v1.HealthCheckResponse](
+		server.Client(), server.URL+checkProcedure, connect.WithInterceptors(NewInterceptor()),
+	)
+}
+
+func TestInterceptor(t *testing.T) {
+	recorder := startTestTracing(t)
+	client := startTestServer(t)
+
+	ctx := context.WithValue(context.Background(), highlight.ContextKeys.SessionSecureID, "session")
+	ctx = context.WithValue(ctx, highlight.ContextKeys.RequestID, "request")
+	resp, err := client.CallUnary(ctx, connect.NewRequest(&grpc_health_v1.HealthCheckRequest{}))
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Msg.Status)
+
+	_, err = client.CallUnary(context.Background(), connect.NewRequest(&grpc_health_v1.HealthCheckRequest{}))
+	assert.Equal(t, connect.CodeNotFound, connect.CodeOf(err))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 4)
+	for _, span := range spans {
+		assert.Equal(t, checkProcedure, span.Name())
+	}
+
+	handler, client0 := spans[0], spans[1]
+	assert.Equal(t, trace.SpanKindServer, handler.SpanKind())
+	assert.Equal(t, trace.SpanKindClient, client0.SpanKind())
+	assert.Equal(t, codes.Unset, handler.Status().Code)
+	assert.Equal(t, client0.SpanContext().TraceID(), handler.SpanContext().TraceID())
+	attrs := spanAttributes(handler)
+	assert.Equal(t, "session", attrs[highlight.SessionIDAttribute].AsString())
+	assert.Equal(t, "request", attrs[highlight.RequestIDAttribute].AsString())
+	assert.Equal(t, "GoConnectMiddleware", attrs[highlight.SourceAttribute].AsString())
+	assert.Equal(t, "grpc.health.v1.Health", attrs[semconv.RPCServiceKey].AsString())
+	assert.Equal(t, "Check", attrs[semconv.RPCMethodKey].AsString())
+	assert.NotEmpty(t, attrs[semconv.HTTPClientIPKey].AsString())
+
+	for _, span := range spans[2:] {
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Equal(t, connect.CodeNotFound.String(), spanAttributes(span)[rpcConnectErrorCode].AsString())
+		assert.Equal(t, semconv.ExceptionEventName, span.Events()[0].Name)
+	}
+}
+
+func TestInterceptorPanic(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	next := func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		panic("boom")
+	}
+	req := connect.NewRequest(&grpc_health_v1.HealthCheckRequest{})
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _ = NewInterceptor().WrapUnary(next)(context.Background(), req)
+	})
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, connect.CodeInternal.String(), spanAttributes(spans[0])[rpcConnectErrorCode].AsString())
+}
@@ -0,0 +1,234 @@
+package grpc
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/highlight/highlight/sdk/highlight-go/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// requestMetadataKey carries highlight.RequestHeader in gRPC metadata, where keys are lowercase.
+var requestMetadataKey = strings.ToLower(highlight.RequestHeader)
+
+// UnaryServerInterceptor is a gRPC server interceptor for unary calls
+// use as follows:
+//
+// import highlightgrpc "github.com/highlight/highlight/sdk/highlight-go/middleware/grpc"
+// ...
+// grpc.NewServer(grpc.UnaryInterceptor(highlightgrpc.UnaryServerInterceptor()), grpc.StreamInterceptor(highlightgrpc.StreamServerInterceptor()))
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	middleware.CheckStatus()
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		span, ctx := startServerSpan(ctx, info.FullMethod)
+		defer highlight.EndTrace(span)
+		defer recordPanic(span)
+
+		resp, err := handler(ctx, req)
+		setStatus(span, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is a gRPC server interceptor for streaming calls.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	middleware.CheckStatus()
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		span, ctx := startServerSpan(ss.Context(), info.FullMethod)
+		defer highlight.EndTrace(span)
+		defer recordPanic(span)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		setStatus(span, err)
+		return err
+	}
+}
+
+// UnaryClientInterceptor is a gRPC client interceptor for unary calls,
+// propagating the highlight session and request of the context to the server
+// use as follows:
+//
+// grpc.Dial(target, grpc.WithUnaryInterceptor(highlightgrpc.UnaryClientInterceptor()), grpc.WithStreamInterceptor(highlightgrpc.StreamClientInterceptor()))
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		span, ctx := startClientSpan(ctx, method)
+		defer highlight.EndTrace(span)
+
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		setStatus(span, err)
+		return err
+	}
+}
+
+// StreamClientInterceptor is a gRPC client interceptor for streaming calls.
+// The span of a call ends once its response stream is read to the end.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		span, ctx := startClientSpan(ctx, method)
+
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			setStatus(span, err)
+			highlight.EndTrace(span)
+			return nil, err
+		}
+		return &clientStream{ClientStream: cs, desc: desc, span: span}, nil
+	}
+}
+
+// GetRequestAttributes returns the attributes of a gRPC call received by a server.
+func GetRequestAttributes(ctx context.Context, fullMethod string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+	service, method := splitFullMethod(fullMethod)
+	if service != "" {
+		attrs = append(attrs, semconv.RPCService(service))
+	}
+	if method != "" {
+		attrs = append(attrs, semconv.RPCMethod(method))
+	}
+	if ip := getIPAddress(ctx); ip != "" {
+		attrs = append(attrs, semconv.HTTPClientIP(ip))
+	}
+	return attrs
+}
+
+// getIPAddress returns the client IP forwarded by a proxy, or the address of the peer.
+func getIPAddress(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, key := range []string{"x-real-ip", "x-client-ip", "x-forwarded-for"} {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return strings.TrimSpace(strings.Split(values[0], ",")[0])
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// splitFullMethod splits a method formatted as /package.service/method.
+func splitFullMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "", ""
+	}
+	return service, method
+}
+
+func startServerSpan(ctx context.Context, fullMethod string) (trace.Span, context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+	if values := md.Get(requestMetadataKey); len(values) > 0 {
+		ctx = highlight.InterceptRequestHeader(ctx, values[0])
+	}
+
+	span, ctx := highlight.StartTraceWithSpanKind(ctx, fullMethod, trace.SpanKindServer)
+	span.SetAttributes(attribute.String(highlight.SourceAttribute, "GoGRPCMiddleware"))
+	span.SetAttributes(GetRequestAttributes(ctx, fullMethod)...)
+	return span, ctx
+}
+
+func startClientSpan(ctx context.Context, fullMethod string) (trace.Span, context.Context) {
+	span, ctx := highlight.StartTraceWithSpanKind(ctx, fullMethod, trace.SpanKindClient)
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+	if service, method := splitFullMethod(fullMethod); service != "" {
+		attrs = append(attrs, semconv.RPCService(service), semconv.RPCMethod(method))
+	}
+	span.SetAttributes(attrs...)
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	if header := highlight.GetRequestHeader(ctx); header != "" {
+		md.Set(requestMetadataKey, header)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, metadataCarrier(md))
+	return span, metadata.NewOutgoingContext(ctx, md)
+}
+
+// metadataCarrier adapts gRPC metadata to propagate the trace context and baggage.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// setStatus records the status code of a call, and the error of calls that did not succeed.
+func setStatus(span trace.Span, err error) {
+	s := status.Convert(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	if s.Code() != grpccodes.OK {
+		highlight.RecordSpanError(span, err)
+		span.SetStatus(codes.Error, s.Message())
+	}
+}
+
+// recordPanic records a panic of the handler on its span before resuming it.
+func recordPanic(span trace.Span) {
+	if rec := recover(); rec != nil {
+		setStatus(span, status.Errorf(grpccodes.Internal, "panic: %v", rec))
+		panic(rec)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	desc *grpc.StreamDesc
+	span trace.Span
+	once sync.Once
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == io.EOF {
+		s.end(nil)
+	} else if err != nil {
+		s.end(err)
+	} else if !s.desc.ServerStreams {
+		// calls without a response stream have a single response
+		s.end(nil)
+	}
+	return err
+}
+
+func (s *clientStream) end(err error) {
+	s.once.Do(func() {
+		setStatus(s.span, err)
+		highlight.EndTrace(s.span)
+	})
+}
@@ -0,0 +1,148 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func startTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	highlight.Start(highlight.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	t.Cleanup(highlight.Stop)
+	return recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+// healthServer reports the session of the call as the status of the service,
+// and fails the check of other services.
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+}
+
+func (healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if req.Service == "panic" {
+		panic("boom")
+	}
+	if ctx.Value(highlight.ContextKeys.SessionSecureID) != "session" {
+		return nil, status.Error(grpccodes.NotFound, "unknown session")
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}, nil
+}
+
+func startTestServer(t *testing.T) grpc_health_v1.HealthClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(grpc.UnaryInterceptor(UnaryServerInterceptor()), grpc.StreamInterceptor(StreamServerInterceptor()))
+	grpc_health_v1.RegisterHealthServer(server, healthServer{})
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(StreamClientInterceptor()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return grpc_health_v1.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	recorder := startTestTracing(t)
+	client := startTestServer(t)
+
+	ctx := context.WithValue(context.Background(), highlight.ContextKeys.SessionSecureID, "session")
+	ctx = context.WithValue(ctx, highlight.ContextKeys.RequestID, "request")
+	resp, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, resp.Status)
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	assert.Equal(t, grpccodes.NotFound, status.Code(err))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 4)
+	for _, span := range spans {
+		assert.Equal(t, "/grpc.health.v1.Health/Check", span.Name())
+	}
+
+	server, client0 := spans[0], spans[1]
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind())
+	assert.Equal(t, trace.SpanKindClient, client0.SpanKind())
+	attrs := spanAttributes(server)
+	assert.Equal(t, "session", attrs[highlight.SessionIDAttribute].AsString())
+	assert.Equal(t, "grpc.health.v1.Health", attrs[semconv.RPCServiceKey].AsString())
+	assert.Equal(t, "Check", attrs[semconv.RPCMethodKey].AsString())
+	assert.Equal(t, int64(grpccodes.OK), attrs[semconv.RPCGRPCStatusCodeKey].AsInt64())
+	assert.NotEmpty(t, attrs[semconv.HTTPClientIPKey].AsString())
+
+	for _, span := range spans[2:] {
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Equal(t, int64(grpccodes.NotFound), spanAttributes(span)[semconv.RPCGRPCStatusCodeKey].AsInt64())
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	recorder := startTestTracing(t)
+	client := startTestServer(t)
+
+	ctx := context.WithValue(context.Background(), highlight.ContextKeys.SessionSecureID, "session")
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, grpccodes.Unimplemented, status.Code(err))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	for _, span := range spans {
+		assert.Equal(t, "/grpc.health.v1.Health/Watch", span.Name())
+		assert.Equal(t, codes.Error, span.Status().Code)
+		assert.Equal(t, "session", spanAttributes(span)[highlight.SessionIDAttribute].AsString())
+	}
+}
+
+func TestUnaryServerInterceptorPanic(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("boom")
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+	assert.PanicsWithValue(t, "boom", func() {
+		_, _ = UnaryServerInterceptor()(context.Background(), nil, info, handler)
+	})
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, int64(grpccodes.Internal), spanAttributes(spans[0])[semconv.RPCGRPCStatusCodeKey].AsInt64())
+}
@@ -0,0 +1,82 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/highlight/highlight/sdk/highlight-go/middleware"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// statusRecorder records the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+func (r *statusRecorder) WriteHeader(statusCode int) {
+	if r.statusCode == 0 {
+		r.statusCode = statusCode
+	}
+	r.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.statusCode == 0 {
+		r.statusCode = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap allows http.ResponseController to reach the optional interfaces of the underlying writer.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Middleware is a net/http compatible middleware
+// use as follows:
+//
+// import highlighthttp "github.com/highlight/highlight/sdk/highlight-go/middleware/http"
+// ...
+// http.ListenAndServe(":8080", highlighthttp.Middleware(mux))
+func Middleware(next http.Handler) http.Handler {
+	middleware.CheckStatus()
+	fn := func(w http.ResponseWriter, r *http.Request) {
+		ctx := highlight.InterceptRequest(r)
+		span, ctx := highlight.StartTraceWithSpanKind(ctx, "highlight.http", trace.SpanKindServer)
+		defer highlight.EndTrace(span)
+
+		recorder := &statusRecorder{ResponseWriter: w}
+		r = r.WithContext(ctx)
+		defer func() {
+			if rec := recover(); rec != nil {
+				setSpanAttributes(span, r, http.StatusInternalServerError)
+				highlight.RecordSpanError(span, fmt.Errorf("panic: %v", rec))
+				span.SetStatus(codes.Error, fmt.Sprint(rec))
+				panic(rec)
+			}
+		}()
+		next.ServeHTTP(recorder, r)
+
+		if recorder.statusCode == 0 {
+			recorder.statusCode = http.StatusOK
+		}
+		setSpanAttributes(span, r, recorder.statusCode)
+		// client errors are the expected outcome of bad requests, so only server errors are recorded
+		if recorder.statusCode >= http.StatusInternalServerError {
+			highlight.RecordSpanError(span, fmt.Errorf("%d %s", recorder.statusCode, http.StatusText(recorder.statusCode)))
+			span.SetStatus(codes.Error, http.StatusText(recorder.statusCode))
+		}
+	}
+	return http.HandlerFunc(fn)
+}
+
+func setSpanAttributes(span trace.Span, r *http.Request, statusCode int) {
+	span.SetAttributes(attribute.String(highlight.SourceAttribute, "GoHTTPMiddleware"))
+	span.SetAttributes(middleware.GetRequestAttributes(r)...)
+	span.SetAttributes(semconv.HTTPStatusCode(statusCode))
+}
@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+)
+
+func startTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	highlight.Start(highlight.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	t.Cleanup(highlight.Stop)
+	return recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	var sessionID interface{}
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sessionID = r.Context().Value(highlight.ContextKeys.SessionSecureID)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/users", nil)
+	r.Header.Set(highlight.RequestHeader, "session/request")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, "session", sessionID)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "session", attrs[highlight.SessionIDAttribute].AsString())
+	assert.Equal(t, "GoHTTPMiddleware", attrs[highlight.SourceAttribute].AsString())
+	assert.Equal(t, int64(http.StatusServiceUnavailable), attrs[semconv.HTTPStatusCodeKey].AsInt64())
+	assert.Equal(t, semconv.ExceptionEventName, spans[0].Events()[0].Name)
+}
+
+func TestMiddlewarePanic(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	handler := Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	assert.PanicsWithValue(t, "boom", func() {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, int64(http.StatusInternalServerError), spanAttributes(spans[0])[semconv.HTTPStatusCodeKey].AsInt64())
+}
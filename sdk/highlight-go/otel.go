@@ -12,15 +12,22 @@ import (
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/credentials"
 )
 
 const OTLPDefaultEndpoint = "https://otel.highlight.io:4318"
+const OTLPDefaultGRPCEndpoint = "https://otel.highlight.io:4317"
 
 const ErrorURLAttribute = "URL"
 
@@ -34,6 +41,7 @@ const RequestIDAttribute = "highlight.trace_id"
 const SourceAttribute = "highlight.source"
 const TraceTypeAttribute = "highlight.type"
 const TraceKeyAttribute = "highlight.key"
+const BaggageAttribute = "highlight.baggage"
 
 const LogEvent = "log"
 const LogSeverityAttribute = "log.severity"
@@ -48,8 +56,18 @@ type TraceType string
 const TraceTypeNetworkRequest TraceType = "http.request"
 const TraceTypeHighlightInternal TraceType = "highlight.internal"
 
+// ExporterProtocol is the OTLP transport used to export traces and metrics.
+type ExporterProtocol string
+
+const ExporterProtocolHTTP ExporterProtocol = "http/protobuf"
+const ExporterProtocolGRPC ExporterProtocol = "grpc"
+
 type OTLP struct {
 	tracerProvider *sdktrace.TracerProvider
+	// spanProcessor is registered on the tracer provider of the application
+	// when one is configured with WithTracerProvider
+	spanProcessor sdktrace.SpanProcessor
+	meterProvider *sdkmetric.MeterProvider
 }
 
 type ErrorWithStack interface {
@@ -58,29 +76,118 @@ type ErrorWithStack interface {
 }
 
 var (
-	tracer = otel.GetTracerProvider().Tracer(
+	tracer = newTracer(otel.GetTracerProvider())
+	meter  = otel.GetMeterProvider().Meter(
+		"github.com/highlight/highlight/sdk/highlight-go",
+		metric.WithInstrumentationVersion("v0.1.0"),
+		metric.WithSchemaURL(semconv.SchemaURL),
+	)
+)
+
+func newTracer(tracerProvider trace.TracerProvider) trace.Tracer {
+	return tracerProvider.Tracer(
 		"github.com/highlight/highlight/sdk/highlight-go",
 		trace.WithInstrumentationVersion("v0.1.0"),
 		trace.WithSchemaURL(semconv.SchemaURL),
 	)
-)
+}
 
+// exporterEndpoint returns the host and port of the configured otlp endpoint,
+// and whether the endpoint does not use TLS.
+func exporterEndpoint() (string, bool) {
+	endpoint := conf.otlpEndpoint
+	if conf.exporterProtocol == ExporterProtocolGRPC && endpoint == OTLPDefaultEndpoint {
+		endpoint = OTLPDefaultGRPCEndpoint
+	}
+	if strings.HasPrefix(endpoint, "http://") {
+		return endpoint[7:], true
+	} else if strings.HasPrefix(endpoint, "https://") {
+		return endpoint[8:], false
+	}
+	logger.Errorf("an invalid otlp endpoint was configured %s", endpoint)
+	return "", false
+}
+
+func newTraceClient() otlptrace.Client {
+	endpoint, insecure := exporterEndpoint()
+	if conf.exporterProtocol == ExporterProtocolGRPC {
+		options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(endpoint), otlptracegrpc.WithCompressor("gzip")}
+		if insecure {
+			options = append(options, otlptracegrpc.WithInsecure())
+		} else if conf.tlsConfig != nil {
+			options = append(options, otlptracegrpc.WithTLSCredentials(credentials.NewTLS(conf.tlsConfig)))
+		}
+		if len(conf.exporterHeaders) > 0 {
+			options = append(options, otlptracegrpc.WithHeaders(conf.exporterHeaders))
+		}
+		return otlptracegrpc.NewClient(options...)
+	}
+
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithCompression(otlptracehttp.GzipCompression)}
+	if insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	} else if conf.tlsConfig != nil {
+		options = append(options, otlptracehttp.WithTLSClientConfig(conf.tlsConfig))
+	}
+	if len(conf.exporterHeaders) > 0 {
+		options = append(options, otlptracehttp.WithHeaders(conf.exporterHeaders))
+	}
+	return otlptracehttp.NewClient(options...)
+}
+
+func newMetricExporter(ctx context.Context) (sdkmetric.Exporter, error) {
+	endpoint, insecure := exporterEndpoint()
+	if conf.exporterProtocol == ExporterProtocolGRPC {
+		options := []otlpmetricgrpc.Option{otlpmetricgrpc.WithEndpoint(endpoint), otlpmetricgrpc.WithCompressor("gzip")}
+		if insecure {
+			options = append(options, otlpmetricgrpc.WithInsecure())
+		} else if conf.tlsConfig != nil {
+			options = append(options, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(conf.tlsConfig)))
+		}
+		if len(conf.exporterHeaders) > 0 {
+			options = append(options, otlpmetricgrpc.WithHeaders(conf.exporterHeaders))
+		}
+		return otlpmetricgrpc.New(ctx, options...)
+	}
+
+	options := []otlpmetrichttp.Option{otlpmetrichttp.WithEndpoint(endpoint), otlpmetrichttp.WithCompression(otlpmetrichttp.GzipCompression)}
+	if insecure {
+		options = append(options, otlpmetrichttp.WithInsecure())
+	} else if conf.tlsConfig != nil {
+		options = append(options, otlpmetrichttp.WithTLSClientConfig(conf.tlsConfig))
+	}
+	if len(conf.exporterHeaders) > 0 {
+		options = append(options, otlpmetrichttp.WithHeaders(conf.exporterHeaders))
+	}
+	return otlpmetrichttp.New(ctx, options...)
+}
+
+// newSampler samples the configured ratio of root spans, following the decision of the parent otherwise.
+func newSampler(samplingRate float64) sdktrace.Sampler {
+	if samplingRate >= 1 {
+		return sdktrace.AlwaysSample()
+	}
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(samplingRate))
+}
+
+// StartOTLP creates the exporters of traces and metrics, and registers the highlight propagator
+// as the global propagator even when the exporters cannot be created.
 func StartOTLP() (*OTLP, error) {
-	var options []otlptracehttp.Option
-	if strings.HasPrefix(conf.otlpEndpoint, "http://") {
-		options = append(options, otlptracehttp.WithEndpoint(conf.otlpEndpoint[7:]), otlptracehttp.WithInsecure())
-	} else if strings.HasPrefix(conf.otlpEndpoint, "https://") {
-		options = append(options, otlptracehttp.WithEndpoint(conf.otlpEndpoint[8:]))
-	} else {
-		logger.Errorf("an invalid otlp endpoint was configured %s", conf.otlpEndpoint)
+	otel.SetTextMapPropagator(NewPropagator())
+	ctx := context.Background()
+	client := newTraceClient()
+	if conf.spool != nil {
+		client = spoolClient{spool: conf.spool, client: client}
 	}
-	options = append(options, otlptracehttp.WithCompression(otlptracehttp.GzipCompression))
-	client := otlptracehttp.NewClient(options...)
-	exporter, err := otlptrace.New(context.Background(), client)
+	exporter, err := otlptrace.New(ctx, client)
 	if err != nil {
 		return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
 	}
-	resources, err := resource.New(context.Background(),
+	metricExporter, err := newMetricExporter(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP metric exporter: %w", err)
+	}
+	resources, err := resource.New(ctx,
 		resource.WithFromEnv(),
 		resource.WithHost(),
 		resource.WithContainer(),
@@ -91,33 +198,73 @@ func StartOTLP() (*OTLP, error) {
 	if err != nil {
 		return nil, fmt.Errorf("creating OTLP resource context: %w", err)
 	}
+
+	spanProcessor := sdktrace.NewBatchSpanProcessor(
+		exporter,
+		sdktrace.WithBatchTimeout(conf.batchTimeout),
+		sdktrace.WithMaxExportBatchSize(conf.maxExportBatchSize),
+		sdktrace.WithMaxQueueSize(conf.maxQueueSize))
 	h := &OTLP{
-		tracerProvider: sdktrace.NewTracerProvider(
-			sdktrace.WithSampler(sdktrace.AlwaysSample()),
-			sdktrace.WithBatcher(
-				exporter,
-				sdktrace.WithBatchTimeout(1000*time.Millisecond),
-				sdktrace.WithMaxExportBatchSize(128),
-				sdktrace.WithMaxQueueSize(1024)),
-			sdktrace.WithResource(resources),
+		meterProvider: sdkmetric.NewMeterProvider(
+			sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter, sdkmetric.WithInterval(conf.metricExportInterval))),
+			// metrics have no span to carry the project id
+			sdkmetric.WithResource(resource.NewSchemaless(append(resources.Attributes(), attribute.String(ProjectIDAttribute, conf.projectID))...)),
 		),
 	}
-	otel.SetTracerProvider(h.tracerProvider)
+	if conf.tracerProvider != nil {
+		h.tracerProvider = conf.tracerProvider
+		h.spanProcessor = spanProcessor
+		h.tracerProvider.RegisterSpanProcessor(baggageSpanProcessor{})
+		h.tracerProvider.RegisterSpanProcessor(spanProcessor)
+	} else {
+		h.tracerProvider = sdktrace.NewTracerProvider(
+			sdktrace.WithSampler(newSampler(conf.samplingRate)),
+			sdktrace.WithSpanProcessor(baggageSpanProcessor{}),
+			sdktrace.WithSpanProcessor(spanProcessor),
+			sdktrace.WithResource(resources),
+		)
+		otel.SetTracerProvider(h.tracerProvider)
+	}
+	tracer = newTracer(h.tracerProvider)
+	otel.SetMeterProvider(h.meterProvider)
 	return h, nil
 }
 
 func (o *OTLP) shutdown() {
-	err := o.tracerProvider.ForceFlush(context.Background())
+	if o.spanProcessor != nil {
+		// unregistering flushes and shuts down the span processor without shutting down the provider of the application
+		o.tracerProvider.UnregisterSpanProcessor(baggageSpanProcessor{})
+		o.tracerProvider.UnregisterSpanProcessor(o.spanProcessor)
+	} else {
+		err := o.tracerProvider.ForceFlush(context.Background())
+		if err != nil {
+			logger.Error(err)
+		}
+		err = o.tracerProvider.Shutdown(context.Background())
+		if err != nil {
+			logger.Error(err)
+		}
+	}
+	err := o.meterProvider.ForceFlush(context.Background())
 	if err != nil {
 		logger.Error(err)
 	}
-	err = o.tracerProvider.Shutdown(context.Background())
+	err = o.meterProvider.Shutdown(context.Background())
 	if err != nil {
 		logger.Error(err)
 	}
 }
 
 func StartTraceWithTimestamp(ctx context.Context, name string, t time.Time, tags ...attribute.KeyValue) (trace.Span, context.Context) {
+	return startTrace(ctx, name, []trace.SpanStartOption{trace.WithTimestamp(t)}, tags...)
+}
+
+// StartTraceWithSpanKind starts a span of the given kind, such as trace.SpanKindClient for outgoing requests.
+func StartTraceWithSpanKind(ctx context.Context, name string, kind trace.SpanKind, tags ...attribute.KeyValue) (trace.Span, context.Context) {
+	return startTrace(ctx, name, []trace.SpanStartOption{trace.WithSpanKind(kind)}, tags...)
+}
+
+func startTrace(ctx context.Context, name string, opts []trace.SpanStartOption, tags ...attribute.KeyValue) (trace.Span, context.Context) {
 	sessionID, requestID, _ := validateRequest(ctx)
 	spanCtx := trace.SpanContextFromContext(ctx)
 	if requestID != "" {
@@ -126,7 +273,7 @@ func StartTraceWithTimestamp(ctx context.Context, name string, t time.Time, tags
 		tid, _ := trace.TraceIDFromHex(hex)
 		spanCtx = spanCtx.WithTraceID(tid)
 	}
-	ctx, span := tracer.Start(trace.ContextWithSpanContext(ctx, spanCtx), name, trace.WithTimestamp(t))
+	ctx, span := tracer.Start(trace.ContextWithSpanContext(ctx, spanCtx), name, opts...)
 	span.SetAttributes(
 		attribute.String(ProjectIDAttribute, conf.projectID),
 		attribute.String(SessionIDAttribute, sessionID),
@@ -172,6 +319,7 @@ func EndTrace(span trace.Span) {
 // through dashboards. For example, you may want to record the latency of a DB query
 // as a metric that you would like to graph and monitor. You'll be able to view the metric
 // in the context of the session and network request and recorded it.
+// Use RecordCounter, RecordGauge or RecordHistogram to export the metric as an OpenTelemetry instrument instead.
 func RecordMetric(ctx context.Context, name string, value float64, tags ...attribute.KeyValue) {
 	span, _ := StartTrace(ctx, "highlight-ctx", tags...)
 	defer EndTrace(span)
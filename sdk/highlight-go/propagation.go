@@ -0,0 +1,86 @@
+package highlight
+
+import (
+	"context"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewPropagator returns a propagator carrying the traceparent header, along with the highlight session
+// and request of a context as the highlight.session_id and highlight.trace_id members of the W3C baggage.
+// It is registered as the global propagator by Start.
+func NewPropagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, baggagePropagator{})
+}
+
+// baggagePropagator propagates the W3C baggage of a context, adding the highlight session and request to it.
+type baggagePropagator struct {
+	propagation.Baggage
+}
+
+func (p baggagePropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sessionSecureID, requestID, _ := validateRequest(ctx)
+	bag := baggage.FromContext(ctx)
+	for key, value := range map[string]string{SessionIDAttribute: sessionSecureID, RequestIDAttribute: requestID} {
+		if value == "" {
+			continue
+		}
+		// members are created from percent-encoded values, request ids are base64 encoded
+		member, err := baggage.NewMember(key, url.QueryEscape(value))
+		if err != nil {
+			logger.Errorf("failed to propagate %s in baggage: %s", key, err)
+			continue
+		}
+		if bag, err = bag.SetMember(member); err != nil {
+			logger.Errorf("failed to propagate %s in baggage: %s", key, err)
+		}
+	}
+	p.Baggage.Inject(baggage.ContextWithBaggage(ctx, bag), carrier)
+}
+
+// baggageRequest returns the highlight session and request propagated in the W3C baggage of a context.
+func baggageRequest(ctx context.Context) (sessionSecureID string, requestID string) {
+	bag := baggage.FromContext(ctx)
+	return bag.Member(SessionIDAttribute).Value(), bag.Member(RequestIDAttribute).Value()
+}
+
+// highlightBaggage returns the W3C baggage of a context restricted to the highlight session and request,
+// or an empty string when the baggage carries neither.
+func highlightBaggage(ctx context.Context) string {
+	bag := baggage.FromContext(ctx)
+	var members []baggage.Member
+	for _, key := range []string{SessionIDAttribute, RequestIDAttribute} {
+		if member := bag.Member(key); member.Key() != "" {
+			members = append(members, member)
+		}
+	}
+	if len(members) == 0 {
+		return ""
+	}
+	highlightBag, err := baggage.New(members...)
+	if err != nil {
+		logger.Errorf("failed to record baggage: %s", err)
+		return ""
+	}
+	return highlightBag.String()
+}
+
+// baggageSpanProcessor records the highlight session and request propagated in the W3C baggage
+// as the BaggageAttribute of every span, including the spans of other instrumentation.
+type baggageSpanProcessor struct{}
+
+func (baggageSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if value := highlightBaggage(parent); value != "" {
+		s.SetAttributes(attribute.String(BaggageAttribute, value))
+	}
+}
+
+func (baggageSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (baggageSpanProcessor) Shutdown(context.Context) error { return nil }
+
+func (baggageSpanProcessor) ForceFlush(context.Context) error { return nil }
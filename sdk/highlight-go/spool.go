@@ -0,0 +1,407 @@
+package highlight
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const spoolFileExt = ".batch"
+
+// SpoolStats reports the activity of a Spool since it was created.
+type SpoolStats struct {
+	// PendingBatches and PendingBytes are the batches stored on disk awaiting export.
+	PendingBatches  int
+	PendingBytes    int64
+	ExportedBatches int64
+	ExportedSpans   int64
+	// DroppedBatches and DroppedSpans count the batches evicted to stay within the size limit,
+	// that could not be written to or read from disk, or that were rejected by the otlp endpoint
+	// or failed to export too many times.
+	DroppedBatches int64
+	DroppedSpans   int64
+	// FailedExports counts the export attempts that failed and were retried.
+	FailedExports int64
+}
+
+type spoolEntry struct {
+	seq   uint64
+	spans int
+	size  int64
+}
+
+func (e spoolEntry) name() string {
+	return fmt.Sprintf("%020d-%d%s", e.seq, e.spans, spoolFileExt)
+}
+
+// Spool is a bounded on-disk queue that exported spans go through, configured with WithSpool.
+// Batches are written to disk before they are uploaded and retried with backoff while the otlp endpoint
+// is unreachable. Batches rejected by the otlp endpoint, or failing to export too many times, are dropped
+// so that they do not block the newer ones. Batches left when the process exits are uploaded by the next
+// process using the same directory. Once the size limit is reached, the oldest batches are evicted first.
+type Spool struct {
+	dir         string
+	maxBytes    int64
+	maxAttempts int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+
+	mu           sync.Mutex
+	entries      []spoolEntry
+	pendingBytes int64
+	nextSeq      uint64
+	stats        SpoolStats
+
+	client   otlptrace.Client
+	notify   chan struct{}
+	stopping chan struct{}
+	stopped  chan struct{}
+	cancel   context.CancelFunc
+}
+
+type SpoolOption func(s *Spool)
+
+// WithSpoolMaxBytes sets the maximum size of the batches stored on disk.
+// The default is 100MB.
+func WithSpoolMaxBytes(maxBytes int64) SpoolOption {
+	return func(s *Spool) {
+		s.maxBytes = maxBytes
+	}
+}
+
+// WithSpoolMaxAttempts sets the number of times a batch is uploaded before it is dropped.
+// The default is 100.
+func WithSpoolMaxAttempts(maxAttempts int) SpoolOption {
+	return func(s *Spool) {
+		s.maxAttempts = maxAttempts
+	}
+}
+
+// WithSpoolRetryBackoff sets the delay before retrying a failed export, doubling after
+// every consecutive failure up to maxBackoff. The default is 1 second up to 1 minute.
+func WithSpoolRetryBackoff(minBackoff, maxBackoff time.Duration) SpoolOption {
+	return func(s *Spool) {
+		s.minBackoff = minBackoff
+		s.maxBackoff = maxBackoff
+	}
+}
+
+// NewSpool opens the spool stored in dir, creating the directory if needed.
+// Metrics are not spooled.
+//
+//	spool, err := highlight.NewSpool("/var/lib/myapp/highlight", highlight.WithSpoolMaxBytes(50<<20))
+//	highlight.Start(highlight.WithSpool(spool))
+func NewSpool(dir string, opts ...SpoolOption) (*Spool, error) {
+	s := &Spool{
+		dir:         dir,
+		maxBytes:    100 << 20,
+		maxAttempts: 100,
+		minBackoff:  time.Second,
+		maxBackoff:  time.Minute,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "error creating spool directory")
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading spool directory")
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		// a batch that was not fully written before the process exited
+		if strings.HasSuffix(f.Name(), spoolFileExt+".tmp") {
+			_ = os.Remove(filepath.Join(dir, f.Name()))
+			continue
+		}
+		var e spoolEntry
+		if _, err := fmt.Sscanf(f.Name(), "%d-%d"+spoolFileExt, &e.seq, &e.spans); err != nil {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		e.size = info.Size()
+		s.entries = append(s.entries, e)
+		s.pendingBytes += e.size
+		if e.seq >= s.nextSeq {
+			s.nextSeq = e.seq + 1
+		}
+	}
+	sort.Slice(s.entries, func(i, j int) bool {
+		return s.entries[i].seq < s.entries[j].seq
+	})
+	s.evictLocked()
+	return s, nil
+}
+
+// Stats returns the activity of the spool.
+func (s *Spool) Stats() SpoolStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	stats.PendingBatches = len(s.entries)
+	stats.PendingBytes = s.pendingBytes
+	return stats
+}
+
+func (s *Spool) start(ctx context.Context, client otlptrace.Client) error {
+	if err := client.Start(ctx); err != nil {
+		return err
+	}
+	s.client = client
+	s.notify = make(chan struct{}, 1)
+	s.stopping = make(chan struct{})
+	s.stopped = make(chan struct{})
+	uploadCtx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	go s.run(uploadCtx)
+	return nil
+}
+
+// stop uploads the batches left in the spool until an upload fails or ctx is done.
+// The batches that are not uploaded stay on disk.
+func (s *Spool) stop(ctx context.Context) error {
+	close(s.stopping)
+	select {
+	case <-s.stopped:
+	case <-ctx.Done():
+		s.cancel()
+		<-s.stopped
+	}
+	s.cancel()
+	return s.client.Stop(ctx)
+}
+
+func (s *Spool) enqueue(protoSpans []*tracepb.ResourceSpans) error {
+	spans := 0
+	for _, rs := range protoSpans {
+		for _, ss := range rs.ScopeSpans {
+			spans += len(ss.Spans)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := proto.Marshal(&tracepb.TracesData{ResourceSpans: protoSpans})
+	if err == nil {
+		e := spoolEntry{seq: s.nextSeq, spans: spans, size: int64(len(data))}
+		s.nextSeq++
+		err = s.writeLocked(e, data)
+	}
+	if err != nil {
+		s.stats.DroppedBatches++
+		s.stats.DroppedSpans += int64(spans)
+		return errors.Wrap(err, "error spooling spans")
+	}
+	s.evictLocked()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// writeLocked writes a batch to a temporary file first so that a partially written batch is never read back.
+func (s *Spool) writeLocked(e spoolEntry, data []byte) error {
+	path := filepath.Join(s.dir, e.name())
+	if err := os.WriteFile(path+".tmp", data, 0o644); err != nil {
+		return err
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	s.entries = append(s.entries, e)
+	s.pendingBytes += e.size
+	return nil
+}
+
+// evictLocked removes the oldest batches until the spool is within its size limit.
+func (s *Spool) evictLocked() {
+	for s.pendingBytes > s.maxBytes && len(s.entries) > 0 {
+		e, _ := s.removeLocked(s.entries[0].seq)
+		s.stats.DroppedBatches++
+		s.stats.DroppedSpans += int64(e.spans)
+	}
+}
+
+func (s *Spool) removeLocked(seq uint64) (spoolEntry, bool) {
+	for i, e := range s.entries {
+		if e.seq != seq {
+			continue
+		}
+		s.entries = append(s.entries[:i], s.entries[i+1:]...)
+		s.pendingBytes -= e.size
+		if err := os.Remove(filepath.Join(s.dir, e.name())); err != nil && !os.IsNotExist(err) {
+			logger.Error(err)
+		}
+		return e, true
+	}
+	return spoolEntry{}, false
+}
+
+func (s *Spool) oldest() (spoolEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.entries) == 0 {
+		return spoolEntry{}, false
+	}
+	return s.entries[0], true
+}
+
+// run uploads the batches of the spool in order, waiting between retries of a failed upload.
+// A batch is dropped when its upload fails with an error that is not retryable, or after maxAttempts uploads.
+// Once stopping, the remaining batches are uploaded until the spool is empty or an upload fails.
+func (s *Spool) run(ctx context.Context) {
+	defer close(s.stopped)
+	backoff := s.minBackoff
+	var attempts int
+	var lastSeq uint64
+	for {
+		e, ok := s.oldest()
+		if !ok {
+			select {
+			case <-s.notify:
+				continue
+			case <-s.stopping:
+				return
+			}
+		}
+
+		if err := s.upload(ctx, e); err != nil {
+			if e.seq != lastSeq {
+				lastSeq, attempts = e.seq, 0
+			}
+			attempts++
+			if !isRetryableExportError(err) || attempts >= s.maxAttempts {
+				s.drop(e, attempts, err)
+				continue
+			}
+			logger.Errorf("failed to export spooled spans, retrying in %s: %s", backoff, err)
+			select {
+			case <-time.After(backoff):
+			case <-s.stopping:
+				return
+			}
+			backoff *= 2
+			if backoff > s.maxBackoff {
+				backoff = s.maxBackoff
+			}
+			continue
+		}
+		backoff = s.minBackoff
+	}
+}
+
+func (s *Spool) upload(ctx context.Context, e spoolEntry) error {
+	traces := &tracepb.TracesData{}
+	data, err := os.ReadFile(filepath.Join(s.dir, e.name()))
+	if err == nil {
+		err = proto.Unmarshal(data, traces)
+	}
+	if err != nil {
+		// the batch may have been evicted since, otherwise it cannot be uploaded and would block the spool
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if _, ok := s.removeLocked(e.seq); ok {
+			logger.Errorf("dropping unreadable spooled spans: %s", err)
+			s.stats.DroppedBatches++
+			s.stats.DroppedSpans += int64(e.spans)
+		}
+		return nil
+	}
+
+	err = s.client.UploadTraces(ctx, traces.ResourceSpans)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		s.stats.FailedExports++
+		return err
+	}
+	if _, ok := s.removeLocked(e.seq); ok {
+		s.stats.ExportedBatches++
+		s.stats.ExportedSpans += int64(e.spans)
+	}
+	return nil
+}
+
+// drop removes a batch that failed to export and will not be retried.
+func (s *Spool) drop(e spoolEntry, attempts int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.removeLocked(e.seq); ok {
+		logger.Errorf("dropping spooled spans after %d failed export attempts: %s", attempts, err)
+		s.stats.DroppedBatches++
+		s.stats.DroppedSpans += int64(e.spans)
+	}
+}
+
+// isRetryableExportError reports whether an upload that failed with err may succeed when retried.
+// The otlp grpc client returns the status of the endpoint, retryable as per the otlp specification.
+// The otlp http client returns an error ending with the response status for the statuses it does not
+// retry itself, of which client errors other than timeouts are not retryable. Other errors, such as
+// failing to reach the endpoint, are retryable.
+func isRetryableExportError(err error) bool {
+	if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown {
+		switch st.Code() {
+		case codes.Canceled, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted,
+			codes.OutOfRange, codes.Unavailable, codes.DataLoss:
+			return true
+		default:
+			return false
+		}
+	}
+
+	msg := err.Error()
+	if !strings.HasPrefix(msg, "failed to send to ") {
+		return true
+	}
+	idx := strings.LastIndex(msg, ": ")
+	if idx < 0 {
+		return true
+	}
+	code, _, _ := strings.Cut(msg[idx+2:], " ")
+	statusCode, err := strconv.Atoi(code)
+	if err != nil {
+		return true
+	}
+	return statusCode < 400 || statusCode >= 500 || statusCode == 408 || statusCode == 429
+}
+
+// spoolClient uploads the spans of an otlp exporter through a Spool.
+type spoolClient struct {
+	spool  *Spool
+	client otlptrace.Client
+}
+
+func (c spoolClient) Start(ctx context.Context) error {
+	return c.spool.start(ctx, c.client)
+}
+
+func (c spoolClient) Stop(ctx context.Context) error {
+	return c.spool.stop(ctx)
+}
+
+func (c spoolClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	return c.spool.enqueue(protoSpans)
+}
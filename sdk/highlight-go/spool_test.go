@@ -0,0 +1,170 @@
+package highlight
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// testTraceClient fails the given number of uploads before accepting them, or every upload when negative.
+// Uploads fail with err, or an unreachable endpoint error when nil.
+type testTraceClient struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	spans    []string
+}
+
+func (c *testTraceClient) Start(context.Context) error { return nil }
+
+func (c *testTraceClient) Stop(context.Context) error { return nil }
+
+func (c *testTraceClient) UploadTraces(_ context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.failures != 0 {
+		c.failures--
+		if c.err != nil {
+			return c.err
+		}
+		return errors.New("otlp endpoint unreachable")
+	}
+	for _, rs := range protoSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				c.spans = append(c.spans, span.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *testTraceClient) uploaded() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.spans
+}
+
+func testResourceSpans(names ...string) []*tracepb.ResourceSpans {
+	scopeSpans := &tracepb.ScopeSpans{}
+	for _, name := range names {
+		scopeSpans.Spans = append(scopeSpans.Spans, &tracepb.Span{Name: name})
+	}
+	return []*tracepb.ResourceSpans{{ScopeSpans: []*tracepb.ScopeSpans{scopeSpans}}}
+}
+
+func TestSpoolRetry(t *testing.T) {
+	spool, err := NewSpool(t.TempDir(), WithSpoolRetryBackoff(time.Millisecond, 10*time.Millisecond))
+	assert.NoError(t, err)
+	client := &testTraceClient{failures: 3}
+	c := spoolClient{spool: spool, client: client}
+	assert.NoError(t, c.Start(context.Background()))
+
+	assert.NoError(t, c.UploadTraces(context.Background(), testResourceSpans("a", "b")))
+	assert.NoError(t, c.UploadTraces(context.Background(), testResourceSpans("c")))
+	assert.Eventually(t, func() bool {
+		return spool.Stats().PendingBatches == 0
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, c.Stop(context.Background()))
+
+	assert.Equal(t, []string{"a", "b", "c"}, client.uploaded())
+	assert.Equal(t, SpoolStats{ExportedBatches: 2, ExportedSpans: 3, FailedExports: 3}, spool.Stats())
+}
+
+func TestSpoolRestart(t *testing.T) {
+	dir := t.TempDir()
+	spool, err := NewSpool(dir, WithSpoolRetryBackoff(time.Hour, time.Hour))
+	assert.NoError(t, err)
+	c := spoolClient{spool: spool, client: &testTraceClient{failures: -1}}
+	assert.NoError(t, c.Start(context.Background()))
+	assert.NoError(t, c.UploadTraces(context.Background(), testResourceSpans("a")))
+	assert.NoError(t, c.UploadTraces(context.Background(), testResourceSpans("b")))
+	assert.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, 2, spool.Stats().PendingBatches)
+
+	spool, err = NewSpool(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, spool.Stats().PendingBatches)
+	client := &testTraceClient{}
+	c = spoolClient{spool: spool, client: client}
+	assert.NoError(t, c.Start(context.Background()))
+	assert.NoError(t, c.UploadTraces(context.Background(), testResourceSpans("c")))
+	assert.NoError(t, c.Stop(context.Background()))
+
+	assert.Equal(t, []string{"a", "b", "c"}, client.uploaded())
+	assert.Equal(t, 0, spool.Stats().PendingBatches)
+}
+
+func TestSpoolEviction(t *testing.T) {
+	batchSize := int64(proto.Size(&tracepb.TracesData{ResourceSpans: testResourceSpans("a")}))
+	spool, err := NewSpool(t.TempDir(), WithSpoolMaxBytes(2*batchSize))
+	assert.NoError(t, err)
+	for _, name := range []string{"a", "b", "c"} {
+		assert.NoError(t, spool.enqueue(testResourceSpans(name)))
+	}
+
+	stats := spool.Stats()
+	assert.Equal(t, 2, stats.PendingBatches)
+	assert.Equal(t, 2*batchSize, stats.PendingBytes)
+	assert.Equal(t, int64(1), stats.DroppedBatches)
+	assert.Equal(t, int64(1), stats.DroppedSpans)
+
+	client := &testTraceClient{}
+	c := spoolClient{spool: spool, client: client}
+	assert.NoError(t, c.Start(context.Background()))
+	assert.NoError(t, c.Stop(context.Background()))
+	assert.Equal(t, []string{"b", "c"}, client.uploaded())
+}
+
+func TestSpoolDropRejected(t *testing.T) {
+	spool, err := NewSpool(t.TempDir(), WithSpoolRetryBackoff(time.Hour, time.Hour))
+	assert.NoError(t, err)
+	client := &testTraceClient{failures: 1, err: errors.New("failed to send to http://localhost:4318/v1/traces: 400 Bad Request")}
+	c := spoolClient{spool: spool, client: client}
+	assert.NoError(t, c.Start(context.Background()))
+
+	assert.NoError(t, c.UploadTraces(context.Background(), testResourceSpans("a", "b")))
+	assert.NoError(t, c.UploadTraces(context.Background(), testResourceSpans("c")))
+	assert.Eventually(t, func() bool {
+		return spool.Stats().PendingBatches == 0
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, c.Stop(context.Background()))
+
+	assert.Equal(t, []string{"c"}, client.uploaded())
+	assert.Equal(t, SpoolStats{ExportedBatches: 1, ExportedSpans: 1, DroppedBatches: 1, DroppedSpans: 2, FailedExports: 1}, spool.Stats())
+}
+
+func TestSpoolMaxAttempts(t *testing.T) {
+	spool, err := NewSpool(t.TempDir(), WithSpoolMaxAttempts(3), WithSpoolRetryBackoff(time.Millisecond, time.Millisecond))
+	assert.NoError(t, err)
+	client := &testTraceClient{failures: 3}
+	c := spoolClient{spool: spool, client: client}
+	assert.NoError(t, c.Start(context.Background()))
+
+	assert.NoError(t, c.UploadTraces(context.Background(), testResourceSpans("a")))
+	assert.NoError(t, c.UploadTraces(context.Background(), testResourceSpans("b")))
+	assert.Eventually(t, func() bool {
+		return spool.Stats().PendingBatches == 0
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, c.Stop(context.Background()))
+
+	assert.Equal(t, []string{"b"}, client.uploaded())
+	assert.Equal(t, SpoolStats{ExportedBatches: 1, ExportedSpans: 1, DroppedBatches: 1, DroppedSpans: 1, FailedExports: 3}, spool.Stats())
+}
+
+func TestIsRetryableExportError(t *testing.T) {
+	assert.True(t, isRetryableExportError(errors.New("otlp endpoint unreachable")))
+	assert.True(t, isRetryableExportError(errors.New("failed to send to http://localhost:4318/v1/traces: 502 Bad Gateway")))
+	assert.True(t, isRetryableExportError(errors.New("failed to send to http://localhost:4318/v1/traces: 408 Request Timeout")))
+	assert.False(t, isRetryableExportError(errors.New("failed to send to http://localhost:4318/v1/traces: 413 Request Entity Too Large")))
+	assert.True(t, isRetryableExportError(status.Error(codes.Unavailable, "unavailable")))
+	assert.False(t, isRetryableExportError(status.Error(codes.InvalidArgument, "invalid spans")))
+}
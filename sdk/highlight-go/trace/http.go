@@ -0,0 +1,82 @@
+package htrace
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type transport struct {
+	base  http.RoundTripper
+	attrs []attribute.KeyValue
+}
+
+type TransportOption func(t *transport)
+
+// NewTransport wraps an http.RoundTripper to create a client span for every request,
+// and to propagate the highlight session and request of the request context to the server.
+// A nil base uses http.DefaultTransport.
+//
+//	client := &http.Client{Transport: htrace.NewTransport(nil)}
+func NewTransport(base http.RoundTripper, opts ...TransportOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &transport{base: base}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	span, ctx := highlight.StartTraceWithSpanKind(r.Context(), fmt.Sprintf("HTTP %s", r.Method), trace.SpanKindClient)
+	defer highlight.EndTrace(span)
+
+	attrs := make([]attribute.KeyValue, 0, len(t.attrs)+5)
+	attrs = append(attrs, attribute.String(highlight.SourceAttribute, "GoHTTPClient"))
+	attrs = append(attrs, t.attrs...)
+	attrs = append(attrs, semconv.HTTPMethod(r.Method))
+	if r.URL != nil {
+		// the userinfo of the url holds credentials, which must not be recorded
+		u := *r.URL
+		u.User = nil
+		attrs = append(attrs, semconv.HTTPURL(u.String()), semconv.NetPeerName(u.Hostname()))
+	}
+	span.SetAttributes(attrs...)
+
+	// the request must not be modified, so the headers are set on a copy
+	r = r.Clone(ctx)
+	if header := highlight.GetRequestHeader(ctx); header != "" {
+		r.Header.Set(highlight.RequestHeader, header)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		highlight.RecordSpanError(span, err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// WithTransportAttributes configures attributes that are added to every client span.
+func WithTransportAttributes(attrs ...attribute.KeyValue) TransportOption {
+	return func(t *transport) {
+		t.attrs = append(t.attrs, attrs...)
+	}
+}
@@ -0,0 +1,68 @@
+package htrace
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startTestTracing starts highlight with a tracer provider recording the spans of the test.
+func startTestTracing(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	highlight.Start(highlight.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	t.Cleanup(highlight.Stop)
+	return recorder
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+	return attrs
+}
+
+func TestTransport(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	var header string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get(highlight.RequestHeader)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx := context.WithValue(context.Background(), highlight.ContextKeys.SessionSecureID, "session")
+	ctx = context.WithValue(ctx, highlight.ContextKeys.RequestID, "request")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/users", nil)
+	assert.NoError(t, err)
+	req.URL.User = url.UserPassword("admin", "hunter2")
+
+	client := &http.Client{Transport: NewTransport(nil, WithTransportAttributes(attribute.String("service", "api")))}
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	assert.NoError(t, resp.Body.Close())
+	assert.Equal(t, "session/request", header)
+	assert.Empty(t, req.Header.Get(highlight.RequestHeader))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "HTTP GET", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "session", attrs[highlight.SessionIDAttribute].AsString())
+	assert.Equal(t, "api", attrs["service"].AsString())
+	assert.Equal(t, server.URL+"/users", attrs["http.url"].AsString())
+	assert.Equal(t, int64(http.StatusNotFound), attrs["http.status_code"].AsInt64())
+}
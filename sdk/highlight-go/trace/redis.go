@@ -0,0 +1,127 @@
+package htrace
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var redisPipelineLength = attribute.Key("db.redis.pipeline_length")
+
+type redisHook struct {
+	attrs           []attribute.KeyValue
+	includeCmdsArgs bool
+}
+
+type RedisOption func(h *redisHook)
+
+// NewRedisHook returns a go-redis hook creating a span for every command and pipeline.
+// The arguments of commands are redacted from the db.statement attribute unless WithRedisCommandArgs is set.
+//
+//	rdb := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	rdb.AddHook(htrace.NewRedisHook())
+func NewRedisHook(opts ...RedisOption) redis.Hook {
+	h := &redisHook{}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	return h
+}
+
+func (h *redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		span, ctx := highlight.StartTraceWithSpanKind(ctx, "redis.dial", trace.SpanKindClient)
+		defer highlight.EndTrace(span)
+
+		span.SetAttributes(h.attrs...)
+		span.SetAttributes(semconv.DBSystemRedis, semconv.NetPeerName(addr))
+
+		conn, err := next(ctx, network, addr)
+		setRedisError(span, err)
+		return conn, err
+	}
+}
+
+func (h *redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		span, ctx := highlight.StartTraceWithSpanKind(ctx, "redis."+cmd.FullName(), trace.SpanKindClient)
+		defer highlight.EndTrace(span)
+
+		span.SetAttributes(h.attrs...)
+		span.SetAttributes(semconv.DBSystemRedis, semconv.DBOperation(cmd.FullName()), semconv.DBStatement(h.statement(cmd)))
+
+		err := next(ctx, cmd)
+		setRedisError(span, err)
+		return err
+	}
+}
+
+func (h *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		span, ctx := highlight.StartTraceWithSpanKind(ctx, "redis.pipeline", trace.SpanKindClient)
+		defer highlight.EndTrace(span)
+
+		statements := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			statements[i] = h.statement(cmd)
+		}
+		span.SetAttributes(h.attrs...)
+		span.SetAttributes(semconv.DBSystemRedis, semconv.DBStatement(strings.Join(statements, "\n")), redisPipelineLength.Int(len(cmds)))
+
+		err := next(ctx, cmds)
+		setRedisError(span, err)
+		return err
+	}
+}
+
+// statement returns the command with its arguments, or with a placeholder for each argument
+// when arguments are redacted, as they may hold credentials (AUTH) and stored values.
+func (h *redisHook) statement(cmd redis.Cmder) string {
+	if h.includeCmdsArgs {
+		args := make([]string, len(cmd.Args()))
+		for i, arg := range cmd.Args() {
+			args[i] = fmt.Sprint(arg)
+		}
+		return strings.Join(args, " ")
+	}
+	// the full name of subcommands such as `cluster countkeysinslot` spans the first two arguments
+	name := cmd.FullName()
+	args := []string{name}
+	for i := len(strings.Fields(name)); i < len(cmd.Args()); i++ {
+		args = append(args, "?")
+	}
+	return strings.Join(args, " ")
+}
+
+func setRedisError(span trace.Span, err error) {
+	// redis.Nil reports a missing key rather than a failure
+	if err == nil || err == redis.Nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// WithRedisAttributes configures attributes that are added to every redis span.
+func WithRedisAttributes(attrs ...attribute.KeyValue) RedisOption {
+	return func(h *redisHook) {
+		h.attrs = append(h.attrs, attrs...)
+	}
+}
+
+// WithRedisCommandArgs configures the db.statement attribute to include the arguments of commands.
+// Arguments may include credentials and the values stored in redis.
+func WithRedisCommandArgs() RedisOption {
+	return func(h *redisHook) {
+		h.includeCmdsArgs = true
+	}
+}
@@ -0,0 +1,72 @@
+package htrace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestRedisHook(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	hook := NewRedisHook()
+	process := hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		return redis.Nil
+	})
+	assert.Equal(t, redis.Nil, process(context.Background(), redis.NewStringCmd(context.Background(), "get", "user:1")))
+
+	pipeline := hook.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		return errors.New("connection refused")
+	})
+	assert.Error(t, pipeline(context.Background(), []redis.Cmder{
+		redis.NewStatusCmd(context.Background(), "set", "user:1", "alice"),
+		redis.NewIntCmd(context.Background(), "incr", "users"),
+	}))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "redis.get", spans[0].Name())
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "redis", attrs["db.system"].AsString())
+	assert.Equal(t, "get ?", attrs["db.statement"].AsString())
+
+	assert.Equal(t, "redis.pipeline", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	attrs = spanAttributes(spans[1])
+	assert.Equal(t, "set ? ?\nincr ?", attrs["db.statement"].AsString())
+	assert.Equal(t, int64(2), attrs[redisPipelineLength].AsInt64())
+}
+
+func TestRedisHookRedactsArgs(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	process := NewRedisHook().ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		return nil
+	})
+	assert.NoError(t, process(context.Background(), redis.NewStatusCmd(context.Background(), "auth", "default", "hunter2")))
+	assert.NoError(t, process(context.Background(), redis.NewStatusCmd(context.Background(), "cluster", "countkeysinslot", "7000")))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "auth ? ?", spanAttributes(spans[0])["db.statement"].AsString())
+	assert.Equal(t, "cluster countkeysinslot ?", spanAttributes(spans[1])["db.statement"].AsString())
+}
+
+func TestWithRedisCommandArgs(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	process := NewRedisHook(WithRedisCommandArgs()).ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		return nil
+	})
+	assert.NoError(t, process(context.Background(), redis.NewStatusCmd(context.Background(), "set", "user:1", "alice")))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "set user:1 alice", spanAttributes(spans[0])["db.statement"].AsString())
+}
@@ -0,0 +1,304 @@
+package htrace
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/highlight/highlight/sdk/highlight-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var dbStatementArgs = attribute.Key("db.statement.args")
+
+type sqlConfig struct {
+	attrs            []attribute.KeyValue
+	excludeQueryVars bool
+}
+
+type SQLOption func(c *sqlConfig)
+
+// OpenSQL opens a database like sql.Open, creating a span for every statement executed.
+// The driver must be registered, usually by importing it.
+//
+//	db, err := htrace.OpenSQL("postgres", dsn, htrace.WithSQLAttributes(semconv.DBSystemPostgreSQL))
+func OpenSQL(driverName, dataSourceName string, opts ...SQLOption) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	// sql.Open does not connect, so closing only releases the unused pool
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+
+	var connector driver.Connector = dsnConnector{dsn: dataSourceName, driver: d}
+	if dc, ok := d.(driver.DriverContext); ok {
+		if connector, err = dc.OpenConnector(dataSourceName); err != nil {
+			return nil, err
+		}
+	}
+	return sql.OpenDB(WrapSQLConnector(connector, opts...)), nil
+}
+
+// WrapSQLConnector wraps a database/sql connector to create a span for every statement executed
+// on the connections it opens. Use it with sql.OpenDB.
+func WrapSQLConnector(connector driver.Connector, opts ...SQLOption) driver.Connector {
+	c := &sqlConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return &sqlConnector{Connector: connector, config: c}
+}
+
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type sqlConnector struct {
+	driver.Connector
+	config *sqlConfig
+}
+
+func (c *sqlConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &sqlConn{Conn: conn, config: c.config}, nil
+}
+
+// sqlConn creates spans for the statements executed on a connection.
+// Optional interfaces not implemented by the wrapped connection return driver.ErrSkip
+// or behave as database/sql would without them.
+type sqlConn struct {
+	driver.Conn
+	config *sqlConfig
+}
+
+var (
+	_ driver.ConnBeginTx        = (*sqlConn)(nil)
+	_ driver.ConnPrepareContext = (*sqlConn)(nil)
+	_ driver.ExecerContext      = (*sqlConn)(nil)
+	_ driver.QueryerContext     = (*sqlConn)(nil)
+	_ driver.Pinger             = (*sqlConn)(nil)
+	_ driver.SessionResetter    = (*sqlConn)(nil)
+	_ driver.Validator          = (*sqlConn)(nil)
+	_ driver.NamedValueChecker  = (*sqlConn)(nil)
+	_ driver.StmtExecContext    = (*sqlStmt)(nil)
+	_ driver.StmtQueryContext   = (*sqlStmt)(nil)
+	_ driver.NamedValueChecker  = (*sqlStmt)(nil)
+)
+
+func (c *sqlConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var stmt driver.Stmt
+	var err error
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sqlStmt{Stmt: stmt, conn: c, query: query}, nil
+}
+
+func (c *sqlConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *sqlConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support non-default isolation level or read-only transactions")
+	}
+	return c.Conn.Begin()
+}
+
+func (c *sqlConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		// database/sql prepares a statement instead, which is traced by sqlStmt
+		return nil, driver.ErrSkip
+	}
+	span, ctx := c.config.startSpan(ctx, "sql.Exec", query, args)
+	result, err := execer.ExecContext(ctx, query, args)
+	endSQLSpan(span, result, err)
+	return result, err
+}
+
+func (c *sqlConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	span, ctx := c.config.startSpan(ctx, "sql.Query", query, args)
+	rows, err := queryer.QueryContext(ctx, query, args)
+	endSQLSpan(span, nil, err)
+	return rows, err
+}
+
+func (c *sqlConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *sqlConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *sqlConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *sqlConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+type sqlStmt struct {
+	driver.Stmt
+	conn  *sqlConn
+	query string
+}
+
+func (s *sqlStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	span, ctx := s.conn.config.startSpan(ctx, "sql.Exec", s.query, args)
+	var result driver.Result
+	var err error
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = e.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			result, err = s.Stmt.Exec(values)
+		}
+	}
+	endSQLSpan(span, result, err)
+	return result, err
+}
+
+func (s *sqlStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	span, ctx := s.conn.config.startSpan(ctx, "sql.Query", s.query, args)
+	var rows driver.Rows
+	var err error
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValuesToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	endSQLSpan(span, nil, err)
+	return rows, err
+}
+
+// CheckNamedValue defers to the statement, then to the connection, as database/sql
+// only checks the statement once it implements driver.NamedValueChecker.
+func (s *sqlStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if n, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return n.CheckNamedValue(nv)
+	}
+	return s.conn.CheckNamedValue(nv)
+}
+
+func namedValuesToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+func (c *sqlConfig) startSpan(ctx context.Context, spanName, query string, args []driver.NamedValue) (trace.Span, context.Context) {
+	span, ctx := highlight.StartTraceWithSpanKind(ctx, spanName, trace.SpanKindClient)
+
+	attrs := make([]attribute.KeyValue, 0, len(c.attrs)+2)
+	attrs = append(attrs, c.attrs...)
+	attrs = append(attrs, semconv.DBStatement(query))
+	if !c.excludeQueryVars && len(args) > 0 {
+		values := make([]string, len(args))
+		for i, arg := range args {
+			values[i] = fmt.Sprintf("%v", arg.Value)
+		}
+		attrs = append(attrs, dbStatementArgs.StringSlice(values))
+	}
+	span.SetAttributes(attrs...)
+	return span, ctx
+}
+
+func endSQLSpan(span trace.Span, result driver.Result, err error) {
+	defer highlight.EndTrace(span)
+
+	switch err {
+	case nil:
+		if result != nil {
+			if rows, err := result.RowsAffected(); err == nil {
+				span.SetAttributes(dbRowsAffected.Int64(rows))
+			}
+		}
+	case driver.ErrSkip,
+		io.EOF, // end of rows iterator
+		sql.ErrNoRows:
+		// ignore
+	default:
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// WithSQLAttributes configures attributes that are added to every statement span.
+func WithSQLAttributes(attrs ...attribute.KeyValue) SQLOption {
+	return func(c *sqlConfig) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// WithSQLDBName configures a db.name attribute.
+func WithSQLDBName(name string) SQLOption {
+	return func(c *sqlConfig) {
+		c.attrs = append(c.attrs, semconv.DBName(name))
+	}
+}
+
+// WithoutSQLQueryVariables configures statement spans to exclude the db.statement.args attribute
+// holding the arguments of the statement.
+func WithoutSQLQueryVariables() SQLOption {
+	return func(c *sqlConfig) {
+		c.excludeQueryVars = true
+	}
+}
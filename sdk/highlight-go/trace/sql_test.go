@@ -0,0 +1,128 @@
+package htrace
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// testDriver is a driver without the optional context interfaces,
+// returning one row of a single column from every query.
+type testDriver struct{}
+
+func (testDriver) Open(string) (driver.Conn, error) {
+	return testConn{}, nil
+}
+
+type testConn struct{}
+
+func (testConn) Prepare(query string) (driver.Stmt, error) {
+	return testStmt{query: query}, nil
+}
+
+func (testConn) Close() error {
+	return nil
+}
+
+func (testConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not supported")
+}
+
+type testStmt struct {
+	query string
+}
+
+func (testStmt) Close() error {
+	return nil
+}
+
+func (testStmt) NumInput() int {
+	return -1
+}
+
+func (s testStmt) Exec([]driver.Value) (driver.Result, error) {
+	if s.query == "fail" {
+		return nil, errors.New("syntax error")
+	}
+	return driver.RowsAffected(2), nil
+}
+
+func (testStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &testRows{}, nil
+}
+
+type testRows struct {
+	done bool
+}
+
+func (*testRows) Columns() []string {
+	return []string{"id"}
+}
+
+func (*testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func init() {
+	sql.Register("htrace-test", testDriver{})
+}
+
+func TestOpenSQL(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	db, err := OpenSQL("htrace-test", "", WithSQLDBName("test"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.ExecContext(context.Background(), "UPDATE users SET name = ? WHERE id = ?", "alice", 1)
+	assert.NoError(t, err)
+	var id int
+	assert.NoError(t, db.QueryRowContext(context.Background(), "SELECT id FROM users").Scan(&id))
+	assert.Equal(t, 1, id)
+	_, err = db.ExecContext(context.Background(), "fail")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+
+	assert.Equal(t, "sql.Exec", spans[0].Name())
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "UPDATE users SET name = ? WHERE id = ?", attrs["db.statement"].AsString())
+	assert.Equal(t, []string{"alice", "1"}, attrs[dbStatementArgs].AsStringSlice())
+	assert.Equal(t, "test", attrs["db.name"].AsString())
+	assert.Equal(t, int64(2), attrs[dbRowsAffected].AsInt64())
+
+	assert.Equal(t, "sql.Query", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func TestWithoutSQLQueryVariables(t *testing.T) {
+	recorder := startTestTracing(t)
+
+	db := sql.OpenDB(WrapSQLConnector(dsnConnector{driver: testDriver{}}, WithoutSQLQueryVariables()))
+	defer db.Close()
+
+	_, err := db.ExecContext(context.Background(), "UPDATE users SET name = ?", "alice")
+	assert.NoError(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	attrs := spanAttributes(spans[0])
+	assert.Equal(t, "UPDATE users SET name = ?", attrs["db.statement"].AsString())
+	assert.NotContains(t, attrs, dbStatementArgs)
+}